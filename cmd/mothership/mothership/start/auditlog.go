@@ -2,14 +2,21 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"mime"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/kubernetes"
 	"github.com/kyma-incubator/reconciler/pkg/server"
 
 	"github.com/google/uuid"
@@ -21,10 +28,107 @@ import (
 
 const (
 	XJWTHeaderName            = "X-Jwt"
+	AuthorizationHeaderName   = "Authorization"
 	ExternalAddressHeaderName = "X-Envoy-External-Address"
+	CorrelationIDHeaderName   = "X-Correlation-ID"
 )
 
-func NewLoggerWithFile(logFile string) (*zap.Logger, error) {
+type correlationIDKey struct{}
+
+// CorrelationIDFromContext returns the correlation ID the audit middleware injected into the request context.
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
+
+// withCorrelationID reads the correlation ID from the request header, or generates one if it is absent,
+// and injects it into the request context and the response header.
+func withCorrelationID(w http.ResponseWriter, r *http.Request) (*http.Request, string) {
+	correlationID := r.Header.Get(CorrelationIDHeaderName)
+	if correlationID == "" {
+		correlationID = uuid.New().String()
+	}
+	w.Header().Set(CorrelationIDHeaderName, correlationID)
+	return r.WithContext(context.WithValue(r.Context(), correlationIDKey{}, correlationID)), correlationID
+}
+
+// LogRotationConfig configures the rotation of the audit log file.
+// Zero values fall back to the defaults.
+type LogRotationConfig struct {
+	MaxSize    int // megabytes
+	MaxBackups int
+	MaxAge     int // days
+	Compress   bool
+}
+
+const (
+	defaultLogMaxSize    = 100 // megabytes
+	defaultLogMaxBackups = 5
+	defaultLogMaxAge     = 1 // days
+)
+
+func newRotatingWriter(logFile string, rotation LogRotationConfig) *lumberjack.Logger {
+	writer := &lumberjack.Logger{
+		Filename:   logFile,
+		MaxSize:    defaultLogMaxSize,
+		MaxBackups: defaultLogMaxBackups,
+		MaxAge:     defaultLogMaxAge,
+		Compress:   rotation.Compress, // disabled by default to save cpu cycles
+	}
+	if rotation.MaxSize > 0 {
+		writer.MaxSize = rotation.MaxSize
+	}
+	if rotation.MaxBackups > 0 {
+		writer.MaxBackups = rotation.MaxBackups
+	}
+	if rotation.MaxAge > 0 {
+		writer.MaxAge = rotation.MaxAge
+	}
+	return writer
+}
+
+// auditLogRecordKeys are the keys of the fields written to every audit log record, static fields must not use them.
+var auditLogRecordKeys = []string{"level", "time", "uuid", "user", "data", "tenant", "ip", "category"}
+
+type loggerOptions struct {
+	fields []zap.Field
+}
+
+// LoggerOption configures the logger created by NewLoggerWithFile.
+type LoggerOption func(*loggerOptions)
+
+// WithFields attaches static fields to every record of the logger, e.g. the instance ID, region and environment of the mothership.
+func WithFields(fields ...zap.Field) LoggerOption {
+	return func(o *loggerOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+// checkStaticFieldKeys returns an error if a key collides with the fields of the audit log records.
+func checkStaticFieldKeys(keys []string) error {
+	for _, key := range keys {
+		for _, recordKey := range auditLogRecordKeys {
+			if key == recordKey {
+				return errors.Errorf("Static audit log field %q collides with a field of the audit log records", key)
+			}
+		}
+	}
+	return nil
+}
+
+func NewLoggerWithFile(logFile string, rotation LogRotationConfig, opts ...LoggerOption) (*zap.Logger, error) {
+	options := &loggerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	keys := make([]string, 0, len(options.fields))
+	for _, field := range options.fields {
+		keys = append(keys, field.Key)
+	}
+	if err := checkStaticFieldKeys(keys); err != nil {
+		return nil, err
+	}
+
 	cfg := zap.Config{
 		Encoding:         "json",
 		Level:            zap.NewAtomicLevelAt(zapcore.DebugLevel),
@@ -36,13 +140,7 @@ func NewLoggerWithFile(logFile string) (*zap.Logger, error) {
 	if err != nil {
 		return nil, err
 	}
-	ws := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   logFile,
-		MaxSize:    100, // megabytes
-		MaxBackups: 5,
-		MaxAge:     1,     // days
-		Compress:   false, // save cpu cycles
-	})
+	ws := zapcore.AddSync(newRotatingWriter(logFile, rotation))
 	// I need to replace the default core logger whit a new one that contains
 	// WriterSyncer that wraps lumberjack. Lumberjack handels the log rotation.
 	return logger.WithOptions(
@@ -60,20 +158,216 @@ func NewLoggerWithFile(logFile string) (*zap.Logger, error) {
 				zap.InfoLevel,
 			)
 		}),
-	), err
+	).With(options.fields...), err
+}
+
+// AuditEvent is the audit record of a request passed to an AuditSink.
+type AuditEvent struct {
+	// CorrelationID identifies the request, it is also returned in the X-Correlation-ID response header.
+	CorrelationID string
+	// Time is the time the response was written.
+	Time time.Time
+	Data data
+}
+
+// AuditSink records audit events in an audit backend.
+type AuditSink interface {
+	Record(ctx context.Context, event AuditEvent) error
+}
+
+// ZapAuditSink writes audit events with a zap logger in the format required by the audit log backend.
+type ZapAuditSink struct {
+	logger *zap.Logger
 }
 
-func newAuditLoggerMiddelware(l *zap.Logger, o *Options) func(http.Handler) http.Handler {
+func NewZapAuditSink(l *zap.Logger) *ZapAuditSink {
+	return &ZapAuditSink{logger: l}
+}
+
+// NewFileAuditSink creates the default AuditSink writing to a rotating log file.
+func NewFileAuditSink(logFile string, rotation LogRotationConfig, opts ...LoggerOption) (*ZapAuditSink, error) {
+	logger, err := NewLoggerWithFile(logFile, rotation, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewZapAuditSink(logger), nil
+}
+
+func (s *ZapAuditSink) Record(_ context.Context, event AuditEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal auditlog JSON payload")
+	}
+	s.logger.With(zap.String("time", event.Time.Format(time.RFC3339))).
+		With(zap.String("uuid", event.CorrelationID)).
+		With(zap.String("user", event.Data.User)).
+		With(zap.String("data", string(data))).
+		With(zap.String("tenant", event.Data.Tenant)).
+		With(zap.String("ip", event.Data.IP)).
+		With(zap.String("category", "audit.security-events")). // comply with required log backend format
+		Info("")
+	return nil
+}
+
+// Sync flushes the buffered log entries.
+func (s *ZapAuditSink) Sync() error {
+	return s.logger.Sync()
+}
+
+// NewAuditLoggerMiddelware records an audit event in the sink for every request which is not skipped.
+func NewAuditLoggerMiddelware(sink AuditSink, o *Options) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			auditLogRequest(w, r, l, o)
+			r, correlationID := withCorrelationID(w, r)
+			if isAuditSkipped(r.URL.Path, o.AuditLogSkipPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			start := time.Now()
+			logData, ok := auditLogData(w, r, o)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-			next.ServeHTTP(w, r)
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			logData.StatusCode = recorder.Status()
+			logData.LatencyMs = time.Since(start).Milliseconds()
+			event := AuditEvent{CorrelationID: correlationID, Time: time.Now(), Data: logData}
+			if err := sink.Record(r.Context(), event); err != nil {
+				// the response was already sent, so the failure can only be logged
+				o.Logger().Errorf("Failed to record audit event: %s", err)
+			}
 		})
 	}
 }
 
+// asyncAuditLogger records audit events in a background worker. If the buffer is full, events are dropped and counted
+// to not block the request handling.
+type asyncAuditLogger struct {
+	dropped uint64 // first field to be 64-bit aligned for atomic access
+	sink    AuditSink
+	o       *Options
+	events  chan AuditEvent
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// newAsyncAuditLogger creates an asyncAuditLogger buffering up to bufferSize events for the sink and starts its worker.
+func newAsyncAuditLogger(sink AuditSink, o *Options, bufferSize int) *asyncAuditLogger {
+	a := &asyncAuditLogger{
+		sink:   sink,
+		o:      o,
+		events: make(chan AuditEvent, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *asyncAuditLogger) run() {
+	defer close(a.done)
+	for event := range a.events {
+		// the request context is already cancelled when the event is written
+		if err := a.sink.Record(context.Background(), event); err != nil {
+			a.o.Logger().Errorf("Failed to record audit event: %s", err)
+		}
+	}
+}
+
+// Record enqueues the event and returns immediately.
+func (a *asyncAuditLogger) Record(_ context.Context, event AuditEvent) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		// requests still running after the shutdown are not logged
+		atomic.AddUint64(&a.dropped, 1)
+		return nil
+	}
+	select {
+	case a.events <- event:
+	default:
+		atomic.AddUint64(&a.dropped, 1)
+	}
+	return nil
+}
+
+// Dropped returns the number of audit events dropped because the buffer was full or the logger was closed.
+func (a *asyncAuditLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Close stops accepting events and blocks until all buffered events are recorded.
+func (a *asyncAuditLogger) Close() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	close(a.events)
+	a.mu.Unlock()
+
+	<-a.done
+	if dropped := a.Dropped(); dropped > 0 {
+		a.o.Logger().Warnf("Dropped %d audit log records because the audit log buffer was full or already closed", dropped)
+	}
+}
+
+// statusRecorder captures the status code of the response written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	// the first Write implicitly sends a 200 status code
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Status returns the status code of the response, which is 200 if the handler wrote nothing.
+func (r *statusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// isAuditSkipped returns true if the path starts with one of the skipped path prefixes.
+func isAuditSkipped(path string, skipPaths []string) bool {
+	for _, skipPath := range skipPaths {
+		if skipPath != "" && strings.HasPrefix(path, skipPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditSchemaVersion is the version of the audit event schema, the JSON shape of data. It must be increased whenever
+// a field of data is added, removed, renamed or changes its meaning, so consumers can branch on the schemaVersion of a record.
+//
+// Version 1 contains schemaVersion, contractVersion, method, uri, requestBody, user, tenant, ip, claims, issuer, audience,
+// tokenAgeSeconds, tokenExpired, statusCode, latencyMs, requestBodyEncoding, requestBodyContentType and requestBodySize.
+// Version 2 adds clusterFingerprint.
+const auditSchemaVersion = 2
+
+// data is the audit event written to the data field of the audit log records, its JSON shape is versioned by auditSchemaVersion.
 type data struct {
+	// SchemaVersion is the auditSchemaVersion the event was written with.
+	SchemaVersion   int    `json:"schemaVersion"`
 	ContractVersion int64  `json:"contractVersion"`
 	Method          string `json:"method"`
 	URI             string `json:"uri"`
@@ -81,9 +375,32 @@ type data struct {
 	User            string `json:"user"`
 	Tenant          string `json:"tenant"`
 	IP              string `json:"ip"`
+	// Claims contains the configured JWT claims as raw JSON, which preserves array claims like groups.
+	Claims map[string]json.RawMessage `json:"claims,omitempty"`
+	// Issuer and Audience are the iss and aud claims of the JWT, which attribute the request to an identity provider and tenant.
+	Issuer   string   `json:"issuer,omitempty"`
+	Audience []string `json:"audience,omitempty"`
+	// TokenAgeSeconds is the age of the JWT when the request was received, based on its iat claim.
+	TokenAgeSeconds *int64 `json:"tokenAgeSeconds,omitempty"`
+	// TokenExpired flags requests using a JWT whose exp claim was in the past, for security review.
+	// Such requests are not rejected, validating the token is done by the auth layer.
+	TokenExpired bool `json:"tokenExpired"`
+	// StatusCode is the status code of the response.
+	StatusCode int `json:"statusCode"`
+	// LatencyMs is the time in milliseconds until the response was written.
+	LatencyMs int64 `json:"latencyMs"`
+	// RequestBodyEncoding is "base64" if the request body is not JSON, e.g. form data or binary, and was encoded to keep the log readable.
+	RequestBodyEncoding string `json:"requestBodyEncoding,omitempty"`
+	// RequestBodyContentType and RequestBodySize describe a request body which is not JSON.
+	// Bodies larger than maxEncodedRequestBodySize are only described, the RequestBody is empty.
+	RequestBodyContentType string `json:"requestBodyContentType,omitempty"`
+	RequestBodySize        int    `json:"requestBodySize,omitempty"`
+	// ClusterFingerprint identifies the cluster of the kubeconfig sent in a JSON request body, see kubernetes.ClusterFingerprint.
+	ClusterFingerprint string `json:"clusterFingerprint,omitempty"`
 }
 
-func auditLogRequest(w http.ResponseWriter, r *http.Request, l *zap.Logger, o *Options) {
+// auditLogData collects the audit data of the request. If it can not be collected, an error response is sent and false is returned.
+func auditLogData(w http.ResponseWriter, r *http.Request, o *Options) (data, bool) {
 	params := server.NewParams(r)
 	contractV, err := params.Int64(paramContractVersion)
 	if err != nil {
@@ -91,9 +408,10 @@ func auditLogRequest(w http.ResponseWriter, r *http.Request, l *zap.Logger, o *O
 		server.SendHTTPError(w, http.StatusBadRequest, &keb.HTTPErrorResponse{
 			Error: errors.Wrap(err, "Contract version undefined").Error(),
 		})
-		return
+		return data{}, false
 	}
 	logData := data{
+		SchemaVersion:   auditSchemaVersion,
 		ContractVersion: contractV,
 		Method:          r.Method,
 		URI:             r.RequestURI,
@@ -101,12 +419,12 @@ func auditLogRequest(w http.ResponseWriter, r *http.Request, l *zap.Logger, o *O
 		Tenant:          o.AuditLogTenantID,
 		IP:              "-",
 	}
-	jwtPayload, err := getJWTPayload(r)
+	jwtPayload, headerName, err := getJWTPayload(r, o.AuditLogJWTHeader, o.AuditLogBearerHeader)
 	if err != nil {
 		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
-			Error: errors.Wrap(err, fmt.Sprintf("Failed to parse %s header content ", XJWTHeaderName)).Error(),
+			Error: errors.Wrap(err, fmt.Sprintf("Failed to parse %s header content ", headerName)).Error(),
 		})
-		return
+		return data{}, false
 	}
 
 	user, err := getJWTPayloadSub(jwtPayload)
@@ -114,12 +432,32 @@ func auditLogRequest(w http.ResponseWriter, r *http.Request, l *zap.Logger, o *O
 		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
 			Error: errors.Wrap(err, "failed to Unmarshal JWT payload").Error(),
 		})
-		return
+		return data{}, false
 	}
 	if user != "" {
 		logData.User = user
 	}
 
+	claims, err := getJWTPayloadClaims(jwtPayload, o.AuditLogJWTClaims)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
+			Error: errors.Wrap(err, "failed to Unmarshal JWT payload").Error(),
+		})
+		return data{}, false
+	}
+	logData.Claims = claims
+
+	identity := getJWTPayloadIdentity(jwtPayload)
+	logData.Issuer = identity.issuer
+	logData.Audience = identity.audience
+
+	freshness := getJWTPayloadFreshness(jwtPayload, time.Now())
+	logData.TokenAgeSeconds = freshness.ageSeconds
+	logData.TokenExpired = freshness.expired
+	if freshness.expired {
+		o.Logger().Warnf("Request %s %s of user %s used an expired JWT", r.Method, r.RequestURI, logData.User)
+	}
+
 	// log request body if needed.
 	if r.Method == "POST" || r.Method == "PUT" {
 		reqBody, err := ioutil.ReadAll(r.Body)
@@ -127,10 +465,11 @@ func auditLogRequest(w http.ResponseWriter, r *http.Request, l *zap.Logger, o *O
 			server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
 				Error: errors.Wrap(err, "Failed to read received JSON payload").Error(),
 			})
-			return
+			return data{}, false
 		}
 		r.Body = ioutil.NopCloser(bytes.NewBuffer(reqBody))
-		logData.RequestBody = string(reqBody)
+		logData.setRequestBody(reqBody, r.Header.Get("Content-Type"))
+		logData.ClusterFingerprint = requestClusterFingerprint(reqBody)
 	}
 
 	ip := r.Header.Get(ExternalAddressHeaderName)
@@ -139,33 +478,93 @@ func auditLogRequest(w http.ResponseWriter, r *http.Request, l *zap.Logger, o *O
 	}
 	logData.IP = ip
 
-	data, err := json.Marshal(logData)
-	if err != nil {
-		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
-			Error: errors.Wrap(err, "Failed to marshal auditlog JSON payload").Error(),
-		})
+	return logData, true
+}
+
+// maxEncodedRequestBodySize is the size up to which request bodies which are not JSON are logged base64 encoded.
+const maxEncodedRequestBodySize = 4096
+
+// setRequestBody logs JSON request bodies as they are. Other bodies are logged base64 encoded together with their content type and size,
+// or only described by them if they are larger than maxEncodedRequestBodySize.
+func (d *data) setRequestBody(body []byte, contentTypeHeader string) {
+	if len(body) == 0 || json.Valid(body) {
+		d.RequestBody = string(body)
 		return
 	}
-	l.With(zap.String("time", time.Now().Format(time.RFC3339))).
-		With(zap.String("uuid", uuid.New().String())).
-		With(zap.String("user", logData.User)).
-		With(zap.String("data", string(data))).
-		With(zap.String("tenant", o.AuditLogTenantID)).
-		With(zap.String("ip", logData.IP)).
-		With(zap.String("category", "audit.security-events")). // comply with required log backend format
-		Info("")
+	d.RequestBodyContentType = requestBodyContentType(body, contentTypeHeader)
+	d.RequestBodySize = len(body)
+	if len(body) <= maxEncodedRequestBodySize {
+		d.RequestBody = base64.StdEncoding.EncodeToString(body)
+		d.RequestBodyEncoding = "base64"
+	}
+}
+
+// requestClusterFingerprint returns the fingerprint of the kubeconfig of a JSON request body, like the body of a cluster update.
+// It is empty if the body has no kubeconfig or it cannot be parsed.
+func requestClusterFingerprint(body []byte) string {
+	cluster := struct {
+		Kubeconfig string `json:"kubeconfig"`
+	}{}
+	if json.Unmarshal(body, &cluster) != nil || cluster.Kubeconfig == "" {
+		return ""
+	}
+	fingerprint, err := kubernetes.ClusterFingerprint(cluster.Kubeconfig)
+	if err != nil {
+		return ""
+	}
+	return fingerprint
 }
 
-func getJWTPayload(r *http.Request) (string, error) {
+// requestBodyContentType returns the media type of the Content-Type header, or the type detected from the body if the header is missing or invalid.
+func requestBodyContentType(body []byte, contentTypeHeader string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentTypeHeader); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(body))
+	return mediaType
+}
+
+// getJWTPayload returns the decoded JWT payload and the name of the header it was read from.
+// The JWT header is preferred, the bearer token of the bearer header is used if it is absent.
+// Empty header names fall back to X-Jwt and Authorization.
+func getJWTPayload(r *http.Request, jwtHeaderName, bearerHeaderName string) (string, string, error) {
+	if jwtHeaderName == "" {
+		jwtHeaderName = XJWTHeaderName
+	}
+	if bearerHeaderName == "" {
+		bearerHeaderName = AuthorizationHeaderName
+	}
+
 	// The jwtHeader here is not a full JWT token. Instead, it's only the
 	// encoded payload part of the token. It's passed by Istio as a header
 	// since Authz/Authn is done by Istio, and we don't receive the original full JWT token.
-	jwtHeader := r.Header.Get(XJWTHeaderName)
-	if len(jwtHeader) == 0 {
-		return "", nil
+	jwtHeader := r.Header.Get(jwtHeaderName)
+	if len(jwtHeader) > 0 {
+		decodedSeg, err := base64.RawURLEncoding.DecodeString(jwtHeader)
+		return string(decodedSeg), jwtHeaderName, err
+	}
+
+	// Gateways which don't strip the token forward it in the bearer header, e.g. "Authorization: Bearer <header>.<payload>.<signature>".
+	token, found := bearerToken(r.Header.Get(bearerHeaderName))
+	if !found {
+		return "", bearerHeaderName, nil
+	}
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return "", bearerHeaderName, fmt.Errorf("bearer token has %d segments, expected 3", len(segments))
+	}
+	decodedSeg, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segments[1], "="))
+	return string(decodedSeg), bearerHeaderName, err
+}
+
+// bearerToken returns the token of a header value using the Bearer scheme. Other schemes are ignored.
+func bearerToken(headerValue string) (string, bool) {
+	const prefix = "bearer "
+	if len(headerValue) <= len(prefix) || !strings.EqualFold(headerValue[:len(prefix)], prefix) {
+		return "", false
 	}
-	decodedSeg, err := base64.RawURLEncoding.DecodeString(jwtHeader)
-	return string(decodedSeg), err
+	token := strings.TrimSpace(headerValue[len(prefix):])
+	return token, token != ""
 }
 
 type jwtSub struct {
@@ -180,3 +579,95 @@ func getJWTPayloadSub(payload string) (string, error) {
 	err := json.Unmarshal([]byte(payload), &s)
 	return s.Sub, err
 }
+
+// getJWTPayloadClaims returns the requested claims found in the payload. Claims missing in the payload are skipped.
+func getJWTPayloadClaims(payload string, claimNames []string) (map[string]json.RawMessage, error) {
+	if payload == "" || len(claimNames) == 0 {
+		return nil, nil
+	}
+	allClaims := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(payload), &allClaims); err != nil {
+		return nil, err
+	}
+	claims := map[string]json.RawMessage{}
+	for _, name := range claimNames {
+		if claim, ok := allClaims[name]; ok {
+			claims[name] = claim
+		}
+	}
+	return claims, nil
+}
+
+type jwtIdentity struct {
+	issuer   string
+	audience []string
+}
+
+// getJWTPayloadIdentity returns the iss and aud claims of the payload. The aud claim is either a single string or an array of strings.
+// Missing or malformed claims are skipped, so a token without them has no identity.
+func getJWTPayloadIdentity(payload string) jwtIdentity {
+	identity := jwtIdentity{}
+	if payload == "" {
+		return identity
+	}
+	claims := struct {
+		Iss json.RawMessage `json:"iss"`
+		Aud json.RawMessage `json:"aud"`
+	}{}
+	if err := json.Unmarshal([]byte(payload), &claims); err != nil {
+		return identity
+	}
+	var issuer string
+	if len(claims.Iss) > 0 && json.Unmarshal(claims.Iss, &issuer) == nil {
+		identity.issuer = issuer
+	}
+	var audience string
+	var audiences []string
+	switch {
+	case len(claims.Aud) == 0:
+	case json.Unmarshal(claims.Aud, &audience) == nil && audience != "":
+		identity.audience = []string{audience}
+	case json.Unmarshal(claims.Aud, &audiences) == nil && len(audiences) > 0:
+		identity.audience = audiences
+	}
+	return identity
+}
+
+type jwtFreshness struct {
+	ageSeconds *int64
+	expired    bool
+}
+
+// getJWTPayloadFreshness evaluates the exp and iat claims of the payload at the given time.
+// Missing or malformed claims are skipped, so a token without them is neither aged nor expired.
+func getJWTPayloadFreshness(payload string, now time.Time) jwtFreshness {
+	freshness := jwtFreshness{}
+	if payload == "" {
+		return freshness
+	}
+	claims := struct {
+		Exp json.RawMessage `json:"exp"`
+		Iat json.RawMessage `json:"iat"`
+	}{}
+	if err := json.Unmarshal([]byte(payload), &claims); err != nil {
+		return freshness
+	}
+	if iat, ok := numericDate(claims.Iat); ok {
+		age := int64(now.Sub(iat) / time.Second)
+		freshness.ageSeconds = &age
+	}
+	if exp, ok := numericDate(claims.Exp); ok {
+		freshness.expired = now.After(exp)
+	}
+	return freshness
+}
+
+// numericDate parses a JWT NumericDate, the seconds since the epoch which may contain a fraction.
+func numericDate(claim json.RawMessage) (time.Time, bool) {
+	var seconds float64
+	if len(claim) == 0 || string(claim) == "null" || json.Unmarshal(claim, &seconds) != nil {
+		return time.Time{}, false
+	}
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*float64(time.Second))), true
+}
@@ -57,6 +57,16 @@ func NewCmd(o *Options) *cobra.Command {
 	cmd.Flags().BoolVar(&o.AuditLog, "audit-log", false, "Enable audit logging")
 	cmd.Flags().StringVar(&o.AuditLogFile, "audit-log-file", "/var/log/auditlog/mothership-audit.log", "Path for mothership audit log file")
 	cmd.Flags().StringVar(&o.AuditLogTenantID, "audit-log-tenant-id", "", "tenant id for audit logging")
+	cmd.Flags().IntVar(&o.AuditLogRotation.MaxSize, "audit-log-max-size", 100, "Maximum size in megabytes of the audit log file before it gets rotated")
+	cmd.Flags().IntVar(&o.AuditLogRotation.MaxBackups, "audit-log-max-backups", 5, "Maximum number of rotated audit log files to retain")
+	cmd.Flags().IntVar(&o.AuditLogRotation.MaxAge, "audit-log-max-age", 1, "Maximum number of days to retain rotated audit log files")
+	cmd.Flags().BoolVar(&o.AuditLogRotation.Compress, "audit-log-compress", false, "Compress rotated audit log files")
+	cmd.Flags().StringSliceVar(&o.AuditLogJWTClaims, "audit-log-jwt-claims", []string{}, "Comma separated list of JWT claims to include in audit logs, e.g. email,groups,iss")
+	cmd.Flags().StringVar(&o.AuditLogJWTHeader, "audit-log-jwt-header", XJWTHeaderName, "Header containing the encoded JWT payload used for audit logs")
+	cmd.Flags().StringVar(&o.AuditLogBearerHeader, "audit-log-bearer-header", AuthorizationHeaderName, "Header containing the bearer token used for audit logs if the JWT header is absent")
+	cmd.Flags().StringSliceVar(&o.AuditLogSkipPaths, "audit-log-skip-paths", []string{"/health", "/metrics"}, "Comma separated list of URL path prefixes which are not audit logged")
+	cmd.Flags().StringToStringVar(&o.AuditLogFields, "audit-log-fields", map[string]string{}, "Comma separated static fields added to every audit log record, e.g. instance=mothership-1,region=eu-west-1,environment=prod")
+	cmd.Flags().IntVar(&o.AuditLogAsyncBuffer, "audit-log-async-buffer", 0, "Size of the buffer for writing audit logs asynchronously, records are dropped if it is full (0 writes synchronously)")
 	cmd.Flags().BoolVar(&o.StopAfterMigration, "stop-after-migrate", false, "Stop mothership after database migration to the latest release")
 	return cmd
 }
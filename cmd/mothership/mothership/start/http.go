@@ -146,13 +146,18 @@ func startWebserver(ctx context.Context, o *Options) error {
 	healthRouter.HandleFunc("/ready", ready(o))
 
 	if o.AuditLog && o.AuditLogFile != "" && o.AuditLogTenantID != "" {
-		auditLogger, err := NewLoggerWithFile(o.AuditLogFile)
+		fileAuditSink, err := NewFileAuditSink(o.AuditLogFile, o.AuditLogRotation, WithFields(o.AuditLogStaticFields()...))
 		if err != nil {
 			return err
 		}
-		defer func() { _ = auditLogger.Sync() }() // make golint happy
-		auditLoggerMiddelware := newAuditLoggerMiddelware(auditLogger, o)
-		apiRouter.Use(auditLoggerMiddelware)
+		defer func() { _ = fileAuditSink.Sync() }() // make golint happy
+		var auditSink AuditSink = fileAuditSink
+		if o.AuditLogAsyncBuffer > 0 {
+			asyncAuditLogger := newAsyncAuditLogger(fileAuditSink, o, o.AuditLogAsyncBuffer)
+			defer asyncAuditLogger.Close() // flush buffered records after the server stopped
+			auditSink = asyncAuditLogger
+		}
+		apiRouter.Use(NewAuditLoggerMiddelware(auditSink, o))
 	}
 	//start server process
 	srv := &server.Webserver{
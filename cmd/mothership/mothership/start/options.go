@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/scheduler/config"
 
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	"github.com/kyma-incubator/reconciler/internal/cli"
 	"github.com/kyma-incubator/reconciler/pkg/ssl"
@@ -30,33 +32,65 @@ type Options struct {
 	AuditLog                       bool
 	AuditLogFile                   string
 	AuditLogTenantID               string
+	AuditLogJWTClaims              []string
+	AuditLogJWTHeader              string
+	AuditLogBearerHeader           string
+	AuditLogSkipPaths              []string
+	AuditLogAsyncBuffer            int
+	AuditLogRotation               LogRotationConfig
+	AuditLogFields                 map[string]string
 	StopAfterMigration             bool
 	Config                         *config.Config
 }
 
 func NewOptions(o *cli.Options) *Options {
 	return &Options{o,
-		0,                //Port
-		"",               //SSLCrt
-		"",               //SSLKey
-		0,                //Workers
-		0 * time.Second,  //WatchInterval
-		0 * time.Minute,  //Orphan timeout
-		0 * time.Second,  //ClusterReconcileInterval
-		0 * time.Minute,  //PurgeEntitiesOlderThan
-		0 * time.Minute,  //CleanerInterval
-		0,                //ReconciliationsKeepLatestCount
-		0,                //EntitiesMaxAgeDays
-		false,            //CreateEncyptionKey
-		0,                //MaxParallelOperations
-		false,            //AuditLog
-		"",               //AuditLogFile
-		"",               //AuditLogTenant
-		false,            //StopAfterMigration
-		&config.Config{}, //Config
+		0,                   //Port
+		"",                  //SSLCrt
+		"",                  //SSLKey
+		0,                   //Workers
+		0 * time.Second,     //WatchInterval
+		0 * time.Minute,     //Orphan timeout
+		0 * time.Second,     //ClusterReconcileInterval
+		0 * time.Minute,     //PurgeEntitiesOlderThan
+		0 * time.Minute,     //CleanerInterval
+		0,                   //ReconciliationsKeepLatestCount
+		0,                   //EntitiesMaxAgeDays
+		false,               //CreateEncyptionKey
+		0,                   //MaxParallelOperations
+		false,               //AuditLog
+		"",                  //AuditLogFile
+		"",                  //AuditLogTenant
+		nil,                 //AuditLogJWTClaims
+		"",                  //AuditLogJWTHeader
+		"",                  //AuditLogBearerHeader
+		nil,                 //AuditLogSkipPaths
+		0,                   //AuditLogAsyncBuffer
+		LogRotationConfig{}, //AuditLogRotation
+		nil,                 //AuditLogFields
+		false,               //StopAfterMigration
+		&config.Config{},    //Config
 	}
 }
 
+// AuditLogStaticFields returns the AuditLogFields as zap fields sorted by key.
+func (o *Options) AuditLogStaticFields() []zap.Field {
+	fields := make([]zap.Field, 0, len(o.AuditLogFields))
+	for _, key := range o.auditLogFieldKeys() {
+		fields = append(fields, zap.String(key, o.AuditLogFields[key]))
+	}
+	return fields
+}
+
+func (o *Options) auditLogFieldKeys() []string {
+	keys := make([]string, 0, len(o.AuditLogFields))
+	for key := range o.AuditLogFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (o *Options) Validate() error {
 	if o.Port <= 0 || o.Port > 65535 {
 		return fmt.Errorf("port %d is out of range 1-65535", o.Port)
@@ -90,6 +124,12 @@ func (o *Options) Validate() error {
 			return errors.New("audit log tenant-id must be set if audit logging is enable")
 
 		}
+		if o.AuditLogAsyncBuffer < 0 {
+			return errors.New("audit log async buffer size cannot be < 0")
+		}
+		if err := checkStaticFieldKeys(o.auditLogFieldKeys()); err != nil {
+			return err
+		}
 	}
 	return ssl.VerifyKeyPair(o.SSLCrt, o.SSLKey)
 }
@@ -0,0 +1,43 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// fingerprintLength is the number of hex characters of the fingerprint, short enough to be used as metrics label.
+const fingerprintLength = 32
+
+// ClusterFingerprint returns a stable identifier of the cluster the kubeconfig points to, which can be used
+// to label logs, metrics and audit events instead of the kubeconfig itself.
+// The fingerprint is a hash of the API server URL and the CA of the current context, so it does not change
+// when credentials are rotated and does not expose them.
+func ClusterFingerprint(kubeConfig string) (string, error) {
+	config, err := clientcmd.Load([]byte(kubeConfig))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	context, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return "", fmt.Errorf("current context '%s' not found in kubeconfig", config.CurrentContext)
+	}
+	cluster, ok := config.Clusters[context.Cluster]
+	if !ok {
+		return "", fmt.Errorf("cluster '%s' of current context not found in kubeconfig", context.Cluster)
+	}
+	if cluster.Server == "" {
+		return "", fmt.Errorf("cluster '%s' of current context has no server URL", context.Cluster)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(cluster.Server))
+	hash.Write([]byte{0})
+	hash.Write(cluster.CertificateAuthorityData)
+	hash.Write([]byte(cluster.CertificateAuthority))
+	return hex.EncodeToString(hash.Sum(nil))[:fingerprintLength], nil
+}
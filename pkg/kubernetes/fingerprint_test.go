@@ -0,0 +1,110 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testServer = "https://api.cluster.example.com"
+	testCA     = "-----BEGIN CERTIFICATE-----\nMIIC5zCCAc+gAwIBAgIBATANBgkqhkiG9w0BAQsFADAVMRMwEQYDVQQDEwptaW5p\n-----END CERTIFICATE-----\n"
+	testToken  = "eyJhbGciOiJSUzI1NiIsImtpZCI6InNlY3JldC10b2tlbiJ9"
+)
+
+func TestClusterFingerprint(t *testing.T) {
+
+	t.Run("should return the same fingerprint for the same cluster", func(t *testing.T) {
+		// when
+		first, err := ClusterFingerprint(fixKubeconfig(testServer, testCA, testToken))
+		require.NoError(t, err)
+		second, err := ClusterFingerprint(fixKubeconfig(testServer, testCA, testToken))
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, first, fingerprintLength)
+		require.Equal(t, first, second)
+	})
+
+	t.Run("should return the same fingerprint when the credentials change", func(t *testing.T) {
+		// when
+		first, err := ClusterFingerprint(fixKubeconfig(testServer, testCA, testToken))
+		require.NoError(t, err)
+		second, err := ClusterFingerprint(fixKubeconfig(testServer, testCA, "rotated-token"))
+		require.NoError(t, err)
+
+		// then
+		require.Equal(t, first, second)
+	})
+
+	t.Run("should return different fingerprints for different clusters", func(t *testing.T) {
+		// when
+		first, err := ClusterFingerprint(fixKubeconfig(testServer, testCA, testToken))
+		require.NoError(t, err)
+		second, err := ClusterFingerprint(fixKubeconfig("https://api.other.example.com", testCA, testToken))
+		require.NoError(t, err)
+
+		// then
+		require.NotEqual(t, first, second)
+	})
+
+	t.Run("should not expose the kubeconfig in the fingerprint", func(t *testing.T) {
+		// given
+		kubeconfig := fixKubeconfig(testServer, testCA, testToken)
+
+		// when
+		fingerprint, err := ClusterFingerprint(kubeconfig)
+
+		// then
+		require.NoError(t, err)
+		require.Regexp(t, "^[0-9a-f]+$", fingerprint)
+		require.Less(t, len(fingerprint), len(kubeconfig))
+		require.NotContains(t, kubeconfig, fingerprint)
+		for _, secret := range []string{testServer, testToken, base64.StdEncoding.EncodeToString([]byte(testCA))} {
+			require.NotContains(t, fingerprint, secret)
+		}
+	})
+
+	t.Run("should return an error for an invalid kubeconfig", func(t *testing.T) {
+		// when
+		_, err := ClusterFingerprint("not a kubeconfig")
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should return an error if the current context does not exist", func(t *testing.T) {
+		// when
+		_, err := ClusterFingerprint(`apiVersion: v1
+kind: Config
+current-context: missing
+`)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "current context 'missing' not found")
+	})
+}
+
+func fixKubeconfig(server, ca, token string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test-cluster
+  cluster:
+    server: %s
+    certificate-authority-data: %s
+contexts:
+- name: test
+  context:
+    cluster: test-cluster
+    user: test-user
+users:
+- name: test-user
+  user:
+    token: %s
+`, server, base64.StdEncoding.EncodeToString([]byte(ca)), token)
+}
@@ -3,6 +3,7 @@ package istio
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/coreos/go-semver/semver"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
@@ -17,6 +18,21 @@ import (
 
 const (
 	istioNamespace = "istio-system"
+
+	// versionOverrideConfigKey pins the target Istio version independent of the Istio chart, e.g. to test an out-of-band istioctl binary.
+	versionOverrideConfigKey = "istio.versionOverride"
+
+	// autoRollbackConfigKey enables the re-installation of the previous Istio version if the update fails.
+	autoRollbackConfigKey = "istio.autoRollback"
+
+	// forceProxyResetConfigKey enables the proxy reset even if all Istio proxies already run the target version.
+	forceProxyResetConfigKey = "istio.forceProxyReset"
+
+	// proxyResetLabelSelectorConfigKey restricts the proxy reset to the pods matching the label selector.
+	proxyResetLabelSelectorConfigKey = "istio.proxyResetLabelSelector"
+
+	// hubConfigKey overrides the registry the Istio images are pulled from, e.g. a private registry of an air-gapped cluster.
+	hubConfigKey = "istio.hub"
 )
 
 type bootstrapIstioPerformer func(logger *zap.SugaredLogger) (actions.IstioPerformer, error)
@@ -88,7 +104,7 @@ func (a *MainReconcileAction) Run(context *service.ActionContext) error {
 	if canInstall(istioStatus) {
 		context.Logger.Info("No Istio version was detected on the cluster, performing installation...")
 
-		err = performer.Install(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, context.Logger)
+		err = performer.Install(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, configuredHub(context), context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not install Istio")
 		}
@@ -97,7 +113,7 @@ func (a *MainReconcileAction) Run(context *service.ActionContext) error {
 		context.Logger.Debugf("Istio version was detected on the cluster, updating pilot from %s and data plane "+
 			"from %s to version %s...", istioStatus.PilotVersion, istioStatus.DataPlaneVersion, istioStatus.TargetVersion)
 
-		err = performer.Update(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, context.Logger)
+		err = performer.Update(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, configuredHub(context), isEnabled(context, autoRollbackConfigKey), context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not update Istio")
 		}
@@ -134,7 +150,7 @@ func (a *MutatingWebhookPostAction) Run(context *service.ActionContext) error {
 	if canUpdateResult, err := canUpdate(istioStatus); canUpdateResult || canInstall(istioStatus) {
 		context.Logger.Debugf("Patching mutating webhook for Istio")
 
-		err = performer.PatchMutatingWebhook(context.Context, context.KubeClient, context.Logger)
+		_, err = performer.PatchMutatingWebhook(context.Context, context.KubeClient, "", context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not patch MutatingWebhookConfiguration")
 		}
@@ -167,7 +183,7 @@ func (a *ProxyResetPostAction) Run(context *service.ActionContext) error {
 	}
 
 	if canUpdateResult, err := canUpdate(istioStatus); canUpdateResult {
-		err = performer.ResetProxy(context.Context, context.KubeClient.Kubeconfig(), istioStatus.TargetVersion, context.Logger)
+		_, err = performer.ResetProxy(context.Context, context.KubeClient.Kubeconfig(), istioStatus.TargetVersion, proxyResetLabelSelector(context), isEnabled(context, forceProxyResetConfigKey), context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not reset istio proxies")
 		}
@@ -267,13 +283,13 @@ func (a *ReconcileIstioConfigurationAction) Run(context *service.ActionContext)
 	if canInstall(istioStatus) {
 		context.Logger.Debug("No Istio version was detected on the cluster, performing installation...")
 
-		err = performer.Install(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, context.Logger)
+		err = performer.Install(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, configuredHub(context), context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not install Istio")
 		}
 
 		context.Logger.Debug("Patching Istio provided mutating webhook")
-		err = performer.PatchMutatingWebhook(context.Context, context.KubeClient, context.Logger)
+		_, err = performer.PatchMutatingWebhook(context.Context, context.KubeClient, "", context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not patch MutatingWebhookConfiguration")
 		}
@@ -285,17 +301,17 @@ func (a *ReconcileIstioConfigurationAction) Run(context *service.ActionContext)
 	} else if canUpdateResult, err := canUpdate(istioStatus); canUpdateResult {
 		context.Logger.Debugf("Istio version was detected on the cluster, updating pilot from %s and data plane from %s to version %s...", istioStatus.PilotVersion, istioStatus.DataPlaneVersion, istioStatus.TargetVersion)
 
-		err = performer.Update(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, context.Logger)
+		err = performer.Update(context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, configuredHub(context), isEnabled(context, autoRollbackConfigKey), context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not update Istio")
 		}
 
-		err = performer.PatchMutatingWebhook(context.Context, context.KubeClient, context.Logger)
+		_, err = performer.PatchMutatingWebhook(context.Context, context.KubeClient, "", context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not patch MutatingWebhookConfiguration")
 		}
 
-		err = performer.ResetProxy(context.Context, context.KubeClient.Kubeconfig(), istioStatus.TargetVersion, context.Logger)
+		_, err = performer.ResetProxy(context.Context, context.KubeClient.Kubeconfig(), istioStatus.TargetVersion, proxyResetLabelSelector(context), isEnabled(context, forceProxyResetConfigKey), context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not reset Istio proxy")
 		}
@@ -357,12 +373,30 @@ func canUninstall(istioStatus actions.IstioStatus) bool {
 	return isInstalled(istioStatus) && istioStatus.ClientVersion != ""
 }
 
+// isEnabled returns true if the configuration value of the key is true, either as bool or as string.
+func isEnabled(context *service.ActionContext, key string) bool {
+	switch value := context.Task.Configuration[key].(type) {
+	case bool:
+		return value
+	case string:
+		enabled, _ := strconv.ParseBool(value)
+		return enabled
+	default:
+		return false
+	}
+}
+
 func getInstalledVersion(context *service.ActionContext, performer actions.IstioPerformer) (actions.IstioStatus, error) {
-	istioStatus, err := performer.Version(context.WorkspaceFactory, context.Task.Version, context.Task.Component, context.KubeClient.Kubeconfig(), context.Logger)
+	versionOverride, _ := context.Task.Configuration[versionOverrideConfigKey].(string)
+	istioStatus, err := performer.Version(context.WorkspaceFactory, context.Task.Version, context.Task.Component, context.KubeClient.Kubeconfig(), versionOverride, context.Logger)
+	if errors.Is(err, actions.ErrIstioNotInstalled) {
+		context.Logger.Debugf("Istio is not installed, istioctl version %s (%s), target Istio version: %s", istioStatus.ClientVersion, istioStatus.BinaryPath, istioStatus.TargetVersion)
+		return istioStatus, nil
+	}
 	if err != nil {
 		return actions.IstioStatus{}, errors.Wrap(err, "Could not fetch Istio version")
 	}
-	context.Logger.Debugf("Detected: istioctl version %s, target Istio version: %s", istioStatus.ClientVersion, istioStatus.TargetVersion)
+	context.Logger.Debugf("Detected: istioctl version %s (%s), target Istio version: %s", istioStatus.ClientVersion, istioStatus.BinaryPath, istioStatus.TargetVersion)
 	return istioStatus, nil
 }
 
@@ -385,6 +419,11 @@ func canUpdate(istioStatus actions.IstioStatus) (bool, error) {
 		return false, err
 	}
 
+	// no data plane to update if no Istio proxy is running yet
+	if !istioStatus.DataPlanePresent && istioStatus.DataPlaneVersion == "" {
+		return true, nil
+	}
+
 	if isDataplaneCompatible, err := isComponentCompatible(istioStatus.DataPlaneVersion, istioStatus.TargetVersion, "Data plane"); !isDataplaneCompatible {
 		return false, err
 	}
@@ -470,3 +509,15 @@ func unDeployIstioRelatedResources(context context.Context, manifest string, cli
 
 	return nil
 }
+
+// proxyResetLabelSelector returns the configured label selector of the proxy reset, or an empty selector matching all pods.
+func proxyResetLabelSelector(context *service.ActionContext) string {
+	labelSelector, _ := context.Task.Configuration[proxyResetLabelSelectorConfigKey].(string)
+	return labelSelector
+}
+
+// configuredHub returns the configured registry of the Istio images, or an empty hub to use the one of the Istio chart.
+func configuredHub(context *service.ActionContext) string {
+	hub, _ := context.Task.Configuration[hubConfigKey].(string)
+	return hub
+}
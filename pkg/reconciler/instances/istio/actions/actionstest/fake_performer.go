@@ -0,0 +1,733 @@
+// Package actionstest provides test doubles for the Istio actions.
+package actionstest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"go.uber.org/zap"
+)
+
+// InstallCall records the parameters of an IstioPerformer.Install or InstallWithOptions call.
+type InstallCall struct {
+	KubeConfig string
+	IstioChart string
+	Version    string
+	Hub        string
+	Overlays   []string
+	DryRun     bool
+}
+
+// InstallFromManifestCall records the parameters of an IstioPerformer.InstallFromManifest call.
+type InstallFromManifestCall struct {
+	Manifest   string
+	KubeConfig string
+	Version    string
+}
+
+// UpdateCall records the parameters of an IstioPerformer.Update or UpdateWithOptions call.
+type UpdateCall struct {
+	KubeConfig    string
+	IstioChart    string
+	TargetVersion string
+	Hub           string
+	AutoRollback  bool
+	Overlays      []string
+	DryRun        bool
+}
+
+// UpdateAlongPathCall records the parameters of an IstioPerformer.UpdateAlongPath call.
+type UpdateAlongPathCall struct {
+	KubeConfig     string
+	IstioChart     string
+	CurrentVersion string
+	TargetVersion  string
+	Hub            string
+}
+
+// UninstallCall records the parameters of an IstioPerformer.Uninstall call.
+type UninstallCall struct {
+	KubeClient kubernetes.Client
+	Version    string
+}
+
+// ResetProxyCall records the parameters of an IstioPerformer.ResetProxy call.
+type ResetProxyCall struct {
+	KubeConfig        string
+	ProxyImageVersion string
+	LabelSelector     string
+	Force             bool
+}
+
+// ResetProxyFromChartCall records the parameters of an IstioPerformer.ResetProxyFromChart call.
+type ResetProxyFromChartCall struct {
+	Branch        string
+	IstioChart    string
+	KubeConfig    string
+	LabelSelector string
+	Force         bool
+}
+
+// ResetProxyFromFileCall records the parameters of an IstioPerformer.ResetProxyFromFile call.
+type ResetProxyFromFileCall struct {
+	KubeConfigPath    string
+	ProxyImageVersion string
+	LabelSelector     string
+	Force             bool
+}
+
+// WaitForReadyCall records the parameters of an IstioPerformer.WaitForReady call.
+type WaitForReadyCall struct {
+	KubeConfig string
+	Timeout    time.Duration
+}
+
+// FakeIstioPerformer is an in-memory actions.IstioPerformer which records calls and returns programmed results.
+// It is safe for concurrent use.
+type FakeIstioPerformer struct {
+	mu sync.Mutex
+
+	status             actions.IstioStatus
+	versionErr         error
+	installErr         error
+	updateErr          error
+	uninstallErr       error
+	resetProxyErr      error
+	resetProxyResult   actions.ProxyResetResult
+	patchErr           error
+	observabilityErr   error
+	waitForReadyErr    error
+	injectionWaitErr   error
+	webhookPatchResult actions.WebhookPatchResult
+	webhookPreview     actions.WebhookPatchPreview
+	injectionStatus    actions.SidecarInjectionStatus
+	injectionLabels    map[string]actions.NamespaceInjectionLabels
+	injectionLabelsErr error
+	staleProxies       actions.StaleProxies
+	orphanedSidecars   actions.StaleProxies
+	sidecarInventory   map[string]actions.NamespaceSidecarStats
+	verification       actions.InstallVerification
+	mtlsMode           string
+	operator           string
+	gateways           []string
+	gatewaysErr        error
+	syncSummary        actions.SyncSummary
+	disruptionEstimate actions.DisruptionEstimate
+	configDump         []byte
+	analysisMessages   []actions.AnalysisMessage
+	preCheckMessages   []actions.PreCheckMessage
+	drift              actions.DriftReport
+	bugReport          string
+	bugReportErr       error
+	kubeconfigErr      error
+
+	installCalls       []InstallCall
+	manifestCalls      []InstallFromManifestCall
+	updateCalls        []UpdateCall
+	updatePathCalls    []UpdateAlongPathCall
+	uninstallCalls     []UninstallCall
+	resetProxyCalls    []ResetProxyCall
+	resetFromFileCalls []ResetProxyFromFileCall
+	resetChartCalls    []ResetProxyFromChartCall
+	patchCalls         int
+	cleanupCalls       int
+	observabilityCalls int
+	waitForReadyCalls  []WaitForReadyCall
+	versionCalls       int
+}
+
+var _ actions.IstioPerformer = &FakeIstioPerformer{}
+
+// NewFakeIstioPerformer creates a new FakeIstioPerformer returning an empty IstioStatus and no errors.
+func NewFakeIstioPerformer() *FakeIstioPerformer {
+	return &FakeIstioPerformer{}
+}
+
+// WithVersion programs the IstioStatus and error returned by Version and VersionDetailed.
+func (f *FakeIstioPerformer) WithVersion(status actions.IstioStatus, err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+	f.versionErr = err
+	return f
+}
+
+// WithInstallError programs the error returned by Install, InstallFromManifest and InstallWithOptions.
+func (f *FakeIstioPerformer) WithInstallError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.installErr = err
+	return f
+}
+
+// WithUpdateError programs the error returned by Update, UpdateWithOptions and UpdateAlongPath.
+func (f *FakeIstioPerformer) WithUpdateError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updateErr = err
+	return f
+}
+
+// WithUninstallError programs the error returned by Uninstall.
+func (f *FakeIstioPerformer) WithUninstallError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.uninstallErr = err
+	return f
+}
+
+// WithResetProxyError programs the error returned by ResetProxy, ResetProxyFromFile and ResetProxyFromChart.
+func (f *FakeIstioPerformer) WithResetProxyError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resetProxyErr = err
+	return f
+}
+
+// WithProxyResetResult programs the ProxyResetResult returned by ResetProxy, ResetProxyFromFile and ResetProxyFromChart.
+func (f *FakeIstioPerformer) WithProxyResetResult(result actions.ProxyResetResult) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resetProxyResult = result
+	return f
+}
+
+// WithPatchMutatingWebhookError programs the error returned by PatchMutatingWebhook.
+func (f *FakeIstioPerformer) WithPatchMutatingWebhookError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patchErr = err
+	return f
+}
+
+// WithApplyObservabilityError programs the error returned by ApplyObservability.
+func (f *FakeIstioPerformer) WithApplyObservabilityError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.observabilityErr = err
+	return f
+}
+
+// WithWaitForReadyError programs the error returned by WaitForReady.
+func (f *FakeIstioPerformer) WithWaitForReadyError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.waitForReadyErr = err
+	return f
+}
+
+// WithWebhookInjectionError programs the error returned by WaitForWebhookInjection.
+func (f *FakeIstioPerformer) WithWebhookInjectionError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.injectionWaitErr = err
+	return f
+}
+
+// WithWebhookPatchResult programs the WebhookPatchResult returned by PatchMutatingWebhook.
+func (f *FakeIstioPerformer) WithWebhookPatchResult(result actions.WebhookPatchResult) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.webhookPatchResult = result
+	return f
+}
+
+// WithWebhookPatchPreview programs the WebhookPatchPreview returned by PreviewMutatingWebhookPatch.
+func (f *FakeIstioPerformer) WithWebhookPatchPreview(preview actions.WebhookPatchPreview) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.webhookPreview = preview
+	return f
+}
+
+// WithSidecarInjectionStatus programs the SidecarInjectionStatus returned by SidecarInjectionStatus.
+func (f *FakeIstioPerformer) WithSidecarInjectionStatus(status actions.SidecarInjectionStatus) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.injectionStatus = status
+	return f
+}
+
+// WithInjectionError programs the error returned by SetInjection.
+func (f *FakeIstioPerformer) WithInjectionError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.injectionLabelsErr = err
+	return f
+}
+
+// WithProxyConfigDump programs the config dump returned by ProxyConfigDump.
+func (f *FakeIstioPerformer) WithProxyConfigDump(configDump []byte) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configDump = configDump
+	return f
+}
+
+// WithAnalysisMessages programs the messages returned by Analyze.
+func (f *FakeIstioPerformer) WithAnalysisMessages(messages []actions.AnalysisMessage) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.analysisMessages = messages
+	return f
+}
+
+// WithPreCheckMessages programs the messages returned by PreCheck.
+func (f *FakeIstioPerformer) WithPreCheckMessages(messages []actions.PreCheckMessage) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.preCheckMessages = messages
+	return f
+}
+
+// WithBugReport programs the archive path and error returned by BugReport.
+func (f *FakeIstioPerformer) WithBugReport(archivePath string, err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bugReport = archivePath
+	f.bugReportErr = err
+	return f
+}
+
+// WithKubeconfigError programs the error returned by ValidateKubeconfig.
+func (f *FakeIstioPerformer) WithKubeconfigError(err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.kubeconfigErr = err
+	return f
+}
+
+// WithVersionDrift programs the DriftReport returned by VersionDrift, the error of WithVersion is returned instead if set.
+func (f *FakeIstioPerformer) WithVersionDrift(drift actions.DriftReport) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drift = drift
+	return f
+}
+
+// WithInstallVerification programs the InstallVerification returned by VerifyInstall.
+func (f *FakeIstioPerformer) WithInstallVerification(verification actions.InstallVerification) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.verification = verification
+	return f
+}
+
+// WithInstalledOperator programs the IstioOperator YAML returned by GetInstalledOperator.
+func (f *FakeIstioPerformer) WithInstalledOperator(operator string) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.operator = operator
+	return f
+}
+
+// WithMTLSMode programs the mode returned by MTLSMode.
+func (f *FakeIstioPerformer) WithMTLSMode(mode string) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mtlsMode = mode
+	return f
+}
+
+// WithRestartedGateways programs the gateways and error returned by RestartGateways.
+func (f *FakeIstioPerformer) WithRestartedGateways(gateways []string, err error) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gateways = gateways
+	f.gatewaysErr = err
+	return f
+}
+
+// WithStaleProxies programs the StaleProxies returned by ListStaleProxies.
+func (f *FakeIstioPerformer) WithStaleProxies(staleProxies actions.StaleProxies) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.staleProxies = staleProxies
+	return f
+}
+
+// WithOrphanedSidecars programs the proxies returned by FindOrphanedSidecars.
+func (f *FakeIstioPerformer) WithOrphanedSidecars(orphaned actions.StaleProxies) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.orphanedSidecars = orphaned
+	return f
+}
+
+// WithSidecarInventory programs the inventory returned by SidecarInventory.
+func (f *FakeIstioPerformer) WithSidecarInventory(inventory map[string]actions.NamespaceSidecarStats) *FakeIstioPerformer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sidecarInventory = inventory
+	return f
+}
+
+func (f *FakeIstioPerformer) Install(kubeConfig, istioChart, version, hub string, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.installCalls = append(f.installCalls, InstallCall{KubeConfig: kubeConfig, IstioChart: istioChart, Version: version, Hub: hub})
+	return f.installErr
+}
+
+func (f *FakeIstioPerformer) InstallFromManifest(istioOperatorManifest, kubeConfig, version string, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.manifestCalls = append(f.manifestCalls, InstallFromManifestCall{Manifest: istioOperatorManifest, KubeConfig: kubeConfig, Version: version})
+	return f.installErr
+}
+
+func (f *FakeIstioPerformer) InstallWithOptions(opts actions.InstallOptions, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.installCalls = append(f.installCalls, InstallCall{KubeConfig: opts.KubeConfig, IstioChart: opts.IstioChart, Version: opts.Version, Hub: opts.Hub, Overlays: opts.Overlays, DryRun: opts.DryRun})
+	return f.installErr
+}
+
+func (f *FakeIstioPerformer) ApplyObservability(_, _ string, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.observabilityCalls++
+	return f.observabilityErr
+}
+
+func (f *FakeIstioPerformer) WaitForReady(_ context.Context, kubeConfig string, timeout time.Duration, _ actions.ReadinessProgressFunc, _ *zap.SugaredLogger) ([]actions.ComponentReadiness, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.waitForReadyCalls = append(f.waitForReadyCalls, WaitForReadyCall{KubeConfig: kubeConfig, Timeout: timeout})
+	return nil, f.waitForReadyErr
+}
+
+func (f *FakeIstioPerformer) WaitForWebhookInjection(_ context.Context, _ kubernetes.Client, _ string, _ time.Duration, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.injectionWaitErr
+}
+
+func (f *FakeIstioPerformer) CleanupResetArtifacts(_ string, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cleanupCalls++
+	return nil
+}
+
+func (f *FakeIstioPerformer) PatchMutatingWebhook(_ context.Context, _ kubernetes.Client, _ string, _ *zap.SugaredLogger) (actions.WebhookPatchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patchCalls++
+	if f.patchErr != nil {
+		return actions.WebhookPatchResult{}, f.patchErr
+	}
+	return f.webhookPatchResult, nil
+}
+
+func (f *FakeIstioPerformer) PreviewMutatingWebhookPatch(_ context.Context, _ kubernetes.Client, _ string, _ *zap.SugaredLogger) (actions.WebhookPatchPreview, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.webhookPreview, nil
+}
+
+func (f *FakeIstioPerformer) SidecarInjectionStatus(_ context.Context, _ kubernetes.Client, _ []string, _ *zap.SugaredLogger) (actions.SidecarInjectionStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.injectionStatus, nil
+}
+
+func (f *FakeIstioPerformer) Update(kubeConfig, istioChart, targetVersion, hub string, autoRollback bool, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updateCalls = append(f.updateCalls, UpdateCall{KubeConfig: kubeConfig, IstioChart: istioChart, TargetVersion: targetVersion, Hub: hub, AutoRollback: autoRollback})
+	return f.updateErr
+}
+
+func (f *FakeIstioPerformer) UpdateWithOptions(opts actions.UpdateOptions, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updateCalls = append(f.updateCalls, UpdateCall{KubeConfig: opts.KubeConfig, IstioChart: opts.IstioChart, TargetVersion: opts.Version, Hub: opts.Hub, AutoRollback: opts.AutoRollback, Overlays: opts.Overlays, DryRun: opts.DryRun})
+	return f.updateErr
+}
+
+func (f *FakeIstioPerformer) UpdateAlongPath(kubeConfig, istioChart, currentVersion, targetVersion, hub string, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updatePathCalls = append(f.updatePathCalls, UpdateAlongPathCall{KubeConfig: kubeConfig, IstioChart: istioChart, CurrentVersion: currentVersion, TargetVersion: targetVersion, Hub: hub})
+	return f.updateErr
+}
+
+func (f *FakeIstioPerformer) ResetProxy(_ context.Context, kubeConfig string, proxyImageVersion string, labelSelector string, force bool, _ *zap.SugaredLogger) (actions.ProxyResetResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resetProxyCalls = append(f.resetProxyCalls, ResetProxyCall{KubeConfig: kubeConfig, ProxyImageVersion: proxyImageVersion, LabelSelector: labelSelector, Force: force})
+	if f.resetProxyErr != nil {
+		return actions.ProxyResetResult{}, f.resetProxyErr
+	}
+	return f.resetProxyResult, nil
+}
+
+func (f *FakeIstioPerformer) ResetProxyFromChart(_ context.Context, _ chart.Factory, branch, istioChart, kubeConfig, labelSelector string, force bool, _ *zap.SugaredLogger) (actions.ProxyResetResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resetChartCalls = append(f.resetChartCalls, ResetProxyFromChartCall{Branch: branch, IstioChart: istioChart, KubeConfig: kubeConfig, LabelSelector: labelSelector, Force: force})
+	if f.resetProxyErr != nil {
+		return actions.ProxyResetResult{}, f.resetProxyErr
+	}
+	return f.resetProxyResult, nil
+}
+
+func (f *FakeIstioPerformer) ResetProxyFromFile(_ context.Context, kubeConfigPath string, proxyImageVersion string, labelSelector string, force bool, _ *zap.SugaredLogger) (actions.ProxyResetResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resetFromFileCalls = append(f.resetFromFileCalls, ResetProxyFromFileCall{KubeConfigPath: kubeConfigPath, ProxyImageVersion: proxyImageVersion, LabelSelector: labelSelector, Force: force})
+	if f.resetProxyErr != nil {
+		return actions.ProxyResetResult{}, f.resetProxyErr
+	}
+	return f.resetProxyResult, nil
+}
+
+func (f *FakeIstioPerformer) Version(_ chart.Factory, _ string, _ string, _ string, _ string, _ *zap.SugaredLogger) (actions.IstioStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.versionCalls++
+	if f.versionErr != nil {
+		return actions.IstioStatus{}, f.versionErr
+	}
+	return f.status, nil
+}
+
+func (f *FakeIstioPerformer) VersionDetailed(_ chart.Factory, _ string, _ string, _ string, _ string, _ *zap.SugaredLogger) (actions.IstioVersionDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.versionErr != nil {
+		return actions.IstioVersionDetails{}, f.versionErr
+	}
+	return actions.IstioVersionDetails{Status: f.status}, nil
+}
+
+func (f *FakeIstioPerformer) VersionDrift(_ chart.Factory, _, _, _ string, _ *zap.SugaredLogger) (actions.DriftReport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.versionErr != nil {
+		return actions.DriftReport{}, f.versionErr
+	}
+	return f.drift, nil
+}
+
+func (f *FakeIstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version string, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.uninstallCalls = append(f.uninstallCalls, UninstallCall{KubeClient: kubeClientSet, Version: version})
+	return f.uninstallErr
+}
+
+func (f *FakeIstioPerformer) VerifyInstall(_, _ string, _ *zap.SugaredLogger) (actions.InstallVerification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.verification, nil
+}
+
+func (f *FakeIstioPerformer) GetInstalledOperator(_ string, _ *zap.SugaredLogger) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.operator, nil
+}
+
+func (f *FakeIstioPerformer) MTLSMode(_ string, _ *zap.SugaredLogger) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mtlsMode, nil
+}
+
+func (f *FakeIstioPerformer) RestartGateways(_ string, _ *zap.SugaredLogger) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.gateways, f.gatewaysErr
+}
+
+func (f *FakeIstioPerformer) ListStaleProxies(_, _ string, _ *zap.SugaredLogger) (actions.StaleProxies, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.staleProxies, nil
+}
+
+func (f *FakeIstioPerformer) FindOrphanedSidecars(_ string, _ *zap.SugaredLogger) (actions.StaleProxies, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.orphanedSidecars, nil
+}
+
+// SetInjection returns the labels of the namespace set by the previous call and remembers the new ones, see InjectionLabels.
+func (f *FakeIstioPerformer) SetInjection(_, namespace string, enabled bool, revision string, _ *zap.SugaredLogger) (actions.NamespaceInjectionLabels, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.injectionLabelsErr != nil {
+		return actions.NamespaceInjectionLabels{}, f.injectionLabelsErr
+	}
+	if f.injectionLabels == nil {
+		f.injectionLabels = map[string]actions.NamespaceInjectionLabels{}
+	}
+	previous := f.injectionLabels[namespace]
+	labels := actions.NamespaceInjectionLabels{Injection: "disabled"}
+	if enabled && revision != "" {
+		labels = actions.NamespaceInjectionLabels{Revision: revision}
+	} else if enabled {
+		labels = actions.NamespaceInjectionLabels{Injection: "enabled"}
+	}
+	f.injectionLabels[namespace] = labels
+	return previous, nil
+}
+
+func (f *FakeIstioPerformer) SidecarInventory(_ string, _ *zap.SugaredLogger) (map[string]actions.NamespaceSidecarStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sidecarInventory, nil
+}
+
+func (f *FakeIstioPerformer) ProxySyncSummary(_ string, _ *zap.SugaredLogger) (actions.SyncSummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.syncSummary, nil
+}
+
+func (f *FakeIstioPerformer) ProxyConfigDump(_, _, _, _ string, _ *zap.SugaredLogger) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.configDump, nil
+}
+
+// ProxyConfigDumpWithCompression returns the programmed config dump uncompressed, regardless of compress.
+func (f *FakeIstioPerformer) ProxyConfigDumpWithCompression(_, _, _, _ string, _ bool, _ *zap.SugaredLogger) (actions.ConfigDump, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return actions.ConfigDump{Data: f.configDump}, nil
+}
+
+func (f *FakeIstioPerformer) Analyze(_, _ string, _ []string, _ *zap.SugaredLogger) ([]actions.AnalysisMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.analysisMessages, nil
+}
+
+func (f *FakeIstioPerformer) PreCheck(_ string, _ *zap.SugaredLogger) ([]actions.PreCheckMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.preCheckMessages, nil
+}
+
+func (f *FakeIstioPerformer) BugReport(_, _ string, _ *zap.SugaredLogger) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bugReport, f.bugReportErr
+}
+
+func (f *FakeIstioPerformer) ValidateKubeconfig(_ string, _ *zap.SugaredLogger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.kubeconfigErr
+}
+
+func (f *FakeIstioPerformer) EstimateDisruption(_, _ string, _ *zap.SugaredLogger) (actions.DisruptionEstimate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.disruptionEstimate, nil
+}
+
+// InstallCalls returns a copy of all recorded Install calls.
+func (f *FakeIstioPerformer) InstallCalls() []InstallCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]InstallCall{}, f.installCalls...)
+}
+
+// InstallFromManifestCalls returns a copy of all recorded InstallFromManifest calls.
+func (f *FakeIstioPerformer) InstallFromManifestCalls() []InstallFromManifestCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]InstallFromManifestCall{}, f.manifestCalls...)
+}
+
+// UpdateCalls returns a copy of all recorded Update calls.
+func (f *FakeIstioPerformer) UpdateCalls() []UpdateCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]UpdateCall{}, f.updateCalls...)
+}
+
+// UpdateAlongPathCalls returns a copy of all recorded UpdateAlongPath calls.
+func (f *FakeIstioPerformer) UpdateAlongPathCalls() []UpdateAlongPathCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]UpdateAlongPathCall{}, f.updatePathCalls...)
+}
+
+// UninstallCalls returns a copy of all recorded Uninstall calls.
+func (f *FakeIstioPerformer) UninstallCalls() []UninstallCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]UninstallCall{}, f.uninstallCalls...)
+}
+
+// ResetProxyCalls returns a copy of all recorded ResetProxy calls.
+func (f *FakeIstioPerformer) ResetProxyCalls() []ResetProxyCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ResetProxyCall{}, f.resetProxyCalls...)
+}
+
+// ResetProxyFromChartCalls returns a copy of all recorded ResetProxyFromChart calls.
+func (f *FakeIstioPerformer) ResetProxyFromChartCalls() []ResetProxyFromChartCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ResetProxyFromChartCall{}, f.resetChartCalls...)
+}
+
+// ResetProxyFromFileCalls returns a copy of all recorded ResetProxyFromFile calls.
+func (f *FakeIstioPerformer) ResetProxyFromFileCalls() []ResetProxyFromFileCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ResetProxyFromFileCall{}, f.resetFromFileCalls...)
+}
+
+// CleanupResetArtifactsCalls returns the number of CleanupResetArtifacts calls.
+func (f *FakeIstioPerformer) CleanupResetArtifactsCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cleanupCalls
+}
+
+// InjectionLabels returns the sidecar injection labels set by SetInjection for the namespace.
+func (f *FakeIstioPerformer) InjectionLabels(namespace string) actions.NamespaceInjectionLabels {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.injectionLabels[namespace]
+}
+
+// PatchMutatingWebhookCalls returns the number of PatchMutatingWebhook calls.
+func (f *FakeIstioPerformer) PatchMutatingWebhookCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.patchCalls
+}
+
+// ApplyObservabilityCalls returns the number of ApplyObservability calls.
+func (f *FakeIstioPerformer) ApplyObservabilityCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.observabilityCalls
+}
+
+// WaitForReadyCalls returns a copy of all recorded WaitForReady calls.
+func (f *FakeIstioPerformer) WaitForReadyCalls() []WaitForReadyCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]WaitForReadyCall{}, f.waitForReadyCalls...)
+}
+
+// VersionCalls returns the number of Version calls.
+func (f *FakeIstioPerformer) VersionCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.versionCalls
+}
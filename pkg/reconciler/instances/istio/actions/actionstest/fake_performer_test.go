@@ -0,0 +1,119 @@
+package actionstest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FakeIstioPerformer(t *testing.T) {
+	log := logger.NewLogger(false)
+
+	t.Run("should return the programmed version status", func(t *testing.T) {
+		// given
+		status := actions.IstioStatus{ClientVersion: "1.11.2", TargetVersion: "1.11.2", PilotVersion: "1.11.1", DataPlaneVersion: "1.11.1"}
+		performer := NewFakeIstioPerformer().WithVersion(status, nil)
+
+		// when
+		got, err := performer.Version(nil, "main", "istio", "kubeconfig", "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, status, got)
+		require.Equal(t, 1, performer.VersionCalls())
+	})
+
+	t.Run("should return the programmed version error", func(t *testing.T) {
+		// given
+		performer := NewFakeIstioPerformer().WithVersion(actions.IstioStatus{ClientVersion: "1.11.2"}, errors.New("version error"))
+
+		// when
+		got, err := performer.Version(nil, "main", "istio", "kubeconfig", "", log)
+
+		// then
+		require.EqualError(t, err, "version error")
+		require.Empty(t, got)
+	})
+
+	t.Run("should record calls and return programmed errors", func(t *testing.T) {
+		// given
+		performer := NewFakeIstioPerformer().
+			WithUpdateError(errors.New("update error")).
+			WithResetProxyError(errors.New("reset error")).
+			WithWaitForReadyError(errors.New("not ready"))
+
+		// when
+		installErr := performer.Install("kubeconfig", "chart", "1.11.2", "registry.local/istio", log)
+		updateErr := performer.Update("kubeconfig", "chart", "1.11.3", "", false, log)
+		_, resetErr := performer.ResetProxy(context.TODO(), "kubeconfig", "1.11.3", "app=payment", false, log)
+		uninstallErr := performer.Uninstall(nil, "1.11.3", log)
+		_, waitErr := performer.WaitForReady(context.TODO(), "kubeconfig", time.Minute, nil, log)
+
+		// then
+		require.NoError(t, installErr)
+		require.EqualError(t, updateErr, "update error")
+		require.EqualError(t, resetErr, "reset error")
+		require.NoError(t, uninstallErr)
+		require.EqualError(t, waitErr, "not ready")
+		require.Equal(t, []InstallCall{{KubeConfig: "kubeconfig", IstioChart: "chart", Version: "1.11.2", Hub: "registry.local/istio"}}, performer.InstallCalls())
+		require.Equal(t, []UpdateCall{{KubeConfig: "kubeconfig", IstioChart: "chart", TargetVersion: "1.11.3"}}, performer.UpdateCalls())
+		require.Equal(t, []ResetProxyCall{{KubeConfig: "kubeconfig", ProxyImageVersion: "1.11.3", LabelSelector: "app=payment"}}, performer.ResetProxyCalls())
+		require.Equal(t, []UninstallCall{{Version: "1.11.3"}}, performer.UninstallCalls())
+		require.Equal(t, []WaitForReadyCall{{KubeConfig: "kubeconfig", Timeout: time.Minute}}, performer.WaitForReadyCalls())
+	})
+
+	t.Run("should record proxy resets from a kubeconfig file and return the programmed result", func(t *testing.T) {
+		// given
+		result := actions.ProxyResetResult{StaleProxies: 2}
+		performer := NewFakeIstioPerformer().WithProxyResetResult(result)
+
+		// when
+		got, err := performer.ResetProxyFromFile(context.TODO(), "/etc/kubeconfig", "1.11.3", "", true, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, result, got)
+		require.Equal(t, []ResetProxyFromFileCall{{KubeConfigPath: "/etc/kubeconfig", ProxyImageVersion: "1.11.3", Force: true}}, performer.ResetProxyFromFileCalls())
+		require.Empty(t, performer.ResetProxyCalls())
+	})
+
+	t.Run("should record proxy resets to the chart version and return the programmed error", func(t *testing.T) {
+		// given
+		performer := NewFakeIstioPerformer().WithResetProxyError(errors.New("reset error"))
+
+		// when
+		_, err := performer.ResetProxyFromChart(context.TODO(), nil, "main", "istio", "kubeconfig", "app=payment", false, log)
+
+		// then
+		require.EqualError(t, err, "reset error")
+		require.Equal(t, []ResetProxyFromChartCall{{Branch: "main", IstioChart: "istio", KubeConfig: "kubeconfig", LabelSelector: "app=payment"}}, performer.ResetProxyFromChartCalls())
+		require.Empty(t, performer.ResetProxyCalls())
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		// given
+		performer := NewFakeIstioPerformer()
+		var wg sync.WaitGroup
+
+		// when
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = performer.Install("kubeconfig", "chart", "1.11.2", "", log)
+				_, _ = performer.Version(nil, "main", "istio", "kubeconfig", "", log)
+			}()
+		}
+		wg.Wait()
+
+		// then
+		require.Len(t, performer.InstallCalls(), 50)
+		require.Equal(t, 50, performer.VersionCalls())
+	})
+}
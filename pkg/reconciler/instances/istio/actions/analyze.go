@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"go.uber.org/zap"
+)
+
+// AnalysisSeverity is the level of an AnalysisMessage.
+type AnalysisSeverity string
+
+const (
+	AnalysisSeverityError   AnalysisSeverity = "Error"
+	AnalysisSeverityWarning AnalysisSeverity = "Warning"
+	AnalysisSeverityInfo    AnalysisSeverity = "Info"
+)
+
+// AnalysisMessage is a misconfiguration of the mesh found by `istioctl analyze`.
+type AnalysisMessage struct {
+	// Code identifies the analyzer message, e.g. IST0101.
+	Code     string           `json:"code"`
+	Severity AnalysisSeverity `json:"level"`
+	// Resource is the affected resource, e.g. "VirtualService default/reviews".
+	Resource         string `json:"origin"`
+	Message          string `json:"message"`
+	DocumentationURL string `json:"documentationUrl,omitempty"`
+}
+
+func (c *DefaultIstioPerformer) Analyze(kubeConfig, version string, namespaces []string, logger *zap.SugaredLogger) ([]AnalysisMessage, error) {
+	logger = operationLogger(logger, "Analyze", version, kubeConfig)
+
+	execVersion, err := istioctl.VersionFromSource(version, "Istio version to analyze with")
+	if err != nil {
+		return nil, err
+	}
+
+	commander, err := c.getCommander(execVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeConfig, logger, err = c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// istioctl analyzes either all namespaces or a single one
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	var messages []AnalysisMessage
+	for _, namespace := range namespaces {
+		analyzeOutput, err := commander.Analyze(kubeConfig, namespace, logger)
+		if err != nil {
+			return nil, istioctlError("analyze", err)
+		}
+		namespaceMessages, err := parseAnalyzeOutput(analyzeOutput)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, namespaceMessages...)
+	}
+
+	counts := map[AnalysisSeverity]int{}
+	for _, message := range messages {
+		counts[message.Severity]++
+	}
+	logger.Debugf("Istio analysis found %d errors, %d warnings and %d infos",
+		counts[AnalysisSeverityError], counts[AnalysisSeverityWarning], counts[AnalysisSeverityInfo])
+
+	return messages, nil
+}
+
+// parseAnalyzeOutput parses the JSON output of `istioctl analyze --output json`.
+func parseAnalyzeOutput(analyzeOutput []byte) ([]AnalysisMessage, error) {
+	var messages []AnalysisMessage
+	if len(bytes.TrimSpace(analyzeOutput)) == 0 {
+		return messages, nil
+	}
+	if err := json.Unmarshal(analyzeOutput, &messages); err != nil {
+		return nil, newParseError("analyze", err)
+	}
+	return messages, nil
+}
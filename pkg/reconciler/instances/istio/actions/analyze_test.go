@@ -0,0 +1,177 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	istioctlMockAnalyzeNoIssues = `[]`
+
+	istioctlMockAnalyzeInfo = `[
+	{
+		"code": "IST0102",
+		"documentationUrl": "https://istio.io/v1.11/docs/reference/config/analysis/ist0102/",
+		"level": "Info",
+		"message": "The namespace is not enabled for Istio injection. Run 'kubectl label namespace default istio-injection=enabled' to enable it, or 'kubectl label namespace default istio-injection=disabled' to explicitly mark it as not needing injection.",
+		"origin": "Namespace default"
+	}
+]`
+
+	istioctlMockAnalyzeMixed = `[
+	{
+		"code": "IST0101",
+		"documentationUrl": "https://istio.io/v1.11/docs/reference/config/analysis/ist0101/",
+		"level": "Error",
+		"message": "Referenced gateway not found: \"bogus-gateway\"",
+		"origin": "VirtualService httpbin/httpbin",
+		"reference": "VirtualService httpbin/httpbin"
+	},
+	{
+		"code": "IST0109",
+		"documentationUrl": "https://istio.io/v1.11/docs/reference/config/analysis/ist0109/",
+		"level": "Warning",
+		"message": "The VirtualServices httpbin/httpbin,httpbin/httpbin-v2 associated with mesh define the same host */httpbin which can lead to undefined behavior.",
+		"origin": "VirtualService httpbin/httpbin-v2"
+	},
+	{
+		"code": "IST0103",
+		"documentationUrl": "https://istio.io/v1.11/docs/reference/config/analysis/ist0103/",
+		"level": "Warning",
+		"message": "The pod is missing the Istio proxy.",
+		"origin": "Pod httpbin/httpbin-74fb669cc6-vlbvz"
+	}
+]`
+)
+
+func Test_parseAnalyzeOutput(t *testing.T) {
+
+	t.Run("should return no messages when no issues were found", func(t *testing.T) {
+		// when
+		messages, err := parseAnalyzeOutput([]byte(istioctlMockAnalyzeNoIssues))
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, messages)
+	})
+
+	t.Run("should return no messages for empty output", func(t *testing.T) {
+		// when
+		messages, err := parseAnalyzeOutput([]byte("\n"))
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, messages)
+	})
+
+	t.Run("should parse info messages", func(t *testing.T) {
+		// when
+		messages, err := parseAnalyzeOutput([]byte(istioctlMockAnalyzeInfo))
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.Equal(t, "IST0102", messages[0].Code)
+		require.Equal(t, AnalysisSeverityInfo, messages[0].Severity)
+		require.Equal(t, "Namespace default", messages[0].Resource)
+		require.Equal(t, "https://istio.io/v1.11/docs/reference/config/analysis/ist0102/", messages[0].DocumentationURL)
+	})
+
+	t.Run("should parse messages of different severity levels", func(t *testing.T) {
+		// when
+		messages, err := parseAnalyzeOutput([]byte(istioctlMockAnalyzeMixed))
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, messages, 3)
+		require.Equal(t, AnalysisMessage{
+			Code:             "IST0101",
+			Severity:         AnalysisSeverityError,
+			Resource:         "VirtualService httpbin/httpbin",
+			Message:          `Referenced gateway not found: "bogus-gateway"`,
+			DocumentationURL: "https://istio.io/v1.11/docs/reference/config/analysis/ist0101/",
+		}, messages[0])
+		require.Equal(t, AnalysisSeverityWarning, messages[1].Severity)
+		require.Equal(t, "Pod httpbin/httpbin-74fb669cc6-vlbvz", messages[2].Resource)
+	})
+
+	t.Run("should return an error when the output is not JSON", func(t *testing.T) {
+		// when
+		_, err := parseAnalyzeOutput([]byte("Error: failed to fetch resources"))
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not parse istioctl analyze output")
+	})
+}
+
+func Test_DefaultIstioPerformer_Analyze(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should not proceed if the version could not be parsed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		_, err := wrapper.Analyze(kubeConfig, "abc", nil, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not parse Istio version to analyze with")
+		cmder.AssertNotCalled(t, "Analyze", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should analyze all namespaces when no namespaces are given", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Analyze", kubeConfig, "", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockAnalyzeMixed), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		messages, err := wrapper.Analyze(kubeConfig, "1.2.3", nil, log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, messages, 3)
+		cmder.AssertNumberOfCalls(t, "Analyze", 1)
+	})
+
+	t.Run("should analyze each given namespace and combine the messages", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Analyze", kubeConfig, "default", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockAnalyzeInfo), nil)
+		cmder.On("Analyze", kubeConfig, "httpbin", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockAnalyzeMixed), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		messages, err := wrapper.Analyze(kubeConfig, "1.2.3", []string{"default", "httpbin"}, log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, messages, 4)
+		require.Equal(t, "IST0102", messages[0].Code)
+		require.Equal(t, "IST0101", messages[1].Code)
+	})
+
+	t.Run("should return an error when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Analyze", kubeConfig, "default", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		_, err := wrapper.Analyze(kubeConfig, "1.2.3", []string{"default"}, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+	})
+}
@@ -0,0 +1,48 @@
+package actions
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func (c *DefaultIstioPerformer) BugReport(kubeConfig, outputDir string, logger *zap.SugaredLogger) (string, error) {
+	if outputDir == "" {
+		return "", errors.New("Output directory of the bug report must not be empty")
+	}
+
+	execVersion, err := c.resolveVersion(latestIstioctlConstraint)
+	if err != nil {
+		return "", err
+	}
+
+	logger = operationLogger(logger, "BugReport", execVersion.String(), kubeConfig)
+	logger.Debug("Collecting Istio bug report...")
+
+	commander, err := c.getCommander(execVersion)
+	if err != nil {
+		return "", err
+	}
+
+	kubeConfig, logger, err = c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return "", errors.Wrapf(err, "Could not create output directory %s of the bug report", outputDir)
+	}
+
+	ctx, cancel := c.istioctlContextFrom(context.Background(), IstioctlBugReport)
+	defer cancel()
+
+	archivePath, err := commander.BugReport(ctx, kubeConfig, outputDir, logger)
+	if err != nil {
+		return "", istioctlError("bug-report", err)
+	}
+
+	logger.Infof("Istio bug report collected at %s", archivePath)
+	return archivePath, nil
+}
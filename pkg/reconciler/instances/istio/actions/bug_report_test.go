@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DefaultIstioPerformer_BugReport(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should collect the bug report with the newest istioctl into the created output directory", func(t *testing.T) {
+		// given
+		tempDir, err := ioutil.TempDir("", "bug-report")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+		outputDir := filepath.Join(tempDir, "reports")
+		archivePath := filepath.Join(outputDir, "bug-report.tar.gz")
+		cmder := istioctlmocks.Commander{}
+		cmder.On("BugReport", mock.Anything, kubeConfig, outputDir, mock.AnythingOfType("*zap.SugaredLogger")).Return(archivePath, nil)
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil)
+
+		// when
+		got, err := wrapper.BugReport(kubeConfig, outputDir, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, archivePath, got)
+		require.Equal(t, []string{"1.17.3"}, resolver.versions)
+		require.DirExists(t, outputDir)
+	})
+
+	t.Run("should return an error when istioctl failed", func(t *testing.T) {
+		// given
+		outputDir, err := ioutil.TempDir("", "bug-report")
+		require.NoError(t, err)
+		defer os.RemoveAll(outputDir)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("BugReport", mock.Anything, kubeConfig, outputDir, mock.AnythingOfType("*zap.SugaredLogger")).Return("", errors.New("istioctl failed"))
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil)
+
+		// when
+		got, err := wrapper.BugReport(kubeConfig, outputDir, log)
+
+		// then
+		require.Error(t, err)
+		require.Empty(t, got)
+		require.Contains(t, err.Error(), "istioctl failed")
+	})
+
+	t.Run("should bound istioctl by the bug-report timeout", func(t *testing.T) {
+		// given
+		outputDir, err := ioutil.TempDir("", "bug-report")
+		require.NoError(t, err)
+		defer os.RemoveAll(outputDir)
+		cmder := istioctlmocks.Commander{}
+		var deadline time.Time
+		cmder.On("BugReport", mock.Anything, kubeConfig, outputDir, mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(args mock.Arguments) { deadline, _ = args.Get(0).(context.Context).Deadline() }).
+			Return(filepath.Join(outputDir, "bug-report.tar.gz"), nil)
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil, WithIstioctlTimeout(IstioctlBugReport, time.Minute))
+
+		// when
+		start := time.Now()
+		_, err = wrapper.BugReport(kubeConfig, outputDir, log)
+
+		// then
+		require.NoError(t, err)
+		require.WithinDuration(t, start.Add(time.Minute), deadline, 10*time.Second)
+	})
+
+	t.Run("should not run istioctl without an output directory", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		resolver := &recordingCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil)
+
+		// when
+		_, err := wrapper.BugReport(kubeConfig, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must not be empty")
+		cmder.AssertNotCalled(t, "BugReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,116 @@
+package actions
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	helmChart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+const (
+	chartCacheHit  = "hit"
+	chartCacheMiss = "miss"
+)
+
+// chartCache keeps the loaded Helm charts by their directory, so unchanged charts are not parsed again on every reconciliation.
+// A cached chart is reused as long as the fingerprint of its directory, built from the paths, sizes and modification times of its files, is unchanged.
+type chartCache struct {
+	mu      sync.Mutex
+	entries map[string]chartCacheEntry
+	lookups *prometheus.CounterVec
+}
+
+type chartCacheEntry struct {
+	fingerprint [sha256.Size]byte
+	chart       *helmChart.Chart
+}
+
+// defaultChartCache is shared by all DefaultIstioPerformer instances, as a new performer is created for every reconciliation.
+var defaultChartCache = newChartCache()
+
+func newChartCache() *chartCache {
+	return &chartCache{
+		entries: map[string]chartCacheEntry{},
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "reconciler",
+			Name:      "istio_chart_cache_lookups_total",
+			Help:      "Lookups of the Istio chart cache by result, which is either hit or miss",
+		}, []string{"result"}),
+	}
+}
+
+// ClearChartCache removes all Istio charts from the cache, so they are loaded from the filesystem again.
+func ClearChartCache() {
+	defaultChartCache.clear()
+}
+
+// ChartCacheCollector returns the collector of the Istio chart cache lookups, labeled by hit or miss, to be registered by the caller.
+func ChartCacheCollector() prometheus.Collector {
+	return defaultChartCache.lookups
+}
+
+// load returns the chart in the directory, from the cache if none of its files changed since it was loaded.
+// The returned chart is shared and must not be modified.
+func (c *chartCache) load(chartDir string) (*helmChart.Chart, error) {
+	fingerprint, err := chartFingerprint(chartDir)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	entry, found := c.entries[chartDir]
+	c.mu.Unlock()
+	if found && entry.fingerprint == fingerprint {
+		c.lookups.WithLabelValues(chartCacheHit).Inc()
+		return entry.chart, nil
+	}
+	c.lookups.WithLabelValues(chartCacheMiss).Inc()
+
+	loaded, err := loader.Load(chartDir)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[chartDir] = chartCacheEntry{fingerprint: fingerprint, chart: loaded}
+	c.mu.Unlock()
+	return loaded, nil
+}
+
+func (c *chartCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]chartCacheEntry{}
+}
+
+// chartFingerprint hashes the relative paths, sizes and modification times of all files in the chart directory.
+func chartFingerprint(chartDir string) ([sha256.Size]byte, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(chartDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(chartDir, path)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(hash, "%s\x00%d\x00%d\x00%s\n", relPath, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		return err
+	})
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	var fingerprint [sha256.Size]byte
+	copy(fingerprint[:], hash.Sum(nil))
+	return fingerprint, nil
+}
@@ -0,0 +1,92 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestChart(t *testing.T, dir, appVersion string) {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("apiVersion: v2\nname: istio-configuration-test\nversion: 1.0.0\nappVersion: "+appVersion+"\n"), 0600))
+}
+
+func chartCacheLookups(cache *chartCache, result string) float64 {
+	return testutil.ToFloat64(cache.lookups.WithLabelValues(result))
+}
+
+func Test_chartCache(t *testing.T) {
+
+	t.Run("should return the cached chart when the chart directory is unchanged", func(t *testing.T) {
+		// given
+		cache := newChartCache()
+
+		// when
+		first, err := cache.load("../test_files/istio-pilot-image-tag")
+		require.NoError(t, err)
+		second, err := cache.load("../test_files/istio-pilot-image-tag")
+		require.NoError(t, err)
+
+		// then
+		require.Same(t, first, second)
+		require.Equal(t, "1.2.3", second.Metadata.AppVersion)
+		require.Equal(t, float64(1), chartCacheLookups(cache, chartCacheHit))
+		require.Equal(t, float64(1), chartCacheLookups(cache, chartCacheMiss))
+	})
+
+	t.Run("should load the chart again when a file of the chart directory changed", func(t *testing.T) {
+		// given
+		cache := newChartCache()
+		dir, err := ioutil.TempDir("", "istio-chart")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		writeTestChart(t, dir, "1.11.1")
+		first, err := cache.load(dir)
+		require.NoError(t, err)
+
+		// when
+		writeTestChart(t, dir, "1.11.2")
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "Chart.yaml"), later, later))
+		second, err := cache.load(dir)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.1", first.Metadata.AppVersion)
+		require.Equal(t, "1.11.2", second.Metadata.AppVersion)
+		require.Equal(t, float64(0), chartCacheLookups(cache, chartCacheHit))
+		require.Equal(t, float64(2), chartCacheLookups(cache, chartCacheMiss))
+	})
+
+	t.Run("should load the chart again after the cache was cleared", func(t *testing.T) {
+		// given
+		cache := newChartCache()
+		first, err := cache.load("../test_files/istio-pilot-image-tag")
+		require.NoError(t, err)
+
+		// when
+		cache.clear()
+		second, err := cache.load("../test_files/istio-pilot-image-tag")
+
+		// then
+		require.NoError(t, err)
+		require.NotSame(t, first, second)
+		require.Equal(t, float64(2), chartCacheLookups(cache, chartCacheMiss))
+	})
+
+	t.Run("should return an error when the chart directory does not exist", func(t *testing.T) {
+		// given
+		cache := newChartCache()
+
+		// when
+		_, err := cache.load("../test_files/not-existing")
+
+		// then
+		require.Error(t, err)
+		require.Empty(t, cache.entries)
+	})
+}
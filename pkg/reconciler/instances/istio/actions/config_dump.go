@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ConfigDump is an Envoy config dump returned by ProxyConfigDumpWithCompression.
+type ConfigDump struct {
+	// Data is the raw JSON config dump, or the gzip-compressed JSON if Compressed is true.
+	Data []byte
+	// Compressed is true if Data is gzip-compressed.
+	Compressed bool
+}
+
+// JSON returns the raw JSON config dump, decompressing Data if it is compressed.
+func (d ConfigDump) JSON() ([]byte, error) {
+	if !d.Compressed {
+		return d.Data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(d.Data))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not decompress config dump")
+	}
+	defer reader.Close()
+	configDump, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not decompress config dump")
+	}
+	return configDump, nil
+}
+
+func (c *DefaultIstioPerformer) ProxyConfigDumpWithCompression(kubeConfig, version, namespace, pod string, compress bool, logger *zap.SugaredLogger) (ConfigDump, error) {
+	configDump, err := c.ProxyConfigDump(kubeConfig, version, namespace, pod, logger)
+	if err != nil {
+		return ConfigDump{}, err
+	}
+	if !compress {
+		return ConfigDump{Data: configDump}, nil
+	}
+
+	compressed, err := gzipCompress(configDump)
+	if err != nil {
+		return ConfigDump{}, errors.Wrapf(err, "Could not compress config dump of pod %s/%s", namespace, pod)
+	}
+	logger.Debugf("Compressed config dump of pod %s/%s from %d to %d bytes", namespace, pod, len(configDump), len(compressed))
+	return ConfigDump{Data: compressed, Compressed: true}, nil
+}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DefaultIstioPerformer_ProxyConfigDumpWithCompression(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	configDump := []byte(`{"configs":[{"@type":"type.googleapis.com/envoy.admin.v3.BootstrapConfigDump","bootstrap":{"node":{"id":"sidecar~10.0.0.1~httpbin.default~default.svc.cluster.local"}}}]}`)
+
+	t.Run("should return the gzip-compressed config dump which decompresses to the raw JSON", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyConfigDump", kubeConfig, "default", "httpbin", mock.AnythingOfType("*zap.SugaredLogger")).Return(configDump, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		got, err := wrapper.ProxyConfigDumpWithCompression(kubeConfig, "1.2.3", "default", "httpbin", true, log)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, got.Compressed)
+		require.NotEqual(t, configDump, got.Data)
+		decompressed, err := got.JSON()
+		require.NoError(t, err)
+		require.Equal(t, configDump, decompressed)
+	})
+
+	t.Run("should return the raw config dump when compression is disabled", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyConfigDump", kubeConfig, "default", "httpbin", mock.AnythingOfType("*zap.SugaredLogger")).Return(configDump, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		got, err := wrapper.ProxyConfigDumpWithCompression(kubeConfig, "1.2.3", "default", "httpbin", false, log)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, got.Compressed)
+		require.Equal(t, configDump, got.Data)
+		raw, err := got.JSON()
+		require.NoError(t, err)
+		require.Equal(t, configDump, raw)
+	})
+
+	t.Run("should return an error when the config dump could not be collected", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyConfigDump", kubeConfig, "default", "httpbin", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		got, err := wrapper.ProxyConfigDumpWithCompression(kubeConfig, "1.2.3", "default", "httpbin", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+		require.Empty(t, got.Data)
+	})
+}
+
+func Test_ConfigDump_JSON(t *testing.T) {
+
+	t.Run("should return an error when compressed data is not gzip", func(t *testing.T) {
+		// when
+		_, err := ConfigDump{Data: []byte("{}"), Compressed: true}.JSON()
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not decompress config dump")
+	})
+}
@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+// purgedCRDGroups lists the API groups whose CRDs are removed by Uninstall if WithCRDPurge is set.
+// Only exact matches are purged, e.g. CRDs of a custom group like example.istio.io are kept.
+var purgedCRDGroups = map[string]bool{
+	"install.istio.io":    true,
+	"networking.istio.io": true,
+	"security.istio.io":   true,
+	"telemetry.istio.io":  true,
+	"extensions.istio.io": true,
+}
+
+const (
+	// crdCleanupFinalizer is set by the API server to delete the custom resources of a CRD before the CRD itself.
+	crdCleanupFinalizer = "customresourcecleanup.apiextensions.k8s.io"
+
+	defaultCRDPurgeGracePeriod = 30 * time.Second
+	defaultCRDPurgeInterval    = 2 * time.Second
+)
+
+// purgeIstioCRDs deletes the CRDs of the purgedCRDGroups. The cleanup finalizer of a CRD is only cleared if the CRD
+// is not gone after the grace period, so its custom resources are deleted by the API server whenever possible.
+func (c *DefaultIstioPerformer) purgeIstioCRDs(ctx context.Context, kubeConfig string, logger *zap.SugaredLogger) error {
+	dynamicClient, err := c.dynamicProvider.RetrieveDynamicFrom(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	crds, err := dynamicClient.Resource(crdResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not list CustomResourceDefinitions")
+	}
+
+	purged := 0
+	for _, crd := range crds.Items {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		if !purgedCRDGroups[group] {
+			continue
+		}
+		if err := c.purgeCRD(ctx, dynamicClient, crd.GetName(), logger); err != nil {
+			return err
+		}
+		purged++
+	}
+	logger.Infof("Purged %d Istio CustomResourceDefinitions", purged)
+	return nil
+}
+
+func (c *DefaultIstioPerformer) purgeCRD(ctx context.Context, dynamicClient dynamic.Interface, name string, logger *zap.SugaredLogger) error {
+	err := dynamicClient.Resource(crdResource).Delete(ctx, name, metav1.DeleteOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Could not delete CustomResourceDefinition %s", name)
+	}
+
+	interval := c.crdPurgeInterval
+	if interval <= 0 {
+		interval = defaultCRDPurgeInterval
+	}
+	var crd *unstructured.Unstructured
+	err = wait.PollImmediate(interval, c.crdPurgeGracePeriod, func() (bool, error) {
+		current, err := dynamicClient.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			logger.Debugf("Could not get CustomResourceDefinition %s: %s", name, err)
+			return false, nil
+		}
+		crd = current
+		return false, nil
+	})
+	if err == nil {
+		logger.Debugf("CustomResourceDefinition %s deleted", name)
+		return nil
+	}
+	if err != wait.ErrWaitTimeout {
+		return err
+	}
+	if crd == nil {
+		return errors.Errorf("CustomResourceDefinition %s could not be checked for its deletion within %s", name, c.crdPurgeGracePeriod)
+	}
+
+	// the cleanup of the custom resources is stuck, e.g. because their finalizers are handled by the uninstalled Istio
+	var remaining []string
+	for _, finalizer := range crd.GetFinalizers() {
+		if finalizer != crdCleanupFinalizer {
+			remaining = append(remaining, finalizer)
+		}
+	}
+	if len(remaining) == len(crd.GetFinalizers()) {
+		logger.Warnf("CustomResourceDefinition %s is not deleted after %s, its finalizers %v are kept", name, c.crdPurgeGracePeriod, crd.GetFinalizers())
+		return nil
+	}
+	logger.Debugf("CustomResourceDefinition %s is not deleted after %s, clearing its %s finalizer", name, c.crdPurgeGracePeriod, crdCleanupFinalizer)
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      remaining,
+			"resourceVersion": crd.GetResourceVersion(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = dynamicClient.Resource(crdResource).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Could not clear the %s finalizer of CustomResourceDefinition %s", crdCleanupFinalizer, name)
+	}
+	return nil
+}
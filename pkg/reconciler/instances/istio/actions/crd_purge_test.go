@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_DefaultIstioPerformer_Uninstall_PurgeCRDs(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should delete the Istio CRDs without clearing their finalizers and keep other CRDs", func(t *testing.T) {
+		// given
+		dynamicClient := fixVerifyDynamicClient(
+			fixPurgeCRD("gateways.networking.istio.io", "networking.istio.io", "customresourcecleanup.apiextensions.k8s.io"),
+			fixPurgeCRD("istiooperators.install.istio.io", "install.istio.io"),
+			fixPurgeCRD("foos.example.istio.io", "example.istio.io"),
+			fixPurgeCRD("certificates.cert-manager.io", "cert-manager.io", "example.com/cleanup"),
+		)
+		wrapper := fixPurgePerformer(dynamicClient, WithCRDPurge())
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(fixIstioNamespaceClientset()), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		crds, listErr := dynamicClient.Resource(crdResource).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, listErr)
+		var remaining []string
+		for _, crd := range crds.Items {
+			remaining = append(remaining, crd.GetName())
+		}
+		require.ElementsMatch(t, []string{"foos.example.istio.io", "certificates.cert-manager.io"}, remaining)
+		require.Empty(t, purgePatches(dynamicClient))
+	})
+
+	t.Run("should only clear the cleanup finalizer of a CRD which is not deleted within the grace period", func(t *testing.T) {
+		// given
+		dynamicClient := fixVerifyDynamicClient(
+			fixPurgeCRD("gateways.networking.istio.io", "networking.istio.io", "customresourcecleanup.apiextensions.k8s.io", "example.com/other"),
+		)
+		dynamicClient.PrependReactor("delete", "customresourcedefinitions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			// the API server keeps the CRD until its finalizers are cleared
+			return true, nil, nil
+		})
+		wrapper := fixPurgePerformer(dynamicClient, WithCRDPurge(), WithCRDPurgeGracePeriod(50*time.Millisecond, 10*time.Millisecond))
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(fixIstioNamespaceClientset()), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		patches := purgePatches(dynamicClient)
+		require.Len(t, patches, 1)
+		require.Equal(t, "gateways.networking.istio.io", patches[0].GetName())
+		patched := map[string]map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(patches[0].GetPatch(), &patched))
+		require.Equal(t, []interface{}{"example.com/other"}, patched["metadata"]["finalizers"])
+	})
+
+	t.Run("should keep the finalizers of a CRD which is not deleted within the grace period if it has no cleanup finalizer", func(t *testing.T) {
+		// given
+		dynamicClient := fixVerifyDynamicClient(
+			fixPurgeCRD("gateways.networking.istio.io", "networking.istio.io", "example.com/other"),
+		)
+		dynamicClient.PrependReactor("delete", "customresourcedefinitions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, nil
+		})
+		wrapper := fixPurgePerformer(dynamicClient, WithCRDPurge(), WithCRDPurgeGracePeriod(50*time.Millisecond, 10*time.Millisecond))
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(fixIstioNamespaceClientset()), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, purgePatches(dynamicClient))
+	})
+
+	t.Run("should not touch the CRDs if the purge is not enabled", func(t *testing.T) {
+		// given
+		dynamicProvider := clientsetmocks.DynamicProvider{}
+		wrapper := NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithDynamicProvider(&dynamicProvider))
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(fixIstioNamespaceClientset()), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		dynamicProvider.AssertNotCalled(t, "RetrieveDynamicFrom", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error if the CRDs could not be listed", func(t *testing.T) {
+		// given
+		dynamicClient := fixVerifyDynamicClient()
+		dynamicClient.PrependReactor("list", "customresourcedefinitions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+		wrapper := fixPurgePerformer(dynamicClient, WithCRDPurge())
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(fixIstioNamespaceClientset()), "1.2.3", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not list CustomResourceDefinitions")
+	})
+}
+
+func purgePatches(dynamicClient *dynamicfake.FakeDynamicClient) []k8stesting.PatchAction {
+	var patches []k8stesting.PatchAction
+	for _, action := range dynamicClient.Actions() {
+		if patch, ok := action.(k8stesting.PatchAction); ok {
+			patches = append(patches, patch)
+		}
+	}
+	return patches
+}
+
+func fixPurgePerformer(dynamicClient *dynamicfake.FakeDynamicClient, opts ...PerformerOption) *DefaultIstioPerformer {
+	dynamicProvider := clientsetmocks.DynamicProvider{}
+	dynamicProvider.On("RetrieveDynamicFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(dynamicClient, nil)
+	opts = append(opts, WithDynamicProvider(&dynamicProvider))
+	return NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, opts...)
+}
+
+func fixPurgeCRD(name, group string, finalizers ...string) runtime.Object {
+	crd := fixVerifyCRDs(name)[0].(*unstructured.Unstructured)
+	crd.Object["spec"] = map[string]interface{}{"group": group}
+	crd.SetFinalizers(finalizers)
+	return crd
+}
+
+func fixIstioNamespaceClientset() *fake.Clientset {
+	return fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}})
+}
@@ -0,0 +1,51 @@
+package actions
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// DisruptionEstimate describes the impact of a proxy reset, computed without performing it.
+type DisruptionEstimate struct {
+	// Pods which would be restarted.
+	Pods int
+
+	// Workloads owning the pods which would be restarted. Pods without an owner are counted as separate workloads.
+	Workloads int
+
+	// Namespaces containing the pods which would be restarted.
+	Namespaces int
+}
+
+type workloadKey struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+func estimateDisruptionFrom(pods v1.PodList) DisruptionEstimate {
+	uniquePods := make(map[workloadKey]bool)
+	workloads := make(map[workloadKey]bool)
+	namespaces := make(map[string]bool)
+
+	for _, pod := range pods.Items {
+		podKey := workloadKey{namespace: pod.Namespace, kind: "Pod", name: pod.Name}
+		if uniquePods[podKey] {
+			continue
+		}
+		uniquePods[podKey] = true
+		namespaces[pod.Namespace] = true
+
+		if len(pod.OwnerReferences) == 0 {
+			workloads[podKey] = true
+			continue
+		}
+		owner := pod.OwnerReferences[0]
+		workloads[workloadKey{namespace: pod.Namespace, kind: owner.Kind, name: owner.Name}] = true
+	}
+
+	return DisruptionEstimate{
+		Pods:       len(uniquePods),
+		Workloads:  len(workloads),
+		Namespaces: len(namespaces),
+	}
+}
@@ -0,0 +1,45 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_estimateDisruptionFrom(t *testing.T) {
+
+	t.Run("should return an empty estimate for no pods", func(t *testing.T) {
+		// when
+		estimate := estimateDisruptionFrom(v1.PodList{})
+
+		// then
+		require.Equal(t, DisruptionEstimate{}, estimate)
+	})
+
+	t.Run("should count pods, workloads and namespaces", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPodOwnedBy("app-1", "default", "ReplicaSet", "app-rs"),
+			fixPodOwnedBy("app-2", "default", "ReplicaSet", "app-rs"),
+			fixPodOwnedBy("app-2", "default", "ReplicaSet", "app-rs"),
+			fixPodOwnedBy("db-0", "default", "StatefulSet", "db"),
+			fixPodOwnedBy("standalone", "kyma-system", "", ""),
+		}}
+
+		// when
+		estimate := estimateDisruptionFrom(pods)
+
+		// then
+		require.Equal(t, DisruptionEstimate{Pods: 4, Workloads: 3, Namespaces: 2}, estimate)
+	})
+}
+
+func fixPodOwnedBy(name, namespace, ownerKind, ownerName string) v1.Pod {
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName}}
+	}
+	return pod
+}
@@ -0,0 +1,117 @@
+package actions
+
+import (
+	"github.com/coreos/go-semver/semver"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// VersionMatchPolicy decides which version differences between a plane and the target version count as drift.
+type VersionMatchPolicy string
+
+const (
+	// VersionMatchExact counts every difference as drift, including patch and pre-release versions.
+	VersionMatchExact VersionMatchPolicy = "exact"
+	// VersionMatchMinorOnly counts only major and minor version differences as drift, so patch updates are not enforced.
+	VersionMatchMinorOnly VersionMatchPolicy = "minor"
+	// VersionMatchMajorOnly counts only major version differences as drift.
+	VersionMatchMajorOnly VersionMatchPolicy = "major"
+)
+
+// matches returns true if the version does not drift from the target version under the policy.
+func (p VersionMatchPolicy) matches(version, target semver.Version) (bool, error) {
+	switch p {
+	case VersionMatchExact:
+		return version.Equal(target), nil
+	case VersionMatchMinorOnly:
+		return version.Major == target.Major && version.Minor == target.Minor, nil
+	case VersionMatchMajorOnly:
+		return version.Major == target.Major, nil
+	default:
+		return false, errors.Errorf("Unknown version match policy %q", p)
+	}
+}
+
+// WithVersionMatchPolicy sets which version differences VersionDrift reports as out of date, separately for the control plane and the data plane.
+// Both default to VersionMatchExact.
+func WithVersionMatchPolicy(controlPlane, dataPlane VersionMatchPolicy) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.controlPlaneMatchPolicy = controlPlane
+		c.dataPlaneMatchPolicy = dataPlane
+	}
+}
+
+// DriftReport tells how far the Istio planes on the cluster are from the target version of the Istio chart.
+type DriftReport struct {
+	TargetVersion string
+	ControlPlane  PlaneDrift
+	DataPlane     PlaneDrift
+}
+
+// PlaneDrift is the version drift of the control plane or the data plane.
+type PlaneDrift struct {
+	Version string
+	// OutOfDate is true if the plane does not run the target version according to the VersionMatchPolicy of the plane.
+	// A data plane which is present but of unknown version is out of date.
+	OutOfDate bool
+	// MinorVersionsBehind is the number of minor versions the plane is behind the target version, negative if the plane is ahead of it.
+	// It is zero if the plane runs another major version than the target version.
+	MinorVersionsBehind int64
+}
+
+// UpToDate returns true if neither the control plane nor the data plane is out of date, so no update and no proxy reset is needed.
+func (r DriftReport) UpToDate() bool {
+	return !r.ControlPlane.OutOfDate && !r.DataPlane.OutOfDate
+}
+
+// VersionDrift compares the target version of the Istio chart against the versions of the control plane and the data plane on the cluster.
+// Which differences count as out of date is decided by the VersionMatchPolicy of each plane, the data plane has no drift if no Istio proxy is running.
+func (c *DefaultIstioPerformer) VersionDrift(workspace chart.Factory, branch, istioChart, kubeConfig string, logger *zap.SugaredLogger) (DriftReport, error) {
+	status, err := c.Version(workspace, branch, istioChart, kubeConfig, "", logger)
+	if err != nil {
+		return DriftReport{TargetVersion: status.TargetVersion}, err
+	}
+
+	report := DriftReport{TargetVersion: status.TargetVersion}
+	report.ControlPlane, err = planeDrift(status.PilotVersion, status.TargetVersion, c.controlPlaneMatchPolicy)
+	if err != nil {
+		return report, errors.Wrap(err, "Could not compute control plane drift")
+	}
+
+	switch {
+	case !status.DataPlanePresent && status.DataPlaneVersion == "":
+		// no data plane to drift if no Istio proxy is running yet
+	case status.DataPlaneVersion == "":
+		report.DataPlane = PlaneDrift{OutOfDate: true}
+	default:
+		report.DataPlane, err = planeDrift(status.DataPlaneVersion, status.TargetVersion, c.dataPlaneMatchPolicy)
+		if err != nil {
+			return report, errors.Wrap(err, "Could not compute data plane drift")
+		}
+	}
+
+	logger.Debugf("Istio version drift to target version %s: control plane %+v, data plane %+v", report.TargetVersion, report.ControlPlane, report.DataPlane)
+	return report, nil
+}
+
+func planeDrift(planeVersion, targetVersion string, policy VersionMatchPolicy) (PlaneDrift, error) {
+	plane, err := semver.NewVersion(planeVersion)
+	if err != nil {
+		return PlaneDrift{}, errors.Wrapf(err, "Invalid version %q", planeVersion)
+	}
+	target, err := semver.NewVersion(targetVersion)
+	if err != nil {
+		return PlaneDrift{}, errors.Wrapf(err, "Invalid target version %q", targetVersion)
+	}
+	matches, err := policy.matches(*plane, *target)
+	if err != nil {
+		return PlaneDrift{}, err
+	}
+
+	drift := PlaneDrift{Version: planeVersion, OutOfDate: !matches}
+	if plane.Major == target.Major {
+		drift.MinorVersionsBehind = target.Minor - plane.Minor
+	}
+	return drift, nil
+}
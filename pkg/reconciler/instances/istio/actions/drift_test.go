@@ -0,0 +1,185 @@
+package actions
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DefaultIstioPerformer_VersionDrift(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should report no drift when both planes run the target version", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(fixVersionOutput("1.2.3", "1.2.3"))
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, DriftReport{
+			TargetVersion: "1.2.3",
+			ControlPlane:  PlaneDrift{Version: "1.2.3"},
+			DataPlane:     PlaneDrift{Version: "1.2.3"},
+		}, report)
+		require.True(t, report.UpToDate())
+	})
+
+	t.Run("should report the minor versions the control plane is behind", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(fixVersionOutput("1.1.7", "1.2.3"))
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, PlaneDrift{Version: "1.1.7", OutOfDate: true, MinorVersionsBehind: 1}, report.ControlPlane)
+		require.Equal(t, PlaneDrift{Version: "1.2.3"}, report.DataPlane)
+		require.False(t, report.UpToDate())
+	})
+
+	t.Run("should report the data plane drift, including patch versions", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(fixVersionOutput("1.2.3", "1.0.4"))
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, PlaneDrift{Version: "1.2.3"}, report.ControlPlane)
+		require.Equal(t, PlaneDrift{Version: "1.0.4", OutOfDate: true, MinorVersionsBehind: 2}, report.DataPlane)
+	})
+
+	t.Run("should report a patch drift without minor versions behind and planes ahead of the target as negative", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(fixVersionOutput("1.2.1", "1.3.0"))
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, PlaneDrift{Version: "1.2.1", OutOfDate: true}, report.ControlPlane)
+		require.Equal(t, PlaneDrift{Version: "1.3.0", OutOfDate: true, MinorVersionsBehind: -1}, report.DataPlane)
+	})
+
+	t.Run("should report no data plane drift when no Istio proxy is running", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(fixVersionOutput("1.2.3", ""))
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, PlaneDrift{}, report.DataPlane)
+		require.True(t, report.UpToDate())
+	})
+
+	t.Run("should return ErrIstioNotInstalled with the target version when there is no control plane", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(`{"clientVersion": {"version": "1.2.3"}}`)
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.True(t, errors.Is(err, ErrIstioNotInstalled))
+		require.Equal(t, DriftReport{TargetVersion: "1.2.3"}, report)
+	})
+
+	t.Run("should report a major version drift as out of date", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(fixVersionOutput("2.2.3", "1.2.3"), WithVersionMatchPolicy(VersionMatchMajorOnly, VersionMatchMajorOnly))
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, PlaneDrift{Version: "2.2.3", OutOfDate: true}, report.ControlPlane)
+		require.Equal(t, PlaneDrift{Version: "1.2.3"}, report.DataPlane)
+	})
+
+	t.Run("should apply the version match policy of each plane", func(t *testing.T) {
+		// given
+		wrapper, factory := fixDriftPerformer(fixVersionOutput("1.2.1", "1.2.1"), WithVersionMatchPolicy(VersionMatchExact, VersionMatchMinorOnly))
+
+		// when
+		report, err := wrapper.VersionDrift(factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, PlaneDrift{Version: "1.2.1", OutOfDate: true}, report.ControlPlane)
+		require.Equal(t, PlaneDrift{Version: "1.2.1"}, report.DataPlane)
+	})
+}
+
+func Test_planeDrift(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		planeVersion  string
+		policy        VersionMatchPolicy
+		wantOutOfDate bool
+		wantErr       string
+	}{
+		{name: "exact policy accepts the same version", planeVersion: "1.12.3", policy: VersionMatchExact},
+		{name: "exact policy rejects a patch difference", planeVersion: "1.12.1", policy: VersionMatchExact, wantOutOfDate: true},
+		{name: "exact policy rejects a pre-release", planeVersion: "1.12.3-rc.1", policy: VersionMatchExact, wantOutOfDate: true},
+		{name: "minor policy accepts a patch difference", planeVersion: "1.12.1", policy: VersionMatchMinorOnly},
+		{name: "minor policy accepts a newer patch", planeVersion: "1.12.5", policy: VersionMatchMinorOnly},
+		{name: "minor policy rejects a minor difference", planeVersion: "1.11.3", policy: VersionMatchMinorOnly, wantOutOfDate: true},
+		{name: "major policy accepts a minor difference", planeVersion: "1.11.3", policy: VersionMatchMajorOnly},
+		{name: "major policy accepts a patch difference", planeVersion: "1.12.1", policy: VersionMatchMajorOnly},
+		{name: "exact policy rejects a major difference", planeVersion: "2.12.3", policy: VersionMatchExact, wantOutOfDate: true},
+		{name: "minor policy rejects a major difference", planeVersion: "2.12.3", policy: VersionMatchMinorOnly, wantOutOfDate: true},
+		{name: "major policy rejects a major difference", planeVersion: "0.12.3", policy: VersionMatchMajorOnly, wantOutOfDate: true},
+		{name: "unknown policy is an error", planeVersion: "1.12.3", policy: "patch", wantErr: `Unknown version match policy "patch"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			drift, err := planeDrift(tt.planeVersion, "1.12.3", tt.policy)
+
+			// then
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOutOfDate, drift.OutOfDate)
+			require.Equal(t, tt.planeVersion, drift.Version)
+		})
+	}
+}
+
+func fixDriftPerformer(versionOutput string, opts ...PerformerOption) (*DefaultIstioPerformer, chart.Factory) {
+	factory := &workspacemocks.Factory{}
+	factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+	cmder := istioctlmocks.Commander{}
+	cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(versionOutput), nil)
+	return NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil, opts...), factory
+}
+
+// fixVersionOutput returns the `istioctl version` output for the pilot and data plane versions, without data plane if dataPlaneVersion is empty.
+func fixVersionOutput(pilotVersion, dataPlaneVersion string) string {
+	dataPlane := ""
+	if dataPlaneVersion != "" {
+		dataPlane = fmt.Sprintf(`, "dataPlaneVersion": [{"ID": "id", "IstioVersion": %q}]`, dataPlaneVersion)
+	}
+	return fmt.Sprintf(`{"clientVersion": {"version": "1.2.3"}, "meshVersion": [{"Component": "pilot", "Info": {"version": %q}}]%s}`, pilotVersion, dataPlane)
+}
@@ -0,0 +1,42 @@
+package actions
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/pkg/errors"
+)
+
+// ExecWrapperCommanderResolver is a CommanderResolver running the istioctl commands of the base resolver through an exec wrapper,
+// e.g. `ssh bastion istioctl` for clusters only reachable via a bastion host. See istioctl.DefaultCommander.WithExecWrapper.
+type ExecWrapperCommanderResolver struct {
+	base    CommanderResolver
+	wrapper []string
+}
+
+// NewExecWrapperCommanderResolver returns a CommanderResolver routing the commands of the base resolver through the wrapper command.
+func NewExecWrapperCommanderResolver(base CommanderResolver, wrapper []string) *ExecWrapperCommanderResolver {
+	return &ExecWrapperCommanderResolver{base: base, wrapper: append([]string{}, wrapper...)}
+}
+
+// GetCommander returns a copy of the commander of the base resolver running through the exec wrapper.
+// Returns an error if the commander of the base resolver does not execute istioctl itself.
+func (r *ExecWrapperCommanderResolver) GetCommander(version istioctl.Version) (istioctl.Commander, error) {
+	commander, err := r.base.GetCommander(version)
+	if err != nil {
+		return nil, err
+	}
+	defaultCommander, ok := commander.(*istioctl.DefaultCommander)
+	if !ok {
+		return nil, errors.Errorf("Commander %T for istioctl version %s can not run through an exec wrapper", commander, version)
+	}
+
+	wrapped := *defaultCommander
+	return wrapped.WithExecWrapper(r.wrapper), nil
+}
+
+func (r *ExecWrapperCommanderResolver) IsVersionSupported(version string) bool {
+	return r.base.IsVersionSupported(version)
+}
+
+func (r *ExecWrapperCommanderResolver) ResolveVersion(constraint string) (istioctl.Version, error) {
+	return r.base.ResolveVersion(constraint)
+}
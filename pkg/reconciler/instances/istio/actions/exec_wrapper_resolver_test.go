@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExecWrapperCommanderResolver_GetCommander(t *testing.T) {
+
+	version := istioctl.Version{}
+	wrapper := []string{"ssh", "bastion", istioctl.ExecWrapperIstioctlPlaceholder}
+
+	t.Run("should return a copy of the base commander running through the wrapper", func(t *testing.T) {
+		// given
+		base := &istioctl.DefaultCommander{}
+		resolver := NewExecWrapperCommanderResolver(TestCommanderResolver{cmder: base}, wrapper)
+
+		// when
+		commander, err := resolver.GetCommander(version)
+
+		// then
+		require.NoError(t, err)
+		wrapped, ok := commander.(*istioctl.DefaultCommander)
+		require.True(t, ok)
+		require.NotSame(t, base, wrapped)
+		require.Equal(t, wrapper, wrapped.ExecWrapper())
+		require.Empty(t, base.ExecWrapper())
+	})
+
+	t.Run("should return an error if the base commander does not execute istioctl itself", func(t *testing.T) {
+		// given
+		resolver := NewExecWrapperCommanderResolver(TestCommanderResolver{cmder: &istioctlmocks.Commander{}}, wrapper)
+
+		// when
+		commander, err := resolver.GetCommander(version)
+
+		// then
+		require.Nil(t, commander)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can not run through an exec wrapper")
+	})
+
+	t.Run("should return the error of the base resolver", func(t *testing.T) {
+		// given
+		resolver := NewExecWrapperCommanderResolver(TestCommanderResolver{err: errors.New("no istioctl")}, wrapper)
+
+		// when
+		commander, err := resolver.GetCommander(version)
+
+		// then
+		require.Nil(t, commander)
+		require.EqualError(t, err, "no istioctl")
+	})
+}
+
+func Test_ExecWrapperCommanderResolver_ResolveVersion(t *testing.T) {
+
+	t.Run("should delegate to the base resolver", func(t *testing.T) {
+		// given
+		resolver := NewExecWrapperCommanderResolver(TestCommanderResolver{}, []string{"ssh"})
+
+		// when
+		version, err := resolver.ResolveVersion("1.2.3")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.2.3", version.String())
+		require.True(t, resolver.IsVersionSupported("1.2.3"))
+	})
+}
@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgo "k8s.io/client-go/kubernetes"
+)
+
+// gatewayDeploymentNames lists the gateway deployments restarted by RestartGateways, in restart order.
+var gatewayDeploymentNames = []string{ingressGatewayDeploymentName, egressGatewayDeploymentName}
+
+// RestartGateways restarts the gateway deployments one after another like "kubectl rollout restart", so that their rolling update strategy
+// keeps serving connections. Each rollout must complete within the readiness timeout before the next gateway is restarted.
+// Gateways which are not installed are skipped. The restarted gateways are also returned together with an error.
+func (c *DefaultIstioPerformer) RestartGateways(kubeConfig string, logger *zap.SugaredLogger) ([]string, error) {
+	logger = operationLogger(logger, "RestartGateways", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := c.clusterLocks.lock(kubeConfig)
+	defer unlock()
+
+	logger.Debug("Starting restart of the Istio gateways...")
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	var restarted []string
+	for _, name := range gatewayDeploymentNames {
+		_, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("Gateway %s is not installed, skipping the restart", name)
+			continue
+		}
+		if err != nil {
+			return restarted, errors.Wrapf(err, "Could not get gateway %s", name)
+		}
+
+		logger.Infof("Restarting gateway %s", name)
+		if err := c.restartDeployment(kubeClient, name); err != nil {
+			return restarted, errors.Wrapf(err, "Could not restart gateway %s", name)
+		}
+		restarted = append(restarted, name)
+
+		if err := c.waitForRollout(kubeClient, name, logger); err != nil {
+			return restarted, err
+		}
+		logger.Infof("Gateway %s restarted", name)
+	}
+
+	logger.Infof("Istio gateways restarted: %d", len(restarted))
+	return restarted, nil
+}
+
+func (c *DefaultIstioPerformer) restartDeployment(kubeClient clientgo.Interface, name string) error {
+	data := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, time.Now().Format(time.RFC3339))
+	_, err := kubeClient.AppsV1().Deployments(c.namespace).Patch(context.Background(), name, types.StrategicMergePatchType, []byte(data), metav1.PatchOptions{})
+	return err
+}
+
+func (c *DefaultIstioPerformer) waitForRollout(kubeClient clientgo.Interface, name string, logger *zap.SugaredLogger) error {
+	err := wait.PollImmediate(c.readinessInterval, c.readinessTimeout, func() (bool, error) {
+		deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			logger.Debugf("Could not get %s deployment: %s", name, err)
+			return false, nil
+		}
+		return isRolloutComplete(deployment), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("Rollout of gateway %s not completed within %s", name, c.readinessTimeout)
+	}
+	return err
+}
+
+// isRolloutComplete returns true if all replicas are updated and available and no old replicas are left, like "kubectl rollout status".
+func isRolloutComplete(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
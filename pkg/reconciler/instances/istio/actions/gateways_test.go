@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_DefaultIstioPerformer_RestartGateways(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should restart the gateways one after another once each rollout is complete", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			fixGatewayDeployment(ingressGatewayDeploymentName, true),
+			fixGatewayDeployment(egressGatewayDeploymentName, true),
+		)
+		pendingGets := 0
+		kubeClient.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			name := action.(k8stesting.GetAction).GetName()
+			if name == ingressGatewayDeploymentName && pendingGets > 0 {
+				pendingGets--
+				return true, fixGatewayDeployment(name, false), nil
+			}
+			return false, nil, nil
+		})
+		kubeClient.PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.(k8stesting.PatchAction).GetName() == ingressGatewayDeploymentName {
+				pendingGets = 2
+			}
+			return false, nil, nil
+		})
+		var calls []string
+		kubeClient.PrependReactor("*", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if namedAction, ok := action.(interface{ GetName() string }); ok {
+				calls = append(calls, action.GetVerb()+" "+namedAction.GetName())
+			}
+			return false, nil, nil
+		})
+		wrapper := fixGatewaysPerformer(kubeClient, time.Second)
+
+		// when
+		restarted, err := wrapper.RestartGateways(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{ingressGatewayDeploymentName, egressGatewayDeploymentName}, restarted)
+		require.Equal(t, []string{
+			"get istio-ingressgateway",
+			"patch istio-ingressgateway",
+			"get istio-ingressgateway",
+			"get istio-ingressgateway",
+			"get istio-ingressgateway",
+			"get istio-egressgateway",
+			"patch istio-egressgateway",
+			"get istio-egressgateway",
+		}, calls)
+		ingressGateway, err := kubeClient.AppsV1().Deployments("istio-system").Get(context.Background(), ingressGatewayDeploymentName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Contains(t, ingressGateway.Spec.Template.Annotations, "kubectl.kubernetes.io/restartedAt")
+	})
+
+	t.Run("should skip gateways which are not installed", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(fixGatewayDeployment(ingressGatewayDeploymentName, true))
+		wrapper := fixGatewaysPerformer(kubeClient, time.Second)
+
+		// when
+		restarted, err := wrapper.RestartGateways(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{ingressGatewayDeploymentName}, restarted)
+	})
+
+	t.Run("should not restart the next gateway when a rollout is not completed within the readiness timeout", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			fixGatewayDeployment(ingressGatewayDeploymentName, false),
+			fixGatewayDeployment(egressGatewayDeploymentName, true),
+		)
+		wrapper := fixGatewaysPerformer(kubeClient, 50*time.Millisecond)
+
+		// when
+		restarted, err := wrapper.RestartGateways(kubeConfig, log)
+
+		// then
+		require.EqualError(t, err, "Rollout of gateway istio-ingressgateway not completed within 50ms")
+		require.Equal(t, []string{ingressGatewayDeploymentName}, restarted)
+		egressGateway, err := kubeClient.AppsV1().Deployments("istio-system").Get(context.Background(), egressGatewayDeploymentName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, egressGateway.Spec.Template.Annotations, "kubectl.kubernetes.io/restartedAt")
+	})
+
+	t.Run("should return an error when the kube client could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("kubeconfig error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		restarted, err := wrapper.RestartGateways(kubeConfig, log)
+
+		// then
+		require.EqualError(t, err, "kubeconfig error")
+		require.Empty(t, restarted)
+	})
+}
+
+func fixGatewaysPerformer(kubeClient *fake.Clientset, readinessTimeout time.Duration) *DefaultIstioPerformer {
+	provider := clientsetmocks.Provider{}
+	provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+	return NewDefaultIstioPerformer(nil, nil, &provider, WithReadinessTimeout(readinessTimeout, 10*time.Millisecond))
+}
+
+func fixGatewayDeployment(name string, rolledOut bool) *appsv1.Deployment {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           replicas,
+			UpdatedReplicas:    replicas,
+			AvailableReplicas:  replicas,
+		},
+	}
+	if !rolledOut {
+		deployment.Status.Replicas = replicas + 1
+		deployment.Status.UpdatedReplicas = 1
+	}
+	return deployment
+}
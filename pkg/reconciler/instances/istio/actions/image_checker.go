@@ -0,0 +1,200 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultRegistry = "registry-1.docker.io"
+
+	manifestMediaTypes = "application/vnd.docker.distribution.manifest.list.v2+json," +
+		"application/vnd.docker.distribution.manifest.v2+json," +
+		"application/vnd.oci.image.index.v1+json," +
+		"application/vnd.oci.image.manifest.v1+json"
+)
+
+// ImageChecker verifies that container images can be pulled.
+type ImageChecker interface {
+	// CheckPullable returns an error if the image can not be pulled.
+	CheckPullable(ctx context.Context, image string) error
+}
+
+// RegistryImageChecker checks the pullability of images by requesting their manifest from the registry.
+// Only anonymous pulls are supported, images of private registries are reported as not pullable.
+type RegistryImageChecker struct {
+	client *http.Client
+	scheme string
+}
+
+// NewRegistryImageChecker creates a new instance of RegistryImageChecker.
+func NewRegistryImageChecker(client *http.Client) *RegistryImageChecker {
+	return &RegistryImageChecker{
+		client: client,
+		scheme: "https",
+	}
+}
+
+func (r *RegistryImageChecker) CheckPullable(ctx context.Context, image string) error {
+	registry, repository, reference := parseImageReference(image)
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", r.scheme, registry, repository, reference)
+
+	resp, err := r.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := r.anonymousToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return errors.Wrapf(err, "Could not authorize to registry %s", registry)
+		}
+		resp, err = r.headManifest(ctx, manifestURL, token)
+		if err != nil {
+			return err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Manifest of image %s could not be fetched from registry %s: %s", image, registry, resp.Status)
+	}
+	return nil
+}
+
+func (r *RegistryImageChecker) headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestMediaTypes)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not request manifest %s", manifestURL)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// anonymousToken requests an anonymous pull token from the token service given in the Bearer challenge.
+func (r *RegistryImageChecker) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	params := parseBearerChallenge(challenge)
+	realm, ok := params["realm"]
+	if !ok {
+		return "", errors.Errorf("Unsupported authentication challenge: %s", challenge)
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Token request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// parseBearerChallenge parses the parameters of a `Bearer realm="...",service="...",scope="..."` WWW-Authenticate header.
+func parseBearerChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return params
+	}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return params
+}
+
+// parseImageReference splits the image into registry, repository and tag or digest, applying the Docker Hub defaults.
+func parseImageReference(image string) (registry, repository, reference string) {
+	registry = defaultRegistry
+	repository = image
+	if i := strings.Index(image, "/"); i >= 0 {
+		if host := image[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			registry = host
+			repository = image[i+1:]
+		}
+	}
+
+	reference = "latest"
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository, reference = repository[:i], repository[i+1:]
+	} else if i := strings.LastIndex(repository, ":"); i >= 0 {
+		repository, reference = repository[:i], repository[i+1:]
+	}
+
+	if registry == defaultRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return registry, repository, reference
+}
+
+// proxyImagesFor returns the Istio proxy images of the pods with their tag replaced by the given one.
+func proxyImagesFor(pods v1.PodList, tag string) []string {
+	var images []string
+	seen := map[string]bool{}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if !strings.Contains(container.Image, istioImagePrefix) {
+				continue
+			}
+			image := fmt.Sprintf("%s:%s", imageRepository(container.Image), tag)
+			if !seen[image] {
+				seen[image] = true
+				images = append(images, image)
+			}
+		}
+	}
+	return images
+}
+
+// imageRepository returns the image without its tag or digest.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
@@ -0,0 +1,137 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func Test_RegistryImageChecker_CheckPullable(t *testing.T) {
+
+	t.Run("should not return an error when the manifest of the image exists", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodHead, r.Method)
+			require.Equal(t, "/v2/istio/proxyv2/manifests/1.11.4-distroless", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		checker := &RegistryImageChecker{client: server.Client(), scheme: "http"}
+
+		// when
+		err := checker.CheckPullable(context.Background(), registryHost(server)+"/istio/proxyv2:1.11.4-distroless")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should return an error when the manifest of the image does not exist", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+		checker := &RegistryImageChecker{client: server.Client(), scheme: "http"}
+
+		// when
+		err := checker.CheckPullable(context.Background(), registryHost(server)+"/istio/proxyv2:0.0.0")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "404 Not Found")
+	})
+
+	t.Run("should authorize with an anonymous token when the registry requests it", func(t *testing.T) {
+		// given
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/token":
+				require.Equal(t, "repository:istio/proxyv2:pull", r.URL.Query().Get("scope"))
+				fmt.Fprint(w, `{"token": "anonymous"}`)
+			case r.Header.Get("Authorization") == "Bearer anonymous":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:istio/proxyv2:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		defer server.Close()
+		checker := &RegistryImageChecker{client: server.Client(), scheme: "http"}
+
+		// when
+		err := checker.CheckPullable(context.Background(), registryHost(server)+"/istio/proxyv2:1.11.4")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should return an error when the registry does not grant an anonymous pull", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+		checker := &RegistryImageChecker{client: server.Client(), scheme: "http"}
+
+		// when
+		err := checker.CheckPullable(context.Background(), registryHost(server)+"/private/proxyv2:1.11.4")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not authorize to registry")
+	})
+}
+
+func Test_parseImageReference(t *testing.T) {
+	tests := []struct {
+		image      string
+		registry   string
+		repository string
+		reference  string
+	}{
+		{image: "eu.gcr.io/kyma-project/external/istio/proxyv2:1.11.4-distroless", registry: "eu.gcr.io", repository: "kyma-project/external/istio/proxyv2", reference: "1.11.4-distroless"},
+		{image: "istio/proxyv2:1.11.4", registry: "registry-1.docker.io", repository: "istio/proxyv2", reference: "1.11.4"},
+		{image: "nginx", registry: "registry-1.docker.io", repository: "library/nginx", reference: "latest"},
+		{image: "localhost:5000/istio/proxyv2@sha256:abc", registry: "localhost:5000", repository: "istio/proxyv2", reference: "sha256:abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			// when
+			registry, repository, reference := parseImageReference(tt.image)
+
+			// then
+			require.Equal(t, tt.registry, registry)
+			require.Equal(t, tt.repository, repository)
+			require.Equal(t, tt.reference, reference)
+		})
+	}
+}
+
+func Test_proxyImagesFor(t *testing.T) {
+
+	t.Run("should return the distinct proxy images with the target tag", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			*fixRunningPodWithProxy("app-1", "default", "1.10.2-distroless", "ReplicaSet", "app"),
+			*fixRunningPodWithProxy("app-2", "default", "1.10.1-distroless", "ReplicaSet", "app"),
+			{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "nginx:1.21"}}}},
+		}}
+
+		// when
+		images := proxyImagesFor(pods, "1.11.4-distroless")
+
+		// then
+		require.Equal(t, []string{"eu.gcr.io/kyma-project/external/istio/proxyv2:1.11.4-distroless"}, images)
+	})
+}
+
+func registryHost(server *httptest.Server) string {
+	return strings.TrimPrefix(server.URL, "http://")
+}
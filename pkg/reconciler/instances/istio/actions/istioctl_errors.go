@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/pkg/errors"
+)
+
+// ExitError is returned when istioctl exited with a non-zero exit code, e.g. because the cluster rejected the change.
+type ExitError struct {
+	// Command is the istioctl sub-command which failed, e.g. "install".
+	Command string
+	// ExitCode of the istioctl process.
+	ExitCode int
+
+	err error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("Error occurred when calling istioctl: %s", e.err)
+}
+
+// Unwrap returns the istioctl.CommandError.
+func (e *ExitError) Unwrap() error {
+	return e.err
+}
+
+// TimeoutError is returned when istioctl was aborted because it did not complete within the timeout of the operation.
+// Retrying it with the same timeout might fail again, e.g. on a slow cluster.
+type TimeoutError struct {
+	// Command is the istioctl sub-command which timed out, e.g. "install".
+	Command string
+
+	err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("Timeout occurred when calling istioctl %s: %s", e.Command, e.err)
+}
+
+// Unwrap returns the error of the aborted istioctl call, which wraps context.DeadlineExceeded.
+func (e *TimeoutError) Unwrap() error {
+	return e.err
+}
+
+// ParseError is returned when istioctl succeeded but its output could not be parsed.
+// Retrying does not help, as the same output of the command would not be understood either.
+type ParseError struct {
+	// Command is the istioctl sub-command whose output could not be parsed, e.g. "version".
+	Command string
+
+	err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Could not parse istioctl %s output: %s", e.Command, e.err)
+}
+
+// Unwrap returns the error of the parser.
+func (e *ParseError) Unwrap() error {
+	return e.err
+}
+
+// istioctlError classifies the error of an istioctl call into an ExitError or a TimeoutError.
+// Other errors, e.g. istioctl could not be started, are wrapped without classification.
+func istioctlError(command string, err error) error {
+	if cmdErr, ok := istioctl.AsCommandError(err); ok {
+		return &ExitError{Command: cmdErr.Command, ExitCode: cmdErr.ExitCode, err: err}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Command: command, err: err}
+	}
+	return errors.Wrap(err, "Error occurred when calling istioctl")
+}
+
+func newParseError(command string, err error) error {
+	return &ParseError{Command: command, err: err}
+}
@@ -0,0 +1,109 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_istioctlError(t *testing.T) {
+
+	t.Run("should classify a non-zero exit code as ExitError", func(t *testing.T) {
+		// when
+		err := istioctlError("install", errors.Wrap(&istioctl.CommandError{Command: "install", ExitCode: 2}, "rendered IstioOperator yaml was: "))
+
+		// then
+		var exitErr *ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, "install", exitErr.Command)
+		require.Equal(t, 2, exitErr.ExitCode)
+		require.Contains(t, err.Error(), "Error occurred when calling istioctl: ")
+	})
+
+	t.Run("should classify an exceeded deadline as TimeoutError", func(t *testing.T) {
+		// when
+		err := istioctlError("upgrade", errors.Wrap(context.DeadlineExceeded, "istioctl upgrade was aborted"))
+
+		// then
+		var timeoutErr *TimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.Equal(t, "upgrade", timeoutErr.Command)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Contains(t, err.Error(), "Timeout occurred when calling istioctl upgrade")
+	})
+
+	t.Run("should not classify other errors", func(t *testing.T) {
+		// when
+		err := istioctlError("install", errors.New("executable file not found"))
+
+		// then
+		var exitErr *ExitError
+		var timeoutErr *TimeoutError
+		require.False(t, errors.As(err, &exitErr))
+		require.False(t, errors.As(err, &timeoutErr))
+		require.Equal(t, "Error occurred when calling istioctl: executable file not found", err.Error())
+	})
+}
+
+func Test_DefaultIstioPerformer_Version_ErrorClassification(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	target := versionTargets(1)[0]
+
+	version := func(output []byte, err error) error {
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", target.KubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(output, err)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+		_, err = wrapper.Version(target.Workspace, target.BranchVersion, target.IstioChart, target.KubeConfig, target.VersionOverride, log)
+		return err
+	}
+
+	t.Run("should return ExitError when istioctl exited with an error", func(t *testing.T) {
+		// when
+		err := version(nil, &istioctl.CommandError{Command: "version", ExitCode: 1})
+
+		// then
+		var exitErr *ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, 1, exitErr.ExitCode)
+	})
+
+	t.Run("should return TimeoutError when istioctl was aborted by the timeout", func(t *testing.T) {
+		// when
+		err := version(nil, errors.Wrap(context.DeadlineExceeded, "istioctl version was aborted"))
+
+		// then
+		var timeoutErr *TimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.Equal(t, "version", timeoutErr.Command)
+	})
+
+	t.Run("should return ParseError when the output of istioctl could not be parsed", func(t *testing.T) {
+		// when
+		err := version([]byte("{not json"), nil)
+
+		// then
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, "version", parseErr.Command)
+		require.Contains(t, err.Error(), "Could not parse istioctl version output")
+	})
+
+	t.Run("should return ParseError when the output of istioctl contains no JSON", func(t *testing.T) {
+		// when
+		err := version([]byte("Error: no running Istio pods in \"istio-system\""), nil)
+
+		// then
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Contains(t, err.Error(), "the result of the version command contains no JSON")
+	})
+}
@@ -0,0 +1,77 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// defaultValidationTimeout bounds the version request of ValidateKubeconfig, so an API server which accepts connections but does not answer fails fast as well.
+const defaultValidationTimeout = 30 * time.Second
+
+// WithValidationTimeout sets the time ValidateKubeconfig waits for the API server to answer. A zero timeout waits until the request returned.
+func WithValidationTimeout(timeout time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.validationTimeout = timeout
+	}
+}
+
+// ValidateKubeconfig checks that the kubeconfig can be parsed and that the API server of its current context answers a version request.
+func (c *DefaultIstioPerformer) ValidateKubeconfig(kubeConfig string, logger *zap.SugaredLogger) error {
+	if strings.TrimSpace(kubeConfig) == "" {
+		return errors.New("Kubeconfig must not be empty")
+	}
+
+	logger = operationLogger(logger, "ValidateKubeconfig", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	if _, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfig)); err != nil {
+		return errors.Wrap(err, "Kubeconfig is malformed")
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		return errors.Wrap(err, "Could not create kubernetes client from kubeconfig")
+	}
+
+	ctx, cancel := contextWithTimeout(context.Background(), c.validationTimeout)
+	defer cancel()
+
+	serverVersion, err := serverVersionContext(ctx, kubeClient.Discovery())
+	if err != nil {
+		return errors.Wrap(err, "API server of the kubeconfig is not reachable")
+	}
+
+	logger.Debugf("API server of the kubeconfig is reachable and runs kubernetes %s", serverVersion.GitVersion)
+	return nil
+}
+
+// serverVersionContext requests the version of the API server like ServerVersion of the discovery client, aborting the request when ctx is done.
+// Discovery clients without a REST client, e.g. fakes, are asked by ServerVersion.
+func serverVersionContext(ctx context.Context, client discovery.DiscoveryInterface) (*version.Info, error) {
+	restClient := client.RESTClient()
+	if restClient == nil {
+		return client.ServerVersion()
+	}
+
+	body, err := restClient.Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, errors.Wrap(err, "Could not parse the version of the API server")
+	}
+	return &info, nil
+}
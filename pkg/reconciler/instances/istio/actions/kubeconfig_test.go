@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testKubeconfig points to a port nothing listens on, so connections to its API server are refused right away.
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: context
+  context:
+    cluster: cluster
+    user: user
+current-context: context
+users:
+- name: user
+  user:
+    token: token
+`
+
+func Test_DefaultIstioPerformer_ValidateKubeconfig(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should accept a kubeconfig of a reachable API server", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", testKubeconfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		err := wrapper.ValidateKubeconfig(testKubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should return an error for an empty kubeconfig", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		err := wrapper.ValidateKubeconfig(" ", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeconfig must not be empty")
+		provider.AssertNotCalled(t, "RetrieveFrom", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error for a malformed kubeconfig", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		err := wrapper.ValidateKubeconfig("clusters: [malformed", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeconfig is malformed")
+		provider.AssertNotCalled(t, "RetrieveFrom", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when the client could not be created", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", testKubeconfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("provider error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		err := wrapper.ValidateKubeconfig(testKubeconfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "provider error")
+	})
+
+	t.Run("should return an error for a kubeconfig of an unreachable API server", func(t *testing.T) {
+		// given
+		wrapper := NewDefaultIstioPerformer(nil, nil, &clientset.DefaultProvider{})
+
+		// when
+		err := wrapper.ValidateKubeconfig(testKubeconfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "API server of the kubeconfig is not reachable")
+	})
+	t.Run("should return an error when the API server does not answer within the validation timeout", func(t *testing.T) {
+		// given
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+		}))
+		defer server.Close()
+		defer close(done)
+		kubeConfig := strings.Replace(testKubeconfig, "https://127.0.0.1:1", server.URL, 1)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &clientset.DefaultProvider{}, WithValidationTimeout(100*time.Millisecond))
+
+		// when
+		start := time.Now()
+		err := wrapper.ValidateKubeconfig(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "API server of the kubeconfig is not reachable")
+		require.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	})
+}
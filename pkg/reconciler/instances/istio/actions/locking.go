@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"crypto/sha256"
+	"sync"
+)
+
+// clusterLocks serializes the operations on the same cluster, identified by the API server of its kubeconfig, while operations on different clusters proceed in parallel.
+type clusterLocks struct {
+	mu    sync.Mutex
+	locks map[[sha256.Size]byte]*clusterLock
+}
+
+type clusterLock struct {
+	mu sync.Mutex
+	// waiters counts the holder and the callers waiting for the lock, the lock is removed from clusterLocks when it drops to zero.
+	waiters int
+}
+
+// defaultClusterLocks is shared by all DefaultIstioPerformer instances, as a new performer is created for every reconciliation.
+var defaultClusterLocks = newClusterLocks()
+
+func newClusterLocks() *clusterLocks {
+	return &clusterLocks{locks: map[[sha256.Size]byte]*clusterLock{}}
+}
+
+// lock blocks until the lock of the cluster with the given resolved kubeconfig is acquired and returns the function releasing it.
+// The cluster is identified by clusterIdentity, which is hashed, so credentials are not kept as map keys.
+func (l *clusterLocks) lock(kubeConfig string) (unlock func()) {
+	key := sha256.Sum256([]byte(clusterIdentity(kubeConfig)))
+
+	l.mu.Lock()
+	lock, found := l.locks[key]
+	if !found {
+		lock = &clusterLock{}
+		l.locks[key] = lock
+	}
+	lock.waiters++
+	l.mu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
+
+// clusterIdentity identifies the cluster of a resolved kubeconfig by the API server host of its current context,
+// so kubeconfigs of the same cluster with other credentials or contexts are treated as the same cluster.
+// The kubeconfig itself is the identity if it cannot be parsed.
+func clusterIdentity(kubeConfig string) string {
+	if host := clusterHost(kubeConfig); host != "" {
+		return host
+	}
+	return kubeConfig
+}
@@ -0,0 +1,250 @@
+package actions
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_clusterLocks(t *testing.T) {
+
+	t.Run("should remove the lock of a cluster when it is released", func(t *testing.T) {
+		// given
+		locks := newClusterLocks()
+
+		// when
+		unlock := locks.lock("kubeconfig")
+		require.Len(t, locks.locks, 1)
+		unlock()
+
+		// then
+		require.Empty(t, locks.locks)
+	})
+
+	t.Run("should block the second caller until the lock of the same cluster is released", func(t *testing.T) {
+		// given
+		locks := newClusterLocks()
+		unlock := locks.lock("kubeconfig")
+		acquired := make(chan struct{})
+
+		// when
+		go func() {
+			secondUnlock := locks.lock("kubeconfig")
+			close(acquired)
+			secondUnlock()
+		}()
+
+		// then
+		select {
+		case <-acquired:
+			t.Fatal("lock of the same cluster was acquired twice")
+		case <-time.After(50 * time.Millisecond):
+		}
+		unlock()
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("lock was not acquired after it was released")
+		}
+	})
+
+	t.Run("should share the lock of kubeconfigs with other credentials for the same API server", func(t *testing.T) {
+		// given
+		locks := newClusterLocks()
+		otherCredentials := strings.Replace(testKubeconfig, "token: token", "token: other", 1)
+
+		// when
+		unlock := locks.lock(testKubeconfig)
+		defer unlock()
+
+		// then
+		require.Equal(t, clusterIdentity(testKubeconfig), clusterIdentity(otherCredentials))
+		require.Equal(t, "127.0.0.1:1", clusterIdentity(otherCredentials))
+		require.Len(t, locks.locks, 1)
+	})
+
+	t.Run("should not block callers of different clusters", func(t *testing.T) {
+		// given
+		locks := newClusterLocks()
+		unlock := locks.lock("kubeconfig-a")
+		defer unlock()
+		acquired := make(chan struct{})
+
+		// when
+		go func() {
+			otherUnlock := locks.lock("kubeconfig-b")
+			close(acquired)
+			otherUnlock()
+		}()
+
+		// then
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("lock of another cluster was blocked")
+		}
+	})
+}
+
+func Test_DefaultIstioPerformer_ClusterLocking(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should not interleave istioctl calls of overlapping operations on the same cluster", func(t *testing.T) {
+		// given
+		var active, maxActive int32
+		trackCall := func(mock.Arguments) {
+			current := atomic.AddInt32(&active, 1)
+			for {
+				observed := atomic.LoadInt32(&maxActive)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxActive, observed, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Run(trackCall).Return(nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Run(trackCall).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+		wrapper.clusterLocks = newClusterLocks()
+		errs := make(chan error, 10)
+		var wg sync.WaitGroup
+
+		// when
+		for i := 0; i < 5; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				errs <- wrapper.Install("kubeconfig", istioManifest, "1.2.3", "", log)
+			}()
+			go func() {
+				defer wg.Done()
+				errs <- wrapper.Update("kubeconfig", istioManifest, "1.2.3", "", false, log)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		// then
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(1), maxActive)
+		cmder.AssertNumberOfCalls(t, "Install", 5)
+		cmder.AssertNumberOfCalls(t, "Upgrade", 5)
+	})
+
+	t.Run("should run operations on different clusters in parallel", func(t *testing.T) {
+		// given
+		var entered int32
+		bothEntered := make(chan struct{})
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Run(func(mock.Arguments) {
+			if atomic.AddInt32(&entered, 1) == 2 {
+				close(bothEntered)
+			}
+			select {
+			case <-bothEntered:
+			case <-time.After(time.Second):
+			}
+		}).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+		wrapper.clusterLocks = newClusterLocks()
+		errs := make(chan error, 2)
+		var wg sync.WaitGroup
+
+		// when
+		for _, kubeConfig := range []string{"kubeconfig-a", "kubeconfig-b"} {
+			wg.Add(1)
+			go func(kubeConfig string) {
+				defer wg.Done()
+				errs <- wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
+			}(kubeConfig)
+		}
+		wg.Wait()
+		close(errs)
+
+		// then
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		select {
+		case <-bothEntered:
+		default:
+			t.Fatal("operations on different clusters were serialized")
+		}
+	})
+
+	t.Run("should serialize operations on a cluster passed once by secret reference and once inline", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		var running, maxRunning int32
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), testKubeconfig, mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(mock.Arguments) {
+				current := atomic.AddInt32(&running, 1)
+				if current > atomic.LoadInt32(&maxRunning) {
+					atomic.StoreInt32(&maxRunning, current)
+				}
+				time.Sleep(50 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			}).
+			Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithKubeconfigResolver(testKubeconfigResolver{kubeconfigs: map[string]string{"secret://ns/name": testKubeconfig}}))
+		wrapper.clusterLocks = newClusterLocks()
+
+		// when
+		var wg sync.WaitGroup
+		errs := make(chan error, 2)
+		for _, kubeConfig := range []string{"secret://ns/name", testKubeconfig} {
+			wg.Add(1)
+			go func(kubeConfig string) {
+				defer wg.Done()
+				errs <- wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
+			}(kubeConfig)
+		}
+		wg.Wait()
+		close(errs)
+
+		// then
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(1), atomic.LoadInt32(&maxRunning))
+		cmder.AssertNumberOfCalls(t, "Install", 2)
+	})
+
+	t.Run("should not deadlock when UpdateAlongPath updates the cluster it locked", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+		wrapper.clusterLocks = newClusterLocks()
+		done := make(chan error)
+
+		// when
+		go func() {
+			done <- wrapper.UpdateAlongPath("kubeconfig", istioManifest, "1.11.2", "1.11.4", "", log)
+		}()
+
+		// then
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("UpdateAlongPath did not finish")
+		}
+		cmder.AssertNumberOfCalls(t, "Upgrade", 1)
+	})
+}
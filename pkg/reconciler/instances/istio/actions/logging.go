@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/kubernetes"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// operationLogger returns the logger with the structured fields identifying the operation, its Istio version and the cluster,
+// so the log lines of concurrent reconciliations can be filtered. The version is empty for operations which are not bound to one.
+// The API server host of the current context is added as well if the kubeconfig can be parsed.
+// A kubeconfig reference does not identify the cluster yet, its cluster fields are added by resolveKubeconfig.
+func operationLogger(logger *zap.SugaredLogger, operation, version, kubeConfig string) *zap.SugaredLogger {
+	fields := []interface{}{
+		zap.String("operation", operation),
+		zap.String("version", version),
+	}
+	if !strings.HasPrefix(kubeConfig, clientset.SecretRefPrefix) {
+		fields = append(fields, clusterFields(kubeConfig)...)
+	}
+	return logger.With(fields...)
+}
+
+// clusterFields returns the structured fields identifying the cluster of a resolved kubeconfig.
+// The cluster is identified by its fingerprint, which stays the same when the credentials are rotated and does not expose them.
+// No fields are returned for a kubeconfig which cannot be parsed.
+func clusterFields(kubeConfig string) []interface{} {
+	var fields []interface{}
+	if fingerprint, err := kubernetes.ClusterFingerprint(kubeConfig); err == nil {
+		fields = append(fields, zap.String("cluster", fingerprint))
+	}
+	if host := clusterHost(kubeConfig); host != "" {
+		fields = append(fields, zap.String("clusterHost", host))
+	}
+	return fields
+}
+
+// clusterHost returns the host and port of the API server of the current context of the kubeconfig, or empty if it cannot be determined.
+// User info, path and query of the server URL are dropped, so no credentials are logged.
+func clusterHost(kubeConfig string) string {
+	config, err := clientcmd.Load([]byte(kubeConfig))
+	if err != nil {
+		return ""
+	}
+	context, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return ""
+	}
+	cluster, ok := config.Clusters[context.Cluster]
+	if !ok {
+		return ""
+	}
+	server, err := url.Parse(cluster.Server)
+	if err != nil {
+		return ""
+	}
+	return server.Host
+}
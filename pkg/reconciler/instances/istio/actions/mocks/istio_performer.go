@@ -12,6 +12,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	zap "go.uber.org/zap"
 )
 
@@ -20,13 +22,36 @@ type IstioPerformer struct {
 	mock.Mock
 }
 
-// Install provides a mock function with given fields: kubeConfig, istioChart, version, logger
-func (_m *IstioPerformer) Install(kubeConfig string, istioChart string, version string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(kubeConfig, istioChart, version, logger)
+// Analyze provides a mock function with given fields: kubeConfig, version, namespaces, logger
+func (_m *IstioPerformer) Analyze(kubeConfig string, version string, namespaces []string, logger *zap.SugaredLogger) ([]actions.AnalysisMessage, error) {
+	ret := _m.Called(kubeConfig, version, namespaces, logger)
+
+	var r0 []actions.AnalysisMessage
+	if rf, ok := ret.Get(0).(func(string, string, []string, *zap.SugaredLogger) []actions.AnalysisMessage); ok {
+		r0 = rf(kubeConfig, version, namespaces, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]actions.AnalysisMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, []string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, version, namespaces, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApplyObservability provides a mock function with given fields: kubeConfig, istioChart, logger
+func (_m *IstioPerformer) ApplyObservability(kubeConfig string, istioChart string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(kubeConfig, istioChart, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(kubeConfig, istioChart, version, logger)
+	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(kubeConfig, istioChart, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -34,13 +59,34 @@ func (_m *IstioPerformer) Install(kubeConfig string, istioChart string, version
 	return r0
 }
 
-// PatchMutatingWebhook provides a mock function with given fields: ctx, kubeClient, logger
-func (_m *IstioPerformer) PatchMutatingWebhook(ctx context.Context, kubeClient kubernetes.Client, logger *zap.SugaredLogger) error {
-	ret := _m.Called(ctx, kubeClient, logger)
+// BugReport provides a mock function with given fields: kubeConfig, outputDir, logger
+func (_m *IstioPerformer) BugReport(kubeConfig string, outputDir string, logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(kubeConfig, outputDir, logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) string); ok {
+		r0 = rf(kubeConfig, outputDir, logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, outputDir, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CleanupResetArtifacts provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) CleanupResetArtifacts(kubeConfig string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(kubeConfig, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, *zap.SugaredLogger) error); ok {
-		r0 = rf(ctx, kubeClient, logger)
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) error); ok {
+		r0 = rf(kubeConfig, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,13 +94,78 @@ func (_m *IstioPerformer) PatchMutatingWebhook(ctx context.Context, kubeClient k
 	return r0
 }
 
-// ResetProxy provides a mock function with given fields: _a0, kubeConfig, proxyImageVersion, logger
-func (_m *IstioPerformer) ResetProxy(_a0 context.Context, kubeConfig string, proxyImageVersion string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(_a0, kubeConfig, proxyImageVersion, logger)
+// EstimateDisruption provides a mock function with given fields: kubeConfig, targetProxyVersion, logger
+func (_m *IstioPerformer) EstimateDisruption(kubeConfig string, targetProxyVersion string, logger *zap.SugaredLogger) (actions.DisruptionEstimate, error) {
+	ret := _m.Called(kubeConfig, targetProxyVersion, logger)
+
+	var r0 actions.DisruptionEstimate
+	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) actions.DisruptionEstimate); ok {
+		r0 = rf(kubeConfig, targetProxyVersion, logger)
+	} else {
+		r0 = ret.Get(0).(actions.DisruptionEstimate)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, targetProxyVersion, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOrphanedSidecars provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) FindOrphanedSidecars(kubeConfig string, logger *zap.SugaredLogger) (actions.StaleProxies, error) {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 actions.StaleProxies
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) actions.StaleProxies); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(actions.StaleProxies)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInstalledOperator provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) GetInstalledOperator(kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) string); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Install provides a mock function with given fields: kubeConfig, istioChart, version, hub, logger
+func (_m *IstioPerformer) Install(kubeConfig string, istioChart string, version string, hub string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(kubeConfig, istioChart, version, hub, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(_a0, kubeConfig, proxyImageVersion, logger)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(kubeConfig, istioChart, version, hub, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,6 +173,359 @@ func (_m *IstioPerformer) ResetProxy(_a0 context.Context, kubeConfig string, pro
 	return r0
 }
 
+// InstallFromManifest provides a mock function with given fields: istioOperatorManifest, kubeConfig, version, logger
+func (_m *IstioPerformer) InstallFromManifest(istioOperatorManifest string, kubeConfig string, version string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(istioOperatorManifest, kubeConfig, version, logger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(istioOperatorManifest, kubeConfig, version, logger)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InstallWithOptions provides a mock function with given fields: opts, logger
+func (_m *IstioPerformer) InstallWithOptions(opts actions.InstallOptions, logger *zap.SugaredLogger) error {
+	ret := _m.Called(opts, logger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(actions.InstallOptions, *zap.SugaredLogger) error); ok {
+		r0 = rf(opts, logger)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListStaleProxies provides a mock function with given fields: kubeConfig, expectedVersion, logger
+func (_m *IstioPerformer) ListStaleProxies(kubeConfig string, expectedVersion string, logger *zap.SugaredLogger) (actions.StaleProxies, error) {
+	ret := _m.Called(kubeConfig, expectedVersion, logger)
+
+	var r0 actions.StaleProxies
+	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) actions.StaleProxies); ok {
+		r0 = rf(kubeConfig, expectedVersion, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(actions.StaleProxies)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, expectedVersion, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MTLSMode provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) MTLSMode(kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) string); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchMutatingWebhook provides a mock function with given fields: ctx, kubeClient, revision, logger
+func (_m *IstioPerformer) PatchMutatingWebhook(ctx context.Context, kubeClient kubernetes.Client, revision string, logger *zap.SugaredLogger) (actions.WebhookPatchResult, error) {
+	ret := _m.Called(ctx, kubeClient, revision, logger)
+
+	var r0 actions.WebhookPatchResult
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, string, *zap.SugaredLogger) actions.WebhookPatchResult); ok {
+		r0 = rf(ctx, kubeClient, revision, logger)
+	} else {
+		r0 = ret.Get(0).(actions.WebhookPatchResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, kubernetes.Client, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(ctx, kubeClient, revision, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreCheck provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) PreCheck(kubeConfig string, logger *zap.SugaredLogger) ([]actions.PreCheckMessage, error) {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 []actions.PreCheckMessage
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) []actions.PreCheckMessage); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]actions.PreCheckMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreviewMutatingWebhookPatch provides a mock function with given fields: ctx, kubeClient, revision, logger
+func (_m *IstioPerformer) PreviewMutatingWebhookPatch(ctx context.Context, kubeClient kubernetes.Client, revision string, logger *zap.SugaredLogger) (actions.WebhookPatchPreview, error) {
+	ret := _m.Called(ctx, kubeClient, revision, logger)
+
+	var r0 actions.WebhookPatchPreview
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, string, *zap.SugaredLogger) actions.WebhookPatchPreview); ok {
+		r0 = rf(ctx, kubeClient, revision, logger)
+	} else {
+		r0 = ret.Get(0).(actions.WebhookPatchPreview)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, kubernetes.Client, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(ctx, kubeClient, revision, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProxyConfigDump provides a mock function with given fields: kubeConfig, version, namespace, pod, logger
+func (_m *IstioPerformer) ProxyConfigDump(kubeConfig string, version string, namespace string, pod string, logger *zap.SugaredLogger) ([]byte, error) {
+	ret := _m.Called(kubeConfig, version, namespace, pod, logger)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string, string, string, string, *zap.SugaredLogger) []byte); ok {
+		r0 = rf(kubeConfig, version, namespace, pod, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, version, namespace, pod, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProxyConfigDumpWithCompression provides a mock function with given fields: kubeConfig, version, namespace, pod, compress, logger
+func (_m *IstioPerformer) ProxyConfigDumpWithCompression(kubeConfig string, version string, namespace string, pod string, compress bool, logger *zap.SugaredLogger) (actions.ConfigDump, error) {
+	ret := _m.Called(kubeConfig, version, namespace, pod, compress, logger)
+
+	var r0 actions.ConfigDump
+	if rf, ok := ret.Get(0).(func(string, string, string, string, bool, *zap.SugaredLogger) actions.ConfigDump); ok {
+		r0 = rf(kubeConfig, version, namespace, pod, compress, logger)
+	} else {
+		r0 = ret.Get(0).(actions.ConfigDump)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string, bool, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, version, namespace, pod, compress, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProxySyncSummary provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) ProxySyncSummary(kubeConfig string, logger *zap.SugaredLogger) (actions.SyncSummary, error) {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 actions.SyncSummary
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) actions.SyncSummary); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		r0 = ret.Get(0).(actions.SyncSummary)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetProxy provides a mock function with given fields: _a0, kubeConfig, proxyImageVersion, labelSelector, force, logger
+func (_m *IstioPerformer) ResetProxy(_a0 context.Context, kubeConfig string, proxyImageVersion string, labelSelector string, force bool, logger *zap.SugaredLogger) (actions.ProxyResetResult, error) {
+	ret := _m.Called(_a0, kubeConfig, proxyImageVersion, labelSelector, force, logger)
+
+	var r0 actions.ProxyResetResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool, *zap.SugaredLogger) actions.ProxyResetResult); ok {
+		r0 = rf(_a0, kubeConfig, proxyImageVersion, labelSelector, force, logger)
+	} else {
+		r0 = ret.Get(0).(actions.ProxyResetResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, proxyImageVersion, labelSelector, force, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetProxyFromChart provides a mock function with given fields: _a0, workspace, branch, istioChart, kubeConfig, labelSelector, force, logger
+func (_m *IstioPerformer) ResetProxyFromChart(_a0 context.Context, workspace chart.Factory, branch string, istioChart string, kubeConfig string, labelSelector string, force bool, logger *zap.SugaredLogger) (actions.ProxyResetResult, error) {
+	ret := _m.Called(_a0, workspace, branch, istioChart, kubeConfig, labelSelector, force, logger)
+
+	var r0 actions.ProxyResetResult
+	if rf, ok := ret.Get(0).(func(context.Context, chart.Factory, string, string, string, string, bool, *zap.SugaredLogger) actions.ProxyResetResult); ok {
+		r0 = rf(_a0, workspace, branch, istioChart, kubeConfig, labelSelector, force, logger)
+	} else {
+		r0 = ret.Get(0).(actions.ProxyResetResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, chart.Factory, string, string, string, string, bool, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, workspace, branch, istioChart, kubeConfig, labelSelector, force, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetProxyFromFile provides a mock function with given fields: _a0, kubeConfigPath, proxyImageVersion, labelSelector, force, logger
+func (_m *IstioPerformer) ResetProxyFromFile(_a0 context.Context, kubeConfigPath string, proxyImageVersion string, labelSelector string, force bool, logger *zap.SugaredLogger) (actions.ProxyResetResult, error) {
+	ret := _m.Called(_a0, kubeConfigPath, proxyImageVersion, labelSelector, force, logger)
+
+	var r0 actions.ProxyResetResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool, *zap.SugaredLogger) actions.ProxyResetResult); ok {
+		r0 = rf(_a0, kubeConfigPath, proxyImageVersion, labelSelector, force, logger)
+	} else {
+		r0 = ret.Get(0).(actions.ProxyResetResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfigPath, proxyImageVersion, labelSelector, force, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestartGateways provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) RestartGateways(kubeConfig string, logger *zap.SugaredLogger) ([]string, error) {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) []string); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetInjection provides a mock function with given fields: kubeConfig, namespace, enabled, revision, logger
+func (_m *IstioPerformer) SetInjection(kubeConfig string, namespace string, enabled bool, revision string, logger *zap.SugaredLogger) (actions.NamespaceInjectionLabels, error) {
+	ret := _m.Called(kubeConfig, namespace, enabled, revision, logger)
+
+	var r0 actions.NamespaceInjectionLabels
+	if rf, ok := ret.Get(0).(func(string, string, bool, string, *zap.SugaredLogger) actions.NamespaceInjectionLabels); ok {
+		r0 = rf(kubeConfig, namespace, enabled, revision, logger)
+	} else {
+		r0 = ret.Get(0).(actions.NamespaceInjectionLabels)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, namespace, enabled, revision, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SidecarInjectionStatus provides a mock function with given fields: ctx, kubeClient, namespaces, logger
+func (_m *IstioPerformer) SidecarInjectionStatus(ctx context.Context, kubeClient kubernetes.Client, namespaces []string, logger *zap.SugaredLogger) (actions.SidecarInjectionStatus, error) {
+	ret := _m.Called(ctx, kubeClient, namespaces, logger)
+
+	var r0 actions.SidecarInjectionStatus
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, []string, *zap.SugaredLogger) actions.SidecarInjectionStatus); ok {
+		r0 = rf(ctx, kubeClient, namespaces, logger)
+	} else {
+		r0 = ret.Get(0).(actions.SidecarInjectionStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, kubernetes.Client, []string, *zap.SugaredLogger) error); ok {
+		r1 = rf(ctx, kubeClient, namespaces, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SidecarInventory provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) SidecarInventory(kubeConfig string, logger *zap.SugaredLogger) (map[string]actions.NamespaceSidecarStats, error) {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 map[string]actions.NamespaceSidecarStats
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) map[string]actions.NamespaceSidecarStats); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]actions.NamespaceSidecarStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Uninstall provides a mock function with given fields: kubeClientSet, version, logger
 func (_m *IstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error {
 	ret := _m.Called(kubeClientSet, version, logger)
@@ -76,13 +540,27 @@ func (_m *IstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version str
 	return r0
 }
 
-// Update provides a mock function with given fields: kubeConfig, istioChart, targetVersion, logger
-func (_m *IstioPerformer) Update(kubeConfig string, istioChart string, targetVersion string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(kubeConfig, istioChart, targetVersion, logger)
+// Update provides a mock function with given fields: kubeConfig, istioChart, targetVersion, hub, autoRollback, logger
+func (_m *IstioPerformer) Update(kubeConfig string, istioChart string, targetVersion string, hub string, autoRollback bool, logger *zap.SugaredLogger) error {
+	ret := _m.Called(kubeConfig, istioChart, targetVersion, hub, autoRollback, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(kubeConfig, istioChart, targetVersion, logger)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, bool, *zap.SugaredLogger) error); ok {
+		r0 = rf(kubeConfig, istioChart, targetVersion, hub, autoRollback, logger)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAlongPath provides a mock function with given fields: kubeConfig, istioChart, currentVersion, targetVersion, hub, logger
+func (_m *IstioPerformer) UpdateAlongPath(kubeConfig string, istioChart string, currentVersion string, targetVersion string, hub string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(kubeConfig, istioChart, currentVersion, targetVersion, hub, logger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(kubeConfig, istioChart, currentVersion, targetVersion, hub, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -90,23 +568,151 @@ func (_m *IstioPerformer) Update(kubeConfig string, istioChart string, targetVer
 	return r0
 }
 
-// Version provides a mock function with given fields: workspace, branchVersion, istioChart, kubeConfig, logger
-func (_m *IstioPerformer) Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (actions.IstioStatus, error) {
-	ret := _m.Called(workspace, branchVersion, istioChart, kubeConfig, logger)
+// UpdateWithOptions provides a mock function with given fields: opts, logger
+func (_m *IstioPerformer) UpdateWithOptions(opts actions.UpdateOptions, logger *zap.SugaredLogger) error {
+	ret := _m.Called(opts, logger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(actions.UpdateOptions, *zap.SugaredLogger) error); ok {
+		r0 = rf(opts, logger)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateKubeconfig provides a mock function with given fields: kubeConfig, logger
+func (_m *IstioPerformer) ValidateKubeconfig(kubeConfig string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(kubeConfig, logger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) error); ok {
+		r0 = rf(kubeConfig, logger)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Version provides a mock function with given fields: workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger
+func (_m *IstioPerformer) Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, versionOverride string, logger *zap.SugaredLogger) (actions.IstioStatus, error) {
+	ret := _m.Called(workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
 
 	var r0 actions.IstioStatus
-	if rf, ok := ret.Get(0).(func(chart.Factory, string, string, string, *zap.SugaredLogger) actions.IstioStatus); ok {
-		r0 = rf(workspace, branchVersion, istioChart, kubeConfig, logger)
+	if rf, ok := ret.Get(0).(func(chart.Factory, string, string, string, string, *zap.SugaredLogger) actions.IstioStatus); ok {
+		r0 = rf(workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
 	} else {
 		r0 = ret.Get(0).(actions.IstioStatus)
 	}
 
+	var r1 error
+	if rf, ok := ret.Get(1).(func(chart.Factory, string, string, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VersionDetailed provides a mock function with given fields: workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger
+func (_m *IstioPerformer) VersionDetailed(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, versionOverride string, logger *zap.SugaredLogger) (actions.IstioVersionDetails, error) {
+	ret := _m.Called(workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
+
+	var r0 actions.IstioVersionDetails
+	if rf, ok := ret.Get(0).(func(chart.Factory, string, string, string, string, *zap.SugaredLogger) actions.IstioVersionDetails); ok {
+		r0 = rf(workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
+	} else {
+		r0 = ret.Get(0).(actions.IstioVersionDetails)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(chart.Factory, string, string, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyInstall provides a mock function with given fields: kubeConfig, version, logger
+func (_m *IstioPerformer) VerifyInstall(kubeConfig string, version string, logger *zap.SugaredLogger) (actions.InstallVerification, error) {
+	ret := _m.Called(kubeConfig, version, logger)
+
+	var r0 actions.InstallVerification
+	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) actions.InstallVerification); ok {
+		r0 = rf(kubeConfig, version, logger)
+	} else {
+		r0 = ret.Get(0).(actions.InstallVerification)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeConfig, version, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VersionDrift provides a mock function with given fields: workspace, branch, istioChart, kubeConfig, logger
+func (_m *IstioPerformer) VersionDrift(workspace chart.Factory, branch string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (actions.DriftReport, error) {
+	ret := _m.Called(workspace, branch, istioChart, kubeConfig, logger)
+
+	var r0 actions.DriftReport
+	if rf, ok := ret.Get(0).(func(chart.Factory, string, string, string, *zap.SugaredLogger) actions.DriftReport); ok {
+		r0 = rf(workspace, branch, istioChart, kubeConfig, logger)
+	} else {
+		r0 = ret.Get(0).(actions.DriftReport)
+	}
+
 	var r1 error
 	if rf, ok := ret.Get(1).(func(chart.Factory, string, string, string, *zap.SugaredLogger) error); ok {
-		r1 = rf(workspace, branchVersion, istioChart, kubeConfig, logger)
+		r1 = rf(workspace, branch, istioChart, kubeConfig, logger)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
+
+// WaitForReady provides a mock function with given fields: ctx, kubeConfig, timeout, progress, logger
+func (_m *IstioPerformer) WaitForReady(ctx context.Context, kubeConfig string, timeout time.Duration, progress actions.ReadinessProgressFunc, logger *zap.SugaredLogger) ([]actions.ComponentReadiness, error) {
+	ret := _m.Called(ctx, kubeConfig, timeout, progress, logger)
+
+	var r0 []actions.ComponentReadiness
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, actions.ReadinessProgressFunc, *zap.SugaredLogger) []actions.ComponentReadiness); ok {
+		r0 = rf(ctx, kubeConfig, timeout, progress, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]actions.ComponentReadiness)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration, actions.ReadinessProgressFunc, *zap.SugaredLogger) error); ok {
+		r1 = rf(ctx, kubeConfig, timeout, progress, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaitForWebhookInjection provides a mock function with given fields: ctx, kubeClient, namespace, timeout, logger
+func (_m *IstioPerformer) WaitForWebhookInjection(ctx context.Context, kubeClient kubernetes.Client, namespace string, timeout time.Duration, logger *zap.SugaredLogger) error {
+	ret := _m.Called(ctx, kubeClient, namespace, timeout, logger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, string, time.Duration, *zap.SugaredLogger) error); ok {
+		r0 = rf(ctx, kubeClient, namespace, timeout, logger)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -13,3 +13,11 @@ func (tcr TestCommanderResolver) GetCommander(version istioctl.Version) (istioct
 	}
 	return tcr.cmder, nil
 }
+
+func (tcr TestCommanderResolver) IsVersionSupported(version string) bool {
+	return tcr.err == nil
+}
+
+func (tcr TestCommanderResolver) ResolveVersion(constraint string) (istioctl.Version, error) {
+	return istioctl.VersionFromString(constraint)
+}
@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// MTLSModeStrict means that the workloads of the mesh only accept mutual TLS traffic.
+	MTLSModeStrict = "STRICT"
+	// MTLSModePermissive means that the workloads of the mesh accept both mutual TLS and plaintext traffic, which is the Istio default.
+	MTLSModePermissive = "PERMISSIVE"
+	// MTLSModeDisabled means that mutual TLS is disabled for the mesh by the PeerAuthentication or the default DestinationRule.
+	MTLSModeDisabled = "DISABLED"
+)
+
+var (
+	peerAuthenticationResource = schema.GroupVersionResource{Group: "security.istio.io", Version: "v1beta1", Resource: "peerauthentications"}
+	destinationRuleResource    = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "destinationrules"}
+)
+
+// MTLSMode reports the mesh-wide mutual TLS mode from the PeerAuthentication and the DestinationRule without workload selector in the Istio root namespace.
+// The mode is DISABLED if the default DestinationRule disables TLS for the clients of a PERMISSIVE mesh.
+func (c *DefaultIstioPerformer) MTLSMode(kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	logger = operationLogger(logger, "MTLSMode", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return "", err
+	}
+
+	dynamicClient, err := c.dynamicProvider.RetrieveDynamicFrom(kubeConfig, logger)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := c.operationContext()
+	defer cancel()
+
+	peerAuthentication, err := c.meshWideResource(ctx, dynamicClient, peerAuthenticationResource, isMeshWidePeerAuthentication)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get mesh-wide PeerAuthentication")
+	}
+	destinationRule, err := c.meshWideResource(ctx, dynamicClient, destinationRuleResource, isMeshWideDestinationRule)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get mesh-wide DestinationRule")
+	}
+
+	mode := MTLSModePermissive
+	if peerAuthentication != nil {
+		peerAuthenticationMode, _, _ := unstructured.NestedString(peerAuthentication.Object, "spec", "mtls", "mode")
+		switch peerAuthenticationMode {
+		case "STRICT":
+			mode = MTLSModeStrict
+		case "DISABLE":
+			mode = MTLSModeDisabled
+		}
+		logger.Debugf("Mesh-wide PeerAuthentication %s/%s has mTLS mode %q", peerAuthentication.GetNamespace(), peerAuthentication.GetName(), peerAuthenticationMode)
+	}
+	if destinationRule != nil {
+		tlsMode, _, _ := unstructured.NestedString(destinationRule.Object, "spec", "trafficPolicy", "tls", "mode")
+		if tlsMode == "DISABLE" && mode == MTLSModePermissive {
+			mode = MTLSModeDisabled
+		}
+		logger.Debugf("Mesh-wide DestinationRule %s/%s has TLS mode %q", destinationRule.GetNamespace(), destinationRule.GetName(), tlsMode)
+	}
+	return mode, nil
+}
+
+// meshWideResource returns the oldest resource in the Istio root namespace accepted by isMeshWide, which is the one Istio applies if there are several.
+// Returns nil if there is none or the resource is not served by the cluster.
+func (c *DefaultIstioPerformer) meshWideResource(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, isMeshWide func(*unstructured.Unstructured) bool) (*unstructured.Unstructured, error) {
+	list, err := dynamicClient.Resource(gvr).Namespace(c.namespace).List(ctx, metav1.ListOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []unstructured.Unstructured
+	for _, item := range list.Items {
+		if isMeshWide(&item) {
+			candidates = append(candidates, item)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		created, otherCreated := candidates[i].GetCreationTimestamp(), candidates[j].GetCreationTimestamp()
+		return created.Before(&otherCreated)
+	})
+	return &candidates[0], nil
+}
+
+func isMeshWidePeerAuthentication(peerAuthentication *unstructured.Unstructured) bool {
+	_, found, _ := unstructured.NestedFieldNoCopy(peerAuthentication.Object, "spec", "selector")
+	return !found
+}
+
+// isMeshWideDestinationRule returns true for DestinationRules without workload selector whose host matches all services, e.g. "*.local".
+func isMeshWideDestinationRule(destinationRule *unstructured.Unstructured) bool {
+	if _, found, _ := unstructured.NestedFieldNoCopy(destinationRule.Object, "spec", "workloadSelector"); found {
+		return false
+	}
+	host, _, _ := unstructured.NestedString(destinationRule.Object, "spec", "host")
+	return strings.HasPrefix(host, "*")
+}
@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func Test_DefaultIstioPerformer_MTLSMode(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		resources []runtime.Object
+		want      string
+	}{
+		{
+			name: "should report PERMISSIVE without mesh-wide resources",
+			want: MTLSModePermissive,
+		},
+		{
+			name:      "should report STRICT for a STRICT mesh-wide PeerAuthentication",
+			resources: []runtime.Object{fixPeerAuthentication("istio-system", "default", "STRICT", created, false)},
+			want:      MTLSModeStrict,
+		},
+		{
+			name:      "should report DISABLED for a DISABLE mesh-wide PeerAuthentication",
+			resources: []runtime.Object{fixPeerAuthentication("istio-system", "default", "DISABLE", created, false)},
+			want:      MTLSModeDisabled,
+		},
+		{
+			name:      "should report PERMISSIVE for an UNSET mesh-wide PeerAuthentication",
+			resources: []runtime.Object{fixPeerAuthentication("istio-system", "default", "UNSET", created, false)},
+			want:      MTLSModePermissive,
+		},
+		{
+			name: "should ignore PeerAuthentications with workload selector or in other namespaces",
+			resources: []runtime.Object{
+				fixPeerAuthentication("istio-system", "ingressgateway", "STRICT", created, true),
+				fixPeerAuthentication("default", "default", "STRICT", created, false),
+			},
+			want: MTLSModePermissive,
+		},
+		{
+			name: "should use the oldest mesh-wide PeerAuthentication",
+			resources: []runtime.Object{
+				fixPeerAuthentication("istio-system", "newer", "DISABLE", created.Add(time.Hour), false),
+				fixPeerAuthentication("istio-system", "default", "STRICT", created, false),
+			},
+			want: MTLSModeStrict,
+		},
+		{
+			name:      "should report DISABLED if the default DestinationRule disables TLS in a PERMISSIVE mesh",
+			resources: []runtime.Object{fixDestinationRule("istio-system", "default", "*.local", "DISABLE")},
+			want:      MTLSModeDisabled,
+		},
+		{
+			name: "should report STRICT even if the default DestinationRule disables TLS",
+			resources: []runtime.Object{
+				fixPeerAuthentication("istio-system", "default", "STRICT", created, false),
+				fixDestinationRule("istio-system", "default", "*.local", "DISABLE"),
+			},
+			want: MTLSModeStrict,
+		},
+		{
+			name:      "should ignore DestinationRules of single hosts",
+			resources: []runtime.Object{fixDestinationRule("istio-system", "legacy", "legacy.default.svc.cluster.local", "DISABLE")},
+			want:      MTLSModePermissive,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			dynamicProvider := clientsetmocks.DynamicProvider{}
+			dynamicProvider.On("RetrieveDynamicFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(newMTLSDynamicClient(tc.resources...), nil)
+			wrapper := NewDefaultIstioPerformer(nil, nil, &clientsetmocks.Provider{}, WithDynamicProvider(&dynamicProvider))
+
+			// when
+			mode, err := wrapper.MTLSMode(kubeConfig, log)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tc.want, mode)
+		})
+	}
+
+	t.Run("should return an error when the dynamic client could not be retrieved", func(t *testing.T) {
+		// given
+		dynamicProvider := clientsetmocks.DynamicProvider{}
+		dynamicProvider.On("RetrieveDynamicFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("kubeconfig error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &clientsetmocks.Provider{}, WithDynamicProvider(&dynamicProvider))
+
+		// when
+		_, err := wrapper.MTLSMode(kubeConfig, log)
+
+		// then
+		require.EqualError(t, err, "kubeconfig error")
+	})
+}
+
+func newMTLSDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{
+		peerAuthenticationResource: "PeerAuthenticationList",
+		destinationRuleResource:    "DestinationRuleList",
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func fixPeerAuthentication(namespace, name, mode string, created time.Time, withSelector bool) *unstructured.Unstructured {
+	spec := map[string]interface{}{"mtls": map[string]interface{}{"mode": mode}}
+	if withSelector {
+		spec["selector"] = map[string]interface{}{"matchLabels": map[string]interface{}{"app": name}}
+	}
+	peerAuthentication := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	peerAuthentication.SetAPIVersion("security.istio.io/v1beta1")
+	peerAuthentication.SetKind("PeerAuthentication")
+	peerAuthentication.SetNamespace(namespace)
+	peerAuthentication.SetName(name)
+	peerAuthentication.SetCreationTimestamp(metav1.NewTime(created))
+	return peerAuthentication
+}
+
+func fixDestinationRule(namespace, name, host, tlsMode string) *unstructured.Unstructured {
+	destinationRule := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"host": host,
+			"trafficPolicy": map[string]interface{}{
+				"tls": map[string]interface{}{"mode": tlsMode},
+			},
+		},
+	}}
+	destinationRule.SetAPIVersion("networking.istio.io/v1beta1")
+	destinationRule.SetKind("DestinationRule")
+	destinationRule.SetNamespace(namespace)
+	destinationRule.SetName(name)
+	return destinationRule
+}
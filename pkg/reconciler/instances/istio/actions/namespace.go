@@ -0,0 +1,135 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgo "k8s.io/client-go/kubernetes"
+)
+
+// NamespaceDeletionTimeoutError is returned by Uninstall if the Istio namespace was not removed within the namespace deletion timeout.
+// Istio itself is uninstalled at this point, so callers can treat it as a warning.
+type NamespaceDeletionTimeoutError struct {
+	Namespace string
+	Timeout   time.Duration
+	// Finalizers lists the resources still holding finalizers, e.g. "pod istio-system/istiod-1 (example.com/cleanup)".
+	Finalizers []string
+}
+
+func (e *NamespaceDeletionTimeoutError) Error() string {
+	msg := fmt.Sprintf("Namespace %s was not deleted within %s", e.Namespace, e.Timeout)
+	if len(e.Finalizers) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s, resources still holding finalizers: %s", msg, strings.Join(e.Finalizers, ", "))
+}
+
+// deletionPropagations lists the propagation policies the Istio namespace can be deleted with.
+var deletionPropagations = []metav1.DeletionPropagation{metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan}
+
+// ParseDeletionPropagation returns the propagation policy for one of Foreground, Background or Orphan, ignoring case.
+func ParseDeletionPropagation(value string) (metav1.DeletionPropagation, error) {
+	for _, policy := range deletionPropagations {
+		if strings.EqualFold(value, string(policy)) {
+			return policy, nil
+		}
+	}
+	return "", errors.Errorf("Invalid deletion propagation policy %q, must be one of Foreground, Background or Orphan", value)
+}
+
+func validateDeletionPropagation(policy metav1.DeletionPropagation) error {
+	for _, valid := range deletionPropagations {
+		if policy == valid {
+			return nil
+		}
+	}
+	return errors.Errorf("Invalid deletion propagation policy %q, must be one of Foreground, Background or Orphan", policy)
+}
+
+// deleteNamespace deletes the Istio namespace and waits until it is removed, if a namespace deletion timeout is set.
+func (c *DefaultIstioPerformer) deleteNamespace(kubeClient clientgo.Interface, logger *zap.SugaredLogger) error {
+	policy := c.namespaceDeletionPropagation
+	err := kubeClient.CoreV1().Namespaces().Delete(context.TODO(), c.namespace, metav1.DeleteOptions{
+		PropagationPolicy: &policy,
+	})
+	if err != nil {
+		return err
+	}
+	if c.namespaceDeletionTimeout <= 0 {
+		logger.Debugf("Istio namespace %s deletion triggered", c.namespace)
+		return nil
+	}
+
+	interval := c.namespaceDeletionInterval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	err = wait.PollImmediate(interval, c.namespaceDeletionTimeout, func() (bool, error) {
+		_, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), c.namespace, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			logger.Debugf("Could not get namespace %s: %s", c.namespace, err)
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		timeoutErr := &NamespaceDeletionTimeoutError{
+			Namespace:  c.namespace,
+			Timeout:    c.namespaceDeletionTimeout,
+			Finalizers: c.finalizerHolders(kubeClient, logger),
+		}
+		logger.Warn(timeoutErr.Error())
+		return timeoutErr
+	}
+	if err != nil {
+		return err
+	}
+	logger.Debugf("Istio namespace %s deleted", c.namespace)
+	return nil
+}
+
+// finalizerHolders lists the Istio namespace and its pods which still have finalizers,
+// together with the remaining finalizers reported in the namespace status.
+func (c *DefaultIstioPerformer) finalizerHolders(kubeClient clientgo.Interface, logger *zap.SugaredLogger) []string {
+	var holders []string
+
+	namespace, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), c.namespace, metav1.GetOptions{})
+	if err != nil {
+		logger.Debugf("Could not get namespace %s: %s", c.namespace, err)
+		return holders
+	}
+	finalizers := append([]string{}, namespace.Finalizers...)
+	for _, finalizer := range namespace.Spec.Finalizers {
+		finalizers = append(finalizers, string(finalizer))
+	}
+	if len(finalizers) > 0 {
+		holders = append(holders, fmt.Sprintf("namespace %s (%s)", namespace.Name, strings.Join(finalizers, ", ")))
+	}
+	for _, condition := range namespace.Status.Conditions {
+		if condition.Type == corev1.NamespaceFinalizersRemaining && condition.Status == corev1.ConditionTrue {
+			holders = append(holders, condition.Message)
+		}
+	}
+
+	pods, err := kubeClient.CoreV1().Pods(c.namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Debugf("Could not list pods in namespace %s: %s", c.namespace, err)
+		return holders
+	}
+	for _, pod := range pods.Items {
+		if len(pod.Finalizers) > 0 {
+			holders = append(holders, fmt.Sprintf("pod %s/%s (%s)", pod.Namespace, pod.Name, strings.Join(pod.Finalizers, ", ")))
+		}
+	}
+	return holders
+}
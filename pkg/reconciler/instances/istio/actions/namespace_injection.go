@@ -0,0 +1,98 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const injectionLabelDisabled = "disabled"
+
+// NamespaceInjectionLabels are the sidecar injection labels of a namespace. An empty value means the label is not set.
+type NamespaceInjectionLabels struct {
+	// Injection is the value of the istio-injection label.
+	Injection string
+	// Revision is the value of the istio.io/rev label.
+	Revision string
+}
+
+// Enabled returns true if the labels enable sidecar injection, either by the istio-injection label or by a revision.
+func (l NamespaceInjectionLabels) Enabled() bool {
+	if l.Injection != "" {
+		return l.Injection == injectionLabelEnabled
+	}
+	return l.Revision != ""
+}
+
+// injectionLabelsFor returns the labels which enable or disable the sidecar injection of a namespace.
+// Istio ignores the istio.io/rev label if the istio-injection label is set, so only one of both is ever returned.
+func injectionLabelsFor(enabled bool, revision string) NamespaceInjectionLabels {
+	switch {
+	case !enabled:
+		return NamespaceInjectionLabels{Injection: injectionLabelDisabled}
+	case revision != "":
+		return NamespaceInjectionLabels{Revision: revision}
+	default:
+		return NamespaceInjectionLabels{Injection: injectionLabelEnabled}
+	}
+}
+
+// apply sets the labels on the given label map and removes the unset ones.
+func (l NamespaceInjectionLabels) apply(labels map[string]string) {
+	for key, value := range map[string]string{injectionLabel: l.Injection, revisionLabel: l.Revision} {
+		if value == "" {
+			delete(labels, key)
+		} else {
+			labels[key] = value
+		}
+	}
+}
+
+func (c *DefaultIstioPerformer) SetInjection(kubeConfig, namespace string, enabled bool, revision string, logger *zap.SugaredLogger) (NamespaceInjectionLabels, error) {
+	logger = operationLogger(logger, "SetInjection", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return NamespaceInjectionLabels{}, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return NamespaceInjectionLabels{}, err
+	}
+
+	desired := injectionLabelsFor(enabled, revision)
+	var previous NamespaceInjectionLabels
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		previous = NamespaceInjectionLabels{Injection: ns.Labels[injectionLabel], Revision: ns.Labels[revisionLabel]}
+		if previous == desired {
+			return nil
+		}
+
+		if ns.Labels == nil {
+			ns.Labels = map[string]string{}
+		}
+		desired.apply(ns.Labels)
+		_, err = kubeClient.CoreV1().Namespaces().Update(context.Background(), ns, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return NamespaceInjectionLabels{}, errors.Wrapf(err, "Could not set sidecar injection labels of namespace %s", namespace)
+	}
+
+	if previous == desired {
+		logger.Debugf("Sidecar injection labels of namespace %s are already %+v", namespace, desired)
+	} else {
+		logger.Infof("Changed sidecar injection labels of namespace %s from %+v to %+v", namespace, previous, desired)
+	}
+	return previous, nil
+}
@@ -0,0 +1,111 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_SetInjection(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	setInjection := func(t *testing.T, ns *corev1.Namespace, enabled bool, revision string) (NamespaceInjectionLabels, map[string]string) {
+		kubeClient := fake.NewSimpleClientset(ns)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		previous, err := wrapper.SetInjection(kubeConfig, ns.Name, enabled, revision, log)
+		require.NoError(t, err)
+
+		got, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), ns.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return previous, got.Labels
+	}
+
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		_, err := wrapper.SetInjection(kubeConfig, "default", true, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
+
+	t.Run("should return error when the namespace does not exist", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		_, err := wrapper.SetInjection(kubeConfig, "missing", true, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not set sidecar injection labels of namespace missing")
+	})
+
+	t.Run("should enable injection of an unlabeled namespace", func(t *testing.T) {
+		// when
+		previous, labels := setInjection(t, fixNamespace("default", nil), true, "")
+
+		// then
+		require.Equal(t, NamespaceInjectionLabels{}, previous)
+		require.False(t, previous.Enabled())
+		require.Equal(t, map[string]string{injectionLabel: "enabled"}, labels)
+	})
+
+	t.Run("should replace the istio-injection label when a revision is given", func(t *testing.T) {
+		// when
+		previous, labels := setInjection(t, fixNamespace("default", map[string]string{injectionLabel: "enabled", "app": "test"}), true, "1-12-1")
+
+		// then
+		require.Equal(t, NamespaceInjectionLabels{Injection: "enabled"}, previous)
+		require.Equal(t, map[string]string{revisionLabel: "1-12-1", "app": "test"}, labels)
+	})
+
+	t.Run("should replace the revision label when no revision is given", func(t *testing.T) {
+		// when
+		previous, labels := setInjection(t, fixNamespace("default", map[string]string{revisionLabel: "1-12-1"}), true, "")
+
+		// then
+		require.Equal(t, NamespaceInjectionLabels{Revision: "1-12-1"}, previous)
+		require.True(t, previous.Enabled())
+		require.Equal(t, map[string]string{injectionLabel: "enabled"}, labels)
+	})
+
+	t.Run("should disable injection and remove the revision label", func(t *testing.T) {
+		// when
+		previous, labels := setInjection(t, fixNamespace("default", map[string]string{revisionLabel: "1-12-1"}), false, "1-12-1")
+
+		// then
+		require.Equal(t, NamespaceInjectionLabels{Revision: "1-12-1"}, previous)
+		require.Equal(t, map[string]string{injectionLabel: "disabled"}, labels)
+	})
+
+	t.Run("should keep the labels when they are already set", func(t *testing.T) {
+		// when
+		previous, labels := setInjection(t, fixNamespace("default", map[string]string{injectionLabel: "disabled"}), false, "")
+
+		// then
+		require.Equal(t, NamespaceInjectionLabels{Injection: "disabled"}, previous)
+		require.False(t, previous.Enabled())
+		require.Equal(t, map[string]string{injectionLabel: "disabled"}, labels)
+	})
+}
@@ -0,0 +1,218 @@
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_DefaultIstioPerformer_Uninstall_NamespaceDeletion(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should return a NamespaceDeletionTimeoutError naming the resources holding finalizers", func(t *testing.T) {
+		// given
+		clientset, policies := fixLingeringNamespaceClientset(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "istiod-1", Namespace: "istio-system", Finalizers: []string{"example.com/cleanup"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "istiod-2", Namespace: "istio-system"}},
+		)
+		wrapper := NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithNamespaceDeletionTimeout(50*time.Millisecond, 10*time.Millisecond))
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(clientset), "1.2.3", log)
+
+		// then
+		var timeoutErr *NamespaceDeletionTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.Equal(t, "istio-system", timeoutErr.Namespace)
+		require.Equal(t, []string{
+			"namespace istio-system (kubernetes)",
+			"Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances",
+			"pod istio-system/istiod-1 (example.com/cleanup)",
+		}, timeoutErr.Finalizers)
+		require.Contains(t, err.Error(), "Namespace istio-system was not deleted within 50ms")
+		require.Equal(t, []metav1.DeletionPropagation{metav1.DeletePropagationForeground}, *policies)
+	})
+
+	t.Run("should delete the namespace with background propagation when enabled", func(t *testing.T) {
+		// given
+		clientset, policies := fixLingeringNamespaceClientset()
+		wrapper := NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithBackgroundNamespaceDeletion())
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(clientset), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []metav1.DeletionPropagation{metav1.DeletePropagationBackground}, *policies)
+	})
+
+	for _, policy := range []metav1.DeletionPropagation{metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan} {
+		policy := policy
+		t.Run("should delete the namespace with the configured "+string(policy)+" propagation", func(t *testing.T) {
+			// given
+			clientset, policies := fixLingeringNamespaceClientset()
+			wrapper := NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+				WithNamespaceDeletionPropagation(policy))
+
+			// when
+			err := wrapper.Uninstall(fixKubeClient(clientset), "1.2.3", log)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, []metav1.DeletionPropagation{policy}, *policies)
+		})
+	}
+
+	t.Run("should delete the namespace with foreground propagation by default", func(t *testing.T) {
+		// given
+		clientset, policies := fixLingeringNamespaceClientset()
+		wrapper := NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(clientset), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []metav1.DeletionPropagation{metav1.DeletePropagationForeground}, *policies)
+	})
+
+	t.Run("should not uninstall when the propagation policy is invalid", func(t *testing.T) {
+		// given
+		clientset, policies := fixLingeringNamespaceClientset()
+		cmder := istioctlmocks.Commander{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithNamespaceDeletionPropagation("Eventually"))
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(clientset), "1.2.3", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `Invalid deletion propagation policy "Eventually"`)
+		require.Empty(t, *policies)
+		cmder.AssertNotCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not wait for a lingering namespace when no timeout is set", func(t *testing.T) {
+		// given
+		clientset, _ := fixLingeringNamespaceClientset()
+		wrapper := NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(clientset), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should wait until the namespace is removed", func(t *testing.T) {
+		// given
+		clientset, _ := fixLingeringNamespaceClientset()
+		gets := 0
+		clientset.PrependReactor("get", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+			gets++
+			if gets < 3 {
+				return false, nil, nil
+			}
+			return true, nil, kerrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "istio-system")
+		})
+		wrapper := NewDefaultIstioPerformer(fixUninstallCommanderResolver(), &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithNamespaceDeletionTimeout(time.Second, 10*time.Millisecond))
+
+		// when
+		err := wrapper.Uninstall(fixKubeClient(clientset), "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 3, gets)
+	})
+}
+
+func Test_ParseDeletionPropagation(t *testing.T) {
+
+	t.Run("should parse the propagation policies ignoring case", func(t *testing.T) {
+		for value, expected := range map[string]metav1.DeletionPropagation{
+			"Foreground": metav1.DeletePropagationForeground,
+			"background": metav1.DeletePropagationBackground,
+			"ORPHAN":     metav1.DeletePropagationOrphan,
+		} {
+			// when
+			policy, err := ParseDeletionPropagation(value)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, expected, policy)
+		}
+	})
+
+	t.Run("should return error for an unknown propagation policy", func(t *testing.T) {
+		// when
+		_, err := ParseDeletionPropagation("cascade")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be one of Foreground, Background or Orphan")
+	})
+}
+
+// fixLingeringNamespaceClientset returns a clientset with an istio-system namespace which is not removed when it is deleted,
+// as if its finalizers were stuck, together with the propagation policies of the delete requests.
+func fixLingeringNamespaceClientset(objects ...runtime.Object) (*fake.Clientset, *[]metav1.DeletionPropagation) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+		Spec:       corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes}},
+		Status: corev1.NamespaceStatus{
+			Phase: corev1.NamespaceTerminating,
+			Conditions: []corev1.NamespaceCondition{
+				{Type: corev1.NamespaceDeletionDiscoveryFailure, Status: corev1.ConditionFalse},
+				{
+					Type:    corev1.NamespaceFinalizersRemaining,
+					Status:  corev1.ConditionTrue,
+					Message: "Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances",
+				},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset(append([]runtime.Object{namespace}, objects...)...)
+
+	var policies []metav1.DeletionPropagation
+	clientset.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		deleteOptions := action.(k8stesting.DeleteActionImpl).DeleteOptions
+		if deleteOptions.PropagationPolicy != nil {
+			policies = append(policies, *deleteOptions.PropagationPolicy)
+		}
+		return true, nil, nil
+	})
+	return clientset, &policies
+}
+
+func fixUninstallCommanderResolver() TestCommanderResolver {
+	cmder := istioctlmocks.Commander{}
+	cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+	return TestCommanderResolver{cmder: &cmder}
+}
+
+func fixKubeClient(clientset *fake.Clientset) *mocks.Client {
+	kc := &mocks.Client{}
+	kc.On("Kubeconfig").Return("kubeconfig")
+	kc.On("Clientset").Return(clientset, nil)
+	return kc
+}
@@ -0,0 +1,201 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// observabilityLabel marks the monitoring resources applied by ApplyObservability, so they can be pruned later on.
+	observabilityLabel = "istio.reconciler.kyma-project.io/observability"
+	// grafanaDashboardLabel marks ConfigMaps containing Grafana dashboards, which are picked up by the Grafana sidecar.
+	grafanaDashboardLabel = "grafana_dashboard"
+
+	monitoringGroupVersion = "monitoring.coreos.com/v1"
+	serviceMonitorKind     = "ServiceMonitor"
+)
+
+// observabilityResources are the resources applied by ApplyObservability, by kind.
+var observabilityResources = map[string]schema.GroupVersionResource{
+	serviceMonitorKind: {Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"},
+	"PodMonitor":       {Group: "monitoring.coreos.com", Version: "v1", Resource: "podmonitors"},
+	"ConfigMap":        {Group: "", Version: "v1", Resource: "configmaps"},
+}
+
+func (c *DefaultIstioPerformer) ApplyObservability(kubeConfig, istioChart string, logger *zap.SugaredLogger) error {
+	logger = operationLogger(logger, "ApplyObservability", "", kubeConfig)
+	logger.Debug("Starting to apply the Istio observability resources...")
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+	installed, err := isServiceMonitorInstalled(kubeClient.Discovery())
+	if err != nil {
+		return errors.Wrap(err, "Could not check if the monitoring CRDs are installed")
+	}
+	if !installed {
+		logger.Infof("%s CRD is not installed, skipping Istio observability resources", serviceMonitorKind)
+		return nil
+	}
+
+	resources, err := extractObservabilityResources(istioChart, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := c.dynamicProvider.RetrieveDynamicFrom(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.operationContext()
+	defer cancel()
+
+	applied := map[string]bool{}
+	for _, resource := range resources {
+		if err := applyResource(ctx, dynamicClient, resource); err != nil {
+			return errors.Wrapf(err, "Could not apply %s %s/%s", resource.GetKind(), resource.GetNamespace(), resource.GetName())
+		}
+		applied[resourceKey(resource)] = true
+		logger.Debugf("Applied %s %s/%s", resource.GetKind(), resource.GetNamespace(), resource.GetName())
+	}
+
+	pruned, err := pruneResources(ctx, dynamicClient, applied)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Istio observability resources applied: %d, pruned: %d", len(resources), pruned)
+	return nil
+}
+
+// isServiceMonitorInstalled returns true if the cluster serves the ServiceMonitor resource.
+func isServiceMonitorInstalled(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return false, err
+	}
+	if !isGroupVersionServed(groups, monitoringGroupVersion) {
+		return false, nil
+	}
+
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(monitoringGroupVersion)
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == serviceMonitorKind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isGroupVersionServed(groups *metav1.APIGroupList, groupVersion string) bool {
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			if version.GroupVersion == groupVersion {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractObservabilityResources returns the ServiceMonitors, PodMonitors and Grafana dashboard ConfigMaps of the istioChart, labeled for pruning.
+// Resources without namespace are placed into the given namespace.
+func extractObservabilityResources(istioChart, namespace string) ([]*unstructured.Unstructured, error) {
+	unstructs, err := kubernetes.ToUnstructured([]byte(istioChart), true)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*unstructured.Unstructured
+	for _, unstruct := range unstructs {
+		if !isObservabilityResource(unstruct) {
+			continue
+		}
+		if unstruct.GetNamespace() == "" {
+			unstruct.SetNamespace(namespace)
+		}
+		labels := unstruct.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[observabilityLabel] = "true"
+		unstruct.SetLabels(labels)
+		resources = append(resources, unstruct)
+	}
+	return resources, nil
+}
+
+func isObservabilityResource(unstruct *unstructured.Unstructured) bool {
+	if _, ok := observabilityResources[unstruct.GetKind()]; !ok {
+		return false
+	}
+	if unstruct.GetKind() == "ConfigMap" {
+		_, ok := unstruct.GetLabels()[grafanaDashboardLabel]
+		return ok
+	}
+	return unstruct.GetAPIVersion() == monitoringGroupVersion
+}
+
+func applyResource(ctx context.Context, dynamicClient dynamic.Interface, resource *unstructured.Unstructured) error {
+	client := dynamicClient.Resource(observabilityResources[resource.GetKind()]).Namespace(resource.GetNamespace())
+
+	existing, err := client.Get(ctx, resource.GetName(), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = client.Create(ctx, resource, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	resource.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(ctx, resource, metav1.UpdateOptions{})
+	return err
+}
+
+// pruneResources deletes the previously applied observability resources which are not part of applied anymore and returns their count.
+func pruneResources(ctx context.Context, dynamicClient dynamic.Interface, applied map[string]bool) (int, error) {
+	pruned := 0
+	for kind, gvr := range observabilityResources {
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{LabelSelector: observabilityLabel + "=true"})
+		if err != nil {
+			return pruned, errors.Wrapf(err, "Could not list %s resources for pruning", kind)
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			item.SetKind(kind)
+			if applied[resourceKey(item)] {
+				continue
+			}
+			err := dynamicClient.Resource(gvr).Namespace(item.GetNamespace()).Delete(ctx, item.GetName(), metav1.DeleteOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return pruned, errors.Wrapf(err, "Could not prune %s %s/%s", kind, item.GetNamespace(), item.GetName())
+			}
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func resourceKey(resource *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", resource.GetKind(), resource.GetNamespace(), resource.GetName())
+}
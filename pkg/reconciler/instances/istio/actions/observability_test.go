@@ -0,0 +1,156 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const observabilityManifest = `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: installed-state
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: istio-component-monitor
+spec:
+  selector:
+    matchLabels:
+      istio: pilot
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  namespace: kyma-system
+  name: istio-dashboard
+  labels:
+    grafana_dashboard: "1"
+data:
+  istio-mesh-dashboard.json: "{}"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  namespace: istio-system
+  name: istio-config
+`
+
+func Test_DefaultIstioPerformer_ApplyObservability(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	serviceMonitors := observabilityResources[serviceMonitorKind]
+	configMaps := observabilityResources["ConfigMap"]
+
+	t.Run("should not apply anything when the ServiceMonitor CRD is not installed", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		dynamicProvider := clientsetmocks.DynamicProvider{}
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider, WithDynamicProvider(&dynamicProvider))
+
+		// when
+		err := wrapper.ApplyObservability("kubeConfig", observabilityManifest, log)
+
+		// then
+		require.NoError(t, err)
+		dynamicProvider.AssertNotCalled(t, "RetrieveDynamicFrom", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should apply the monitoring resources of the chart when the ServiceMonitor CRD is installed", func(t *testing.T) {
+		// given
+		dynamicClient := newObservabilityDynamicClient()
+		dynamicProvider := clientsetmocks.DynamicProvider{}
+		dynamicProvider.On("RetrieveDynamicFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(dynamicClient, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, providerWithMonitoringCRDs(), WithDynamicProvider(&dynamicProvider))
+
+		// when
+		err := wrapper.ApplyObservability("kubeConfig", observabilityManifest, log)
+
+		// then
+		require.NoError(t, err)
+		serviceMonitor, err := dynamicClient.Resource(serviceMonitors).Namespace("istio-system").Get(context.TODO(), "istio-component-monitor", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "true", serviceMonitor.GetLabels()[observabilityLabel])
+		_, err = dynamicClient.Resource(configMaps).Namespace("kyma-system").Get(context.TODO(), "istio-dashboard", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = dynamicClient.Resource(configMaps).Namespace("istio-system").Get(context.TODO(), "istio-config", metav1.GetOptions{})
+		require.Error(t, err)
+	})
+
+	t.Run("should update existing and prune stale monitoring resources", func(t *testing.T) {
+		// given
+		existing := fixObservabilityResource(serviceMonitorKind, "istio-system", "istio-component-monitor")
+		stale := fixObservabilityResource(serviceMonitorKind, "istio-system", "istio-removed-monitor")
+		unmanaged := &unstructured.Unstructured{}
+		unmanaged.SetAPIVersion(monitoringGroupVersion)
+		unmanaged.SetKind(serviceMonitorKind)
+		unmanaged.SetNamespace("istio-system")
+		unmanaged.SetName("custom-monitor")
+		dynamicClient := newObservabilityDynamicClient(existing, stale, unmanaged)
+		dynamicProvider := clientsetmocks.DynamicProvider{}
+		dynamicProvider.On("RetrieveDynamicFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(dynamicClient, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, providerWithMonitoringCRDs(), WithDynamicProvider(&dynamicProvider))
+
+		// when
+		err := wrapper.ApplyObservability("kubeConfig", observabilityManifest, log)
+
+		// then
+		require.NoError(t, err)
+		list, err := dynamicClient.Resource(serviceMonitors).List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		require.ElementsMatch(t, []string{"istio-component-monitor", "custom-monitor"}, names)
+	})
+}
+
+func providerWithMonitoringCRDs() *clientsetmocks.Provider {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: monitoringGroupVersion,
+			APIResources: []metav1.APIResource{
+				{Name: "servicemonitors", Kind: serviceMonitorKind, Namespaced: true},
+				{Name: "podmonitors", Kind: "PodMonitor", Namespaced: true},
+			},
+		},
+	}
+	provider := clientsetmocks.Provider{}
+	provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+	return &provider
+}
+
+func newObservabilityDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for kind, gvr := range observabilityResources {
+		listKinds[gvr] = kind + "List"
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func fixObservabilityResource(kind, namespace, name string) *unstructured.Unstructured {
+	resource := &unstructured.Unstructured{}
+	resource.SetAPIVersion(monitoringGroupVersion)
+	resource.SetKind(kind)
+	resource.SetNamespace(namespace)
+	resource.SetName(name)
+	resource.SetLabels(map[string]string{observabilityLabel: "true"})
+	return resource
+}
@@ -0,0 +1,41 @@
+package actions
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+	helmChart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/registry"
+)
+
+// ociChartPrefix marks Istio chart references which are pulled from an OCI registry instead of being loaded from the workspace.
+const ociChartPrefix = registry.OCIScheme + "://"
+
+// loadOCIChart pulls the chart referenced by an oci:// reference, replaced in tests.
+var loadOCIChart = pullOCIChart
+
+func isOCIChart(istioChart string) bool {
+	return strings.HasPrefix(istioChart, ociChartPrefix)
+}
+
+// pullOCIChart pulls the chart from the OCI registry into memory, authenticated with the credentials of helm registry login.
+func pullOCIChart(ref string) (*helmChart.Chart, error) {
+	client, err := registry.NewClient(registry.ClientOptCredentialsFile(helmpath.ConfigPath(registry.CredentialsFileBasename)))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not create Helm registry client")
+	}
+
+	result, err := client.Pull(strings.TrimPrefix(ref, ociChartPrefix))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not pull Istio chart %s", ref)
+	}
+
+	loaded, err := loader.LoadArchive(bytes.NewReader(result.Chart.Data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not load Istio chart %s", ref)
+	}
+	return loaded, nil
+}
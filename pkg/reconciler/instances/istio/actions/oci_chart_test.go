@@ -0,0 +1,77 @@
+package actions
+
+import (
+	"testing"
+
+	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	helmChart "helm.sh/helm/v3/pkg/chart"
+)
+
+// stubOCIChartLoader replaces the OCI chart loader until the test finished, recording the pulled references.
+func stubOCIChartLoader(t *testing.T, loaded *helmChart.Chart, err error) *[]string {
+	var refs []string
+	original := loadOCIChart
+	loadOCIChart = func(ref string) (*helmChart.Chart, error) {
+		refs = append(refs, ref)
+		return loaded, err
+	}
+	t.Cleanup(func() { loadOCIChart = original })
+	return &refs
+}
+
+func Test_getTargetVersionFromIstioChart_OCI(t *testing.T) {
+
+	istioChart := "oci://registry.example.com/charts/istio:1.11.4"
+
+	t.Run("should get target version from the values of the pulled chart without the workspace", func(t *testing.T) {
+		// given
+		refs := stubOCIChartLoader(t, &helmChart.Chart{
+			Metadata: &helmChart.Metadata{AppVersion: "1.11.4"},
+			Values:   map[string]interface{}{"global": map[string]interface{}{"tag": "1.11.4-distroless"}},
+		}, nil)
+		factory := &workspacemocks.Factory{}
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, "branch", istioChart, pilotVersionValuePaths)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.4-distroless", targetVersion)
+		require.Equal(t, TargetVersionSourceValues, source)
+		require.Equal(t, "global.tag", valuePath)
+		require.Equal(t, []string{istioChart}, *refs)
+		factory.AssertNotCalled(t, "Get")
+	})
+
+	t.Run("should get target version from the appVersion of the pulled chart", func(t *testing.T) {
+		// given
+		stubOCIChartLoader(t, &helmChart.Chart{Metadata: &helmChart.Metadata{AppVersion: "1.11.4"}}, nil)
+
+		// when
+		targetVersion, source, _, err := getTargetVersionFromIstioChart(&workspacemocks.Factory{}, "branch", istioChart, pilotVersionValuePaths)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.4", targetVersion)
+		require.Equal(t, TargetVersionSourceAppVersion, source)
+	})
+
+	t.Run("should return error when the chart could not be pulled", func(t *testing.T) {
+		// given
+		stubOCIChartLoader(t, nil, errors.New("unauthorized"))
+
+		// when
+		_, _, _, err := getTargetVersionFromIstioChart(&workspacemocks.Factory{}, "branch", istioChart, pilotVersionValuePaths)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unauthorized")
+	})
+}
+
+func Test_isOCIChart(t *testing.T) {
+	require.True(t, isOCIChart("oci://registry.example.com/charts/istio:1.11.4"))
+	require.False(t, isOCIChart("istio-configuration"))
+}
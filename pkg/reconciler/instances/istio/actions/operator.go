@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// installedStateOperatorName is the name of the IstioOperator in which `istioctl install` stores the installed configuration.
+// Revisioned installations append the revision to it.
+const installedStateOperatorName = "installed-state"
+
+var istioOperatorResource = schema.GroupVersionResource{Group: "install.istio.io", Version: "v1alpha1", Resource: "istiooperators"}
+
+// GetInstalledOperator returns the effective IstioOperator of the installation as YAML, which is the installed-state IstioOperator
+// merged over its profile by `istioctl profile dump`, run with the istioctl matching the installed tag.
+func (c *DefaultIstioPerformer) GetInstalledOperator(kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	// the version is only known once the installed-state IstioOperator was read
+	operationLog := operationLogger(logger, "GetInstalledOperator", "", kubeConfig)
+	operationLog.Debug("Starting export of the installed IstioOperator...")
+
+	resolvedKubeConfig, operationLog, err := c.resolveKubeconfig(kubeConfig, operationLog)
+	if err != nil {
+		return "", err
+	}
+
+	dynamicClient, err := c.dynamicProvider.RetrieveDynamicFrom(resolvedKubeConfig, operationLog)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := c.operationContext()
+	defer cancel()
+
+	list, err := dynamicClient.Resource(istioOperatorResource).Namespace(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return "", errors.Wrap(err, "Could not list IstioOperators")
+	}
+	var installed *unstructured.Unstructured
+	if list != nil {
+		installed = installedStateOperator(list.Items)
+	}
+	if installed == nil {
+		return "", errors.Wrapf(ErrIstioNotInstalled, "No %s IstioOperator found in namespace %s", installedStateOperatorName, c.namespace)
+	}
+
+	istioOperator, err := effectiveOperatorInput(installed)
+	if err != nil {
+		return "", err
+	}
+
+	constraint := latestIstioctlConstraint
+	if tag, _, _ := unstructured.NestedString(installed.Object, "spec", "tag"); tag != "" {
+		constraint = strings.TrimSuffix(tag, distrolessSuffix)
+	}
+	execVersion, err := c.resolveVersion(constraint)
+	if err != nil {
+		operationLog.Debugf("Installed tag %q is no istioctl version, using the newest istioctl: %s", constraint, err)
+		execVersion, err = c.resolveVersion(latestIstioctlConstraint)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	commander, err := c.getCommander(execVersion)
+	if err != nil {
+		return "", err
+	}
+
+	logger = operationLogger(logger, "GetInstalledOperator", execVersion.String(), kubeConfig)
+
+	profileDump, err := commander.ProfileDump(istioOperator, logger)
+	if err != nil {
+		return "", istioctlError("profile dump", err)
+	}
+
+	logger.Debugf("Exported effective IstioOperator %s/%s with istioctl %s", installed.GetNamespace(), installed.GetName(), execVersion.String())
+	return string(profileDump), nil
+}
+
+// installedStateOperator returns the IstioOperator of the default revision, or the first revisioned one by name if there is none.
+func installedStateOperator(operators []unstructured.Unstructured) *unstructured.Unstructured {
+	var revisioned []unstructured.Unstructured
+	for i := range operators {
+		name := operators[i].GetName()
+		if name == installedStateOperatorName {
+			return &operators[i]
+		}
+		if strings.HasPrefix(name, installedStateOperatorName+"-") {
+			revisioned = append(revisioned, operators[i])
+		}
+	}
+	if len(revisioned) == 0 {
+		return nil
+	}
+	sort.Slice(revisioned, func(i, j int) bool {
+		return revisioned[i].GetName() < revisioned[j].GetName()
+	})
+	return &revisioned[0]
+}
+
+// effectiveOperatorInput returns the IstioOperator as JSON without the status and server-side metadata, which istioctl does not accept as input.
+func effectiveOperatorInput(installed *unstructured.Unstructured) (string, error) {
+	spec, _, err := unstructured.NestedMap(installed.Object, "spec")
+	if err != nil {
+		return "", errors.Wrapf(err, "Invalid spec of IstioOperator %s", installed.GetName())
+	}
+
+	istioOperator := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	istioOperator.SetAPIVersion(installed.GetAPIVersion())
+	istioOperator.SetKind(installed.GetKind())
+	istioOperator.SetName(installed.GetName())
+	istioOperator.SetNamespace(installed.GetNamespace())
+
+	istioOperatorBytes, err := istioOperator.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(istioOperatorBytes), nil
+}
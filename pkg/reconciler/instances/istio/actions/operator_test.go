@@ -0,0 +1,151 @@
+package actions
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// istioctlMockProfileDump is the output of `istioctl profile dump` 1.17.3 for the installed-state IstioOperator, shortened.
+const istioctlMockProfileDump = `apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  name: installed-state
+  namespace: istio-system
+spec:
+  components:
+    base:
+      enabled: true
+    egressGateways:
+    - enabled: false
+      name: istio-egressgateway
+    ingressGateways:
+    - enabled: true
+      name: istio-ingressgateway
+    pilot:
+      enabled: true
+  hub: docker.io/istio
+  meshConfig:
+    defaultConfig:
+      proxyMetadata: {}
+    enablePrometheusMerge: true
+  profile: default
+  tag: 1.17.3-distroless
+  values:
+    global:
+      istioNamespace: istio-system
+`
+
+func Test_DefaultIstioPerformer_GetInstalledOperator(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should return the profile dump of the installed-state IstioOperator", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		var istioOperator string
+		cmder.On("ProfileDump", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(args mock.Arguments) { istioOperator = args.String(0) }).
+			Return([]byte(istioctlMockProfileDump), nil)
+		resolver := &recordingCommanderResolver{cmder: &cmder}
+		wrapper := fixOperatorPerformer(resolver, fixIstioOperator("installed-state", "1.17.3-distroless"), fixIstioOperator("custom", "1.16.0"))
+
+		// when
+		got, err := wrapper.GetInstalledOperator(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, istioctlMockProfileDump, got)
+		require.Equal(t, []string{"1.17.3"}, resolver.versions)
+		var passed map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(istioOperator), &passed))
+		require.Equal(t, map[string]interface{}{
+			"apiVersion": "install.istio.io/v1alpha1",
+			"kind":       "IstioOperator",
+			"metadata":   map[string]interface{}{"name": "installed-state", "namespace": "istio-system"},
+			"spec":       map[string]interface{}{"profile": "default", "tag": "1.17.3-distroless"},
+		}, passed)
+	})
+
+	t.Run("should use the first revisioned IstioOperator and the newest istioctl for tags which are no version", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProfileDump", mock.MatchedBy(func(istioOperator string) bool {
+			return json.Valid([]byte(istioOperator)) && strings.Contains(istioOperator, `"name":"installed-state-1-17"`)
+		}), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockProfileDump), nil)
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := fixOperatorPerformer(resolver, fixIstioOperator("installed-state-1-18", "latest"), fixIstioOperator("installed-state-1-17", "latest"))
+
+		// when
+		got, err := wrapper.GetInstalledOperator(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, istioctlMockProfileDump, got)
+		require.Equal(t, []string{"1.17.3"}, resolver.versions)
+	})
+
+	t.Run("should return ErrIstioNotInstalled without installed-state IstioOperator", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		wrapper := fixOperatorPerformer(&recordingCommanderResolver{cmder: &cmder}, fixIstioOperator("custom", "1.17.3"))
+
+		// when
+		_, err := wrapper.GetInstalledOperator(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrIstioNotInstalled))
+		require.Contains(t, err.Error(), "No installed-state IstioOperator found in namespace istio-system")
+		cmder.AssertNotCalled(t, "ProfileDump", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProfileDump", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		wrapper := fixOperatorPerformer(&recordingCommanderResolver{cmder: &cmder}, fixIstioOperator("installed-state", "1.17.3"))
+
+		// when
+		_, err := wrapper.GetInstalledOperator(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+	})
+}
+
+func fixOperatorPerformer(resolver CommanderResolver, operators ...runtime.Object) *DefaultIstioPerformer {
+	listKinds := map[schema.GroupVersionResource]string{istioOperatorResource: "IstioOperatorList"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, operators...)
+	dynamicProvider := clientsetmocks.DynamicProvider{}
+	dynamicProvider.On("RetrieveDynamicFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(dynamicClient, nil)
+	return NewDefaultIstioPerformer(resolver, nil, &clientsetmocks.Provider{}, WithDynamicProvider(&dynamicProvider))
+}
+
+// fixIstioOperator returns an IstioOperator as stored by istioctl, with status and server-side metadata.
+func fixIstioOperator(name, tag string) *unstructured.Unstructured {
+	istioOperator := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"profile": "default", "tag": tag},
+		"status": map[string]interface{}{"status": "HEALTHY"},
+	}}
+	istioOperator.SetAPIVersion("install.istio.io/v1alpha1")
+	istioOperator.SetKind("IstioOperator")
+	istioOperator.SetNamespace("istio-system")
+	istioOperator.SetName(name)
+	istioOperator.SetGeneration(3)
+	istioOperator.SetAnnotations(map[string]string{"install.istio.io/ignoreReconcile": "true"})
+	return istioOperator
+}
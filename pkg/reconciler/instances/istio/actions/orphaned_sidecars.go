@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (c *DefaultIstioPerformer) FindOrphanedSidecars(kubeConfig string, logger *zap.SugaredLogger) (StaleProxies, error) {
+	logger = operationLogger(logger, "FindOrphanedSidecars", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	pods, err := kubeClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list pods")
+	}
+
+	orphaned := orphanedSidecarsFrom(*pods)
+	if orphaned.Count() > 0 {
+		logger.Warnf("Found %d orphaned Istio proxies in %d namespaces, their workloads need a rolling restart", orphaned.Count(), len(orphaned))
+	} else {
+		logger.Info("Found no orphaned Istio proxies")
+	}
+
+	return orphaned, nil
+}
+
+// orphanedSidecarsFrom groups all pods still running an Istio proxy by namespace and workload, regardless of the proxy version.
+func orphanedSidecarsFrom(pods v1.PodList) StaleProxies {
+	return proxiesFrom(pods, func(string) bool {
+		return true
+	})
+}
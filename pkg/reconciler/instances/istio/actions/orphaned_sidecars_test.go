@@ -0,0 +1,98 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_orphanedSidecarsFrom(t *testing.T) {
+
+	t.Run("should group all pods with an Istio proxy regardless of its version", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPodWithProxyImage("app-5d8f-abc", "default", "istio/proxyv2:1.10.2-distroless", "ReplicaSet", "app-5d8f", "5d8f"),
+			fixPodWithProxyImage("app-5d8f-def", "default", "istio/proxyv2:1.11.4", "ReplicaSet", "app-5d8f", "5d8f"),
+			fixPodWithProxyImage("standalone", "kyma-system", "istio/proxyv2:1.11.4", "", "", ""),
+			fixPodWithProxyImage("no-proxy", "kyma-system", "nginx:1.21", "", "", ""),
+		}}
+
+		// when
+		orphaned := orphanedSidecarsFrom(pods)
+
+		// then
+		require.Equal(t, StaleProxies{
+			"default": {
+				{Kind: "Deployment", Name: "app", Proxies: []StaleProxy{
+					{Pod: "app-5d8f-abc", ProxyVersion: "1.10.2"},
+					{Pod: "app-5d8f-def", ProxyVersion: "1.11.4"},
+				}},
+			},
+			"kyma-system": {
+				{Kind: "Pod", Name: "standalone", Proxies: []StaleProxy{{Pod: "standalone", ProxyVersion: "1.11.4"}}},
+			},
+		}, orphaned)
+		require.Equal(t, 3, orphaned.Count())
+	})
+}
+
+func Test_DefaultIstioPerformer_FindOrphanedSidecars(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		_, err := wrapper.FindOrphanedSidecars(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
+
+	t.Run("should list the pods still running an Istio proxy", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.11.4-distroless", "StatefulSet", "app"),
+			fixRunningPodWithProxy("job-1", "kyma-system", "1.10.2", "Job", "job"),
+		)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		orphaned, err := wrapper.FindOrphanedSidecars(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, StaleProxies{
+			"default":     {{Kind: "StatefulSet", Name: "app", Proxies: []StaleProxy{{Pod: "app-1", ProxyVersion: "1.11.4"}}}},
+			"kyma-system": {{Kind: "Job", Name: "job", Proxies: []StaleProxy{{Pod: "job-1", ProxyVersion: "1.10.2"}}}},
+		}, orphaned)
+	})
+
+	t.Run("should return no proxies when no pod runs an Istio proxy", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		orphaned, err := wrapper.FindOrphanedSidecars(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, orphaned)
+	})
+}
@@ -7,16 +7,22 @@ import (
 	"fmt"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/manifest"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
 	v1 "k8s.io/api/admissionregistration/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientgo "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
 
@@ -24,24 +30,103 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	helmChart "helm.sh/helm/v3/pkg/chart"
-	"helm.sh/helm/v3/pkg/chart/loader"
 )
 
 const (
-	istioImagePrefix    = "istio/proxyv2"
-	retriesCount        = 5
-	delayBetweenRetries = 5 * time.Second
-	timeout             = 5 * time.Minute
-	interval            = 12 * time.Second
+	istioImagePrefix           = "istio/proxyv2"
+	defaultRetriesCount        = 5
+	defaultDelayBetweenRetries = 5 * time.Second
+	defaultTimeout             = 5 * time.Minute
+	defaultInterval            = 12 * time.Second
+
+	webhookNameToChange = "auto.sidecar-injector.istio.io"
+	defaultRevision     = "default"
+
+	defaultIstioNamespace = "istio-system"
+	istiodDeploymentName  = "istiod"
 )
 
+// IstioctlOperation names an istioctl operation with its own timeout, see WithIstioctlTimeout.
+type IstioctlOperation string
+
+const (
+	// IstioctlInstall is istioctl install, also used for rollbacks.
+	IstioctlInstall IstioctlOperation = "install"
+	// IstioctlUpgrade is istioctl upgrade.
+	IstioctlUpgrade IstioctlOperation = "upgrade"
+	// IstioctlUninstall is istioctl x uninstall.
+	IstioctlUninstall IstioctlOperation = "uninstall"
+	// IstioctlVersion is istioctl version, only bounded if the commander implements istioctl.ContextVersioner.
+	IstioctlVersion IstioctlOperation = "version"
+	// IstioctlBugReport is istioctl bug-report.
+	IstioctlBugReport IstioctlOperation = "bug-report"
+)
+
+// defaultIstioctlTimeouts bounds the fast istioctl operations by default, the others are bound by the operation timeout only.
+var defaultIstioctlTimeouts = map[IstioctlOperation]time.Duration{
+	IstioctlVersion: time.Minute,
+}
+
+// webhookCandidatesNames lists the MutatingWebhookConfigurations patched by PatchMutatingWebhook by default, in order of preference.
+var webhookCandidatesNames = []string{"istio-revision-tag-default", "istio-sidecar-injector"}
+
+// defaultWebhookPatchBackoff retries PatchMutatingWebhook with a jittered exponential backoff, so that concurrent writers of the
+// MutatingWebhookConfiguration do not retry in lockstep.
+var defaultWebhookPatchBackoff = wait.Backoff{Steps: 6, Duration: 10 * time.Millisecond, Factor: 2.0, Jitter: 0.5}
+
+// pilotVersionValuePaths lists the Istio chart values checked for the target version by default, in order of preference.
+var pilotVersionValuePaths = []string{"global.images.istio_pilot.version", "pilot.image.tag", "global.tag"}
+
+// proxyVersionValuePaths lists the Istio chart values checked for the proxy version, in order of preference.
+var proxyVersionValuePaths = []string{"global.images.istio_proxyv2.version", "global.tag"}
+
+// ErrIstioNotInstalled is returned when the Istio control plane is not installed on the cluster.
+var ErrIstioNotInstalled = errors.New("Istio control plane is not installed")
+
 type VersionType string
 
+// IstioStatus is the state of the Istio installation on the cluster reported by Version.
+// TargetVersion is the concrete Istio version the cluster is reconciled to: a version constraint like "1.17.x" is resolved to the
+// newest available istioctl version matching it, so TargetVersion can be passed on to Install, Update and ResetProxy as is.
+// The version as it was requested is kept in RequestedTargetVersion.
 type IstioStatus struct {
 	ClientVersion    string
 	TargetVersion    string
 	PilotVersion     string
 	DataPlaneVersion string
+	// DataPlanePresent is false if no Istio proxy is running on the cluster, e.g. after a fresh installation without workloads.
+	// An empty DataPlaneVersion with DataPlanePresent set to true means that the data plane version could not be determined.
+	DataPlanePresent bool
+	// TargetVersionSource tells where TargetVersion was resolved from.
+	TargetVersionSource TargetVersionSource
+	// TargetVersionValuePath is the path of the Istio chart value TargetVersion was read from, if TargetVersionSource is TargetVersionSourceValues.
+	TargetVersionValuePath string
+	// RequestedTargetVersion is the target version as read from TargetVersionSource before it was resolved, equal to TargetVersion for exact versions.
+	RequestedTargetVersion string
+	// BinaryPath is the path of the istioctl binary resolved for TargetVersion, which reported ClientVersion.
+	// Empty if the commander does not implement istioctl.BinaryPathReporter.
+	BinaryPath string
+}
+
+// TargetVersionSource describes where the target Istio version was resolved from.
+type TargetVersionSource string
+
+const (
+	// TargetVersionSourceOverride means that the target version was explicitly overridden.
+	TargetVersionSourceOverride TargetVersionSource = "override"
+	// TargetVersionSourceValues means that the target version was taken from the pilot image tag in the Istio chart values.
+	TargetVersionSourceValues TargetVersionSource = "values"
+	// TargetVersionSourceAppVersion means that the target version was taken from the appVersion of the Istio chart definition.
+	TargetVersionSourceAppVersion TargetVersionSource = "appVersion"
+)
+
+// IstioVersionDetails holds the IstioStatus together with the istioctl version output it was mapped from.
+type IstioVersionDetails struct {
+	Status IstioStatus
+	// Output is the parsed istioctl version output, including the fields not mapped to Status, e.g. the versions of all proxies.
+	Output IstioVersionOutput
+	// Raw is the unmodified output of istioctl version.
+	Raw []byte
 }
 
 type IstioVersionOutput struct {
@@ -50,336 +135,1585 @@ type IstioVersionOutput struct {
 	DataPlaneVersion []*DataPlaneVersion `json:"dataPlaneVersion,omitempty"`
 }
 
-type ClientVersion struct {
-	Version string `json:"version"`
+type ClientVersion struct {
+	Version string `json:"version"`
+}
+
+type MeshComponent struct {
+	Component string    `json:"Component,omitempty"`
+	Info      *MeshInfo `json:"Info,omitempty"`
+}
+
+type MeshInfo struct {
+	Version string `json:"version,omitempty"`
+}
+
+type DataPlaneVersion struct {
+	ID           string `json:"ID,omitempty"`
+	IstioVersion string `json:"IstioVersion,omitempty"`
+}
+
+//go:generate mockery --name=IstioPerformer --outpkg=mock --case=underscore
+// IstioPerformer performs actions on Istio component on the cluster.
+type IstioPerformer interface {
+
+	// Install Istio in given version on the cluster using istioChart.
+	// If hub is not empty, the Istio images are pulled from it instead of the hub of the istioChart, e.g. from a private registry.
+	Install(kubeConfig, istioChart, version, hub string, logger *zap.SugaredLogger) error
+
+	// InstallFromManifest installs Istio in given version on the cluster using the pre-rendered IstioOperator manifest, e.g. produced by an external pipeline.
+	// The manifest is passed to istioctl as is, neither the hub override nor the manifest transformers of the performer are applied.
+	InstallFromManifest(istioOperatorManifest, kubeConfig, version string, logger *zap.SugaredLogger) error
+
+	// InstallWithOptions installs Istio like Install, with the parameters and additional settings passed as InstallOptions.
+	InstallWithOptions(opts InstallOptions, logger *zap.SugaredLogger) error
+
+	// ApplyObservability applies the ServiceMonitors and Grafana dashboards of the istioChart to the cluster and prunes the ones no longer part of it.
+	// It does nothing if the monitoring CRDs are not installed on the cluster.
+	ApplyObservability(kubeConfig, istioChart string, logger *zap.SugaredLogger) error
+
+	// PatchMutatingWebhook patches Istio's webhook configuration. The result reports whether the webhook configuration was changed by this call.
+	// An empty or "default" revision patches the configured webhook candidates, any other revision the webhook configuration of that revision.
+	PatchMutatingWebhook(ctx context.Context, kubeClient kubernetes.Client, revision string, logger *zap.SugaredLogger) (WebhookPatchResult, error)
+
+	// PreviewMutatingWebhookPatch reports the change PatchMutatingWebhook would apply to Istio's webhook configuration of the revision, without applying it.
+	PreviewMutatingWebhookPatch(ctx context.Context, kubeClient kubernetes.Client, revision string, logger *zap.SugaredLogger) (WebhookPatchPreview, error)
+
+	// WaitForWebhookInjection waits until Istio's webhook injects sidecars into the pods of the namespace, e.g. after PatchMutatingWebhook, or returns when ctx is done.
+	// The namespace must have sidecar injection enabled. A timeout of zero uses the readiness timeout of the performer.
+	WaitForWebhookInjection(ctx context.Context, kubeClient kubernetes.Client, namespace string, timeout time.Duration, logger *zap.SugaredLogger) error
+
+	// SidecarInjectionStatus reports for the given namespaces whether sidecar injection is enabled by their labels and which webhooks of Istio's webhook configuration select them.
+	SidecarInjectionStatus(ctx context.Context, kubeClient kubernetes.Client, namespaces []string, logger *zap.SugaredLogger) (SidecarInjectionStatus, error)
+
+	// SetInjection enables or disables the sidecar injection of the namespace by its labels, using the revision label if a revision is given.
+	// The istio-injection and istio.io/rev labels are mutually exclusive, so the other label is removed. Returns the previous labels of the namespace.
+	SetInjection(kubeConfig, namespace string, enabled bool, revision string, logger *zap.SugaredLogger) (NamespaceInjectionLabels, error)
+
+	// WaitForReady waits until istiod and the installed Istio gateways have available replicas, or returns when ctx is done.
+	// A timeout of zero uses the readiness timeout of the performer. The returned error lists the components which did not become ready.
+	// The optional progress callback is called on each poll, the last observed state of the components is returned.
+	WaitForReady(ctx context.Context, kubeConfig string, timeout time.Duration, progress ReadinessProgressFunc, logger *zap.SugaredLogger) ([]ComponentReadiness, error)
+
+	// VerifyInstall reports for each CRD, deployment and webhook configuration expected from an Istio installation whether it is present, missing or mismatching.
+	// If version is not empty, Istio deployments running another version are reported as mismatching.
+	VerifyInstall(kubeConfig, version string, logger *zap.SugaredLogger) (InstallVerification, error)
+
+	// GetInstalledOperator returns the effective IstioOperator of the Istio installation on the cluster as YAML, e.g. to detect drift from the Istio chart.
+	// Returns an error wrapping ErrIstioNotInstalled if no installed-state IstioOperator exists.
+	GetInstalledOperator(kubeConfig string, logger *zap.SugaredLogger) (string, error)
+
+	// MTLSMode reports the mesh-wide mutual TLS mode, which is one of MTLSModeStrict, MTLSModePermissive or MTLSModeDisabled.
+	MTLSMode(kubeConfig string, logger *zap.SugaredLogger) (string, error)
+
+	// Update Istio on the cluster to the targetVersion using istioChart.
+	// If hub is not empty, the Istio images are pulled from it instead of the hub of the istioChart.
+	// If autoRollback is true and the update fails, the previously installed version is re-installed.
+	Update(kubeConfig, istioChart, targetVersion, hub string, autoRollback bool, logger *zap.SugaredLogger) error
+
+	// UpdateWithOptions updates Istio like Update, with the parameters and additional settings passed as UpdateOptions.
+	UpdateWithOptions(opts UpdateOptions, logger *zap.SugaredLogger) error
+
+	// UpdateAlongPath updates Istio on the cluster from the currentVersion to the targetVersion using istioChart, stepping through all intermediate minor versions.
+	// Between the steps it waits until the Istio control plane is ready.
+	UpdateAlongPath(kubeConfig, istioChart, currentVersion, targetVersion, hub string, logger *zap.SugaredLogger) error
+
+	// ResetProxy resets Istio proxy of all Istio sidecars on the cluster. The proxyImageVersion parameter controls the Istio proxy version, it always adds "-distroless" suffix to the provided value.
+	// The reset is skipped if all proxies already run the proxyImageVersion, unless force is true.
+	// No proxy is reset while istiod is not available, as restarting sidecars during a control plane incident makes it worse, unless force is true.
+	// If labelSelector is not empty, only the sidecars of the pods matching it are reset.
+	// If only some of the sidecars could not be reset, the returned error wraps a reset.AggregatedError.
+	ResetProxy(context context.Context, kubeConfig string, proxyImageVersion string, labelSelector string, force bool, logger *zap.SugaredLogger) (ProxyResetResult, error)
+
+	// ResetProxyFromFile resets Istio proxies like ResetProxy, using the kubeconfig read from the file at kubeConfigPath.
+	ResetProxyFromFile(context context.Context, kubeConfigPath string, proxyImageVersion string, labelSelector string, force bool, logger *zap.SugaredLogger) (ProxyResetResult, error)
+
+	// ResetProxyFromChart resets Istio proxies like ResetProxy, to the proxy version read from the values of the istioChart.
+	ResetProxyFromChart(context context.Context, workspace chart.Factory, branch, istioChart, kubeConfig, labelSelector string, force bool, logger *zap.SugaredLogger) (ProxyResetResult, error)
+
+	// CleanupResetArtifacts removes the checkpoints of interrupted proxy resets from the cluster, which are otherwise kept until a reset to the same version completes.
+	// A later ResetProxy does not resume the interrupted resets, but still only resets the proxies not running its version.
+	CleanupResetArtifacts(kubeConfig string, logger *zap.SugaredLogger) error
+
+	// RestartGateways restarts the ingress and egress gateway deployments one after another, waiting for each rollout to complete.
+	// Returns the names of the restarted gateways, gateways which are not installed are skipped.
+	RestartGateways(kubeConfig string, logger *zap.SugaredLogger) ([]string, error)
+
+	// Version reports status of Istio installation on the cluster.
+	// If versionOverride is not empty, it is used as the target version instead of the version resolved from the istioChart.
+	// If the Istio control plane is not installed, the status is returned together with ErrIstioNotInstalled.
+	Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, versionOverride string, logger *zap.SugaredLogger) (IstioStatus, error)
+
+	// VersionDetailed reports status of Istio installation on the cluster like Version, together with the istioctl version output.
+	VersionDetailed(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, versionOverride string, logger *zap.SugaredLogger) (IstioVersionDetails, error)
+
+	// VersionDrift reports which Istio planes on the cluster are out of date compared to the target version of the istioChart, and by how many minor versions.
+	VersionDrift(workspace chart.Factory, branch, istioChart, kubeConfig string, logger *zap.SugaredLogger) (DriftReport, error)
+
+	// Uninstall Istio from the cluster and its corresponding resources, using given Istio version.
+	// The CRDs of the well-known Istio API groups are removed as well if the performer was created WithCRDPurge.
+	Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error
+
+	// EstimateDisruption reports how many pods, workloads and namespaces would be affected by ResetProxy to the targetProxyVersion, without performing it.
+	EstimateDisruption(kubeConfig, targetProxyVersion string, logger *zap.SugaredLogger) (DisruptionEstimate, error)
+
+	// ListStaleProxies lists the Istio proxies on the cluster running in another version than expectedVersion, grouped by namespace and workload.
+	ListStaleProxies(kubeConfig, expectedVersion string, logger *zap.SugaredLogger) (StaleProxies, error)
+
+	// FindOrphanedSidecars lists all Istio proxies still running on the cluster, grouped by namespace and workload.
+	// After Uninstall, these proxies point to a removed control plane and their workloads need a rolling restart.
+	FindOrphanedSidecars(kubeConfig string, logger *zap.SugaredLogger) (StaleProxies, error)
+
+	// SidecarInventory counts the Istio sidecars on the cluster per namespace and proxy version, e.g. for capacity planning.
+	// Namespaces without sidecars are not part of the result.
+	SidecarInventory(kubeConfig string, logger *zap.SugaredLogger) (map[string]NamespaceSidecarStats, error)
+
+	// ProxySyncSummary reports aggregated config sync status of all Istio proxies on the cluster, using the newest istioctl.
+	ProxySyncSummary(kubeConfig string, logger *zap.SugaredLogger) (SyncSummary, error)
+
+	// Analyze runs `istioctl analyze` in given Istio version for the namespaces, or all namespaces if none are given, and returns the found misconfigurations.
+	Analyze(kubeConfig, version string, namespaces []string, logger *zap.SugaredLogger) ([]AnalysisMessage, error)
+
+	// PreCheck runs `istioctl x precheck` with the newest available istioctl and returns the found cluster incompatibilities.
+	// Installing Istio is not safe if any of the messages has error severity, see PreCheckErrors.
+	PreCheck(kubeConfig string, logger *zap.SugaredLogger) ([]PreCheckMessage, error)
+
+	// ProxyConfigDump returns the raw JSON Envoy config dump of the Istio proxy of the pod in the namespace, using given Istio version.
+	ProxyConfigDump(kubeConfig, version, namespace, pod string, logger *zap.SugaredLogger) ([]byte, error)
+
+	// ProxyConfigDumpWithCompression returns the config dump like ProxyConfigDump, gzip-compressed if compress is true, e.g. when collecting the dumps of many pods.
+	ProxyConfigDumpWithCompression(kubeConfig, version, namespace, pod string, compress bool, logger *zap.SugaredLogger) (ConfigDump, error)
+
+	// BugReport runs `istioctl bug-report` with the newest available istioctl and returns the path of the diagnostic archive created in outputDir.
+	// The outputDir is created if it does not exist. istioctl is bound by the IstioctlBugReport timeout, or the operation timeout if none is set.
+	// It fails when istioctl runs through an exec wrapper, as the archive would be created on the remote host.
+	BugReport(kubeConfig, outputDir string, logger *zap.SugaredLogger) (string, error)
+
+	// ValidateKubeconfig checks that the kubeconfig can be parsed and its API server is reachable,
+	// so callers can fail fast with a clear message instead of failing deep inside istioctl.
+	// The API server is given at most the kubeconfig validation timeout to answer.
+	ValidateKubeconfig(kubeConfig string, logger *zap.SugaredLogger) error
+}
+
+// InstallOptions holds the parameters of InstallWithOptions.
+type InstallOptions struct {
+	// Context bounds the istioctl call in addition to the operation timeout of the performer. Defaults to context.Background().
+	Context context.Context
+	// KubeConfig of the cluster
+	KubeConfig string
+	// IstioChart is the rendered manifest containing the IstioOperator
+	IstioChart string
+	// Version of Istio to install
+	Version string
+	// Hub the Istio images are pulled from instead of the hub of the IstioChart, if not empty
+	Hub string
+	// Overlays are IstioOperator manifests merged over the IstioOperator of the IstioChart in the given order, before the Hub is set.
+	Overlays []string
+	// DryRun renders and validates the IstioOperator and resolves the istioctl binary, without calling it.
+	DryRun bool
+}
+
+// UpdateOptions holds the parameters of UpdateWithOptions.
+type UpdateOptions struct {
+	// Context bounds the istioctl call in addition to the operation timeout of the performer. Defaults to context.Background().
+	Context context.Context
+	// KubeConfig of the cluster
+	KubeConfig string
+	// IstioChart is the rendered manifest containing the IstioOperator
+	IstioChart string
+	// Version of Istio to update to
+	Version string
+	// Hub the Istio images are pulled from instead of the hub of the IstioChart, if not empty
+	Hub string
+	// Overlays are IstioOperator manifests merged over the IstioOperator of the IstioChart in the given order, before the Hub is set.
+	Overlays []string
+	// DryRun renders and validates the IstioOperator and resolves the istioctl binary, without calling it.
+	DryRun bool
+	// AutoRollback re-installs the previously installed version if the update fails.
+	AutoRollback bool
+}
+
+// ProxyResetResult describes the outcome of ResetProxy.
+type ProxyResetResult struct {
+	// NoResetNeeded is true if the reset was skipped because all Istio proxies already run the requested version.
+	NoResetNeeded bool
+	// StaleProxies is the number of Istio proxies which did not run the requested version before the reset.
+	StaleProxies int
+}
+
+// WebhookPatchPreview describes the namespace selector change of PatchMutatingWebhook.
+type WebhookPatchPreview struct {
+	// WebhookConfiguration is the name of the selected MutatingWebhookConfiguration.
+	WebhookConfiguration string
+	// Webhook is the name of the patched webhook inside the WebhookConfiguration.
+	Webhook string
+	// Requirement is the namespace selector requirement added by the patch.
+	Requirement metav1.LabelSelectorRequirement
+	// AlreadyPresent is true if the Requirement is already part of the namespace selector, so the patch would not change anything.
+	AlreadyPresent bool
+}
+
+// WebhookPatchResult describes the outcome of PatchMutatingWebhook.
+type WebhookPatchResult struct {
+	// WebhookConfiguration is the name of the selected MutatingWebhookConfiguration.
+	WebhookConfiguration string
+	// Changed is false if the webhook configuration was already patched, e.g. by a previous reconciliation.
+	Changed bool
+	// ModifiedWebhooks is the number of webhook entries whose namespace selector was modified by the patch.
+	ModifiedWebhooks int
+	// CompliantWebhooks is the number of webhook entries whose namespace selector already contained the required requirement.
+	CompliantWebhooks int
+	// Configuration is the MutatingWebhookConfiguration as returned by the server after the update,
+	// or as read from the server if nothing changed.
+	Configuration *v1.MutatingWebhookConfiguration
+}
+
+// CommanderResolver interface implementations must be able to provide istioctl.Commander instances for given istioctl.Version
+type CommanderResolver interface {
+	// GetCommander function returns istioctl.Commander instance for given istioctl version if supported, returns an error otherwise.
+	GetCommander(version istioctl.Version) (istioctl.Commander, error)
+
+	// IsVersionSupported returns true if an istioctl.Commander can be provided for the given istioctl version.
+	IsVersionSupported(version string) bool
+
+	// ResolveVersion returns the istioctl version for the given exact version or version constraint, e.g. "1.17.x" resolves to the newest available 1.17 patch.
+	ResolveVersion(constraint string) (istioctl.Version, error)
+}
+
+// DefaultIstioPerformer provides a default implementation of IstioPerformer.
+// It uses istioctl binary to do it's job. It delegates the job of finding proper istioctl binary for given operation to the configured CommandResolver.
+//
+// Install, InstallFromManifest, InstallWithOptions, Update, UpdateWithOptions, UpdateAlongPath, Uninstall, ResetProxy and RestartGateways are serialized per cluster, identified by the passed kubeconfig, across all performer instances.
+// An operation waits until the running operation on the same cluster is finished, operations on different clusters run in parallel.
+// The remaining methods only read the cluster state and are not serialized.
+type DefaultIstioPerformer struct {
+	resolver           CommanderResolver
+	istioProxyReset    proxy.IstioProxyReset
+	provider           clientset.Provider
+	kubeconfigResolver clientset.KubeconfigResolver
+	namespace          string
+	imageChecker       ImageChecker
+	webhookPatchHook   func(WebhookPatchResult)
+	dynamicProvider    clientset.DynamicProvider
+	transformers       []ManifestTransformer
+	webhookCandidates  []string
+	webhookBackoff     wait.Backoff
+	clusterLocks       *clusterLocks
+	versionValuePaths  []string
+	istioctlEnv        map[string]string
+
+	retriesCount        int
+	delayBetweenRetries time.Duration
+	retryBackoff        istioConfig.RetryBackoff
+	maxRetryDelay       time.Duration
+	retryJitter         time.Duration
+	respectRetryAfter   bool
+	maxRetryAfter       time.Duration
+	timeout             time.Duration
+	interval            time.Duration
+	operationTimeout    time.Duration
+	validationTimeout   time.Duration
+	istioctlTimeouts    map[IstioctlOperation]time.Duration
+	resetOrder          istioConfig.ResetOrder
+	resetDeadline       time.Duration
+	respectPDB          bool
+	readinessTimeout    time.Duration
+	readinessInterval   time.Duration
+
+	namespaceDeletionTimeout     time.Duration
+	namespaceDeletionInterval    time.Duration
+	namespaceDeletionPropagation metav1.DeletionPropagation
+	purgeCRDs                    bool
+	crdPurgeGracePeriod          time.Duration
+	crdPurgeInterval             time.Duration
+
+	versionConcurrency    int
+	versionClusterTimeout time.Duration
+
+	controlPlaneMatchPolicy VersionMatchPolicy
+	dataPlaneMatchPolicy    VersionMatchPolicy
+}
+
+// ManifestTransformer post-processes the IstioOperator manifest before it is passed to istioctl, e.g. to inject imagePullSecrets or a mesh ID.
+// Returning an error aborts the operation.
+type ManifestTransformer func(manifest string) (string, error)
+
+// PerformerOption configures the DefaultIstioPerformer.
+type PerformerOption func(*DefaultIstioPerformer)
+
+// WithNamespace sets the namespace of the Istio control plane, if it was relocated from istio-system.
+func WithNamespace(namespace string) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.namespace = namespace
+	}
+}
+
+// WithProxyImageCheck enables the check that the target proxy image is pullable before any pod is reset by ResetProxy.
+func WithProxyImageCheck(imageChecker ImageChecker) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.imageChecker = imageChecker
+	}
+}
+
+// WithWebhookPatchHook sets a hook which is called with the result of every successful PatchMutatingWebhook, e.g. to persist or count it.
+func WithWebhookPatchHook(hook func(WebhookPatchResult)) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.webhookPatchHook = hook
+	}
+}
+
+// WithWebhookCandidates sets the names of the MutatingWebhookConfigurations patched by PatchMutatingWebhook, in order of preference,
+// e.g. for clusters using a custom revision tag.
+func WithWebhookCandidates(names ...string) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.webhookCandidates = names
+	}
+}
+
+// WithWebhookPatchBackoff sets the backoff of PatchMutatingWebhook, which retries on conflicting updates
+// and on MutatingWebhookConfigurations deleted before they could be updated. At least one attempt is made, even if the backoff has no steps.
+func WithWebhookPatchBackoff(backoff wait.Backoff) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		if backoff.Steps < 1 {
+			backoff.Steps = 1
+		}
+		c.webhookBackoff = backoff
+	}
+}
+
+// WithVersionValuePaths sets the dot-separated paths of the Istio chart values checked for the target version, in order of preference,
+// e.g. for charts which keep the pilot image tag under another key. The appVersion of the Istio chart is used if none of them is set.
+func WithVersionValuePaths(paths ...string) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.versionValuePaths = paths
+	}
+}
+
+// WithDynamicProvider sets the DynamicProvider used by ApplyObservability to apply the monitoring resources.
+func WithDynamicProvider(dynamicProvider clientset.DynamicProvider) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.dynamicProvider = dynamicProvider
+	}
+}
+
+// WithManifestTransformers appends transformers which are applied in order to the IstioOperator manifest before istioctl install and upgrade.
+func WithManifestTransformers(transformers ...ManifestTransformer) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.transformers = append(c.transformers, transformers...)
+	}
+}
+
+// WithIstioctlEnv sets environment variables for all istioctl invocations, e.g. ISTIOCTL_ENABLE_ALPHA_COMMANDS or proxy settings.
+// They are set on top of the inherited environment, PATH is never overridden.
+func WithIstioctlEnv(env map[string]string) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.istioctlEnv = make(map[string]string, len(env))
+		for name, value := range env {
+			c.istioctlEnv[name] = value
+		}
+	}
+}
+
+// WithProxyResetTimeout sets the timeout for waiting on restarted pods during the proxy reset and the interval between the checks.
+func WithProxyResetTimeout(timeout, interval time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.timeout = timeout
+		c.interval = interval
+	}
+}
+
+// WithProxyResetRetries sets how often and with which delay the proxy reset retries failed pod operations.
+func WithProxyResetRetries(retriesCount int, delayBetweenRetries time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.retriesCount = retriesCount
+		c.delayBetweenRetries = delayBetweenRetries
+	}
+}
+
+// WithProxyResetBackoff makes the proxy reset double the delay between retries of failed pod operations up to maxDelay, adding a random jitter of at most jitter to every delay.
+// A zero maxDelay leaves the backoff uncapped, a zero jitter disables it.
+func WithProxyResetBackoff(maxDelay, jitter time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.retryBackoff = istioConfig.ExponentialBackoff
+		c.maxRetryDelay = maxDelay
+		c.retryJitter = jitter
+	}
+}
+
+// WithProxyResetRetryAfter makes the proxy reset wait for the Retry-After suggested by the API server before retrying a throttled pod operation,
+// at most for maxRetryAfter. A zero maxRetryAfter leaves the Retry-After uncapped.
+func WithProxyResetRetryAfter(maxRetryAfter time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.respectRetryAfter = true
+		c.maxRetryAfter = maxRetryAfter
+	}
+}
+
+// WithProxyResetOrder sets the order in which the pods are reset during the proxy reset.
+func WithProxyResetOrder(order istioConfig.ResetOrder) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.resetOrder = order
+	}
+}
+
+// WithProxyResetDeadline sets the overall deadline of the proxy reset, after which no new pod reset is started. A zero deadline disables it.
+func WithProxyResetDeadline(deadline time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.resetDeadline = deadline
+	}
+}
+
+// WithPodDisruptionBudgets makes the proxy reset evict pods and delay rollouts while a PodDisruptionBudget allows no disruption.
+// The reset waits at most for the proxy reset timeout.
+func WithPodDisruptionBudgets() PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.respectPDB = true
+	}
+}
+
+// WithReadinessTimeout sets the timeout for waiting on the Istio control plane between the steps of UpdateAlongPath and the interval between the checks.
+func WithReadinessTimeout(timeout, interval time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.readinessTimeout = timeout
+		c.readinessInterval = interval
+	}
+}
+
+// WithNamespaceDeletionTimeout makes Uninstall wait until the Istio namespace is removed, checking it in the given interval.
+// If the namespace is still present after the timeout, e.g. because of stuck finalizers, Uninstall returns a NamespaceDeletionTimeoutError.
+// A zero timeout returns right after the deletion of the namespace was requested.
+func WithNamespaceDeletionTimeout(timeout, interval time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.namespaceDeletionTimeout = timeout
+		c.namespaceDeletionInterval = interval
+	}
+}
+
+// WithBackgroundNamespaceDeletion makes Uninstall delete the Istio namespace with background instead of foreground propagation,
+// so the namespace is not kept until all of its dependents are deleted.
+func WithBackgroundNamespaceDeletion() PerformerOption {
+	return WithNamespaceDeletionPropagation(metav1.DeletePropagationBackground)
+}
+
+// WithNamespaceDeletionPropagation sets the propagation policy Uninstall deletes the Istio namespace with, which defaults to foreground propagation.
+// Uninstall fails before anything is removed if the policy is not one of Foreground, Background or Orphan, see ParseDeletionPropagation.
+func WithNamespaceDeletionPropagation(policy metav1.DeletionPropagation) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.namespaceDeletionPropagation = policy
+	}
+}
+
+// WithCRDPurge makes Uninstall remove the CRDs of the well-known Istio API groups left behind by istioctl,
+// so they do not block a reinstallation. CRDs of other groups are never touched.
+func WithCRDPurge() PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.purgeCRDs = true
+	}
+}
+
+// WithCRDPurgeGracePeriod sets how long the CRD purge waits for a deleted CRD to disappear, checking it in the given interval.
+// The customresourcecleanup finalizer of a CRD still present after the grace period is cleared, which skips the deletion of its custom resources.
+func WithCRDPurgeGracePeriod(gracePeriod, interval time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.crdPurgeGracePeriod = gracePeriod
+		c.crdPurgeInterval = interval
+	}
+}
+
+// WithOperationTimeout sets the deadline for istioctl install, upgrade and uninstall, unless they have their own timeout set by WithIstioctlTimeout.
+// A zero timeout disables the deadline.
+func WithOperationTimeout(operationTimeout time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.operationTimeout = operationTimeout
+	}
+}
+
+// WithIstioctlTimeout sets the deadline of a single istioctl operation, overriding the operation timeout, e.g. a tight one for version and a generous one for install.
+// A zero timeout disables the deadline of the operation. istioctl version is bound to one minute by default.
+func WithIstioctlTimeout(operation IstioctlOperation, timeout time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.istioctlTimeouts[operation] = timeout
+	}
+}
+
+// WithKubeconfigResolver sets the KubeconfigResolver used to resolve passed kubeconfigs, e.g. from a Secret reference.
+func WithKubeconfigResolver(kubeconfigResolver clientset.KubeconfigResolver) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.kubeconfigResolver = kubeconfigResolver
+	}
+}
+
+// NewDefaultIstioPerformer creates a new instance of the DefaultIstioPerformer.
+func NewDefaultIstioPerformer(resolver CommanderResolver, istioProxyReset proxy.IstioProxyReset, provider clientset.Provider, opts ...PerformerOption) *DefaultIstioPerformer {
+	performer := &DefaultIstioPerformer{
+		resolver:            resolver,
+		istioProxyReset:     istioProxyReset,
+		provider:            provider,
+		kubeconfigResolver:  &clientset.RawKubeconfigResolver{},
+		dynamicProvider:     &clientset.DefaultProvider{},
+		webhookCandidates:   webhookCandidatesNames,
+		webhookBackoff:      defaultWebhookPatchBackoff,
+		versionValuePaths:   pilotVersionValuePaths,
+		clusterLocks:        defaultClusterLocks,
+		namespace:           defaultIstioNamespace,
+		retriesCount:        defaultRetriesCount,
+		delayBetweenRetries: defaultDelayBetweenRetries,
+		timeout:             defaultTimeout,
+		interval:            defaultInterval,
+		readinessTimeout:    defaultTimeout,
+		readinessInterval:   defaultInterval,
+		versionConcurrency:  defaultVersionConcurrency,
+		validationTimeout:   defaultValidationTimeout,
+		istioctlTimeouts:    copyIstioctlTimeouts(defaultIstioctlTimeouts),
+
+		controlPlaneMatchPolicy: VersionMatchExact,
+		dataPlaneMatchPolicy:    VersionMatchExact,
+
+		namespaceDeletionPropagation: metav1.DeletePropagationForeground,
+		crdPurgeGracePeriod:          defaultCRDPurgeGracePeriod,
+		crdPurgeInterval:             defaultCRDPurgeInterval,
+	}
+	for _, opt := range opts {
+		opt(performer)
+	}
+	return performer
+}
+
+// resolveKubeconfig resolves the passed kubeconfig and returns the logger with the fields of the resolved cluster,
+// if they could not be added by operationLogger as the kubeconfig was a reference.
+func (c *DefaultIstioPerformer) resolveKubeconfig(kubeConfig string, logger *zap.SugaredLogger) (string, *zap.SugaredLogger, error) {
+	resolved, err := c.kubeconfigResolver.Resolve(kubeConfig, logger)
+	if err != nil {
+		return "", logger, errors.Wrap(err, "Could not resolve kubeconfig")
+	}
+	if resolved != kubeConfig {
+		logger = logger.With(clusterFields(resolved)...)
+	}
+	return resolved, logger, nil
+}
+
+// resolveVersion resolves the requested version, which may be a version constraint, to the concrete istioctl version.
+func (c *DefaultIstioPerformer) resolveVersion(version string) (istioctl.Version, error) {
+	resolved, err := c.resolver.ResolveVersion(version)
+	if err != nil {
+		return istioctl.Version{}, errors.Wrap(err, "Error parsing version")
+	}
+	return resolved, nil
+}
+
+func (c *DefaultIstioPerformer) getCommander(version istioctl.Version) (istioctl.Commander, error) {
+	commander, err := c.resolver.GetCommander(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "No istioctl binary available for the requested Istio version %s, "+
+			"provision an istioctl binary of the same minor version for the reconciler", version.String())
+	}
+	// only the DefaultCommander runs istioctl processes, other commanders have no environment to set
+	if defaultCommander, ok := commander.(*istioctl.DefaultCommander); ok && len(c.istioctlEnv) > 0 {
+		// the resolver may hand out the same commander to every caller, so the environment is set on a copy
+		copied := *defaultCommander
+		return copied.WithEnv(c.istioctlEnv), nil
+	}
+	return commander, nil
+}
+
+// istioOperatorManifestFrom extracts the IstioOperator manifest from the istioChart, merges the overlays over it, overrides its hub if set
+// and applies the configured transformers to it.
+func (c *DefaultIstioPerformer) istioOperatorManifestFrom(istioChart, hub string, overlays ...string) (string, error) {
+	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
+	if err != nil {
+		return "", err
+	}
+	for i, overlay := range overlays {
+		istioOperatorManifest, err = manifest.MergeIstioOperatorOverlay(istioOperatorManifest, overlay)
+		if err != nil {
+			return "", errors.Wrapf(err, "Could not merge IstioOperator overlay %d of %d", i+1, len(overlays))
+		}
+	}
+	if hub != "" {
+		istioOperatorManifest, err = manifest.SetIstioOperatorHub(istioOperatorManifest, hub)
+		if err != nil {
+			return "", err
+		}
+	}
+	for i, transform := range c.transformers {
+		istioOperatorManifest, err = transform(istioOperatorManifest)
+		if err != nil {
+			return "", errors.Wrapf(err, "Manifest transformer %d of %d failed", i+1, len(c.transformers))
+		}
+	}
+	return istioOperatorManifest, nil
+}
+
+func (c *DefaultIstioPerformer) operationContext() (context.Context, context.CancelFunc) {
+	return c.operationContextFrom(context.Background())
+}
+
+// operationContextFrom derives the context of an istioctl call from parent, bounded by the operation timeout if set. A nil parent is treated as context.Background().
+func (c *DefaultIstioPerformer) operationContextFrom(parent context.Context) (context.Context, context.CancelFunc) {
+	return contextWithTimeout(parent, c.operationTimeout)
+}
+
+// istioctlContextFrom derives the context of the istioctl operation from parent, bounded by the timeout of the operation if one is set,
+// or by the operation timeout otherwise.
+func (c *DefaultIstioPerformer) istioctlContextFrom(parent context.Context, operation IstioctlOperation) (context.Context, context.CancelFunc) {
+	timeout, found := c.istioctlTimeouts[operation]
+	if !found {
+		timeout = c.operationTimeout
+	}
+	return contextWithTimeout(parent, timeout)
+}
+
+// contextWithTimeout derives a context from parent with the timeout, or without deadline if the timeout is not positive. A nil parent is treated as context.Background().
+func contextWithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+func copyIstioctlTimeouts(timeouts map[IstioctlOperation]time.Duration) map[IstioctlOperation]time.Duration {
+	copied := make(map[IstioctlOperation]time.Duration, len(timeouts))
+	for operation, timeout := range timeouts {
+		copied[operation] = timeout
+	}
+	return copied
+}
+
+// istioctlVersion calls istioctl version, bounded by the timeout of the version operation and the parent context if the commander can abort it.
+// A failed call is classified into an ExitError or a TimeoutError.
+func (c *DefaultIstioPerformer) istioctlVersion(parent context.Context, commander istioctl.Commander, kubeConfig string, logger *zap.SugaredLogger) ([]byte, error) {
+	var versionOutput []byte
+	var err error
+	if versioner, ok := commander.(istioctl.ContextVersioner); ok {
+		ctx, cancel := c.istioctlContextFrom(parent, IstioctlVersion)
+		defer cancel()
+		versionOutput, err = versioner.VersionContext(ctx, kubeConfig, logger)
+	} else {
+		versionOutput, err = commander.Version(kubeConfig, logger)
+	}
+	if err != nil {
+		return nil, istioctlError("version", err)
+	}
+	return versionOutput, nil
+}
+
+func (c *DefaultIstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error {
+	logger = operationLogger(logger, "Uninstall", version, kubeClientSet.Kubeconfig())
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeClientSet.Kubeconfig(), logger)
+	if err != nil {
+		return err
+	}
+
+	unlock := c.clusterLocks.lock(kubeConfig)
+	defer unlock()
+
+	logger.Debug("Starting Istio uninstallation...")
+
+	if err := validateDeletionPropagation(c.namespaceDeletionPropagation); err != nil {
+		return err
+	}
+
+	execVersion, err := c.resolveVersion(version)
+	if err != nil {
+		return err
+	}
+
+	commander, err := c.getCommander(execVersion)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.istioctlContextFrom(context.Background(), IstioctlUninstall)
+	defer cancel()
+
+	err = commander.Uninstall(ctx, kubeConfig, logger)
+	if err != nil {
+		return istioctlError("uninstall", err)
+	}
+	logger.Debug("Istio uninstall triggered")
+	kubeClient, err := kubeClientSet.Clientset()
+	if err != nil {
+		return err
+	}
+
+	if c.purgeCRDs {
+		// the purge waits for the CRDs, so it must not be bound to the deadline of istioctl uninstall
+		err = c.purgeIstioCRDs(context.Background(), kubeConfig, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.deleteNamespace(kubeClient, logger)
+	if err != nil {
+		return err
+	}
+	logger.Info("Istio successfully uninstalled")
+	return nil
+}
+
+func (c *DefaultIstioPerformer) Install(kubeConfig, istioChart, version, hub string, logger *zap.SugaredLogger) error {
+	return c.InstallWithOptions(InstallOptions{
+		KubeConfig: kubeConfig,
+		IstioChart: istioChart,
+		Version:    version,
+		Hub:        hub,
+	}, logger)
+}
+
+func (c *DefaultIstioPerformer) InstallWithOptions(opts InstallOptions, logger *zap.SugaredLogger) error {
+	logger = operationLogger(logger, "Install", opts.Version, opts.KubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(opts.KubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	unlock := c.clusterLocks.lock(kubeConfig)
+	defer unlock()
+
+	logger.Debug("Starting Istio installation...")
+
+	execVersion, err := c.resolveVersion(opts.Version)
+	if err != nil {
+		return err
+	}
+
+	istioOperatorManifest, err := c.istioOperatorManifestFrom(opts.IstioChart, opts.Hub, opts.Overlays...)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		return c.dryRun(istioOperatorManifest, execVersion, logger)
+	}
+
+	return c.installIstioOperator(opts.Context, istioOperatorManifest, kubeConfig, execVersion, logger)
+}
+
+func (c *DefaultIstioPerformer) InstallFromManifest(istioOperatorManifest, kubeConfig, version string, logger *zap.SugaredLogger) error {
+	logger = operationLogger(logger, "InstallFromManifest", version, kubeConfig)
+
+	if err := validateIstioOperatorManifest(istioOperatorManifest); err != nil {
+		return err
+	}
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	unlock := c.clusterLocks.lock(kubeConfig)
+	defer unlock()
+
+	logger.Debug("Starting Istio installation from a pre-rendered IstioOperator manifest...")
+
+	execVersion, err := c.resolveVersion(version)
+	if err != nil {
+		return err
+	}
+
+	return c.installIstioOperator(context.Background(), istioOperatorManifest, kubeConfig, execVersion, logger)
+}
+
+// installIstioOperator applies the IstioOperator manifest with the istioctl binary of the execVersion.
+func (c *DefaultIstioPerformer) installIstioOperator(parent context.Context, istioOperatorManifest, kubeConfig string, execVersion istioctl.Version, logger *zap.SugaredLogger) error {
+	commander, err := c.getCommander(execVersion)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.istioctlContextFrom(parent, IstioctlInstall)
+	defer cancel()
+
+	err = commander.Install(ctx, istioOperatorManifest, kubeConfig, logger)
+	if err != nil {
+		return istioctlError("install", err)
+	}
+	logger.Infof("Istio in version %s successfully installed", execVersion)
+	return nil
+}
+
+// dryRun validates the rendered IstioOperator manifest and checks that an istioctl binary of the execVersion is available, without calling it.
+func (c *DefaultIstioPerformer) dryRun(istioOperatorManifest string, execVersion istioctl.Version, logger *zap.SugaredLogger) error {
+	if err := validateIstioOperatorManifest(istioOperatorManifest); err != nil {
+		return err
+	}
+	if _, err := c.getCommander(execVersion); err != nil {
+		return err
+	}
+	logger.Infof("Dry run: IstioOperator manifest is valid and istioctl %s is available, istioctl was not called", execVersion)
+	return nil
+}
+
+// validateIstioOperatorManifest returns an error if the manifest is empty or does not consist of a single valid IstioOperator.
+func validateIstioOperatorManifest(istioOperatorManifest string) error {
+	if strings.TrimSpace(istioOperatorManifest) == "" {
+		return errors.New("IstioOperator manifest must not be empty")
+	}
+	unstructs, err := kubernetes.ToUnstructured([]byte(istioOperatorManifest), true)
+	if err != nil {
+		return errors.Wrap(err, "Could not parse IstioOperator manifest")
+	}
+	if len(unstructs) == 0 {
+		return errors.New("Could not parse IstioOperator manifest, it contains no resource")
+	}
+	if len(unstructs) != 1 {
+		return errors.Errorf("IstioOperator manifest must contain exactly one resource but contains %d", len(unstructs))
+	}
+	return manifest.ValidateIstioOperator(unstructs[0])
+}
+
+func (c *DefaultIstioPerformer) PatchMutatingWebhook(context context.Context, kubeClient kubernetes.Client, revision string, logger *zap.SugaredLogger) (WebhookPatchResult, error) {
+	logger = operationLogger(logger, "PatchMutatingWebhook", "", kubeClient.Kubeconfig())
+	logger.Debugf("Starting patch of the MutatingWebhookConfiguration of revision %s...", revisionOrDefault(revision))
+
+	clientSet, err := kubeClient.Clientset()
+	if err != nil {
+		return WebhookPatchResult{}, err
+	}
+
+	requiredLabelSelector := webhookRequiredLabelSelector()
+
+	var result WebhookPatchResult
+	var deleted bool
+	err = retry.OnError(c.webhookBackoff, func(err error) bool {
+		// a configuration deleted between Get and Update is selected again from the candidates
+		return kerrors.IsConflict(err) || deleted && kerrors.IsNotFound(err)
+	}, func() error {
+		deleted = false
+		whConf, err := c.selectWebhookConfFormCandidates(context, c.webhookCandidatesFor(revision), clientSet, logger)
+		if err != nil {
+			return err
+		}
+		modified, compliant, err := c.addNamespaceSelectorIfNotPresent(whConf, webhookNameToChange, requiredLabelSelector)
+		if err != nil {
+			return err
+		}
+		result = WebhookPatchResult{
+			WebhookConfiguration: whConf.Name,
+			Changed:              modified > 0,
+			ModifiedWebhooks:     modified,
+			CompliantWebhooks:    compliant,
+			Configuration:        whConf,
+		}
+		if !result.Changed {
+			return nil
+		}
+		updated, err := clientSet.AdmissionregistrationV1().
+			MutatingWebhookConfigurations().
+			Update(context, whConf, metav1.UpdateOptions{})
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("MutatingWebhookConfiguration %s was deleted before it could be patched", whConf.Name)
+			deleted = true
+		}
+		if err != nil {
+			return err
+		}
+		result.Configuration = updated
+		return nil
+	})
+	if err != nil {
+		return WebhookPatchResult{}, err
+	}
+
+	logger.Infof("Namespace selectors of MutatingWebhookConfiguration %s: %d webhooks modified, %d already compliant",
+		result.WebhookConfiguration, result.ModifiedWebhooks, result.CompliantWebhooks)
+	webhookPatchSelectors.WithLabelValues(webhookSelectorModified).Add(float64(result.ModifiedWebhooks))
+	webhookPatchSelectors.WithLabelValues(webhookSelectorCompliant).Add(float64(result.CompliantWebhooks))
+	if result.Changed {
+		logger.Infof("Patch has been applied successfully to MutatingWebhookConfiguration %s", result.WebhookConfiguration)
+	} else {
+		logger.Infof("MutatingWebhookConfiguration %s was already patched, nothing changed", result.WebhookConfiguration)
+	}
+	if c.webhookPatchHook != nil {
+		c.webhookPatchHook(result)
+	}
+
+	return result, nil
+}
+
+func (c *DefaultIstioPerformer) PreviewMutatingWebhookPatch(context context.Context, kubeClient kubernetes.Client, revision string, logger *zap.SugaredLogger) (WebhookPatchPreview, error) {
+	logger = operationLogger(logger, "PreviewMutatingWebhookPatch", "", kubeClient.Kubeconfig())
+
+	clientSet, err := kubeClient.Clientset()
+	if err != nil {
+		return WebhookPatchPreview{}, err
+	}
+
+	whConf, err := c.selectWebhookConfFormCandidates(context, c.webhookCandidatesFor(revision), clientSet, logger)
+	if err != nil {
+		return WebhookPatchPreview{}, err
+	}
+
+	requiredLabelSelector := webhookRequiredLabelSelector()
+	for i := range whConf.Webhooks {
+		if whConf.Webhooks[i].Name == webhookNameToChange {
+			preview := WebhookPatchPreview{
+				WebhookConfiguration: whConf.Name,
+				Webhook:              webhookNameToChange,
+				Requirement:          requiredLabelSelector,
+				AlreadyPresent:       hasSelectorRequirement(whConf.Webhooks[i].NamespaceSelector, requiredLabelSelector),
+			}
+			logger.Debugf("Patch of webhook %s in %s already applied: %t", preview.Webhook, preview.WebhookConfiguration, preview.AlreadyPresent)
+			return preview, nil
+		}
+	}
+	return WebhookPatchPreview{}, fmt.Errorf("could not find webhook %s in WebhookConfiguration %s", webhookNameToChange, whConf.Name)
+}
+
+func webhookRequiredLabelSelector() metav1.LabelSelectorRequirement {
+	return metav1.LabelSelectorRequirement{
+		Key:      "gardener.cloud/purpose",
+		Operator: "NotIn",
+		Values:   []string{"kube-system"},
+	}
+}
+
+func hasSelectorRequirement(selector *metav1.LabelSelector, requirement metav1.LabelSelectorRequirement) bool {
+	if selector == nil {
+		return false
+	}
+	for i := range selector.MatchExpressions {
+		if reflect.DeepEqual(selector.MatchExpressions[i], requirement) {
+			return true
+		}
+	}
+	return false
+}
+
+// addNamespaceSelectorIfNotPresent adds the requiredLabelSelector to all webhooks of the given name which do not contain it yet.
+// It returns the number of modified webhooks and the number of webhooks which already contained it.
+func (c *DefaultIstioPerformer) addNamespaceSelectorIfNotPresent(whConf *v1.MutatingWebhookConfiguration, webhookNameToChange string, requiredLabelSelector metav1.LabelSelectorRequirement) (modified int, compliant int, err error) {
+	for i := range whConf.Webhooks {
+		if whConf.Webhooks[i].Name != webhookNameToChange {
+			continue
+		}
+		if hasSelectorRequirement(whConf.Webhooks[i].NamespaceSelector, requiredLabelSelector) {
+			compliant++
+			continue
+		}
+		if whConf.Webhooks[i].NamespaceSelector == nil {
+			whConf.Webhooks[i].NamespaceSelector = &metav1.LabelSelector{}
+		}
+		whConf.Webhooks[i].NamespaceSelector.MatchExpressions = append(whConf.Webhooks[i].NamespaceSelector.MatchExpressions, requiredLabelSelector)
+		modified++
+	}
+	if modified+compliant == 0 {
+		return 0, 0, fmt.Errorf("could not find webhook %s in WebhookConfiguration %s", webhookNameToChange, whConf.Name)
+	}
+	return modified, compliant, nil
+}
+
+// webhookCandidatesFor returns the names of the MutatingWebhookConfigurations of the revision, in order of preference.
+// The default revision uses the configured webhook candidates.
+func (c *DefaultIstioPerformer) webhookCandidatesFor(revision string) []string {
+	if revisionOrDefault(revision) == defaultRevision {
+		return c.webhookCandidates
+	}
+	return []string{"istio-revision-tag-" + revision, "istio-sidecar-injector-" + revision}
 }
 
-type MeshComponent struct {
-	Component string    `json:"Component,omitempty"`
-	Info      *MeshInfo `json:"Info,omitempty"`
+// revisionOrDefault returns the revision, or the default revision if it is empty.
+func revisionOrDefault(revision string) string {
+	if revision == "" {
+		return defaultRevision
+	}
+	return revision
 }
 
-type MeshInfo struct {
-	Version string `json:"version,omitempty"`
-}
+func (c *DefaultIstioPerformer) selectWebhookConfFormCandidates(context context.Context, candidatesNames []string, clientSet clientgo.Interface, logger *zap.SugaredLogger) (wh *v1.MutatingWebhookConfiguration, err error) {
+	if len(candidatesNames) == 0 {
+		return nil, errors.New("No MutatingWebhookConfiguration candidates configured")
+	}
 
-type DataPlaneVersion struct {
-	IstioVersion string `json:"IstioVersion,omitempty"`
+	var existingNames []string
+	for _, webhookName := range candidatesNames {
+		candidate, getErr := clientSet.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context, webhookName, metav1.GetOptions{})
+		if getErr != nil {
+			err = getErr
+			continue
+		}
+		if wh == nil {
+			wh = candidate
+		}
+		existingNames = append(existingNames, webhookName)
+	}
+	if wh == nil {
+		return nil, errors.Wrapf(err, "MutatingWebhookConfigurations could not be selected from candidates %s", strings.Join(candidatesNames, ", "))
+	}
+	if len(existingNames) > 1 {
+		logger.Warnf("Multiple MutatingWebhookConfigurations exist: %s. This may cause double sidecar injection, only %s is patched", strings.Join(existingNames, ", "), wh.Name)
+	}
+	return wh, nil
 }
 
-type chartValues struct {
-	Global struct {
-		Images struct {
-			IstioPilot struct {
-				Version string `json:"version"`
-			} `json:"istio_pilot"`
-		} `json:"images"`
-	} `json:"global"`
+func (c *DefaultIstioPerformer) Update(kubeConfig, istioChart, targetVersion, hub string, autoRollback bool, logger *zap.SugaredLogger) error {
+	return c.UpdateWithOptions(UpdateOptions{
+		KubeConfig:   kubeConfig,
+		IstioChart:   istioChart,
+		Version:      targetVersion,
+		Hub:          hub,
+		AutoRollback: autoRollback,
+	}, logger)
 }
 
-//go:generate mockery --name=IstioPerformer --outpkg=mock --case=underscore
-// IstioPerformer performs actions on Istio component on the cluster.
-type IstioPerformer interface {
+func (c *DefaultIstioPerformer) UpdateWithOptions(opts UpdateOptions, logger *zap.SugaredLogger) error {
+	logger = operationLogger(logger, "Update", opts.Version, opts.KubeConfig)
 
-	// Install Istio in given version on the cluster using istioChart.
-	Install(kubeConfig, istioChart, version string, logger *zap.SugaredLogger) error
+	kubeConfig, logger, err := c.resolveKubeconfig(opts.KubeConfig, logger)
+	if err != nil {
+		return err
+	}
 
-	// PatchMutatingWebhook patches Istio's webhook configuration.
-	PatchMutatingWebhook(ctx context.Context, kubeClient kubernetes.Client, logger *zap.SugaredLogger) error
+	unlock := c.clusterLocks.lock(kubeConfig)
+	defer unlock()
 
-	// Update Istio on the cluster to the targetVersion using istioChart.
-	Update(kubeConfig, istioChart, targetVersion string, logger *zap.SugaredLogger) error
+	opts.KubeConfig = kubeConfig
+	return c.update(opts, logger)
+}
 
-	// ResetProxy resets Istio proxy of all Istio sidecars on the cluster. The proxyImageVersion parameter controls the Istio proxy version, it always adds "-distroless" suffix to the provided value.
-	ResetProxy(context context.Context, kubeConfig string, proxyImageVersion string, logger *zap.SugaredLogger) error
+// update performs Update without locking the cluster, so it can be called while the cluster lock is held.
+// The kubeconfig of the options is expected to be resolved and the logger to carry the operation and cluster fields already.
+func (c *DefaultIstioPerformer) update(opts UpdateOptions, logger *zap.SugaredLogger) error {
+	logger.Debug("Starting Istio update...")
 
-	// Version reports status of Istio installation on the cluster.
-	Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (IstioStatus, error)
+	kubeConfig := opts.KubeConfig
+	version, err := c.resolveVersion(opts.Version)
+	if err != nil {
+		return err
+	}
 
-	// Uninstall Istio from the cluster and its corresponding resources, using given Istio version.
-	Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error
-}
+	istioOperatorManifest, err := c.istioOperatorManifestFrom(opts.IstioChart, opts.Hub, opts.Overlays...)
+	if err != nil {
+		return err
+	}
 
-// CommanderResolver interface implementations must be able to provide istioctl.Commander instances for given istioctl.Version
-type CommanderResolver interface {
-	// GetCommander function returns istioctl.Commander instance for given istioctl version if supported, returns an error otherwise.
-	GetCommander(version istioctl.Version) (istioctl.Commander, error)
-}
+	if opts.DryRun {
+		return c.dryRun(istioOperatorManifest, version, logger)
+	}
 
-// DefaultIstioPerformer provides a default implementation of IstioPerformer.
-// It uses istioctl binary to do it's job. It delegates the job of finding proper istioctl binary for given operation to the configured CommandResolver.
-type DefaultIstioPerformer struct {
-	resolver        CommanderResolver
-	istioProxyReset proxy.IstioProxyReset
-	provider        clientset.Provider
-}
+	commander, err := c.getCommander(version)
+	if err != nil {
+		return err
+	}
 
-// NewDefaultIstioPerformer creates a new instance of the DefaultIstioPerformer.
-func NewDefaultIstioPerformer(resolver CommanderResolver, istioProxyReset proxy.IstioProxyReset, provider clientset.Provider) *DefaultIstioPerformer {
-	return &DefaultIstioPerformer{resolver, istioProxyReset, provider}
-}
+	var previousVersion string
+	if opts.AutoRollback {
+		previousVersion, err = c.installedPilotVersion(opts.Context, commander, kubeConfig, logger)
+		if err != nil {
+			logger.Warnf("Could not determine installed Istio version, rollback will not be possible: %s", err)
+		}
+	}
 
-func (c *DefaultIstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error {
-	logger.Debug("Starting Istio uninstallation...")
+	ctx, cancel := c.istioctlContextFrom(opts.Context, IstioctlUpgrade)
+	defer cancel()
 
-	execVersion, err := istioctl.VersionFromString(version)
+	err = commander.Upgrade(ctx, istioOperatorManifest, kubeConfig, logger)
 	if err != nil {
-		return errors.Wrap(err, "Error parsing version")
+		err = istioctlError("upgrade", err)
+		if previousVersion == "" {
+			return err
+		}
+		logger.Errorf("Istio update to version %s failed, rolling back to version %s: %s", version, previousVersion, err)
+		rollbackErr := c.rollback(opts.Context, istioOperatorManifest, kubeConfig, previousVersion, logger)
+		if rollbackErr != nil {
+			logger.Errorf("Rollback of Istio to version %s failed: %s", previousVersion, rollbackErr)
+			return errors.Wrapf(err, "rollback to version %s failed: %s", previousVersion, rollbackErr)
+		}
+		logger.Infof("Istio has been rolled back successfully to version %s", previousVersion)
+		return errors.Wrapf(err, "rolled back to version %s", previousVersion)
 	}
 
-	commander, err := c.resolver.GetCommander(execVersion)
+	logger.Infof("Istio has been updated successfully to version %s", version)
+
+	return nil
+}
+
+// installedPilotVersion returns the version of the Istio control plane on the cluster.
+func (c *DefaultIstioPerformer) installedPilotVersion(parent context.Context, commander istioctl.Commander, kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	versionOutput, err := c.istioctlVersion(parent, commander, kubeConfig, logger)
 	if err != nil {
-		return err
+		return "", err
 	}
-
-	err = commander.Uninstall(kubeClientSet.Kubeconfig(), logger)
+	parsedVersionOutput, err := parseVersionOutput(versionOutput)
 	if err != nil {
-		return errors.Wrap(err, "Error occurred when calling istioctl")
+		return "", err
 	}
-	logger.Debug("Istio uninstall triggered")
-	kubeClient, err := kubeClientSet.Clientset()
+	pilotVersion := getVersionFromJSON("pilot", parsedVersionOutput)
+	if pilotVersion == "" {
+		return "", ErrIstioNotInstalled
+	}
+	return pilotVersion, nil
+}
+
+// rollback re-installs Istio in the previousVersion with the istioctl binary of that version, bounded by the parent context of the update.
+func (c *DefaultIstioPerformer) rollback(parent context.Context, istioOperatorManifest, kubeConfig, previousVersion string, logger *zap.SugaredLogger) error {
+	version, err := c.resolveVersion(previousVersion)
 	if err != nil {
 		return err
 	}
 
-	policy := metav1.DeletePropagationForeground
-	err = kubeClient.CoreV1().Namespaces().Delete(context.TODO(), "istio-system", metav1.DeleteOptions{
-		PropagationPolicy: &policy,
-	})
+	commander, err := c.getCommander(version)
 	if err != nil {
 		return err
 	}
-	logger.Debug("Istio namespace deleted")
-	return nil
-}
 
-func (c *DefaultIstioPerformer) Install(kubeConfig, istioChart, version string, logger *zap.SugaredLogger) error {
-	logger.Debug("Starting Istio installation...")
+	ctx, cancel := c.istioctlContextFrom(parent, IstioctlInstall)
+	defer cancel()
 
-	execVersion, err := istioctl.VersionFromString(version)
+	return commander.Install(ctx, istioOperatorManifest, kubeConfig, logger)
+}
+
+func (c *DefaultIstioPerformer) UpdateAlongPath(kubeConfig, istioChart, currentVersion, targetVersion, hub string, logger *zap.SugaredLogger) error {
+	// the cluster fields of a kubeconfig reference are added by resolveKubeconfig, so the step loggers are derived from its logger
+	resolved, resolvedLog, err := c.resolveKubeconfig(kubeConfig, logger)
 	if err != nil {
-		return errors.Wrap(err, "Error parsing version")
+		return err
 	}
+	operationLog := operationLogger(resolvedLog, "UpdateAlongPath", targetVersion, kubeConfig)
 
-	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
+	unlock := c.clusterLocks.lock(resolved)
+	defer unlock()
+
+	operationLog.Debugf("Starting Istio update from version %s...", currentVersion)
+
+	current, err := istioctl.VersionFromSource(currentVersion, "current Istio version")
 	if err != nil {
 		return err
 	}
 
-	commander, err := c.resolver.GetCommander(execVersion)
+	target, err := c.resolveVersion(targetVersion)
 	if err != nil {
 		return err
 	}
 
-	err = commander.Install(istioOperatorManifest, kubeConfig, logger)
+	path, err := istioctl.UpgradePath(current, target)
 	if err != nil {
-		return errors.Wrap(err, "Error occurred when calling istioctl")
+		return err
+	}
+	operationLog.Infof("Updating Istio from version %s to %s in %d steps", currentVersion, target, len(path))
+
+	for i, step := range path {
+		// the steps are logged with their own version, the operation is the same
+		stepLog := operationLogger(resolvedLog, "UpdateAlongPath", step.String(), kubeConfig)
+		if i > 0 {
+			err = c.waitForControlPlane(resolved, stepLog)
+			if err != nil {
+				return errors.Wrapf(err, "Istio control plane not ready before update to version %s", step)
+			}
+		}
+
+		err = c.update(UpdateOptions{KubeConfig: resolved, IstioChart: istioChart, Version: step.String(), Hub: hub}, stepLog)
+		if err != nil {
+			return errors.Wrapf(err, "Istio update step %d of %d to version %s failed", i+1, len(path), step)
+		}
 	}
-	logger.Infof("Istio in version %s successfully installed", version)
+
+	operationLog.Infof("Istio has been updated successfully from version %s to %s", currentVersion, target)
 	return nil
 }
 
-func (c *DefaultIstioPerformer) PatchMutatingWebhook(context context.Context, kubeClient kubernetes.Client, logger *zap.SugaredLogger) error {
-	clientSet, err := kubeClient.Clientset()
+// waitForControlPlane waits until istiod is ready in the cluster of the resolved kubeconfig.
+func (c *DefaultIstioPerformer) waitForControlPlane(kubeConfig string, logger *zap.SugaredLogger) error {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
 	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
 		return err
 	}
 
-	const primary = "istio-revision-tag-default"
-	const secondary = "istio-sidecar-injector"
-	candidatesNames := []string{primary, secondary}
+	return wait.PollImmediate(c.readinessInterval, c.readinessTimeout, func() (bool, error) {
+		deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context.Background(), istiodDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			logger.Debugf("Could not get %s deployment: %s", istiodDeploymentName, err)
+			return false, nil
+		}
+		return isDeploymentReady(deployment), nil
+	})
+}
 
-	webhookNameToChange := "auto.sidecar-injector.istio.io"
+func (c *DefaultIstioPerformer) ResetProxy(context context.Context, kubeConfig string, proxyImageVersion string, labelSelector string, force bool, logger *zap.SugaredLogger) (ProxyResetResult, error) {
+	logger = operationLogger(logger, "ResetProxy", proxyImageVersion, kubeConfig)
 
-	requiredLabelSelector := metav1.LabelSelectorRequirement{
-		Key:      "gardener.cloud/purpose",
-		Operator: "NotIn",
-		Values:   []string{"kube-system"},
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return ProxyResetResult{}, errors.Wrapf(err, "Invalid label selector %q for the proxy reset", labelSelector)
 	}
 
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		whConf, err := c.selectWebhookConfFormCandidates(context, candidatesNames, clientSet)
-		if err != nil {
-			return err
-		}
-		err = c.addNamespaceSelectorIfNotPresent(whConf, webhookNameToChange, requiredLabelSelector)
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return ProxyResetResult{}, err
+	}
+
+	unlock := c.clusterLocks.lock(kubeConfig)
+	defer unlock()
+
+	logger.Debug("Starting Istio proxy reset...")
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return ProxyResetResult{}, err
+	}
+
+	pods, err := kubeClient.CoreV1().Pods("").List(context, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return ProxyResetResult{}, errors.Wrap(err, "Could not list pods")
+	}
+	result := ProxyResetResult{StaleProxies: staleProxiesFrom(*pods, proxyImageVersion).Count()}
+	if result.StaleProxies == 0 && !force {
+		logger.Infof("All Istio proxies already run version %s, no proxy reset needed", proxyImageVersion)
+		result.NoResetNeeded = true
+		return result, nil
+	}
+
+	if force {
+		logger.Debug("Proxy reset is forced, skipping the control plane health check")
+	} else if err := c.checkControlPlaneHealthy(context, kubeClient); err != nil {
+		return ProxyResetResult{}, errors.Wrap(err, "Istio proxies are not reset while the control plane is unhealthy, force the reset to override")
+	}
+
+	cfg := c.newIstioProxyConfig(context, kubeClient, proxyImageVersion, logger)
+	cfg.LabelSelector = labelSelector
+
+	if c.imageChecker != nil {
+		err = c.checkProxyImagesPullable(cfg, logger)
 		if err != nil {
-			return err
+			return ProxyResetResult{}, err
 		}
-		_, err = clientSet.AdmissionregistrationV1().
-			MutatingWebhookConfigurations().
-			Update(context, whConf, metav1.UpdateOptions{})
-		return err
-	})
+	}
+
+	err = c.istioProxyReset.Run(cfg)
 	if err != nil {
-		return err
+		aggregatedErr, ok := reset.AsAggregatedError(err)
+		switch {
+		case ok && aggregatedErr.Incomplete:
+			return ProxyResetResult{}, errors.Wrap(err, "Istio proxy reset incomplete")
+		case ok && aggregatedErr.IsPartial():
+			return ProxyResetResult{}, errors.Wrap(err, "Istio proxy reset partially failed")
+		default:
+			return ProxyResetResult{}, errors.Wrap(err, "Istio proxy reset error")
+		}
 	}
 
-	logger.Debugf("Patch has been applied successfully")
+	logger.Infof("Istio proxy reset to version %s completed, %d stale proxies found", proxyImageVersion, result.StaleProxies)
+	return result, nil
+}
 
-	return nil
+// ResetProxyFromFile reads the kubeconfig from the file at kubeConfigPath and calls ResetProxy with it.
+func (c *DefaultIstioPerformer) ResetProxyFromFile(context context.Context, kubeConfigPath string, proxyImageVersion string, labelSelector string, force bool, logger *zap.SugaredLogger) (ProxyResetResult, error) {
+	kubeConfig, err := clientset.ReadKubeconfigFile(kubeConfigPath)
+	if err != nil {
+		return ProxyResetResult{}, err
+	}
+	return c.ResetProxy(context, kubeConfig, proxyImageVersion, labelSelector, force, logger)
 }
 
-func (c *DefaultIstioPerformer) addNamespaceSelectorIfNotPresent(whConf *v1.MutatingWebhookConfiguration, webhookNameToChange string, requiredLabelSelector metav1.LabelSelectorRequirement) error {
-	for i := range whConf.Webhooks {
-		if whConf.Webhooks[i].Name == webhookNameToChange {
-			matchExpressions := whConf.Webhooks[i].NamespaceSelector.MatchExpressions
-			var hasRequiredLabel bool
-			for j := range matchExpressions {
-				if hasRequiredLabel = reflect.DeepEqual(matchExpressions[j], requiredLabelSelector); hasRequiredLabel {
-					break
-				}
-			}
-			if !hasRequiredLabel {
-				matchExpressions = append(matchExpressions, requiredLabelSelector)
-				whConf.Webhooks[i].NamespaceSelector.MatchExpressions = matchExpressions
-			}
-			return nil
-		}
+// ResetProxyFromChart calls ResetProxy with the proxy version read from the values of the istioChart, so proxies and control plane stay in the same version.
+func (c *DefaultIstioPerformer) ResetProxyFromChart(context context.Context, workspace chart.Factory, branch, istioChart, kubeConfig, labelSelector string, force bool, logger *zap.SugaredLogger) (ProxyResetResult, error) {
+	proxyImageVersion, err := getProxyVersionFromChart(workspace, branch, istioChart)
+	if err != nil {
+		return ProxyResetResult{}, err
 	}
-	return fmt.Errorf("could not find webhook %s in WebhookConfiguration %s", webhookNameToChange, whConf.Name)
+	return c.ResetProxy(context, kubeConfig, proxyImageVersion, labelSelector, force, logger)
 }
 
-func (c *DefaultIstioPerformer) selectWebhookConfFormCandidates(context context.Context, candidatesNames []string, clientSet clientgo.Interface) (wh *v1.MutatingWebhookConfiguration, err error) {
-	for _, webhookName := range candidatesNames {
-		wh, err = clientSet.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context, webhookName, metav1.GetOptions{})
+// checkProxyImagesPullable verifies that the target proxy images of all pods which would be reset can be pulled,
+// so that the pods are not restarted into ImagePullBackOff.
+func (c *DefaultIstioPerformer) checkProxyImagesPullable(cfg istioConfig.IstioProxyConfig, logger *zap.SugaredLogger) error {
+	pods, err := c.istioProxyReset.Preview(cfg)
+	if err != nil {
+		return errors.Wrap(err, "Istio proxy reset preview error")
+	}
+
+	for _, image := range proxyImagesFor(pods, cfg.ImageVersion) {
+		err = c.imageChecker.CheckPullable(cfg.Context, image)
 		if err != nil {
-			continue
+			return errors.Wrapf(err, "Istio proxy image %s is not pullable, proxy reset aborted", image)
 		}
-		return
+		logger.Debugf("Istio proxy image %s is pullable", image)
 	}
-	return nil, errors.Wrap(err, "MutatingWebhookConfigurations could not be selected from candidates")
+	return nil
 }
 
-func (c *DefaultIstioPerformer) Update(kubeConfig, istioChart, targetVersion string, logger *zap.SugaredLogger) error {
-	logger.Debug("Starting Istio update...")
+// EstimateDisruption previews the proxy reset to the targetProxyVersion and summarizes the pods it would restart.
+func (c *DefaultIstioPerformer) EstimateDisruption(kubeConfig, targetProxyVersion string, logger *zap.SugaredLogger) (DisruptionEstimate, error) {
+	logger = operationLogger(logger, "EstimateDisruption", targetProxyVersion, kubeConfig)
 
-	version, err := istioctl.VersionFromString(targetVersion)
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
 	if err != nil {
-		return errors.Wrap(err, "Error parsing version")
+		return DisruptionEstimate{}, err
 	}
 
-	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
 	if err != nil {
-		return err
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return DisruptionEstimate{}, err
 	}
 
-	commander, err := c.resolver.GetCommander(version)
+	cfg := c.newIstioProxyConfig(context.Background(), kubeClient, targetProxyVersion, logger)
+	pods, err := c.istioProxyReset.Preview(cfg)
 	if err != nil {
-		return err
+		return DisruptionEstimate{}, errors.Wrap(err, "Istio proxy reset preview error")
+	}
+
+	estimate := estimateDisruptionFrom(pods)
+	logger.Infof("Proxy reset to version %s would restart %d pods of %d workloads in %d namespaces", targetProxyVersion, estimate.Pods, estimate.Workloads, estimate.Namespaces)
+
+	return estimate, nil
+}
+
+func (c *DefaultIstioPerformer) ListStaleProxies(kubeConfig, expectedVersion string, logger *zap.SugaredLogger) (StaleProxies, error) {
+	logger = operationLogger(logger, "ListStaleProxies", expectedVersion, kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
 	}
 
-	err = commander.Upgrade(istioOperatorManifest, kubeConfig, logger)
+	pods, err := kubeClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
 	if err != nil {
-		return errors.Wrap(err, "Error occurred when calling istioctl")
+		return nil, errors.Wrap(err, "Could not list pods")
 	}
 
-	logger.Infof("Istio has been updated successfully to version %s", targetVersion)
+	stale := staleProxiesFrom(*pods, expectedVersion)
+	logger.Infof("Found %d proxies in %d namespaces not running version %s", stale.Count(), len(stale), expectedVersion)
 
-	return nil
+	return stale, nil
 }
 
-func (c *DefaultIstioPerformer) ResetProxy(context context.Context, kubeConfig string, proxyImageVersion string, logger *zap.SugaredLogger) error {
-	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+func (c *DefaultIstioPerformer) newIstioProxyConfig(context context.Context, kubeClient clientgo.Interface, proxyImageVersion string, logger *zap.SugaredLogger) istioConfig.IstioProxyConfig {
+	return istioConfig.IstioProxyConfig{
+		Context:                context,
+		ImagePrefix:            istioImagePrefix,
+		ImageVersion:           fmt.Sprintf("%s-distroless", proxyImageVersion),
+		RetriesCount:           c.retriesCount,
+		DelayBetweenRetries:    c.delayBetweenRetries,
+		Backoff:                c.retryBackoff,
+		MaxDelayBetweenRetries: c.maxRetryDelay,
+		Jitter:                 c.retryJitter,
+		RespectRetryAfter:      c.respectRetryAfter,
+		MaxRetryAfter:          c.maxRetryAfter,
+		Timeout:                c.timeout,
+		Interval:               c.interval,
+		Order:                  c.resetOrder,
+		Deadline:               c.resetDeadline,
+		RespectPDB:             c.respectPDB,
+		Kubeclient:             kubeClient,
+		Debug:                  false,
+		Log:                    logger,
+	}
+}
+
+func (c *DefaultIstioPerformer) Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, versionOverride string, logger *zap.SugaredLogger) (IstioStatus, error) {
+	details, err := c.VersionDetailed(workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
+	return details.Status, err
+}
+
+func (c *DefaultIstioPerformer) VersionDetailed(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, versionOverride string, logger *zap.SugaredLogger) (IstioVersionDetails, error) {
+	return c.versionDetailed(context.Background(), workspace, branchVersion, istioChart, kubeConfig, versionOverride, logger)
+}
+
+// versionDetailed performs VersionDetailed, aborting istioctl version when ctx is done if the commander supports it.
+func (c *DefaultIstioPerformer) versionDetailed(ctx context.Context, workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, versionOverride string, logger *zap.SugaredLogger) (IstioVersionDetails, error) {
+	targetVersion, targetVersionSource, targetVersionValuePath := versionOverride, TargetVersionSourceOverride, ""
+	if targetVersion == "" {
+		var err error
+		targetVersion, targetVersionSource, targetVersionValuePath, err = getTargetVersionFromIstioChart(workspace, branchVersion, istioChart, c.versionValuePaths)
+		if err != nil {
+			return IstioVersionDetails{}, errors.Wrap(err, "Target Version could not be found")
+		}
+	}
+	logger = operationLogger(logger, "Version", targetVersion, kubeConfig)
+	if targetVersionSource == TargetVersionSourceOverride {
+		logger.Infof("Target Istio version overridden: using %s instead of the version from the Istio chart", targetVersion)
+	}
+	logger.With("targetVersion", targetVersion, "targetVersionSource", string(targetVersionSource), "targetVersionValuePath", targetVersionValuePath).Debug("Resolved target Istio version")
+
+	version, err := c.resolveVersion(targetVersion)
 	if err != nil {
-		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
-		return err
+		return IstioVersionDetails{}, err
 	}
 
-	cfg := istioConfig.IstioProxyConfig{
-		Context:             context,
-		ImagePrefix:         istioImagePrefix,
-		ImageVersion:        fmt.Sprintf("%s-distroless", proxyImageVersion),
-		RetriesCount:        retriesCount,
-		DelayBetweenRetries: delayBetweenRetries,
-		Timeout:             timeout,
-		Interval:            interval,
-		Kubeclient:          kubeClient,
-		Debug:               false,
-		Log:                 logger,
+	commander, err := c.getCommander(version)
+	if err != nil {
+		return IstioVersionDetails{}, err
 	}
 
-	err = c.istioProxyReset.Run(cfg)
+	kubeConfig, logger, err = c.resolveKubeconfig(kubeConfig, logger)
 	if err != nil {
-		return errors.Wrap(err, "Istio proxy reset error")
+		return IstioVersionDetails{}, err
 	}
 
-	return nil
+	versionOutput, err := c.istioctlVersion(ctx, commander, kubeConfig, logger)
+	if err != nil {
+		return IstioVersionDetails{}, err
+	}
+
+	parsedVersionOutput, err := parseVersionOutput(versionOutput)
+	if err != nil {
+		return IstioVersionDetails{}, err
+	}
+
+	status := mapVersionOutputToStatus(parsedVersionOutput, version.String())
+	status.RequestedTargetVersion = targetVersion
+	status.TargetVersionSource = targetVersionSource
+	status.TargetVersionValuePath = targetVersionValuePath
+	if reporter, ok := commander.(istioctl.BinaryPathReporter); ok {
+		status.BinaryPath = reporter.BinaryPath()
+		logger.Debugf("Istio version %s reported by istioctl %s at %s", status.PilotVersion, status.ClientVersion, status.BinaryPath)
+	}
+
+	details := IstioVersionDetails{
+		Status: status,
+		Output: parsedVersionOutput,
+		Raw:    versionOutput,
+	}
+	if status.PilotVersion == "" {
+		return details, ErrIstioNotInstalled
+	}
+	return details, nil
 }
 
-func (c *DefaultIstioPerformer) Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (IstioStatus, error) {
-	targetVersion, err := getTargetVersionFromIstioChart(workspace, branchVersion, istioChart, logger)
+// ProxySyncSummary parses `istioctl proxy-status` of the newest istioctl binary into a SyncSummary.
+func (c *DefaultIstioPerformer) ProxySyncSummary(kubeConfig string, logger *zap.SugaredLogger) (SyncSummary, error) {
+	execVersion, err := c.resolveVersion(latestIstioctlConstraint)
 	if err != nil {
-		return IstioStatus{}, errors.Wrap(err, "Target Version could not be found")
+		return SyncSummary{}, err
 	}
 
-	version, err := istioctl.VersionFromString(targetVersion)
+	logger = operationLogger(logger, "ProxySyncSummary", execVersion.String(), kubeConfig)
+
+	commander, err := c.getCommander(execVersion)
 	if err != nil {
-		return IstioStatus{}, errors.Wrap(err, "Error parsing version")
+		return SyncSummary{}, err
 	}
 
-	commander, err := c.resolver.GetCommander(version)
+	kubeConfig, logger, err = c.resolveKubeconfig(kubeConfig, logger)
 	if err != nil {
-		return IstioStatus{}, err
+		return SyncSummary{}, err
 	}
 
-	versionOutput, err := commander.Version(kubeConfig, logger)
+	proxyStatusOutput, err := commander.ProxyStatus(kubeConfig, logger)
 	if err != nil {
-		return IstioStatus{}, err
+		return SyncSummary{}, istioctlError("proxy-status", err)
 	}
 
-	mappedIstioVersion, err := mapVersionToStruct(versionOutput, targetVersion)
+	summary, err := mapProxyStatusToSummary(proxyStatusOutput)
+	if err != nil {
+		return SyncSummary{}, newParseError("proxy-status", err)
+	}
+	logger.Debugf("Proxy sync summary: %d proxies, CDS: %+v, LDS: %+v, EDS: %+v, RDS: %+v", summary.Proxies, summary.CDS, summary.LDS, summary.EDS, summary.RDS)
 
-	return mappedIstioVersion, err
+	return summary, nil
 }
 
-func getTargetVersionFromIstioChart(workspace chart.Factory, branch string, istioChart string, logger *zap.SugaredLogger) (string, error) {
-	ws, err := workspace.Get(branch)
+func (c *DefaultIstioPerformer) ProxyConfigDump(kubeConfig, version, namespace, pod string, logger *zap.SugaredLogger) ([]byte, error) {
+	logger = operationLogger(logger, "ProxyConfigDump", version, kubeConfig)
+
+	execVersion, err := c.resolveVersion(version)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	istioHelmChart, err := loader.Load(filepath.Join(ws.ResourceDir, istioChart))
+	commander, err := c.getCommander(execVersion)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	pilotVersion, err := getTargetVersionFromPilotInChartValues(istioHelmChart)
+	kubeConfig, logger, err = c.resolveKubeconfig(kubeConfig, logger)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	configDump, err := commander.ProxyConfigDump(kubeConfig, namespace, pod, logger)
+	if err != nil {
+		return nil, errors.Wrapf(istioctlError("proxy-config", err), "Config dump of pod %s/%s failed", namespace, pod)
+	}
+	if !json.Valid(configDump) {
+		return nil, newParseError("proxy-config", errors.Errorf("Config dump of pod %s/%s is not valid JSON", namespace, pod))
+	}
+
+	return configDump, nil
+}
+
+// getTargetVersionFromIstioChart returns the target version from the first of the valuePaths set in the Istio chart values, or from the appVersion of the Istio chart.
+// The returned value path is empty if the version was not read from the values.
+// An oci:// istioChart is pulled from the OCI registry instead of being loaded from the workspace.
+func getTargetVersionFromIstioChart(workspace chart.Factory, branch string, istioChart string, valuePaths []string) (string, TargetVersionSource, string, error) {
+	istioHelmChart, err := loadIstioChart(workspace, branch, istioChart)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	pilotVersion, valuePath, err := getTargetVersionFromPilotInChartValues(istioHelmChart, valuePaths)
+	if err != nil {
+		return "", "", "", err
 	}
 
 	if pilotVersion != "" {
-		logger.Debugf("Resolved target Istio version: %s from values", pilotVersion)
-		return pilotVersion, nil
+		return pilotVersion, TargetVersionSourceValues, valuePath, nil
 	}
 
 	appVersion := getTargetVersionFromAppVersionInChartDefinition(istioHelmChart)
 	if appVersion != "" {
-		logger.Debugf("Resolved target Istio version: %s from Chart definition", appVersion)
-		return appVersion, nil
+		return appVersion, TargetVersionSourceAppVersion, "", nil
 	}
 
-	return "", errors.New("Target Istio version could not be found neither in Chart.yaml nor in helm values")
+	return "", "", "", errors.Errorf("Target Istio version could not be found neither in Chart.yaml nor in helm values %s", strings.Join(valuePaths, ", "))
 }
 
-func getTargetVersionFromAppVersionInChartDefinition(helmChart *helmChart.Chart) string {
-	return helmChart.Metadata.AppVersion
-}
+func loadIstioChart(workspace chart.Factory, branch string, istioChart string) (*helmChart.Chart, error) {
+	if isOCIChart(istioChart) {
+		return loadOCIChart(istioChart)
+	}
 
-func getTargetVersionFromPilotInChartValues(helmChart *helmChart.Chart) (string, error) {
-	mapAsJSON, err := json.Marshal(helmChart.Values)
+	ws, err := workspace.Get(branch)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var chartValues chartValues
-	err = json.Unmarshal(mapAsJSON, &chartValues)
+	return defaultChartCache.load(filepath.Join(ws.ResourceDir, istioChart))
+}
+
+// getProxyVersionFromChart returns the Istio proxy version set in the Istio chart values, or the appVersion of the Istio chart, without the distroless suffix.
+func getProxyVersionFromChart(workspace chart.Factory, branch string, istioChart string) (string, error) {
+	version, _, _, err := getTargetVersionFromIstioChart(workspace, branch, istioChart, proxyVersionValuePaths)
 	if err != nil {
-		return "", err
+		return "", errors.Wrap(err, "Could not determine the Istio proxy version from the Istio chart")
 	}
+	return strings.TrimSuffix(version, distrolessSuffix), nil
+}
+
+func getTargetVersionFromAppVersionInChartDefinition(helmChart *helmChart.Chart) string {
+	return helmChart.Metadata.AppVersion
+}
 
-	return chartValues.Global.Images.IstioPilot.Version, nil
+// getTargetVersionFromPilotInChartValues returns the first non-empty value of the valuePaths in the chart values, together with its path.
+func getTargetVersionFromPilotInChartValues(helmChart *helmChart.Chart, valuePaths []string) (string, string, error) {
+	for _, valuePath := range valuePaths {
+		value, found, err := unstructured.NestedFieldNoCopy(helmChart.Values, strings.Split(valuePath, ".")...)
+		if err != nil || !found || value == nil {
+			continue
+		}
+		version, ok := value.(string)
+		if !ok {
+			return "", "", errors.Errorf("Istio chart value %s must be a string but is %v", valuePath, value)
+		}
+		if version != "" {
+			return version, valuePath, nil
+		}
+	}
+	return "", "", nil
 }
 
 func getVersionFromJSON(versionType VersionType, json IstioVersionOutput) string {
@@ -402,25 +1736,45 @@ func getVersionFromJSON(versionType VersionType, json IstioVersionOutput) string
 }
 
 func mapVersionToStruct(versionOutput []byte, targetVersion string) (IstioStatus, error) {
+	version, err := parseVersionOutput(versionOutput)
+	if err != nil {
+		return IstioStatus{}, err
+	}
+
+	return mapVersionOutputToStatus(version, targetVersion), nil
+}
+
+func parseVersionOutput(versionOutput []byte) (IstioVersionOutput, error) {
 	if len(versionOutput) == 0 {
-		return IstioStatus{}, errors.New("the result of the version command is empty")
+		return IstioVersionOutput{}, newParseError("version", errors.New("the result of the version command is empty"))
 	}
 
-	if index := bytes.IndexRune(versionOutput, '{'); index != 0 {
-		versionOutput = versionOutput[bytes.IndexRune(versionOutput, '{'):]
+	index := bytes.IndexRune(versionOutput, '{')
+	if index < 0 {
+		return IstioVersionOutput{}, newParseError("version", errors.New("the result of the version command contains no JSON"))
 	}
+	versionOutput = versionOutput[index:]
 
+	// only the first JSON object is decoded, so trailing output like the istioctl.TruncatedOutputMarker is ignored
 	var version IstioVersionOutput
-	err := json.Unmarshal(versionOutput, &version)
+	err := json.NewDecoder(bytes.NewReader(versionOutput)).Decode(&version)
 
 	if err != nil {
-		return IstioStatus{}, err
+		if bytes.Contains(versionOutput, []byte(istioctl.TruncatedOutputMarker)) {
+			return IstioVersionOutput{}, newParseError("version", errors.Wrap(err, "the result of the version command was truncated"))
+		}
+		return IstioVersionOutput{}, newParseError("version", err)
 	}
 
+	return version, nil
+}
+
+func mapVersionOutputToStatus(version IstioVersionOutput, targetVersion string) IstioStatus {
 	return IstioStatus{
 		ClientVersion:    getVersionFromJSON("client", version),
 		TargetVersion:    targetVersion,
 		PilotVersion:     getVersionFromJSON("pilot", version),
 		DataPlaneVersion: getVersionFromJSON("dataPlane", version),
-	}, nil
+		DataPlanePresent: len(version.DataPlaneVersion) > 0,
+	}
 }
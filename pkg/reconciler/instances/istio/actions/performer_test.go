@@ -3,24 +3,41 @@ package actions
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
 	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
 	v1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
 	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	istioConfig "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
+	resetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 const (
@@ -54,6 +71,47 @@ metadata:
 	}
 }`
 
+	istioctlMockNoDataPlaneVersion = `{
+		"clientVersion": {
+		  "version": "1.11.1",
+		  "revision": "revision",
+		  "golang_version": "go1.16.7",
+		  "status": "Clean",
+		  "tag": "1.11.1"
+		},
+		"meshVersion": [
+		  {
+			"Component": "pilot",
+			"Info": {
+			  "version": "1.11.1",
+			  "revision": "revision",
+			  "golang_version": "",
+			  "status": "Clean",
+			  "tag": "1.11.1"
+			}
+		  }
+		]
+	  }`
+
+	istioctlMockUnknownDataPlaneVersion = `{
+		"clientVersion": {
+		  "version": "1.11.1"
+		},
+		"meshVersion": [
+		  {
+			"Component": "pilot",
+			"Info": {
+			  "version": "1.11.1"
+			}
+		  }
+		],
+		"dataPlaneVersion": [
+		  {
+			"ID": "id"
+		  }
+		]
+	  }`
+
 	istioctlMockCompleteVersion = `{
 		"clientVersion": {
 		  "version": "1.11.1",
@@ -97,7 +155,7 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Install(kubeConfig, "", "1.2.3", log)
+		err := wrapper.Install(kubeConfig, "", "1.2.3", "", log)
 
 		// then
 		require.Error(t, err)
@@ -107,7 +165,7 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 	t.Run("should not install when istio operator could not be found in manifest", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -115,18 +173,18 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Install(kubeConfig, "", "1.2.3", log)
+		err := wrapper.Install(kubeConfig, "", "1.2.3", "", log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
-		cmder.AssertNotCalled(t, "Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should not install Istio when istioctl returned an error", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 		proxy := proxymocks.IstioProxyReset{}
@@ -134,18 +192,18 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "istioctl error")
-		cmder.AssertCalled(t, "Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should install Istio when istioctl command was successful", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -153,283 +211,342 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
 
 		// then
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-}
-
-func Test_DefaultIstioPerformer_Uninstall(t *testing.T) {
-	kc := &mocks.Client{}
-	kc.On("Kubeconfig").Return("kubeconfig")
-	kc.On("Clientset").Return(fake.NewSimpleClientset(&corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
-	}), nil)
-	log := logger.NewLogger(false)
-
-	t.Run("should not uninstall when istio version could not be resolved", func(t *testing.T) {
+	t.Run("should install Istio with the images of the given hub", func(t *testing.T) {
 		// given
-		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.MatchedBy(func(istioOperator string) bool {
+			return strings.Contains(istioOperator, `"hub":"registry.local:5000/istio"`)
+		}), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
-		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Uninstall(kc, "1.2.3", log)
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "registry.local:5000/istio", log)
 
 		// then
-		require.Error(t, err)
-		require.Equal(t, "istioctl not found", err.Error())
+		require.NoError(t, err)
+		cmder.AssertNumberOfCalls(t, "Install", 1)
 	})
 
-	t.Run("should not uninstall Istio when istioctl returned an error", func(t *testing.T) {
+	t.Run("should not install Istio when the hub is invalid", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
-		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Uninstall(kc, "1.2.3", log)
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "https://registry.local", log)
 
 		// then
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "istioctl error")
-		cmder.AssertCalled(t, "Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		require.Contains(t, err.Error(), `Invalid hub "https://registry.local"`)
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should uninstall Istio when istioctl command was successful", func(t *testing.T) {
+	t.Run("should install Istio with the istioctl binary of the version resolved from a constraint", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"1.17.x": "1.17.3"}}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Uninstall(kc, "1.2.3", log)
+		err := wrapper.Install(kubeConfig, istioManifest, "1.17.x", "", log)
 
 		// then
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		require.Equal(t, []string{"1.17.3"}, cmdResolver.versions)
+	})
+
+	t.Run("should not install Istio when the version constraint could not be resolved", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := &recordingCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.Install(kubeConfig, istioManifest, "1.17.x", "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Error parsing version")
+		require.Empty(t, cmdResolver.versions)
 	})
 
 }
 
-func Test_DefaultIstioPerformer_PatchMutatingWebhook(t *testing.T) {
+func Test_DefaultIstioPerformer_InstallFromManifest(t *testing.T) {
 
+	kubeConfig := "kubeConfig"
 	log := logger.NewLogger(false)
+	istioOperator := `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: installed-state
+spec:
+  profile: default
+`
 
-	t.Run("should not patch MutatingWebhookConfiguration when kubeclient had returned an error", func(t *testing.T) {
+	t.Run("should pass the manifest as is to istioctl", func(t *testing.T) {
 		// given
-		kubeClient := mocks.Client{}
-		kubeClient.On("Clientset").Return(nil, errors.New("kubeclient error"))
-		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, istioOperator, kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		transformerCalled := false
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithManifestTransformers(func(manifest string) (string, error) {
+				transformerCalled = true
+				return manifest, nil
+			}))
+
+		// when
+		err := wrapper.InstallFromManifest(istioOperator, kubeConfig, "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, transformerCalled)
+		cmder.AssertNumberOfCalls(t, "Install", 1)
+	})
+
+	t.Run("should not install when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, istioOperator, kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
 
 		// when
-		err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, log)
+		err := wrapper.InstallFromManifest(istioOperator, kubeConfig, "1.2.3", log)
 
 		// then
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "kubeclient error")
+		require.Contains(t, err.Error(), "istioctl error")
 	})
 
-	t.Run("should patch MutatingWebhookConfiguration when kubeclient had not returned an error", func(t *testing.T) {
+	for name, tt := range map[string]struct {
+		manifest string
+		err      string
+	}{
+		"empty":              {manifest: " \n", err: "IstioOperator manifest must not be empty"},
+		"not parseable":      {manifest: "kind: [IstioOperator", err: "Could not parse IstioOperator manifest"},
+		"not IstioOperator":  {manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: name\n", err: "must be IstioOperator"},
+		"multiple resources": {manifest: istioManifest, err: "must contain exactly one resource but contains 3"},
+	} {
+		tt := tt
+		t.Run("should not install a manifest which is "+name, func(t *testing.T) {
+			// given
+			cmder := istioctlmocks.Commander{}
+			wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+			// when
+			err := wrapper.InstallFromManifest(tt.manifest, kubeConfig, "1.2.3", log)
+
+			// then
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.err)
+			cmder.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_DefaultIstioPerformer_InstallWithOptions(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	overlay := `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  hub: docker.io/istio
+  values:
+    global:
+      meshID: mesh1
+`
+
+	t.Run("should install the IstioOperator with the overlays merged and the hub set", func(t *testing.T) {
 		// given
-		whConfName := "istio-sidecar-injector"
-		kubeClient := mocks.Client{}
-		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(whConfName))
-		kubeClient.On("Clientset").Return(clientset, nil)
-		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
 
 		// when
-		err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, log)
-		require.NoError(t, err)
+		err := wrapper.InstallWithOptions(InstallOptions{
+			KubeConfig: kubeConfig,
+			IstioChart: istioManifest,
+			Version:    "1.2.3",
+			Hub:        "registry.local/istio",
+			Overlays:   []string{overlay},
+		}, log)
 
 		// then
-		got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
 		require.NoError(t, err)
-		want := metav1.LabelSelectorRequirement{
-			Key:      "gardener.cloud/purpose",
-			Operator: "NotIn",
-			Values:   []string{"kube-system"},
-		}
-		require.Contains(t, got.Webhooks[0].NamespaceSelector.MatchExpressions, want)
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.MatchedBy(func(manifest string) bool {
+			return strings.Contains(manifest, `"meshID":"mesh1"`) && strings.Contains(manifest, `"hub":"registry.local/istio"`)
+		}), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should patch new `istio-revision-tag-default` MutatingWebhookConfiguration instead of old", func(t *testing.T) {
+	t.Run("should pass the context to istioctl", func(t *testing.T) {
 		// given
-		oldWhConfName := "istio-sidecar-injector"
-		newWhConfName := "istio-revision-tag-default"
-		kubeClient := mocks.Client{}
-		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(newWhConfName), createIstioAutoMutatingWebhookConf(oldWhConfName))
-		kubeClient.On("Clientset").Return(clientset, nil)
-		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
 
 		// when
-		err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, log)
-		require.NoError(t, err)
+		err := wrapper.InstallWithOptions(InstallOptions{Context: ctx, KubeConfig: kubeConfig, IstioChart: istioManifest, Version: "1.2.3"}, log)
 
 		// then
-		gotNew, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), newWhConfName, metav1.GetOptions{})
-		require.NoError(t, err)
-		gotOld, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), oldWhConfName, metav1.GetOptions{})
 		require.NoError(t, err)
-		want := metav1.LabelSelectorRequirement{
-			Key:      "gardener.cloud/purpose",
-			Operator: "NotIn",
-			Values:   []string{"kube-system"},
-		}
-		require.Contains(t, gotNew.Webhooks[0].NamespaceSelector.MatchExpressions, want)
-		require.NotContains(t, gotOld.Webhooks[0].NamespaceSelector.MatchExpressions, want)
+		cmder.AssertCalled(t, "Install", mock.MatchedBy(func(ctx context.Context) bool {
+			return errors.Is(ctx.Err(), context.Canceled)
+		}), mock.AnythingOfType("string"), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should not change if the webhook already has the selector", func(t *testing.T) {
+	t.Run("should not call istioctl on dry run", func(t *testing.T) {
 		// given
-		whConfName := "istio-revision-tag-default"
-		kubeClient := mocks.Client{}
-		selectors := []metav1.LabelSelectorRequirement{
-			{
-				Key:      "gardener.cloud/purpose",
-				Operator: "NotIn",
-				Values:   []string{"kube-system"},
-			},
-			{
-				Key:      "foo",
-				Operator: "bar",
-				Values:   nil,
-			},
-		}
-		mutatingWebhookConf := createIstioAutoMutatingWebhookConfWithSelector(whConfName, selectors...)
-		clientset := fake.NewSimpleClientset(mutatingWebhookConf)
-		kubeClient.On("Clientset").Return(clientset, nil)
-		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+		cmder := istioctlmocks.Commander{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
 
 		// when
-		err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, log)
-		require.NoError(t, err)
+		err := wrapper.InstallWithOptions(InstallOptions{KubeConfig: kubeConfig, IstioChart: istioManifest, Version: "1.2.3", Overlays: []string{overlay}, DryRun: true}, log)
 
 		// then
-		whConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
 		require.NoError(t, err)
-		require.Equal(t, len(whConf.Webhooks[0].NamespaceSelector.MatchExpressions), len(selectors))
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should patch mutating webhook only once when patch was performed twice", func(t *testing.T) {
+	t.Run("should return an error on dry run when no istioctl binary is available", func(t *testing.T) {
 		// given
-		whConfName := "istio-revision-tag-default"
-		kubeClient := mocks.Client{}
-		mutatingWebhookConf := createIstioAutoMutatingWebhookConf(whConfName)
-		clientset := fake.NewSimpleClientset(mutatingWebhookConf)
-		kubeClient.On("Clientset").Return(clientset, nil)
-		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{err: errors.New("no istioctl")}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
 
 		// when
-		err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, log)
-		require.NoError(t, err)
-		// saving intermediate result after first iteration
-		intermediateWhConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
-		require.NoError(t, err)
-		err = wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, log)
-		require.NoError(t, err)
+		err := wrapper.InstallWithOptions(InstallOptions{KubeConfig: kubeConfig, IstioChart: istioManifest, Version: "1.2.3", DryRun: true}, log)
 
 		// then
-		finalWhConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
-		require.NoError(t, err)
-		require.Equal(t, intermediateWhConf, finalWhConf)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no istioctl")
 	})
-}
 
-func createIstioAutoMutatingWebhookConfWithSelector(whConfName string, selector ...metav1.LabelSelectorRequirement) *v1.MutatingWebhookConfiguration {
-	return &v1.MutatingWebhookConfiguration{
-		ObjectMeta: metav1.ObjectMeta{Name: whConfName},
-		Webhooks: []v1.MutatingWebhook{
-			{
-				Name: "auto.sidecar-injector.istio.io",
-				NamespaceSelector: &metav1.LabelSelector{
-					MatchExpressions: selector,
-				},
-			},
-		},
-	}
-}
+	t.Run("should not install when an overlay is invalid", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
 
-func createIstioAutoMutatingWebhookConf(whConfName string) *v1.MutatingWebhookConfiguration {
-	return createIstioAutoMutatingWebhookConfWithSelector(whConfName, metav1.LabelSelectorRequirement{})
+		// when
+		err := wrapper.InstallWithOptions(InstallOptions{KubeConfig: kubeConfig, IstioChart: istioManifest, Version: "1.2.3", Overlays: []string{overlay, "kind: ConfigMap\napiVersion: v1\n"}}, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not merge IstioOperator overlay 2 of 2")
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
-func Test_DefaultIstioPerformer_Update(t *testing.T) {
+func Test_DefaultIstioPerformer_UpdateWithOptions(t *testing.T) {
 
 	kubeConfig := "kubeConfig"
 	log := logger.NewLogger(false)
+	overlay := `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator","spec":{"profile":"demo"}}`
 
-	t.Run("should not update when istio version could not be resolved", func(t *testing.T) {
+	t.Run("should update with the overlays merged", func(t *testing.T) {
 		// given
-		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
-
-		proxy := proxymocks.IstioProxyReset{}
-		provider := clientsetmocks.Provider{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
 
 		// when
-		err := wrapper.Update(kubeConfig, "", "1.2.3", log)
+		err := wrapper.UpdateWithOptions(UpdateOptions{KubeConfig: kubeConfig, IstioChart: istioManifest, Version: "1.2.3", Overlays: []string{overlay}}, log)
 
 		// then
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.MatchedBy(func(manifest string) bool {
+			return strings.Contains(manifest, `"profile":"demo"`)
+		}), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should not update when istio operator could not be found in manifest", func(t *testing.T) {
+	t.Run("should not call istioctl on dry run", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.UpdateWithOptions(UpdateOptions{KubeConfig: kubeConfig, IstioChart: istioManifest, Version: "1.2.3", DryRun: true, AutoRollback: true}, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertNotCalled(t, "Upgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		cmder.AssertNotCalled(t, "Version", mock.Anything, mock.Anything)
+	})
+}
+
+func Test_DefaultIstioPerformer_Uninstall(t *testing.T) {
+	kc := &mocks.Client{}
+	kc.On("Kubeconfig").Return("kubeconfig")
+	kc.On("Clientset").Return(fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+	}), nil)
+	log := logger.NewLogger(false)
+
+	t.Run("should not uninstall when istio version could not be resolved", func(t *testing.T) {
+		// given
+		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Update(kubeConfig, "", "1.2.3", log)
+		err := wrapper.Uninstall(kc, "1.2.3", log)
 
 		// then
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
-		cmder.AssertNotCalled(t, "Update", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		require.Contains(t, err.Error(), "No istioctl binary available for the requested Istio version 1.2.3")
+		require.Contains(t, err.Error(), "istioctl not found")
 	})
 
-	t.Run("should not update Istio when istioctl returned an error", func(t *testing.T) {
+	t.Run("should not uninstall Istio when istioctl returned an error", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Update(kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Uninstall(kc, "1.2.3", log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "istioctl error")
-		cmder.AssertCalled(t, "Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should update Istio when istioctl command was successful", func(t *testing.T) {
+	t.Run("should uninstall Istio when istioctl command was successful", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -437,19 +554,1067 @@ func Test_DefaultIstioPerformer_Update(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		err := wrapper.Update(kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Uninstall(kc, "1.2.3", log)
 
 		// then
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-}
+	t.Run("should uninstall Istio with the resolved kubeconfig of a kubeconfig reference", func(t *testing.T) {
+		// given
+		refKc := &mocks.Client{}
+		refKc.On("Kubeconfig").Return("secret://ns/name")
+		refKc.On("Clientset").Return(fake.NewSimpleClientset(), nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, testKubeconfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithKubeconfigResolver(testKubeconfigResolver{kubeconfigs: map[string]string{"secret://ns/name": testKubeconfig}}))
 
-func Test_DefaultIstioPerformer_ResetProxy(t *testing.T) {
+		// when
+		err := wrapper.Uninstall(refKc, "1.2.3", log)
 
-	kubeConfig := "kubeconfig"
-	log := logger.NewLogger(false)
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, testKubeconfig, mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not uninstall Istio when the kubeconfig reference could not be resolved", func(t *testing.T) {
+		// given
+		refKc := &mocks.Client{}
+		refKc.On("Kubeconfig").Return("secret://ns/missing")
+		cmder := istioctlmocks.Commander{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithKubeconfigResolver(testKubeconfigResolver{}))
+
+		// when
+		err := wrapper.Uninstall(refKc, "1.2.3", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not resolve kubeconfig")
+		cmder.AssertNotCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should delete the custom namespace Istio was installed into", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "custom-istio"}},
+		)
+		customKc := &mocks.Client{}
+		customKc.On("Kubeconfig").Return("kubeconfig")
+		customKc.On("Clientset").Return(clientset, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, WithNamespace("custom-istio"))
+		require.NoError(t, wrapper.Install("kubeconfig", "istioManifest", "1.2.3", "", log))
+
+		// when
+		err := wrapper.Uninstall(customKc, "1.2.3", log)
+
+		// then
+		require.NoError(t, err)
+		_, err = clientset.CoreV1().Namespaces().Get(context.TODO(), "custom-istio", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+		_, err = clientset.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+}
+
+func Test_DefaultIstioPerformer_ManifestTransformers(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should apply the transformers in order before istioctl install", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		appendTo := func(suffix string) ManifestTransformer {
+			return func(manifest string) (string, error) {
+				return manifest + suffix, nil
+			}
+		}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithManifestTransformers(appendTo("-first")), WithManifestTransformers(appendTo("-second")))
+
+		// when
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.MatchedBy(func(manifest string) bool {
+			return strings.Contains(manifest, "IstioOperator") && strings.HasSuffix(manifest, "-first-second")
+		}), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should apply the transformers before istioctl upgrade", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithManifestTransformers(func(manifest string) (string, error) {
+				return "transformed", nil
+			}))
+
+		// when
+		err := wrapper.Update(kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, "transformed", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should abort the installation when a transformer failed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		secondCalled := false
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithManifestTransformers(
+				func(manifest string) (string, error) {
+					return "", errors.New("transformer error")
+				},
+				func(manifest string) (string, error) {
+					secondCalled = true
+					return manifest, nil
+				}))
+
+		// when
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Manifest transformer 1 of 2 failed: transformer error")
+		require.False(t, secondCalled)
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func Test_DefaultIstioPerformer_PatchMutatingWebhook(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should not patch MutatingWebhookConfiguration when kubeclient had returned an error", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(nil, errors.New("kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "kubeclient error")
+	})
+
+	t.Run("should patch MutatingWebhookConfiguration when kubeclient had not returned an error", func(t *testing.T) {
+		// given
+		whConfName := "istio-sidecar-injector"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(whConfName))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		want := metav1.LabelSelectorRequirement{
+			Key:      "gardener.cloud/purpose",
+			Operator: "NotIn",
+			Values:   []string{"kube-system"},
+		}
+		require.Contains(t, got.Webhooks[0].NamespaceSelector.MatchExpressions, want)
+	})
+
+	t.Run("should patch a webhook without namespace selector", func(t *testing.T) {
+		// given
+		whConfName := "istio-sidecar-injector"
+		whConf := createIstioAutoMutatingWebhookConf(whConfName)
+		whConf.Webhooks[0].NamespaceSelector = nil
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(whConf)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, result.ModifiedWebhooks)
+		got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []metav1.LabelSelectorRequirement{webhookRequiredLabelSelector()}, got.Webhooks[0].NamespaceSelector.MatchExpressions)
+	})
+
+	t.Run("should patch new `istio-revision-tag-default` MutatingWebhookConfiguration instead of old", func(t *testing.T) {
+		// given
+		oldWhConfName := "istio-sidecar-injector"
+		newWhConfName := "istio-revision-tag-default"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(newWhConfName), createIstioAutoMutatingWebhookConf(oldWhConfName))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+
+		// then
+		gotNew, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), newWhConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		gotOld, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), oldWhConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		want := metav1.LabelSelectorRequirement{
+			Key:      "gardener.cloud/purpose",
+			Operator: "NotIn",
+			Values:   []string{"kube-system"},
+		}
+		require.Contains(t, gotNew.Webhooks[0].NamespaceSelector.MatchExpressions, want)
+		require.NotContains(t, gotOld.Webhooks[0].NamespaceSelector.MatchExpressions, want)
+	})
+
+	t.Run("should not change if the webhook already has the selector", func(t *testing.T) {
+		// given
+		whConfName := "istio-revision-tag-default"
+		kubeClient := mocks.Client{}
+		selectors := []metav1.LabelSelectorRequirement{
+			{
+				Key:      "gardener.cloud/purpose",
+				Operator: "NotIn",
+				Values:   []string{"kube-system"},
+			},
+			{
+				Key:      "foo",
+				Operator: "bar",
+				Values:   nil,
+			},
+		}
+		mutatingWebhookConf := createIstioAutoMutatingWebhookConfWithSelector(whConfName, selectors...)
+		clientset := fake.NewSimpleClientset(mutatingWebhookConf)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+
+		// then
+		whConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, len(whConf.Webhooks[0].NamespaceSelector.MatchExpressions), len(selectors))
+	})
+
+	t.Run("should patch mutating webhook only once when patch was performed twice", func(t *testing.T) {
+		// given
+		whConfName := "istio-revision-tag-default"
+		kubeClient := mocks.Client{}
+		mutatingWebhookConf := createIstioAutoMutatingWebhookConf(whConfName)
+		clientset := fake.NewSimpleClientset(mutatingWebhookConf)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+		// saving intermediate result after first iteration
+		intermediateWhConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+
+		// then
+		finalWhConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, intermediateWhConf, finalWhConf)
+	})
+
+	t.Run("should report a change on the first run and no change on the second run", func(t *testing.T) {
+		// given
+		whConfName := "istio-revision-tag-default"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(whConfName))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		var hookResults []WebhookPatchResult
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookPatchHook(func(result WebhookPatchResult) {
+			hookResults = append(hookResults, result)
+		}))
+
+		// when
+		first, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+		second, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+
+		// then
+		require.Equal(t, whConfName, first.WebhookConfiguration)
+		require.True(t, first.Changed)
+		require.Equal(t, whConfName, second.WebhookConfiguration)
+		require.False(t, second.Changed)
+		require.Equal(t, []WebhookPatchResult{first, second}, hookResults)
+	})
+
+	t.Run("should not update the webhook configuration when nothing changed", func(t *testing.T) {
+		// given
+		whConfName := "istio-revision-tag-default"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConfWithSelector(whConfName, webhookRequiredLabelSelector()))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, result.Changed)
+		require.Contains(t, result.Configuration.Webhooks[0].NamespaceSelector.MatchExpressions, webhookRequiredLabelSelector())
+		for _, action := range clientset.Actions() {
+			require.NotEqual(t, "update", action.GetVerb())
+		}
+	})
+
+	t.Run("should return the webhook configuration as updated on the server", func(t *testing.T) {
+		// given
+		whConfName := "istio-revision-tag-default"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(whConfName))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, result.Changed)
+		got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, got, result.Configuration)
+		require.Contains(t, result.Configuration.Webhooks[0].NamespaceSelector.MatchExpressions, webhookRequiredLabelSelector())
+	})
+}
+
+func Test_DefaultIstioPerformer_PatchMutatingWebhook_MultipleCandidates(t *testing.T) {
+
+	t.Run("should warn when both MutatingWebhookConfiguration candidates exist", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.WarnLevel)
+		log := zap.New(core).Sugar()
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf("istio-revision-tag-default"), createIstioAutoMutatingWebhookConf("istio-sidecar-injector"))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		warnings := logs.FilterMessageSnippet("Multiple MutatingWebhookConfigurations exist").All()
+		require.Len(t, warnings, 1)
+		require.Contains(t, warnings[0].Message, "istio-revision-tag-default, istio-sidecar-injector")
+		require.Contains(t, warnings[0].Message, "only istio-revision-tag-default is patched")
+	})
+
+	t.Run("should not warn when only one MutatingWebhookConfiguration candidate exists", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.WarnLevel)
+		log := zap.New(core).Sugar()
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf("istio-sidecar-injector"))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Zero(t, logs.Len())
+	})
+
+	t.Run("should patch the first existing custom candidate in order", func(t *testing.T) {
+		// given
+		log := logger.NewLogger(false)
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(
+			createIstioAutoMutatingWebhookConf("istio-revision-tag-default"),
+			createIstioAutoMutatingWebhookConf("istio-revision-tag-stable"),
+			createIstioAutoMutatingWebhookConf("istio-revision-tag-canary"),
+		)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookCandidates("istio-revision-tag-missing", "istio-revision-tag-stable", "istio-revision-tag-canary"))
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "istio-revision-tag-stable", result.WebhookConfiguration)
+		for _, name := range []string{"istio-revision-tag-default", "istio-revision-tag-canary"} {
+			whConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.NotContains(t, whConf.Webhooks[0].NamespaceSelector.MatchExpressions, webhookRequiredLabelSelector())
+		}
+	})
+
+	t.Run("should report the attempted candidates when none of them exists", func(t *testing.T) {
+		// given
+		log := logger.NewLogger(false)
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf("istio-revision-tag-default")), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookCandidates("istio-revision-tag-stable", "istio-revision-tag-canary"))
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "could not be selected from candidates istio-revision-tag-stable, istio-revision-tag-canary")
+	})
+
+	t.Run("should return error when no candidates are configured", func(t *testing.T) {
+		// given
+		log := logger.NewLogger(false)
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf("istio-revision-tag-default")), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookCandidates())
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "No MutatingWebhookConfiguration candidates configured")
+	})
+}
+
+func Test_DefaultIstioPerformer_PatchMutatingWebhook_Revision(t *testing.T) {
+
+	t.Run("should patch the revision tag of the revision", func(t *testing.T) {
+		// given
+		log := logger.NewLogger(false)
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(
+			createIstioAutoMutatingWebhookConf("istio-revision-tag-default"),
+			createIstioAutoMutatingWebhookConf("istio-revision-tag-canary"),
+		)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "canary", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "istio-revision-tag-canary", result.WebhookConfiguration)
+		require.True(t, result.Changed)
+		whConf, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), "istio-revision-tag-default", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, whConf.Webhooks[0].NamespaceSelector.MatchExpressions, webhookRequiredLabelSelector())
+	})
+
+	t.Run("should fall back to the sidecar injector of the revision", func(t *testing.T) {
+		// given
+		log := logger.NewLogger(false)
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf("istio-sidecar-injector-canary"))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "canary", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "istio-sidecar-injector-canary", result.WebhookConfiguration)
+	})
+
+	t.Run("should return error when no webhook configuration of the revision exists", func(t *testing.T) {
+		// given
+		log := logger.NewLogger(false)
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf("istio-revision-tag-default")), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "canary", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istio-revision-tag-canary, istio-sidecar-injector-canary")
+	})
+}
+
+func Test_DefaultIstioPerformer_webhookCandidatesFor(t *testing.T) {
+
+	wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookCandidates("istio-revision-tag-stable"))
+
+	t.Run("should return the configured candidates for the default revision", func(t *testing.T) {
+		require.Equal(t, []string{"istio-revision-tag-stable"}, wrapper.webhookCandidatesFor(""))
+		require.Equal(t, []string{"istio-revision-tag-stable"}, wrapper.webhookCandidatesFor("default"))
+	})
+
+	t.Run("should return the revisioned candidates for any other revision", func(t *testing.T) {
+		require.Equal(t, []string{"istio-revision-tag-1-12", "istio-sidecar-injector-1-12"}, wrapper.webhookCandidatesFor("1-12"))
+	})
+}
+
+func Test_DefaultIstioPerformer_PreviewMutatingWebhookPatch(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	want := metav1.LabelSelectorRequirement{
+		Key:      "gardener.cloud/purpose",
+		Operator: "NotIn",
+		Values:   []string{"kube-system"},
+	}
+
+	t.Run("should not preview patch when kubeclient had returned an error", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(nil, errors.New("kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PreviewMutatingWebhookPatch(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "kubeclient error")
+	})
+
+	t.Run("should report pending change of `istio-revision-tag-default` without updating it", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf("istio-revision-tag-default"), createIstioAutoMutatingWebhookConf("istio-sidecar-injector"))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		preview, err := wrapper.PreviewMutatingWebhookPatch(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, WebhookPatchPreview{
+			WebhookConfiguration: "istio-revision-tag-default",
+			Webhook:              "auto.sidecar-injector.istio.io",
+			Requirement:          want,
+			AlreadyPresent:       false,
+		}, preview)
+		for _, action := range clientset.Actions() {
+			require.Equal(t, "get", action.GetVerb())
+		}
+		got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), "istio-revision-tag-default", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, got.Webhooks[0].NamespaceSelector.MatchExpressions, want)
+	})
+
+	t.Run("should report already present requirement", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConfWithSelector("istio-sidecar-injector", want))
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		preview, err := wrapper.PreviewMutatingWebhookPatch(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "istio-sidecar-injector", preview.WebhookConfiguration)
+		require.True(t, preview.AlreadyPresent)
+	})
+
+	t.Run("should preview the webhook configuration of the revision", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(
+			createIstioAutoMutatingWebhookConfWithSelector("istio-revision-tag-default", want),
+			createIstioAutoMutatingWebhookConf("istio-revision-tag-canary"),
+		)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		preview, err := wrapper.PreviewMutatingWebhookPatch(context.TODO(), &kubeClient, "canary", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "istio-revision-tag-canary", preview.WebhookConfiguration)
+		require.False(t, preview.AlreadyPresent)
+	})
+
+	t.Run("should return error when no webhook configuration exists", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.PreviewMutatingWebhookPatch(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "MutatingWebhookConfigurations could not be selected from candidates")
+	})
+}
+
+func Test_DefaultIstioPerformer_PatchMutatingWebhook_Retry(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	backoff := wait.Backoff{Steps: 4, Duration: time.Millisecond, Factor: 2.0, Jitter: 0.5}
+	webhookConfigurations := schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}
+
+	t.Run("should retry conflicting updates until the patch succeeds", func(t *testing.T) {
+		// given
+		whConfName := "istio-sidecar-injector"
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(whConfName))
+		updates := 0
+		clientset.PrependReactor("update", "mutatingwebhookconfigurations", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updates++
+			if updates <= 2 {
+				return true, nil, kerrors.NewConflict(webhookConfigurations.GroupResource(), whConfName, errors.New("the object has been modified"))
+			}
+			return false, nil, nil
+		})
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookPatchBackoff(backoff))
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, result.Changed)
+		require.Equal(t, 3, updates)
+		got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), whConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Contains(t, got.Webhooks[0].NamespaceSelector.MatchExpressions, webhookRequiredLabelSelector())
+	})
+
+	t.Run("should return the conflict when the backoff is exhausted", func(t *testing.T) {
+		// given
+		whConfName := "istio-sidecar-injector"
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(whConfName))
+		updates := 0
+		clientset.PrependReactor("update", "mutatingwebhookconfigurations", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updates++
+			return true, nil, kerrors.NewConflict(webhookConfigurations.GroupResource(), whConfName, errors.New("the object has been modified"))
+		})
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookPatchBackoff(backoff))
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.True(t, kerrors.IsConflict(err))
+		require.Equal(t, backoff.Steps, updates)
+	})
+
+	t.Run("should select the configuration again from the candidates when it was deleted before the update", func(t *testing.T) {
+		// given
+		newWhConfName := "istio-revision-tag-default"
+		oldWhConfName := "istio-sidecar-injector"
+		clientset := fake.NewSimpleClientset(createIstioAutoMutatingWebhookConf(newWhConfName), createIstioAutoMutatingWebhookConf(oldWhConfName))
+		clientset.PrependReactor("update", "mutatingwebhookconfigurations", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			name := action.(k8stesting.UpdateAction).GetObject().(*v1.MutatingWebhookConfiguration).Name
+			if name != newWhConfName {
+				return false, nil, nil
+			}
+			// the configuration is deleted concurrently, e.g. by removing the revision tag
+			if err := clientset.Tracker().Delete(webhookConfigurations, "", name); err != nil {
+				return true, nil, err
+			}
+			return true, nil, kerrors.NewNotFound(webhookConfigurations.GroupResource(), name)
+		})
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookPatchBackoff(backoff))
+
+		// when
+		result, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, oldWhConfName, result.WebhookConfiguration)
+		got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), oldWhConfName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Contains(t, got.Webhooks[0].NamespaceSelector.MatchExpressions, webhookRequiredLabelSelector())
+	})
+
+	t.Run("should not retry when no candidate exists", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		gets := 0
+		clientset.PrependReactor("get", "mutatingwebhookconfigurations", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			gets++
+			return false, nil, nil
+		})
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithWebhookPatchBackoff(backoff))
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, len(webhookCandidatesNames), gets)
+	})
+}
+
+func createIstioAutoMutatingWebhookConfWithSelector(whConfName string, selector ...metav1.LabelSelectorRequirement) *v1.MutatingWebhookConfiguration {
+	return &v1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: whConfName},
+		Webhooks: []v1.MutatingWebhook{
+			{
+				Name: "auto.sidecar-injector.istio.io",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: selector,
+				},
+			},
+		},
+	}
+}
+
+func createIstioAutoMutatingWebhookConf(whConfName string) *v1.MutatingWebhookConfiguration {
+	return createIstioAutoMutatingWebhookConfWithSelector(whConfName, metav1.LabelSelectorRequirement{})
+}
+
+func Test_DefaultIstioPerformer_Update(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should not update when istio version could not be resolved", func(t *testing.T) {
+		// given
+		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.Update(kubeConfig, "", "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
+	})
+
+	t.Run("should not update when istio operator could not be found in manifest", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.Update(kubeConfig, "", "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
+		cmder.AssertNotCalled(t, "Update", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not update Istio when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.Update(kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should update Istio when istioctl command was successful", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.Update(kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not roll back when auto rollback is disabled", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.Update(kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		cmder.AssertNotCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should re-install the previous version when the update failed and auto rollback is enabled", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.InfoLevel)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := &recordingCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.Update(kubeConfig, istioManifest, "1.12.0", "", true, zap.New(core).Sugar())
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+		require.Contains(t, err.Error(), "rolled back to version 1.11.1")
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger"))
+		require.Equal(t, []string{"1.12.0", "1.11.1"}, cmdResolver.versions)
+		require.Equal(t, 1, logs.FilterMessage("Istio update to version 1.12.0 failed, rolling back to version 1.11.1: Error occurred when calling istioctl: istioctl error").Len())
+		require.Equal(t, 1, logs.FilterMessage("Istio has been rolled back successfully to version 1.11.1").Len())
+	})
+
+	t.Run("should report both errors when the rollback failed", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.InfoLevel)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("install error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.Update(kubeConfig, istioManifest, "1.12.0", "", true, zap.New(core).Sugar())
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+		require.Contains(t, err.Error(), "rollback to version 1.11.1 failed: install error")
+		require.Equal(t, 1, logs.FilterMessage("Rollback of Istio to version 1.11.1 failed: install error").Len())
+	})
+
+	t.Run("should roll back within the context of the update", func(t *testing.T) {
+		// given
+		type contextKey struct{}
+		parent := context.WithValue(context.Background(), contextKey{}, "update")
+		var rollbackCtx context.Context
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(args mock.Arguments) { rollbackCtx = args.Get(0).(context.Context) }).
+			Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.UpdateWithOptions(UpdateOptions{Context: parent, KubeConfig: kubeConfig, IstioChart: istioManifest, Version: "1.12.0", AutoRollback: true}, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rolled back to version 1.11.1")
+		require.NotNil(t, rollbackCtx)
+		require.Equal(t, "update", rollbackCtx.Value(contextKey{}))
+	})
+
+	t.Run("should not roll back when the installed version could not be determined", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("version error"))
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		err := wrapper.Update(kubeConfig, istioManifest, "1.12.0", "", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func Test_DefaultIstioPerformer_UpdateAlongPath(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should update through all intermediate minor versions", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(fixIstiodDeployment(true)), nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, WithReadinessTimeout(time.Second, 10*time.Millisecond))
+
+		// when
+		err := wrapper.UpdateAlongPath(kubeConfig, istioManifest, "1.9.5", "1.12.2", "", log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertNumberOfCalls(t, "Upgrade", 3)
+		provider.AssertNumberOfCalls(t, "RetrieveFrom", 2)
+	})
+
+	t.Run("should not continue when the control plane is not ready after a step", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(fixIstiodDeployment(false)), nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, WithReadinessTimeout(50*time.Millisecond, 10*time.Millisecond))
+
+		// when
+		err := wrapper.UpdateAlongPath(kubeConfig, istioManifest, "1.10.1", "1.12.2", "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio control plane not ready before update to version 1.12.2")
+		cmder.AssertNumberOfCalls(t, "Upgrade", 1)
+	})
+
+	t.Run("should not continue when an update step failed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.UpdateAlongPath(kubeConfig, istioManifest, "1.10.1", "1.12.2", "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio update step 1 of 2 to version 1.11.0 failed")
+		cmder.AssertNumberOfCalls(t, "Upgrade", 1)
+	})
+
+	t.Run("should not update when the upgrade path could not be computed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.UpdateAlongPath(kubeConfig, istioManifest, "1.12.2", "1.10.1", "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "downgrade is not supported")
+		cmder.AssertNotCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should name the current version when it could not be parsed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		err := wrapper.UpdateAlongPath(kubeConfig, istioManifest, "latest", "1.12.2", "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not parse current Istio version")
+		require.Contains(t, err.Error(), "'latest'")
+		cmder.AssertNotCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func fixIstiodDeployment(ready bool) *appsv1.Deployment {
+	replicas := int32(2)
+	readyReplicas := replicas
+	if !ready {
+		readyReplicas = 1
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: replicas, ReadyReplicas: readyReplicas, AvailableReplicas: readyReplicas},
+	}
+}
+
+func Test_DefaultIstioPerformer_ResetProxy(t *testing.T) {
+
+	kubeConfig := "kubeconfig"
+	log := logger.NewLogger(false)
 	ctx := context.Background()
 	defer ctx.Done()
 	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
@@ -459,315 +1624,1756 @@ func Test_DefaultIstioPerformer_ResetProxy(t *testing.T) {
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
-
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
-		proxyImageVersion := "1.2.0"
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		proxyImageVersion := "1.2.0"
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, "", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
+
+	t.Run("should return error when istio proxy reset returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(errors.New("Proxy reset error"))
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		proxyImageVersion := "1.2.0"
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, "", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Proxy reset error")
+	})
+
+	t.Run("should return error distinguishing partial failure when some proxies could not be reset", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(&reset.AggregatedError{
+			Total:  3,
+			Failed: []reset.ObjectError{{Object: pod.CustomObject{Name: "name", Namespace: "namespace", Kind: "Pod"}, Err: errors.New("stuck terminating")}},
+		})
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		proxyImageVersion := "1.2.0"
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, "", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio proxy reset partially failed")
+		aggregatedErr, ok := reset.AsAggregatedError(err)
+		require.True(t, ok)
+		require.Equal(t, 2, aggregatedErr.Succeeded())
+	})
+
+	t.Run("should return error marking the reset incomplete when the proxy reset deadline was exceeded", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.MatchedBy(func(cfg istioConfig.IstioProxyConfig) bool {
+			return cfg.Deadline == 10*time.Minute
+		})).Return(&reset.AggregatedError{
+			Total:      3,
+			Incomplete: true,
+			Remaining:  []pod.CustomObject{{Name: "name", Namespace: "namespace", Kind: "Pod"}},
+		})
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, WithProxyResetDeadline(10*time.Minute))
+		proxyImageVersion := "1.2.0"
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, "", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio proxy reset incomplete")
+		aggregatedErr, ok := reset.AsAggregatedError(err)
+		require.True(t, ok)
+		require.Len(t, aggregatedErr.Remaining, 1)
+		require.Equal(t, 2, aggregatedErr.Succeeded())
+	})
+
+	t.Run("should reset proxies when the target proxy image is pullable", func(t *testing.T) {
+		// given
+		pods := corev1.PodList{Items: []corev1.Pod{*fixRunningPodWithProxy("app-1", "default", "1.1.0-distroless", "ReplicaSet", "app")}}
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Preview", mock.Anything).Return(pods, nil)
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		imageChecker := &stubImageChecker{}
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider, WithProxyImageCheck(imageChecker))
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", true, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"eu.gcr.io/kyma-project/external/istio/proxyv2:1.2.0-distroless"}, imageChecker.checked)
+		proxy.AssertNumberOfCalls(t, "Run", 1)
+	})
+
+	t.Run("should not reset proxies when the target proxy image is not pullable", func(t *testing.T) {
+		// given
+		pods := corev1.PodList{Items: []corev1.Pod{*fixRunningPodWithProxy("app-1", "default", "1.1.0-distroless", "ReplicaSet", "app")}}
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Preview", mock.Anything).Return(pods, nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		imageChecker := &stubImageChecker{err: errors.New("manifest unknown")}
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider, WithProxyImageCheck(imageChecker))
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio proxy image eu.gcr.io/kyma-project/external/istio/proxyv2:1.2.0-distroless is not pullable")
+		require.Contains(t, err.Error(), "manifest unknown")
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+
+	t.Run("should not check the proxy image when the check is not enabled", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", true, log)
+
+		// then
+		require.NoError(t, err)
+		proxy.AssertNotCalled(t, "Preview", mock.Anything)
+	})
+
+	t.Run("should return no error when istio proxy reset was successful", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		proxyImageVersion := "1.2.0"
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, "", true, log)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should skip the reset when all proxies already run the target version", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.2.0-distroless", "ReplicaSet", "app"),
+			fixRunningPodWithProxy("app-2", "default", "1.2.0-distroless", "ReplicaSet", "app"),
+		), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		result, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ProxyResetResult{NoResetNeeded: true}, result)
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+
+	t.Run("should reset proxies when some proxies do not run the target version", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.2.0-distroless", "ReplicaSet", "app"),
+			fixRunningPodWithProxy("app-2", "default", "1.1.0-distroless", "ReplicaSet", "app"),
+			fixIstiodDeployment(true),
+		), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		result, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ProxyResetResult{StaleProxies: 1}, result)
+		proxy.AssertNumberOfCalls(t, "Run", 1)
+	})
+
+	t.Run("should reset proxies running the target version when the reset is forced", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.2.0-distroless", "ReplicaSet", "app"),
+		), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		result, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", true, log)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, result.NoResetNeeded)
+		proxy.AssertNumberOfCalls(t, "Run", 1)
+	})
+
+	t.Run("should return error and not reset proxies when the label selector is invalid", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "app in (", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid label selector \"app in (\" for the proxy reset")
+		provider.AssertNotCalled(t, "RetrieveFrom", mock.Anything, mock.Anything)
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+
+	t.Run("should only reset proxies of pods matching the label selector", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.MatchedBy(func(cfg istioConfig.IstioProxyConfig) bool {
+			return cfg.LabelSelector == "app=payment"
+		})).Return(nil)
+		paymentPod := fixRunningPodWithProxy("payment-1", "default", "1.1.0-distroless", "ReplicaSet", "payment")
+		paymentPod.Labels = map[string]string{"app": "payment"}
+		ordersPod := fixRunningPodWithProxy("orders-1", "default", "1.1.0-distroless", "ReplicaSet", "orders")
+		ordersPod.Labels = map[string]string{"app": "orders"}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(paymentPod, ordersPod, fixIstiodDeployment(true)), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		result, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "app=payment", false, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ProxyResetResult{StaleProxies: 1}, result)
+		proxy.AssertNumberOfCalls(t, "Run", 1)
+	})
+
+	t.Run("should not reset proxies when istiod is not available", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.1.0-distroless", "ReplicaSet", "app"),
+			fixIstiodDeployment(false),
+		), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "control plane is unhealthy")
+		require.Contains(t, err.Error(), "istiod has 1 of 2 replicas available")
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+
+	t.Run("should not reset proxies when istiod is not installed", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.1.0-distroless", "ReplicaSet", "app"),
+		), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrIstioNotInstalled))
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+
+	t.Run("should reset proxies when istiod is not available but the reset is forced", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.1.0-distroless", "ReplicaSet", "app"),
+			fixIstiodDeployment(false),
+		), nil)
+
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, "1.2.0", "", true, log)
+
+		// then
+		require.NoError(t, err)
+		proxy.AssertNumberOfCalls(t, "Run", 1)
+	})
+}
+
+func Test_DefaultIstioPerformer_ResetProxyFromFile(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should return error and not reset proxies when the kubeconfig file does not exist", func(t *testing.T) {
+		// given
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(nil, &proxy, &provider)
+
+		// when
+		_, err := wrapper.ResetProxyFromFile(context.Background(), "/does/not/exist", "1.2.0", "", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not exist")
+		provider.AssertNotCalled(t, "RetrieveFrom", mock.Anything, mock.Anything)
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+}
+
+func Test_DefaultIstioPerformer_ResetProxyFromChart(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should reset the proxies to the proxy version of the chart", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(fake.NewSimpleClientset(fixRunningPodWithProxy("app-1", "default", "1.2.3-distroless", "StatefulSet", "app"), fixIstiodDeployment(true)), nil)
+		wrapper := NewDefaultIstioPerformer(nil, &proxy, &provider)
+
+		// when
+		result, err := wrapper.ResetProxyFromChart(context.Background(), factory, "branch", "istio-proxy-version", kubeConfig, "", false, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ProxyResetResult{StaleProxies: 1}, result)
+		proxy.AssertCalled(t, "Run", mock.MatchedBy(func(cfg istioConfig.IstioProxyConfig) bool {
+			return cfg.ImageVersion == "1.2.4-distroless"
+		}))
+	})
+
+	t.Run("should not reset proxies already running the proxy version of the chart", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(fake.NewSimpleClientset(fixRunningPodWithProxy("app-1", "default", "1.2.4-distroless", "StatefulSet", "app")), nil)
+		wrapper := NewDefaultIstioPerformer(nil, &proxy, &provider)
+
+		// when
+		result, err := wrapper.ResetProxyFromChart(context.Background(), factory, "branch", "istio-proxy-version", kubeConfig, "", false, log)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, result.NoResetNeeded)
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+
+	t.Run("should not reset proxies when the proxy version could not be read from the chart", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(nil, &proxy, &provider)
+
+		// when
+		_, err := wrapper.ResetProxyFromChart(context.Background(), factory, "branch", "istio-no-values-no-appversion", kubeConfig, "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not determine the Istio proxy version from the Istio chart")
+		provider.AssertNotCalled(t, "RetrieveFrom", mock.Anything, mock.Anything)
+		proxy.AssertNotCalled(t, "Run", mock.Anything)
+	})
+}
+
+func Test_getProxyVersionFromChart(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		istioChart string
+		want       string
+	}{
+		{
+			name:       "should return the proxy image version from the values without the distroless suffix",
+			istioChart: "istio-proxy-version",
+			want:       "1.2.4",
+		},
+		{
+			name:       "should fall back to the global tag",
+			istioChart: "istio-pilot-image-tag",
+			want:       "1.11.3",
+		},
+		{
+			name:       "should fall back to the appVersion of the chart",
+			istioChart: "istio-no-values-only-appversion",
+			want:       "1.2.3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			factory := &workspacemocks.Factory{}
+			factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+			// when
+			version, err := getProxyVersionFromChart(factory, "branch", tt.istioChart)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.want, version)
+		})
+	}
+
+	t.Run("should return an error when the chart does not exist", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		_, err := getProxyVersionFromChart(factory, "branch", "not-existing-chart")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no such file or directory")
+	})
+}
+
+func Test_DefaultIstioPerformer_Version(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should not proceed if the istio version could not be resolved", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.Empty(t, ver)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "No istioctl binary available for the requested Istio version 1.2.3")
+		require.Contains(t, err.Error(), "istioctl not found")
+	})
+
+	t.Run("should not proceed if the version command output returns an empty string", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.Empty(t, ver)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "command is empty")
+	})
+
+	t.Run("should not proceed if the targetVersion is not found", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.Empty(t, ver)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Target Version could not be found")
+	})
+
+	t.Run("should get only the client version and ErrIstioNotInstalled when istio is not yet installed on the cluster", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockSimpleVersion), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.2", TargetVersion: "1.2.3-solo-fips-distroless", TargetVersionSource: TargetVersionSourceValues, TargetVersionValuePath: "global.images.istio_pilot.version", RequestedTargetVersion: "1.2.3-solo-fips-distroless"}, ver)
+		require.True(t, errors.Is(err, ErrIstioNotInstalled))
+		cmder.AssertCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertNumberOfCalls(t, "Version", 1)
+	})
+
+	t.Run("should get all the expected versions when istio installed on the cluster", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.2.3-solo-fips-distroless", PilotVersion: "1.11.1", DataPlaneVersion: "1.11.1", DataPlanePresent: true, TargetVersionSource: TargetVersionSourceValues, TargetVersionValuePath: "global.images.istio_pilot.version", RequestedTargetVersion: "1.2.3-solo-fips-distroless"}, ver)
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertNumberOfCalls(t, "Version", 1)
+	})
+
+	t.Run("should report the path of the istioctl binary resolved for the target version", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmdResolver := TestCommanderResolver{cmder: binaryPathCommander{Commander: &cmder, path: "/bin/istioctl-1.11.1"}}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "/bin/istioctl-1.11.1", ver.BinaryPath)
+		require.Equal(t, "1.11.1", ver.ClientVersion)
+	})
+
+	t.Run("should use the version override as target version without looking up the chart", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "1.11.4", log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.11.4", PilotVersion: "1.11.1", DataPlaneVersion: "1.11.1", DataPlanePresent: true, TargetVersionSource: TargetVersionSourceOverride, RequestedTargetVersion: "1.11.4"}, ver)
+		factory.AssertNotCalled(t, "Get", mock.AnythingOfType("string"))
+	})
+
+	t.Run("should not proceed if the version override could not be parsed", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, "abc", log)
+
+		// then
+		require.Empty(t, ver)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Error parsing version")
+		cmder.AssertNotCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func Test_getTargetVersionFromIstioChart(t *testing.T) {
+	branch := "branch"
+
+	t.Run("should not get target version when the istio Chart does not exist", func(t *testing.T) {
+		// given
+		istioChart := "not-existing-chart"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, pilotVersionValuePaths)
+
+		// then
+		require.Empty(t, targetVersion)
+		require.Empty(t, source)
+		require.Empty(t, valuePath)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no such file or directory")
+	})
+
+	t.Run("should not get target version when the istio Chart does not contain definition and values", func(t *testing.T) {
+		// given
+		istioChart := "istio-no-values-no-appversion"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, pilotVersionValuePaths)
+
+		// then
+		require.Empty(t, targetVersion)
+		require.Empty(t, source)
+		require.Empty(t, valuePath)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Target Istio version could not be found neither in Chart.yaml nor in helm values")
+	})
+
+	t.Run("should return pilot version from values when version was found in values", func(t *testing.T) {
+		// given
+		istioChart := "istio-values-appversion"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, pilotVersionValuePaths)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, "1.2.3-solo-fips-distroless", targetVersion)
+		require.Equal(t, TargetVersionSourceValues, source)
+		require.Equal(t, "global.images.istio_pilot.version", valuePath)
+	})
+
+	t.Run("should fallback to chart appVersion when version is not found in values", func(t *testing.T) {
+		// given
+		istioChart := "istio-incorrect-values-only-appversion"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, pilotVersionValuePaths)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, "1.2.3", targetVersion)
+		require.Equal(t, TargetVersionSourceAppVersion, source)
+		require.Empty(t, valuePath)
+	})
+
+	t.Run("should fallback to chart appVersion when values.yaml is not present in the chart", func(t *testing.T) {
+		// given
+		istioChart := "istio-no-values-only-appversion"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, pilotVersionValuePaths)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, "1.2.3", targetVersion)
+		require.Equal(t, TargetVersionSourceAppVersion, source)
+		require.Empty(t, valuePath)
+	})
+
+	t.Run("should return pilot version from the first value path set in values", func(t *testing.T) {
+		// given
+		istioChart := "istio-pilot-image-tag"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, pilotVersionValuePaths)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.4", targetVersion)
+		require.Equal(t, TargetVersionSourceValues, source)
+		require.Equal(t, "pilot.image.tag", valuePath)
+	})
+
+	t.Run("should return pilot version from the given value paths in order of preference", func(t *testing.T) {
+		// given
+		istioChart := "istio-pilot-image-tag"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, []string{"global.unknown", "global.tag", "pilot.image.tag"})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.3", targetVersion)
+		require.Equal(t, TargetVersionSourceValues, source)
+		require.Equal(t, "global.tag", valuePath)
+	})
+
+	t.Run("should fallback to chart appVersion when none of the value paths is set", func(t *testing.T) {
+		// given
+		istioChart := "istio-pilot-image-tag"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		targetVersion, source, valuePath, err := getTargetVersionFromIstioChart(factory, branch, istioChart, []string{"global.unknown", "pilot.image.tag.version"})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.2.3", targetVersion)
+		require.Equal(t, TargetVersionSourceAppVersion, source)
+		require.Empty(t, valuePath)
+	})
+
+	t.Run("should not get target version when the value is not a string", func(t *testing.T) {
+		// given
+		istioChart := "istio-pilot-image-tag"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		_, _, _, err := getTargetVersionFromIstioChart(factory, branch, istioChart, []string{"pilot.image"})
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio chart value pilot.image must be a string")
+	})
+}
+
+func TestMapVersionToStruct(t *testing.T) {
+
+	t.Run("Empty byte array for version coomand returns an error", func(t *testing.T) {
+		// given
+		versionOutput := []byte("")
+		targetVersion := "targetVersion"
+
+		// when
+		_, err := mapVersionToStruct(versionOutput, targetVersion)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "command is empty")
+	})
+
+	t.Run("If unmarshalled properly, the byte array must be converted to struct", func(t *testing.T) {
+		// given
+		versionOutput := []byte(istioctlMockCompleteVersion)
+		targetVersion := "targetVersion"
+		expectedStruct := IstioStatus{
+			ClientVersion:    "1.11.1",
+			TargetVersion:    targetVersion,
+			PilotVersion:     "1.11.1",
+			DataPlaneVersion: "1.11.1",
+			DataPlanePresent: true,
+		}
+
+		// when
+		gotStruct, err := mapVersionToStruct(versionOutput, targetVersion)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, expectedStruct, gotStruct)
+	})
+
+	t.Run("should report no data plane when istio is installed without workloads", func(t *testing.T) {
+		// given
+		versionOutput := []byte(istioctlMockNoDataPlaneVersion)
+		targetVersion := "targetVersion"
+		expectedStruct := IstioStatus{
+			ClientVersion:    "1.11.1",
+			TargetVersion:    targetVersion,
+			PilotVersion:     "1.11.1",
+			DataPlaneVersion: "",
+			DataPlanePresent: false,
+		}
+
+		// when
+		gotStruct, err := mapVersionToStruct(versionOutput, targetVersion)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, expectedStruct, gotStruct)
+	})
+
+	t.Run("should report present data plane with unknown version", func(t *testing.T) {
+		// given
+		versionOutput := []byte(istioctlMockUnknownDataPlaneVersion)
+		targetVersion := "targetVersion"
+
+		// when
+		gotStruct, err := mapVersionToStruct(versionOutput, targetVersion)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, gotStruct.DataPlaneVersion)
+		require.True(t, gotStruct.DataPlanePresent)
+	})
+
+	t.Run("should ignore the truncation marker following complete version output", func(t *testing.T) {
+		// given
+		versionOutput := []byte(istioctlMockCompleteVersion + istioctl.TruncatedOutputMarker)
+
+		// when
+		gotStruct, err := mapVersionToStruct(versionOutput, "targetVersion")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.1", gotStruct.PilotVersion)
+	})
+
+	t.Run("should report truncated version output which is incomplete", func(t *testing.T) {
+		// given
+		versionOutput := []byte(istioctlMockCompleteVersion[:len(istioctlMockCompleteVersion)/2] + istioctl.TruncatedOutputMarker)
+
+		// when
+		_, err := mapVersionToStruct(versionOutput, "targetVersion")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the result of the version command was truncated")
+	})
+
+}
+
+func TestGetVersionFromJSON(t *testing.T) {
+	t.Run("should get all the expected versions when istio installed on the cluster", func(t *testing.T) {
+		// given
+		var version IstioVersionOutput
+		err := json.Unmarshal([]byte(istioctlMockCompleteVersion), &version)
+
+		// when
+		gotClient := getVersionFromJSON("client", version)
+		gotPilot := getVersionFromJSON("pilot", version)
+		gotDataPlane := getVersionFromJSON("dataPlane", version)
+		gotNothing := getVersionFromJSON("", version)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.1", gotClient)
+		require.Equal(t, "1.11.1", gotPilot)
+		require.Equal(t, "1.11.1", gotDataPlane)
+		require.Equal(t, "", gotNothing)
+
+	})
+}
+
+type stubImageChecker struct {
+	err     error
+	checked []string
+}
+
+func (s *stubImageChecker) CheckPullable(ctx context.Context, image string) error {
+	s.checked = append(s.checked, image)
+	return s.err
+}
+
+// versionContextCommander is an istioctl.Commander implementing istioctl.ContextVersioner, recording the deadline of the version call.
+type versionContextCommander struct {
+	*istioctlmocks.Commander
+	output      []byte
+	hasDeadline bool
+	remaining   time.Duration
+}
+
+func (c *versionContextCommander) VersionContext(ctx context.Context, _ string, _ *zap.SugaredLogger) ([]byte, error) {
+	var deadline time.Time
+	deadline, c.hasDeadline = ctx.Deadline()
+	c.remaining = time.Until(deadline)
+	return c.output, nil
+}
+
+// binaryPathCommander is an istioctl.Commander implementing istioctl.BinaryPathReporter.
+type binaryPathCommander struct {
+	*istioctlmocks.Commander
+	path string
+}
+
+func (c binaryPathCommander) BinaryPath() string {
+	return c.path
+}
+
+type TestCommanderResolver struct {
+	err   error
+	cmder istioctl.Commander
+}
+
+func (tcr TestCommanderResolver) GetCommander(version istioctl.Version) (istioctl.Commander, error) {
+	if tcr.err != nil {
+		return nil, tcr.err
+	}
+	return tcr.cmder, nil
+}
+
+func (tcr TestCommanderResolver) IsVersionSupported(version string) bool {
+	return tcr.err == nil
+}
+
+func (tcr TestCommanderResolver) ResolveVersion(constraint string) (istioctl.Version, error) {
+	return istioctl.VersionFromString(constraint)
+}
+
+// recordingCommanderResolver records the versions of the requested commanders.
+type recordingCommanderResolver struct {
+	cmder    istioctl.Commander
+	versions []string
+	// constraints maps the version constraints to the versions they resolve to, other versions are parsed as is.
+	constraints map[string]string
+	// available lists the istioctl versions in ascending order, other constraints resolve to the newest of them satisfying the constraint.
+	available []string
+}
+
+func (r *recordingCommanderResolver) GetCommander(version istioctl.Version) (istioctl.Commander, error) {
+	r.versions = append(r.versions, version.String())
+	return r.cmder, nil
+}
+
+func (r *recordingCommanderResolver) IsVersionSupported(version string) bool {
+	return true
+}
+
+func (r *recordingCommanderResolver) ResolveVersion(constraint string) (istioctl.Version, error) {
+	if version, ok := r.constraints[constraint]; ok {
+		return istioctl.VersionFromString(version)
+	}
+	if version, err := istioctl.VersionFromString(constraint); err == nil || len(r.available) == 0 {
+		return version, err
+	}
+	versionConstraint, err := istioctl.ParseVersionConstraint(constraint)
+	if err != nil {
+		return istioctl.Version{}, err
+	}
+	for i := len(r.available) - 1; i >= 0; i-- {
+		version, err := istioctl.VersionFromString(r.available[i])
+		if err != nil {
+			return istioctl.Version{}, err
+		}
+		if versionConstraint.Check(version) {
+			return version, nil
+		}
+	}
+	return istioctl.Version{}, errors.Errorf("No istioctl version available for constraint %s", versionConstraint)
+}
+
+func Test_DefaultIstioPerformer_IstioctlEnv(t *testing.T) {
+
+	version := istioctl.Version{}
+
+	t.Run("should pass the environment variables to the commander", func(t *testing.T) {
+		// given
+		defaultCommander := istioctl.NewDefaultCommander(istioctl.Executable{})
+		env := map[string]string{"ISTIOCTL_ENABLE_ALPHA_COMMANDS": "true", "HTTPS_PROXY": "http://proxy:3128"}
+		wrapper := NewDefaultIstioPerformer(&recordingCommanderResolver{cmder: &defaultCommander}, nil, nil, WithIstioctlEnv(env))
+
+		// when
+		commander, err := wrapper.getCommander(version)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, env, commander.(*istioctl.DefaultCommander).Env())
+	})
+
+	t.Run("should not set the environment on the commander of the resolver", func(t *testing.T) {
+		// given
+		defaultCommander := istioctl.NewDefaultCommander(istioctl.Executable{})
+		env := map[string]string{"ISTIOCTL_ENABLE_ALPHA_COMMANDS": "true"}
+		wrapper := NewDefaultIstioPerformer(&recordingCommanderResolver{cmder: &defaultCommander}, nil, nil, WithIstioctlEnv(env))
+
+		// when
+		commander, err := wrapper.getCommander(version)
+
+		// then
+		require.NoError(t, err)
+		require.NotSame(t, &defaultCommander, commander)
+		require.Empty(t, defaultCommander.Env())
+	})
+
+	t.Run("should not set an environment by default", func(t *testing.T) {
+		// given
+		defaultCommander := istioctl.NewDefaultCommander(istioctl.Executable{})
+		wrapper := NewDefaultIstioPerformer(&recordingCommanderResolver{cmder: &defaultCommander}, nil, nil)
+
+		// when
+		commander, err := wrapper.getCommander(version)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, commander.(*istioctl.DefaultCommander).Env())
+	})
+}
+
+func Test_DefaultIstioPerformer_VersionDetailed(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should return the mapped status together with the parsed and raw istioctl output", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		details, err := wrapper.VersionDetailed(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.2.3-solo-fips-distroless", PilotVersion: "1.11.1", DataPlaneVersion: "1.11.1", DataPlanePresent: true, TargetVersionSource: TargetVersionSourceValues, TargetVersionValuePath: "global.images.istio_pilot.version", RequestedTargetVersion: "1.2.3-solo-fips-distroless"}, details.Status)
+		require.Equal(t, []byte(istioctlMockCompleteVersion), details.Raw)
+		require.Len(t, details.Output.DataPlaneVersion, 1)
+		require.Equal(t, "id", details.Output.DataPlaneVersion[0].ID)
+		require.Equal(t, "1.11.1", details.Output.DataPlaneVersion[0].IstioVersion)
+		cmder.AssertNumberOfCalls(t, "Version", 1)
+	})
+
+	t.Run("should return the details together with ErrIstioNotInstalled on an empty cluster", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockSimpleVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		details, err := wrapper.VersionDetailed(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.True(t, errors.Is(err, ErrIstioNotInstalled))
+		require.True(t, errors.Is(errors.Wrap(err, "Could not fetch Istio version"), ErrIstioNotInstalled))
+		require.Equal(t, "1.11.2", details.Status.ClientVersion)
+		require.Empty(t, details.Status.PilotVersion)
+		require.Equal(t, []byte(istioctlMockSimpleVersion), details.Raw)
+	})
+
+	t.Run("should not return details if the version command output could not be parsed", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		details, err := wrapper.VersionDetailed(factory, "version", "istio-test", kubeConfig, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "command is empty")
+		require.Empty(t, details)
+	})
+
+	t.Run("should report the resolved version of a version constraint as target version together with the requested one", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmdResolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"1.11.x": "1.11.4"}}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		details, err := wrapper.VersionDetailed(factory, "version", "istio-test", kubeConfig, "1.11.x", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.4", details.Status.TargetVersion)
+		require.Equal(t, "1.11.x", details.Status.RequestedTargetVersion)
+	})
+
+	t.Run("should resolve the target version from the configured value paths", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, WithVersionValuePaths("global.tag"))
+
+		// when
+		details, err := wrapper.VersionDetailed(factory, "version", "istio-pilot-image-tag", kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.3", details.Status.TargetVersion)
+		require.Equal(t, TargetVersionSourceValues, details.Status.TargetVersionSource)
+		require.Equal(t, "global.tag", details.Status.TargetVersionValuePath)
+	})
+
+	t.Run("should log the target version resolved from the chart values together with its source", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.DebugLevel)
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		details, err := wrapper.VersionDetailed(factory, "version", "istio-values-appversion", kubeConfig, "", zap.New(core).Sugar())
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, TargetVersionSourceValues, details.Status.TargetVersionSource)
+		entries := logs.FilterMessage("Resolved target Istio version").All()
+		require.Len(t, entries, 1)
+		require.Equal(t, "1.2.3-solo-fips-distroless", entries[0].ContextMap()["targetVersion"])
+		require.Equal(t, "values", entries[0].ContextMap()["targetVersionSource"])
+	})
+
+	t.Run("should log the target version resolved from the chart appVersion together with its source", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.DebugLevel)
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		details, err := wrapper.VersionDetailed(factory, "version", "istio-no-values-only-appversion", kubeConfig, "", zap.New(core).Sugar())
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, TargetVersionSourceAppVersion, details.Status.TargetVersionSource)
+		entries := logs.FilterMessage("Resolved target Istio version").All()
+		require.Len(t, entries, 1)
+		require.Equal(t, "1.2.3", entries[0].ContextMap()["targetVersion"])
+		require.Equal(t, "appVersion", entries[0].ContextMap()["targetVersionSource"])
+	})
+}
+
+func Test_DefaultIstioPerformer_ProxySyncSummary(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should not proceed if no istioctl is available", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := &recordingCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		summary, err := wrapper.ProxySyncSummary(kubeConfig, log)
+
+		// then
+		require.Empty(t, summary)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Error parsing version")
+		cmder.AssertNotCalled(t, "ProxyStatus", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should return an error when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyStatus", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		cmdResolver := &recordingCommanderResolver{cmder: &cmder, available: []string{"1.16.2", "1.17.3"}}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		summary, err := wrapper.ProxySyncSummary(kubeConfig, log)
+
+		// then
+		require.Empty(t, summary)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+	})
+
+	t.Run("should return the aggregated summary when istioctl command was successful", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyStatus", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockProxyStatus), nil)
+		cmdResolver := &recordingCommanderResolver{cmder: &cmder, available: []string{"1.16.2", "1.17.3"}}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+
+		// when
+		summary, err := wrapper.ProxySyncSummary(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"1.17.3"}, cmdResolver.versions)
+		require.Equal(t, 4, summary.Proxies)
+		require.Equal(t, SyncCounts{Synced: 2, Stale: 1, NotSent: 1}, summary.RDS)
+		cmder.AssertNumberOfCalls(t, "ProxyStatus", 1)
+	})
+}
+
+func Test_DefaultIstioPerformer_ProxyConfigDump(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should not proceed if the version could not be parsed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, nil, nil)
+
+		// when
+		_, err := wrapper.ProxyConfigDump(kubeConfig, "abc", "default", "httpbin", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Error parsing version")
+		cmder.AssertNotCalled(t, "ProxyConfigDump", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyConfigDump", kubeConfig, "default", "httpbin", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, nil, nil)
+
+		// when
+		_, err := wrapper.ProxyConfigDump(kubeConfig, "1.2.3", "default", "httpbin", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "pod default/httpbin")
+		require.Contains(t, err.Error(), "istioctl error")
+	})
+
+	t.Run("should return an error when istioctl did not return JSON", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyConfigDump", kubeConfig, "default", "httpbin", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte("Error: pod not found"), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, nil, nil)
+
+		// when
+		_, err := wrapper.ProxyConfigDump(kubeConfig, "1.2.3", "default", "httpbin", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not valid JSON")
+	})
+
+	t.Run("should return the raw config dump", func(t *testing.T) {
+		// given
+		configDump := []byte(`{"configs":[{"@type":"type.googleapis.com/envoy.admin.v3.ListenersConfigDump"}]}`)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ProxyConfigDump", kubeConfig, "default", "httpbin", mock.AnythingOfType("*zap.SugaredLogger")).Return(configDump, nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, nil, nil)
+
+		// when
+		got, err := wrapper.ProxyConfigDump(kubeConfig, "1.2.3", "default", "httpbin", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, configDump, got)
+	})
+}
+
+func Test_DefaultIstioPerformer_WithKubeconfigResolver(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should install Istio using the resolved kubeconfig", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider,
+			WithKubeconfigResolver(testKubeconfigResolver{kubeconfigs: map[string]string{"secret://ns/name": "resolved-kubeconfig"}}))
 
 		// when
-		err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, log)
+		err := wrapper.Install("secret://ns/name", istioManifest, "1.2.3", "", log)
 
 		// then
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "Kubeclient error")
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should return error when istio proxy reset returned an error", func(t *testing.T) {
+	t.Run("should not install Istio when the kubeconfig could not be resolved", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
-		proxy.On("Run", mock.Anything).Return(errors.New("Proxy reset error"))
 		provider := clientsetmocks.Provider{}
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
-
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
-		proxyImageVersion := "1.2.0"
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider,
+			WithKubeconfigResolver(testKubeconfigResolver{}))
 
 		// when
-		err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, log)
+		err := wrapper.Install("secret://ns/unknown", istioManifest, "1.2.3", "", log)
 
 		// then
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "Proxy reset error")
+		require.Contains(t, err.Error(), "Could not resolve kubeconfig")
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should return no error when istio proxy reset was successful", func(t *testing.T) {
+	t.Run("should update Istio using the kubeconfig resolved once", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithKubeconfigResolver(testKubeconfigResolver{kubeconfigs: map[string]string{"secret://ns/name": "resolved-kubeconfig"}}))
+
+		// when
+		err := wrapper.Update("secret://ns/name", istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should update Istio along the path using the kubeconfig resolved once", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(fixIstiodDeployment(true)), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &provider,
+			WithKubeconfigResolver(testKubeconfigResolver{kubeconfigs: map[string]string{"secret://ns/name": "resolved-kubeconfig"}}),
+			WithReadinessTimeout(time.Second, 10*time.Millisecond))
+
+		// when
+		err := wrapper.UpdateAlongPath("secret://ns/name", istioManifest, "1.10.1", "1.12.2", "", log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertNumberOfCalls(t, "Upgrade", 2)
+		provider.AssertNumberOfCalls(t, "RetrieveFrom", 1)
+	})
 
+	t.Run("should reset proxies using the resolved kubeconfig", func(t *testing.T) {
+		// given
 		proxy := proxymocks.IstioProxyReset{}
 		proxy.On("Run", mock.Anything).Return(nil)
 		provider := clientsetmocks.Provider{}
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
-
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
-		proxyImageVersion := "1.2.0"
+		provider.On("RetrieveFrom", "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider,
+			WithKubeconfigResolver(testKubeconfigResolver{kubeconfigs: map[string]string{"secret://ns/name": "resolved-kubeconfig"}}))
 
 		// when
-		err := wrapper.ResetProxy(ctx, kubeConfig, proxyImageVersion, log)
+		_, err := wrapper.ResetProxy(context.TODO(), "secret://ns/name", "1.2.0", "", true, log)
 
 		// then
 		require.NoError(t, err)
+		provider.AssertCalled(t, "RetrieveFrom", "resolved-kubeconfig", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
+}
 
+type testKubeconfigResolver struct {
+	kubeconfigs map[string]string
 }
 
-func Test_DefaultIstioPerformer_Version(t *testing.T) {
+func (r testKubeconfigResolver) Resolve(kubeConfig string, _ *zap.SugaredLogger) (string, error) {
+	resolved, ok := r.kubeconfigs[kubeConfig]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return resolved, nil
+}
+
+func Test_DefaultIstioPerformer_EstimateDisruption(t *testing.T) {
 
 	kubeConfig := "kubeConfig"
 	log := logger.NewLogger(false)
 
-	t.Run("should not proceed if the istio version could not be resolved", func(t *testing.T) {
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
 		// given
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
-		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
-
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		_, err := wrapper.EstimateDisruption(kubeConfig, "1.2.0", log)
 
 		// then
-		require.Empty(t, ver)
 		require.Error(t, err)
-		require.Equal(t, "istioctl not found", err.Error())
+		require.Contains(t, err.Error(), "Kubeclient error")
 	})
 
-	t.Run("should not proceed if the version command output returns an empty string", func(t *testing.T) {
+	t.Run("should return error when the proxy reset preview failed", func(t *testing.T) {
 		// given
-		cmder := istioctlmocks.Commander{}
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
-		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
-
 		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Preview", mock.Anything).Return(corev1.PodList{}, errors.New("Preview error"))
 		provider := clientsetmocks.Provider{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxy, &provider)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		_, err := wrapper.EstimateDisruption(kubeConfig, "1.2.0", log)
 
 		// then
-		require.Empty(t, ver)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "command is empty")
+		require.Contains(t, err.Error(), "Preview error")
 	})
 
-	t.Run("should not proceed if the targetVersion is not found", func(t *testing.T) {
+	t.Run("should estimate the disruption from the pods on a fake cluster without resetting them", func(t *testing.T) {
 		// given
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{}, nil)
-		cmder := istioctlmocks.Commander{}
-		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		kubeClient := fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.1.0", "ReplicaSet", "app-rs"),
+			fixRunningPodWithProxy("app-2", "default", "1.1.0", "ReplicaSet", "app-rs"),
+			fixRunningPodWithProxy("db-0", "data", "1.1.0", "StatefulSet", "db"),
+			fixRunningPodWithProxy("up-to-date", "default", "1.2.0-distroless", "ReplicaSet", "other-rs"),
+		)
+		action := resetmocks.Action{}
+		proxyReset := proxy.NewDefaultIstioProxyReset(data.NewDefaultGatherer(), &action)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, proxyReset, &provider)
 
-		proxy := proxymocks.IstioProxyReset{}
+		// when
+		estimate, err := wrapper.EstimateDisruption(kubeConfig, "1.2.0", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, DisruptionEstimate{Pods: 3, Workloads: 2, Namespaces: 2}, estimate)
+		action.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_DefaultIstioPerformer_ListStaleProxies(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should not list stale proxies when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
 		provider := clientsetmocks.Provider{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		_, err := wrapper.ListStaleProxies(kubeConfig, "1.11.4", log)
 
 		// then
-		require.Empty(t, ver)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "Target Version could not be found")
+		require.Contains(t, err.Error(), "Kubeclient error")
 	})
 
-	t.Run("should get only the client version when istio is not yet installed on the cluster", func(t *testing.T) {
+	t.Run("should list proxies not running the expected version", func(t *testing.T) {
 		// given
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
-		cmder := istioctlmocks.Commander{}
-		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockSimpleVersion), nil)
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
-
-		proxy := proxymocks.IstioProxyReset{}
+		kubeClient := fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.10.2-distroless", "StatefulSet", "app"),
+			fixRunningPodWithProxy("app-2", "default", "1.11.4-distroless", "StatefulSet", "app"),
+			fixRunningPodWithProxy("job-1", "kyma-system", "1.10.2", "Job", "job"),
+		)
 		provider := clientsetmocks.Provider{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		stale, err := wrapper.ListStaleProxies(kubeConfig, "1.11.4", log)
 
 		// then
-		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.2", TargetVersion: "1.2.3-solo-fips-distroless"}, ver)
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		cmder.AssertNumberOfCalls(t, "Version", 1)
+		require.Equal(t, StaleProxies{
+			"default":     {{Kind: "StatefulSet", Name: "app", Proxies: []StaleProxy{{Pod: "app-1", ProxyVersion: "1.10.2"}}}},
+			"kyma-system": {{Kind: "Job", Name: "job", Proxies: []StaleProxy{{Pod: "job-1", ProxyVersion: "1.10.2"}}}},
+		}, stale)
 	})
+}
 
-	t.Run("should get all the expected versions when istio installed on the cluster", func(t *testing.T) {
+func fixRunningPodWithProxy(name, namespace, proxyVersion, ownerKind, ownerName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName}},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "istio-proxy", Image: "eu.gcr.io/kyma-project/external/istio/proxyv2:" + proxyVersion},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func Test_DefaultIstioPerformer_Options(t *testing.T) {
+
+	kubeConfig := "kubeconfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should reset proxies with default timeouts when no options are passed", func(t *testing.T) {
 		// given
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
-		cmder := istioctlmocks.Commander{}
-		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		cmdResolver := TestCommanderResolver{cmder: &istioctlmocks.Commander{}}
 		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
 		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		_, err := wrapper.ResetProxy(context.Background(), kubeConfig, "1.2.0", "", true, log)
 
 		// then
-		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.2.3-solo-fips-distroless", PilotVersion: "1.11.1", DataPlaneVersion: "1.11.1"}, ver)
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		cmder.AssertNumberOfCalls(t, "Version", 1)
+		proxy.AssertCalled(t, "Run", mock.MatchedBy(func(cfg istioConfig.IstioProxyConfig) bool {
+			return cfg.RetriesCount == 5 && cfg.DelayBetweenRetries == 5*time.Second &&
+				cfg.Timeout == 5*time.Minute && cfg.Interval == 12*time.Second && cfg.Order == istioConfig.UnspecifiedOrder &&
+				cfg.Backoff == istioConfig.LinearBackoff && cfg.Jitter == 0
+		}))
 	})
-}
-
-func Test_getTargetVersionFromIstioChart(t *testing.T) {
-	branch := "branch"
-	log := logger.NewLogger(false)
 
-	t.Run("should not get target version when the istio Chart does not exist", func(t *testing.T) {
+	t.Run("should reset proxies with the configured exponential backoff", func(t *testing.T) {
 		// given
-		istioChart := "not-existing-chart"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmdResolver := TestCommanderResolver{cmder: &istioctlmocks.Commander{}}
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider,
+			WithProxyResetRetries(4, time.Second),
+			WithProxyResetBackoff(10*time.Second, 500*time.Millisecond))
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		_, err := wrapper.ResetProxy(context.Background(), kubeConfig, "1.2.0", "", true, log)
 
 		// then
-		require.Empty(t, targetVersion)
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "no such file or directory")
+		require.NoError(t, err)
+		proxy.AssertCalled(t, "Run", mock.MatchedBy(func(cfg istioConfig.IstioProxyConfig) bool {
+			return cfg.RetriesCount == 4 && cfg.DelayBetweenRetries == time.Second && cfg.Backoff == istioConfig.ExponentialBackoff &&
+				cfg.MaxDelayBetweenRetries == 10*time.Second && cfg.Jitter == 500*time.Millisecond
+		}))
 	})
 
-	t.Run("should not get target version when the istio Chart does not contain definition and values", func(t *testing.T) {
+	t.Run("should reset proxies respecting the Retry-After of the API server", func(t *testing.T) {
 		// given
-		istioChart := "istio-no-values-no-appversion"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmdResolver := TestCommanderResolver{cmder: &istioctlmocks.Commander{}}
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, WithProxyResetRetryAfter(30*time.Second))
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		_, err := wrapper.ResetProxy(context.Background(), kubeConfig, "1.2.0", "", true, log)
 
 		// then
-		require.Empty(t, targetVersion)
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "Target Istio version could not be found neither in Chart.yaml nor in helm values")
+		require.NoError(t, err)
+		proxy.AssertCalled(t, "Run", mock.MatchedBy(func(cfg istioConfig.IstioProxyConfig) bool {
+			return cfg.RespectRetryAfter && cfg.MaxRetryAfter == 30*time.Second
+		}))
 	})
 
-	t.Run("should return pilot version from values when version was found in values", func(t *testing.T) {
+	t.Run("should reset proxies with configured timeouts, retries and order", func(t *testing.T) {
 		// given
-		istioChart := "istio-values-appversion"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmdResolver := TestCommanderResolver{cmder: &istioctlmocks.Commander{}}
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider,
+			WithProxyResetTimeout(time.Minute, time.Second),
+			WithProxyResetRetries(2, 3*time.Second),
+			WithProxyResetOrder(istioConfig.OldestFirst))
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		_, err := wrapper.ResetProxy(context.Background(), kubeConfig, "1.2.0", "", true, log)
 
 		// then
 		require.NoError(t, err)
-		require.EqualValues(t, "1.2.3-solo-fips-distroless", targetVersion)
+		proxy.AssertCalled(t, "Run", mock.MatchedBy(func(cfg istioConfig.IstioProxyConfig) bool {
+			return cfg.RetriesCount == 2 && cfg.DelayBetweenRetries == 3*time.Second &&
+				cfg.Timeout == time.Minute && cfg.Interval == time.Second && cfg.Order == istioConfig.OldestFirst
+		}))
 	})
 
-	t.Run("should fallback to chart appVersion when version is not found in values", func(t *testing.T) {
+	t.Run("should call istioctl without deadline by default", func(t *testing.T) {
 		// given
-		istioChart := "istio-incorrect-values-only-appversion"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
 
 		// then
 		require.NoError(t, err)
-		require.EqualValues(t, "1.2.3", targetVersion)
+		cmder.AssertCalled(t, "Install", mock.MatchedBy(func(ctx context.Context) bool {
+			_, hasDeadline := ctx.Deadline()
+			return !hasDeadline
+		}), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should fallback to chart appVersion when values.yaml is not present in the chart", func(t *testing.T) {
+	t.Run("should call istioctl with the configured operation timeout as deadline", func(t *testing.T) {
 		// given
-		istioChart := "istio-no-values-only-appversion"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithOperationTimeout(10*time.Minute))
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		err := wrapper.Update(kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.NoError(t, err)
-		require.EqualValues(t, "1.2.3", targetVersion)
+		cmder.AssertCalled(t, "Upgrade", mock.MatchedBy(func(ctx context.Context) bool {
+			deadline, hasDeadline := ctx.Deadline()
+			return hasDeadline && time.Until(deadline) <= 10*time.Minute
+		}), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
-}
-
-func TestMapVersionToStruct(t *testing.T) {
 
-	t.Run("Empty byte array for version coomand returns an error", func(t *testing.T) {
+	t.Run("should not bound istioctl version by the operation timeout of the slow operations", func(t *testing.T) {
 		// given
-		versionOutput := []byte("")
-		targetVersion := "targetVersion"
+		cmder := &versionContextCommander{Commander: &istioctlmocks.Commander{}, output: []byte(istioctlMockCompleteVersion)}
+		cmdResolver := TestCommanderResolver{cmder: cmder}
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithOperationTimeout(time.Nanosecond))
 
 		// when
-		_, err := mapVersionToStruct(versionOutput, targetVersion)
+		_, err := wrapper.Version(&workspacemocks.Factory{}, "version", "istio-test", kubeConfig, "1.11.4", log)
 
 		// then
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "command is empty")
+		require.NoError(t, err)
+		require.True(t, cmder.hasDeadline)
+		require.True(t, cmder.remaining > time.Second && cmder.remaining <= time.Minute)
 	})
 
-	t.Run("If unmarshalled properly, the byte array must be converted to struct", func(t *testing.T) {
+	t.Run("should bound istioctl version by its own timeout", func(t *testing.T) {
 		// given
-		versionOutput := []byte(istioctlMockCompleteVersion)
-		targetVersion := "targetVersion"
-		expectedStruct := IstioStatus{
-			ClientVersion:    "1.11.1",
-			TargetVersion:    targetVersion,
-			PilotVersion:     "1.11.1",
-			DataPlaneVersion: "1.11.1",
-		}
+		cmder := &versionContextCommander{Commander: &istioctlmocks.Commander{}, output: []byte(istioctlMockCompleteVersion)}
+		cmdResolver := TestCommanderResolver{cmder: cmder}
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithIstioctlTimeout(IstioctlVersion, 5*time.Second))
 
 		// when
-		gotStruct, err := mapVersionToStruct(versionOutput, targetVersion)
+		_, err := wrapper.Version(&workspacemocks.Factory{}, "version", "istio-test", kubeConfig, "1.11.4", log)
 
 		// then
 		require.NoError(t, err)
-		require.EqualValues(t, expectedStruct, gotStruct)
+		require.True(t, cmder.hasDeadline)
+		require.True(t, cmder.remaining <= 5*time.Second)
 	})
 
-}
-
-func TestGetVersionFromJSON(t *testing.T) {
-	t.Run("should get all the expected versions when istio installed on the cluster", func(t *testing.T) {
+	t.Run("should call istioctl install with its own timeout instead of the operation timeout", func(t *testing.T) {
 		// given
-		var version IstioVersionOutput
-		err := json.Unmarshal([]byte(istioctlMockCompleteVersion), &version)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithOperationTimeout(time.Minute), WithIstioctlTimeout(IstioctlInstall, 30*time.Minute))
 
 		// when
-		gotClient := getVersionFromJSON("client", version)
-		gotPilot := getVersionFromJSON("pilot", version)
-		gotDataPlane := getVersionFromJSON("dataPlane", version)
-		gotNothing := getVersionFromJSON("", version)
+		err := wrapper.Install(kubeConfig, istioManifest, "1.2.3", "", log)
 
 		// then
 		require.NoError(t, err)
-		require.Equal(t, "1.11.1", gotClient)
-		require.Equal(t, "1.11.1", gotPilot)
-		require.Equal(t, "1.11.1", gotDataPlane)
-		require.Equal(t, "", gotNothing)
-
+		cmder.AssertCalled(t, "Install", mock.MatchedBy(func(ctx context.Context) bool {
+			deadline, hasDeadline := ctx.Deadline()
+			return hasDeadline && time.Until(deadline) > time.Minute
+		}), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
-}
 
-type TestCommanderResolver struct {
-	err   error
-	cmder istioctl.Commander
-}
+	t.Run("should not share the istioctl timeouts between performers", func(t *testing.T) {
+		// when
+		NewDefaultIstioPerformer(nil, nil, nil, WithIstioctlTimeout(IstioctlVersion, time.Second))
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
 
-func (tcr TestCommanderResolver) GetCommander(version istioctl.Version) (istioctl.Commander, error) {
-	if tcr.err != nil {
-		return nil, tcr.err
-	}
-	return tcr.cmder, nil
+		// then
+		require.Equal(t, time.Minute, wrapper.istioctlTimeouts[IstioctlVersion])
+	})
 }
@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"go.uber.org/zap"
+)
+
+// latestIstioctlConstraint selects the newest available istioctl binary.
+const latestIstioctlConstraint = istioctl.LatestVersionConstraint
+
+// PreCheckMessage is a cluster incompatibility found by `istioctl x precheck`, e.g. an unsupported Kubernetes version.
+// It has the same format as the messages of `istioctl analyze`.
+type PreCheckMessage AnalysisMessage
+
+// PreCheckErrors returns the messages with error severity, which make the cluster unsafe for installing Istio.
+func PreCheckErrors(messages []PreCheckMessage) []PreCheckMessage {
+	var errorMessages []PreCheckMessage
+	for _, message := range messages {
+		if message.Severity == AnalysisSeverityError {
+			errorMessages = append(errorMessages, message)
+		}
+	}
+	return errorMessages
+}
+
+func (c *DefaultIstioPerformer) PreCheck(kubeConfig string, logger *zap.SugaredLogger) ([]PreCheckMessage, error) {
+	execVersion, err := c.resolveVersion(latestIstioctlConstraint)
+	if err != nil {
+		return nil, err
+	}
+
+	logger = operationLogger(logger, "PreCheck", execVersion.String(), kubeConfig)
+	logger.Debug("Starting Istio precheck...")
+
+	commander, err := c.getCommander(execVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeConfig, logger, err = c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	preCheckOutput, err := commander.PreCheck(kubeConfig, logger)
+	if err != nil {
+		return nil, istioctlError("precheck", err)
+	}
+	messages, err := parsePreCheckOutput(preCheckOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Istio precheck with istioctl %s found %d messages, %d of them errors", execVersion.String(), len(messages), len(PreCheckErrors(messages)))
+	return messages, nil
+}
+
+// parsePreCheckOutput parses the JSON output of `istioctl x precheck --output json`.
+func parsePreCheckOutput(preCheckOutput []byte) ([]PreCheckMessage, error) {
+	var messages []PreCheckMessage
+	if len(bytes.TrimSpace(preCheckOutput)) == 0 {
+		return messages, nil
+	}
+	if err := json.Unmarshal(preCheckOutput, &messages); err != nil {
+		return nil, newParseError("precheck", err)
+	}
+	return messages, nil
+}
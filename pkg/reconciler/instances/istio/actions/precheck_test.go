@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const istioctlMockPreCheckIssues = `[
+	{
+		"code": "IST0141",
+		"documentationUrl": "https://istio.io/v1.17/docs/reference/config/analysis/ist0141/",
+		"level": "Error",
+		"message": "The Kubernetes Version \"1.21.0\" is lower than the minimum version: 1.23",
+		"origin": ""
+	},
+	{
+		"code": "IST0136",
+		"documentationUrl": "https://istio.io/v1.17/docs/reference/config/analysis/ist0136/",
+		"level": "Info",
+		"message": "Deployment httpbin/httpbin is using an alpha annotation.",
+		"origin": "Deployment httpbin/httpbin"
+	}
+]`
+
+func Test_DefaultIstioPerformer_PreCheck(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should run the precheck with the newest istioctl and return the severity-tagged messages", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("PreCheck", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockPreCheckIssues), nil)
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil)
+
+		// when
+		messages, err := wrapper.PreCheck(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"1.17.3"}, resolver.versions)
+		require.Len(t, messages, 2)
+		require.Equal(t, PreCheckMessage{
+			Code:             "IST0141",
+			Severity:         AnalysisSeverityError,
+			Message:          `The Kubernetes Version "1.21.0" is lower than the minimum version: 1.23`,
+			DocumentationURL: "https://istio.io/v1.17/docs/reference/config/analysis/ist0141/",
+		}, messages[0])
+		require.Equal(t, AnalysisSeverityInfo, messages[1].Severity)
+		require.Equal(t, messages[:1], PreCheckErrors(messages))
+	})
+
+	t.Run("should return no messages for a compatible cluster", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("PreCheck", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil)
+
+		// when
+		messages, err := wrapper.PreCheck(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, messages)
+		require.Empty(t, PreCheckErrors(messages))
+	})
+
+	t.Run("should return an error when the output could not be parsed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("PreCheck", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte("Error: unknown command"), nil)
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil)
+
+		// when
+		_, err := wrapper.PreCheck(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not parse istioctl precheck output")
+	})
+
+	t.Run("should return an error when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("PreCheck", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		resolver := &recordingCommanderResolver{cmder: &cmder, constraints: map[string]string{"*": "1.17.3"}}
+		wrapper := NewDefaultIstioPerformer(resolver, nil, nil)
+
+		// when
+		_, err := wrapper.PreCheck(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+	})
+
+	t.Run("should not proceed if no istioctl is available", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, nil, nil)
+
+		// when
+		_, err := wrapper.PreCheck(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Error parsing version")
+		cmder.AssertNotCalled(t, "PreCheck", mock.Anything, mock.Anything)
+	})
+}
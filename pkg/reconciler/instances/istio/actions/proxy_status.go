@@ -0,0 +1,154 @@
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	proxyStatusNameColumn = "NAME"
+
+	syncStatusSynced  = "SYNCED"
+	syncStatusStale   = "STALE"
+	syncStatusNotSent = "NOT SENT"
+)
+
+// SyncCounts holds the number of proxies in each sync state for a single xDS config type.
+type SyncCounts struct {
+	Synced  int
+	Stale   int
+	NotSent int
+	// Other counts any remaining state reported by istioctl (e.g. IGNORED), so that all counts add up to the number of proxies.
+	Other int
+}
+
+// SyncSummary aggregates the `istioctl proxy-status` output over all proxies of the cluster.
+type SyncSummary struct {
+	// Proxies is the total number of proxies reported.
+	Proxies int
+
+	CDS SyncCounts
+	LDS SyncCounts
+	EDS SyncCounts
+	RDS SyncCounts
+}
+
+// IsSynced returns true if none of the proxies has stale or not sent configuration.
+func (s SyncSummary) IsSynced() bool {
+	for _, counts := range []SyncCounts{s.CDS, s.LDS, s.EDS, s.RDS} {
+		if counts.Stale > 0 || counts.NotSent > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SyncSummary) countsFor(configType string) *SyncCounts {
+	switch configType {
+	case "CDS":
+		return &s.CDS
+	case "LDS":
+		return &s.LDS
+	case "EDS":
+		return &s.EDS
+	case "RDS":
+		return &s.RDS
+	default:
+		return nil
+	}
+}
+
+func (c *SyncCounts) add(status string) {
+	// newer istioctl versions append details to the state, e.g. "SYNCED (2m)"
+	switch {
+	case strings.HasPrefix(status, syncStatusSynced):
+		c.Synced++
+	case strings.HasPrefix(status, syncStatusStale):
+		c.Stale++
+	case strings.HasPrefix(status, syncStatusNotSent):
+		c.NotSent++
+	default:
+		c.Other++
+	}
+}
+
+type proxyStatusColumn struct {
+	name  string
+	start int
+	end   int
+}
+
+// mapProxyStatusToSummary parses the tabular output of `istioctl proxy-status`.
+// Columns are located by their header offsets, as some status values (e.g. "NOT SENT") contain spaces.
+func mapProxyStatusToSummary(proxyStatusOutput []byte) (SyncSummary, error) {
+	if len(proxyStatusOutput) == 0 {
+		return SyncSummary{}, errors.New("the result of the proxy-status command is empty")
+	}
+
+	var columns []proxyStatusColumn
+	summary := SyncSummary{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(proxyStatusOutput))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if columns == nil {
+			if strings.HasPrefix(line, proxyStatusNameColumn) {
+				columns = parseProxyStatusHeader(line)
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		summary.Proxies++
+		for _, column := range columns {
+			counts := summary.countsFor(column.name)
+			if counts == nil {
+				continue
+			}
+			counts.add(column.valueFrom(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return SyncSummary{}, err
+	}
+
+	if columns == nil {
+		return SyncSummary{}, errors.New("the result of the proxy-status command does not contain a header")
+	}
+
+	return summary, nil
+}
+
+func parseProxyStatusHeader(header string) []proxyStatusColumn {
+	var columns []proxyStatusColumn
+	for i := 0; i < len(header); {
+		if header[i] == ' ' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(header) && header[i] != ' ' {
+			i++
+		}
+		if len(columns) > 0 {
+			columns[len(columns)-1].end = start
+		}
+		columns = append(columns, proxyStatusColumn{name: header[start:i], start: start, end: -1})
+	}
+	return columns
+}
+
+func (c proxyStatusColumn) valueFrom(line string) string {
+	if c.start >= len(line) {
+		return ""
+	}
+	if c.end < 0 || c.end > len(line) {
+		return strings.TrimSpace(line[c.start:])
+	}
+	return strings.TrimSpace(line[c.start:c.end])
+}
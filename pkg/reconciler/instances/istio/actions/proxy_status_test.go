@@ -0,0 +1,76 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const istioctlMockProxyStatus = `NAME                                                   CDS        LDS        EDS        RDS          ISTIOD                      VERSION
+details-v1-558b8b4b76-qzqsg.default                    SYNCED     SYNCED     SYNCED     SYNCED       istiod-6cf8d4f9cb-wm7x6     1.11.1
+istio-ingressgateway-8d7d49b55-bmlzm.istio-system      SYNCED     SYNCED     SYNCED     NOT SENT     istiod-6cf8d4f9cb-wm7x6     1.11.1
+productpage-v1-6987489c74-nc7tj.default                STALE      SYNCED     STALE      SYNCED       istiod-6cf8d4f9cb-wm7x6     1.11.1
+ratings-v1-7dc98c7588-5m6xj.default                    SYNCED     NOT SENT   SYNCED     STALE        istiod-6cf8d4f9cb-wm7x6     1.11.1
+`
+
+func Test_mapProxyStatusToSummary(t *testing.T) {
+
+	t.Run("should return an error when proxy-status output is empty", func(t *testing.T) {
+		// when
+		_, err := mapProxyStatusToSummary([]byte(""))
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "command is empty")
+	})
+
+	t.Run("should return an error when proxy-status output has no header", func(t *testing.T) {
+		// when
+		_, err := mapProxyStatusToSummary([]byte("Error: no running Istio pods in \"istio-system\""))
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain a header")
+	})
+
+	t.Run("should return empty summary when no proxies are reported", func(t *testing.T) {
+		// when
+		summary, err := mapProxyStatusToSummary([]byte("NAME     CDS     LDS     EDS     RDS     ISTIOD     VERSION\n"))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, SyncSummary{}, summary)
+		require.True(t, summary.IsSynced())
+	})
+
+	t.Run("should count sync states per config type for mixed output", func(t *testing.T) {
+		// when
+		summary, err := mapProxyStatusToSummary([]byte("some warning printed by istioctl\n" + istioctlMockProxyStatus))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 4, summary.Proxies)
+		require.Equal(t, SyncCounts{Synced: 3, Stale: 1}, summary.CDS)
+		require.Equal(t, SyncCounts{Synced: 3, NotSent: 1}, summary.LDS)
+		require.Equal(t, SyncCounts{Synced: 3, Stale: 1}, summary.EDS)
+		require.Equal(t, SyncCounts{Synced: 2, Stale: 1, NotSent: 1}, summary.RDS)
+		require.False(t, summary.IsSynced())
+	})
+
+	t.Run("should count states with details and unknown states", func(t *testing.T) {
+		// given
+		output := `NAME                                            CDS             LDS             EDS             RDS             ISTIOD                      VERSION
+details-v1-558b8b4b76-qzqsg.default             SYNCED (2m)     SYNCED (2m)     SYNCED (2m)     SYNCED (2m)     istiod-6cf8d4f9cb-wm7x6     1.14.1
+istio-ingressgateway-8d7d49b55.istio-system     SYNCED (5m)     SYNCED (5m)     IGNORED         NOT SENT        istiod-6cf8d4f9cb-wm7x6     1.14.1
+`
+		// when
+		summary, err := mapProxyStatusToSummary([]byte(output))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 2, summary.Proxies)
+		require.Equal(t, SyncCounts{Synced: 2}, summary.CDS)
+		require.Equal(t, SyncCounts{Synced: 1, Other: 1}, summary.EDS)
+		require.Equal(t, SyncCounts{Synced: 1, NotSent: 1}, summary.RDS)
+	})
+}
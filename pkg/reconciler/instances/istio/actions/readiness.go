@@ -0,0 +1,160 @@
+package actions
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgo "k8s.io/client-go/kubernetes"
+)
+
+const (
+	ingressGatewayDeploymentName = "istio-ingressgateway"
+	egressGatewayDeploymentName  = "istio-egressgateway"
+)
+
+type readinessComponent struct {
+	name string
+	// optional components are only awaited if their deployment exists, as they can be disabled in the IstioOperator.
+	optional bool
+}
+
+// readinessComponents lists the deployments awaited by WaitForReady.
+var readinessComponents = []readinessComponent{
+	{name: istiodDeploymentName},
+	{name: ingressGatewayDeploymentName, optional: true},
+	{name: egressGatewayDeploymentName, optional: true},
+}
+
+// ComponentReadiness is the state of an Istio component observed by WaitForReady.
+type ComponentReadiness struct {
+	Name string
+	// ReadyReplicas is the number of available replicas, Replicas the desired number.
+	ReadyReplicas int32
+	Replicas      int32
+	Ready         bool
+}
+
+// ReadinessProgressFunc is called by WaitForReady on each poll with the state of the awaited components.
+type ReadinessProgressFunc func(components []ComponentReadiness)
+
+// WaitForReady polls the readinessComponents until they are all ready, the timeout expired or ctx is done, whichever comes first.
+// The last observed state of the components is also returned together with an error.
+func (c *DefaultIstioPerformer) WaitForReady(ctx context.Context, kubeConfig string, timeout time.Duration, progress ReadinessProgressFunc, logger *zap.SugaredLogger) ([]ComponentReadiness, error) {
+	logger = operationLogger(logger, "WaitForReady", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = c.readinessTimeout
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var components []ComponentReadiness
+	err = wait.PollImmediateUntil(c.readinessInterval, func() (bool, error) {
+		components = c.componentsReadiness(pollCtx, kubeClient, logger)
+		if progress != nil {
+			progress(components)
+		}
+		return len(notReadyComponents(components)) == 0, nil
+	}, pollCtx.Done())
+	if err == wait.ErrWaitTimeout && ctx.Err() != nil {
+		return components, errors.Wrapf(ctx.Err(), "Waiting for Istio components cancelled, not ready: %s", strings.Join(notReadyComponents(components), ", "))
+	}
+	if err == wait.ErrWaitTimeout {
+		return components, errors.Errorf("Istio components not ready within %s: %s", timeout, strings.Join(notReadyComponents(components), ", "))
+	}
+	if err != nil {
+		return components, err
+	}
+
+	logger.Info("Istio control plane is ready")
+	return components, nil
+}
+
+// componentsReadiness returns the readiness of the readinessComponents, optional components which are not installed are omitted.
+func (c *DefaultIstioPerformer) componentsReadiness(ctx context.Context, kubeClient clientgo.Interface, logger *zap.SugaredLogger) []ComponentReadiness {
+	var components []ComponentReadiness
+	for _, component := range readinessComponents {
+		deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(ctx, component.name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) && component.optional {
+			continue
+		}
+		if err != nil {
+			logger.Debugf("Could not get %s deployment: %s", component.name, err)
+			components = append(components, ComponentReadiness{Name: component.name, Replicas: 1})
+			continue
+		}
+		components = append(components, ComponentReadiness{
+			Name:          component.name,
+			ReadyReplicas: deployment.Status.AvailableReplicas,
+			Replicas:      desiredReplicas(deployment),
+			Ready:         isDeploymentReady(deployment),
+		})
+	}
+	return components
+}
+
+// checkControlPlaneHealthy returns an error if istiod is not installed or not available, e.g. during a control plane incident.
+func (c *DefaultIstioPerformer) checkControlPlaneHealthy(ctx context.Context, kubeClient clientgo.Interface) error {
+	deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(ctx, istiodDeploymentName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return errors.Wrapf(ErrIstioNotInstalled, "%s deployment not found in namespace %s", istiodDeploymentName, c.namespace)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Could not get %s deployment", istiodDeploymentName)
+	}
+	if !isDeploymentAvailable(deployment) {
+		return errors.Errorf("Istio control plane is degraded, %s has %d of %d replicas available",
+			istiodDeploymentName, deployment.Status.AvailableReplicas, desiredReplicas(deployment))
+	}
+	return nil
+}
+
+// notReadyComponents returns the names of the components which are not ready.
+func notReadyComponents(components []ComponentReadiness) []string {
+	var notReady []string
+	for _, component := range components {
+		if !component.Ready {
+			notReady = append(notReady, component.Name)
+		}
+	}
+	return notReady
+}
+
+// isDeploymentReady returns true if the rollout of the current spec of the deployment reached the desired replicas, which are all available.
+// It is awaited after installations and updates, while a deployment which is only available is still serving, e.g. during a rollout.
+func isDeploymentReady(deployment *appsv1.Deployment) bool {
+	return isDeploymentAvailable(deployment) &&
+		deployment.Status.UpdatedReplicas >= desiredReplicas(deployment)
+}
+
+// isDeploymentAvailable returns true if the desired replicas of the deployment are available, regardless of the spec they run.
+func isDeploymentAvailable(deployment *appsv1.Deployment) bool {
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.AvailableReplicas >= desiredReplicas(deployment)
+}
+
+// desiredReplicas returns the replicas of the deployment spec, which default to one.
+func desiredReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas != nil {
+		return *deployment.Spec.Replicas
+	}
+	return 1
+}
@@ -0,0 +1,159 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_WaitForReady(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		_, err := wrapper.WaitForReady(context.TODO(), kubeConfig, time.Second, nil, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
+
+	t.Run("should succeed when istiod and the installed gateways are available", func(t *testing.T) {
+		// given
+		provider := providerWithDeployments(
+			fixAvailableDeployment("istiod", true),
+			fixAvailableDeployment("istio-ingressgateway", true),
+		)
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, WithReadinessTimeout(time.Second, 10*time.Millisecond))
+
+		// when
+		components, err := wrapper.WaitForReady(context.TODO(), kubeConfig, 0, nil, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []ComponentReadiness{
+			{Name: "istiod", ReadyReplicas: 2, Replicas: 2, Ready: true},
+			{Name: "istio-ingressgateway", ReadyReplicas: 2, Replicas: 2, Ready: true},
+		}, components)
+	})
+
+	t.Run("should list all components which did not become ready within the timeout", func(t *testing.T) {
+		// given
+		provider := providerWithDeployments(
+			fixAvailableDeployment("istio-ingressgateway", false),
+			fixAvailableDeployment("istio-egressgateway", true),
+		)
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, WithReadinessTimeout(time.Second, 10*time.Millisecond))
+
+		// when
+		components, err := wrapper.WaitForReady(context.TODO(), kubeConfig, 50*time.Millisecond, nil, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio components not ready within 50ms: istiod, istio-ingressgateway")
+		require.NotContains(t, err.Error(), "istio-egressgateway")
+		require.Len(t, components, 3)
+	})
+
+	t.Run("should not report a component as ready while its rollout is in progress", func(t *testing.T) {
+		// given
+		rollingOut := fixAvailableDeployment("istiod", true)
+		rollingOut.Status.UpdatedReplicas = 1
+		provider := providerWithDeployments(rollingOut)
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, WithReadinessTimeout(time.Second, 10*time.Millisecond))
+
+		// when
+		components, err := wrapper.WaitForReady(context.TODO(), kubeConfig, 50*time.Millisecond, nil, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio components not ready within 50ms: istiod")
+		require.Equal(t, []ComponentReadiness{{Name: "istiod", ReadyReplicas: 2, Replicas: 2, Ready: false}}, components)
+	})
+
+	t.Run("should report the progress on each poll", func(t *testing.T) {
+		// given
+		provider := providerWithDeployments(
+			fixAvailableDeployment("istiod", true),
+			fixAvailableDeployment("istio-ingressgateway", false),
+		)
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, WithReadinessTimeout(time.Second, 10*time.Millisecond))
+		var polls [][]ComponentReadiness
+
+		// when
+		_, err := wrapper.WaitForReady(context.TODO(), kubeConfig, 50*time.Millisecond, func(components []ComponentReadiness) {
+			polls = append(polls, components)
+		}, log)
+
+		// then
+		require.Error(t, err)
+		require.Greater(t, len(polls), 1)
+		require.Equal(t, []ComponentReadiness{
+			{Name: "istiod", ReadyReplicas: 2, Replicas: 2, Ready: true},
+			{Name: "istio-ingressgateway", ReadyReplicas: 1, Replicas: 2, Ready: false},
+		}, polls[len(polls)-1])
+	})
+
+	t.Run("should return promptly with the last observed state when the context is cancelled", func(t *testing.T) {
+		// given
+		provider := providerWithDeployments(fixAvailableDeployment("istio-ingressgateway", true))
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, WithReadinessTimeout(time.Minute, 10*time.Millisecond))
+		ctx, cancel := context.WithCancel(context.Background())
+		polls := 0
+		start := time.Now()
+
+		// when
+		components, err := wrapper.WaitForReady(ctx, kubeConfig, 0, func(components []ComponentReadiness) {
+			polls++
+			if polls == 2 {
+				cancel()
+			}
+		}, log)
+
+		// then
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Contains(t, err.Error(), "Waiting for Istio components cancelled, not ready: istiod")
+		require.Less(t, time.Since(start), time.Second)
+		require.Equal(t, []ComponentReadiness{
+			{Name: "istiod", Replicas: 1},
+			{Name: "istio-ingressgateway", ReadyReplicas: 2, Replicas: 2, Ready: true},
+		}, components)
+	})
+}
+
+func providerWithDeployments(deployments ...runtime.Object) *clientsetmocks.Provider {
+	provider := clientsetmocks.Provider{}
+	provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(deployments...), nil)
+	return &provider
+}
+
+func fixAvailableDeployment(name string, available bool) *appsv1.Deployment {
+	replicas := int32(2)
+	availableReplicas := replicas
+	if !available {
+		availableReplicas = 1
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: replicas, AvailableReplicas: availableReplicas},
+	}
+}
@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CleanupResetArtifacts deletes the ConfigMap holding the proxy reset checkpoints in the Istio namespace. Nothing else is touched,
+// the restartedAt annotations of the reset workloads are part of their rollout and stay.
+func (c *DefaultIstioPerformer) CleanupResetArtifacts(kubeConfig string, logger *zap.SugaredLogger) error {
+	logger = operationLogger(logger, "CleanupResetArtifacts", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	// a running reset on this cluster still needs its checkpoint
+	unlock := c.clusterLocks.lock(kubeConfig)
+	defer unlock()
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return err
+	}
+
+	configMaps := kubeClient.CoreV1().ConfigMaps(c.namespace)
+	configMap, err := configMaps.Get(context.Background(), proxy.CheckpointConfigMapName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		logger.Debug("No proxy reset checkpoints found")
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "Could not get the proxy reset checkpoints")
+	}
+
+	for key, data := range configMap.Data {
+		var checkpoint proxy.Checkpoint
+		if err := json.Unmarshal([]byte(data), &checkpoint); err != nil {
+			logger.Infof("Removing unreadable proxy reset checkpoint %s", key)
+			continue
+		}
+		logger.Infof("Removing checkpoint of interrupted proxy reset to %s, %d objects were reset", checkpoint.Image, len(checkpoint.Done))
+	}
+
+	err = configMaps.Delete(context.Background(), proxy.CheckpointConfigMapName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "Could not delete the proxy reset checkpoints")
+	}
+	return nil
+}
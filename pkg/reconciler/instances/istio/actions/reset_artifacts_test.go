@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func fixConfigMap(name, namespace string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Data: data}
+}
+
+func Test_DefaultIstioPerformer_CleanupResetArtifacts(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		err := wrapper.CleanupResetArtifacts(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
+
+	t.Run("should remove the checkpoints of interrupted resets and keep other ConfigMaps", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			fixConfigMap(proxy.CheckpointConfigMapName, "istio-system", map[string]string{
+				"0123456789abcdef": `{"image":"istio/proxyv2:1.11.4","done":[{"Name":"app","Namespace":"default","Kind":"Deployment"}]}`,
+				"fedcba9876543210": "not json",
+			}),
+			fixConfigMap("istio", "istio-system", map[string]string{"mesh": ""}),
+			fixConfigMap(proxy.CheckpointConfigMapName, "default", nil),
+		)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		err := wrapper.CleanupResetArtifacts(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		_, err = kubeClient.CoreV1().ConfigMaps("istio-system").Get(context.TODO(), proxy.CheckpointConfigMapName, metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+		_, err = kubeClient.CoreV1().ConfigMaps("istio-system").Get(context.TODO(), "istio", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = kubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), proxy.CheckpointConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("should do nothing when there are no checkpoints", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		err := wrapper.CleanupResetArtifacts(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+	})
+}
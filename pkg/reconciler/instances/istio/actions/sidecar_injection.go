@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	injectionLabel        = "istio-injection"
+	injectionLabelEnabled = "enabled"
+	revisionLabel         = "istio.io/rev"
+	revisionTagLabel      = "istio.io/tag"
+)
+
+// NamespaceInjectionStatus describes whether Istio sidecars are injected into the pods of a namespace.
+type NamespaceInjectionStatus struct {
+	Namespace string
+	// InjectionEnabled is true if the namespace has the istio-injection=enabled label.
+	InjectionEnabled bool
+	// Revision is the value of the istio.io/rev label of the namespace, if set.
+	Revision string
+	// RevisionMatches is true if the Revision is the revision or revision tag of the WebhookConfiguration.
+	RevisionMatches bool
+	// MatchingWebhooks lists the webhooks of the WebhookConfiguration whose namespace selector matches the namespace.
+	// Object selectors are not evaluated, so a matching webhook might still skip some pods.
+	MatchingWebhooks []string
+}
+
+// Enabled returns true if sidecar injection is enabled by the labels of the namespace.
+func (s NamespaceInjectionStatus) Enabled() bool {
+	return s.InjectionEnabled || s.RevisionMatches
+}
+
+// SidecarInjectionStatus describes the sidecar injection of namespaces by the Istio MutatingWebhookConfiguration.
+type SidecarInjectionStatus struct {
+	// WebhookConfiguration is the name of the selected MutatingWebhookConfiguration.
+	WebhookConfiguration string
+	Namespaces           []NamespaceInjectionStatus
+}
+
+func (c *DefaultIstioPerformer) SidecarInjectionStatus(context context.Context, kubeClient kubernetes.Client, namespaces []string, logger *zap.SugaredLogger) (SidecarInjectionStatus, error) {
+	logger = operationLogger(logger, "SidecarInjectionStatus", "", kubeClient.Kubeconfig())
+
+	clientSet, err := kubeClient.Clientset()
+	if err != nil {
+		return SidecarInjectionStatus{}, err
+	}
+
+	whConf, err := c.selectWebhookConfFormCandidates(context, c.webhookCandidates, clientSet, logger)
+	if err != nil {
+		return SidecarInjectionStatus{}, err
+	}
+
+	webhookRevisions := map[string]bool{}
+	for _, label := range []string{revisionLabel, revisionTagLabel} {
+		if value, ok := whConf.Labels[label]; ok {
+			webhookRevisions[value] = true
+		}
+	}
+
+	status := SidecarInjectionStatus{WebhookConfiguration: whConf.Name}
+	for _, name := range namespaces {
+		namespace, err := clientSet.CoreV1().Namespaces().Get(context, name, metav1.GetOptions{})
+		if err != nil {
+			return SidecarInjectionStatus{}, errors.Wrapf(err, "Could not get namespace %s", name)
+		}
+
+		namespaceStatus := NamespaceInjectionStatus{
+			Namespace:        name,
+			InjectionEnabled: namespace.Labels[injectionLabel] == injectionLabelEnabled,
+			Revision:         namespace.Labels[revisionLabel],
+		}
+		namespaceStatus.RevisionMatches = namespaceStatus.Revision != "" && webhookRevisions[namespaceStatus.Revision]
+
+		for _, webhook := range whConf.Webhooks {
+			selector, err := namespaceSelectorOf(webhook)
+			if err != nil {
+				return SidecarInjectionStatus{}, errors.Wrapf(err, "Invalid namespace selector of webhook %s in %s", webhook.Name, whConf.Name)
+			}
+			if selector.Matches(labels.Set(namespace.Labels)) {
+				namespaceStatus.MatchingWebhooks = append(namespaceStatus.MatchingWebhooks, webhook.Name)
+			}
+		}
+
+		if !namespaceStatus.Enabled() {
+			logger.Warnf("Sidecar injection is not enabled for namespace %s", name)
+		}
+		status.Namespaces = append(status.Namespaces, namespaceStatus)
+	}
+	return status, nil
+}
+
+// namespaceSelectorOf returns the namespace selector of the webhook, which selects all namespaces if it is not set.
+func namespaceSelectorOf(webhook v1.MutatingWebhook) (labels.Selector, error) {
+	if webhook.NamespaceSelector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(webhook.NamespaceSelector)
+}
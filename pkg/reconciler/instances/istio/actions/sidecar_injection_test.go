@@ -0,0 +1,139 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_SidecarInjectionStatus(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should return error when kubeclient had returned an error", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(nil, errors.New("kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.SidecarInjectionStatus(context.TODO(), &kubeClient, []string{"default"}, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "kubeclient error")
+	})
+
+	t.Run("should report injection labels and matching webhooks of the namespaces", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(
+			fixInjectionWebhookConf(),
+			fixNamespace("enabled", map[string]string{"istio-injection": "enabled"}),
+			fixNamespace("revision", map[string]string{"istio.io/rev": "default"}),
+			fixNamespace("other-revision", map[string]string{"istio.io/rev": "canary"}),
+			fixNamespace("unlabeled", nil),
+		)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		status, err := wrapper.SidecarInjectionStatus(context.TODO(), &kubeClient, []string{"enabled", "revision", "other-revision", "unlabeled"}, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, SidecarInjectionStatus{
+			WebhookConfiguration: "istio-revision-tag-default",
+			Namespaces: []NamespaceInjectionStatus{
+				{Namespace: "enabled", InjectionEnabled: true, MatchingWebhooks: []string{"namespace.sidecar-injector.istio.io"}},
+				{Namespace: "revision", Revision: "default", RevisionMatches: true, MatchingWebhooks: []string{"rev.namespace.sidecar-injector.istio.io"}},
+				{Namespace: "other-revision", Revision: "canary"},
+				{Namespace: "unlabeled", MatchingWebhooks: []string{"auto.sidecar-injector.istio.io"}},
+			},
+		}, status)
+		require.True(t, status.Namespaces[0].Enabled())
+		require.True(t, status.Namespaces[1].Enabled())
+		require.False(t, status.Namespaces[2].Enabled())
+		require.False(t, status.Namespaces[3].Enabled())
+	})
+
+	t.Run("should return error when a namespace does not exist", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(fixInjectionWebhookConf()), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.SidecarInjectionStatus(context.TODO(), &kubeClient, []string{"missing"}, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not get namespace missing")
+	})
+
+	t.Run("should return error when no webhook configuration exists", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		_, err := wrapper.SidecarInjectionStatus(context.TODO(), &kubeClient, []string{"default"}, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "MutatingWebhookConfigurations could not be selected from candidates")
+	})
+}
+
+// fixInjectionWebhookConf returns the webhooks of an Istio revision tag with their default namespace selectors.
+func fixInjectionWebhookConf() *v1.MutatingWebhookConfiguration {
+	return &v1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "istio-revision-tag-default",
+			Labels: map[string]string{"istio.io/rev": "1-11", "istio.io/tag": "default"},
+		},
+		Webhooks: []v1.MutatingWebhook{
+			{
+				Name: "rev.namespace.sidecar-injector.istio.io",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "istio.io/rev", Operator: metav1.LabelSelectorOpIn, Values: []string{"default"}},
+						{Key: "istio-injection", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				},
+			},
+			{
+				Name: "namespace.sidecar-injector.istio.io",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"istio-injection": "enabled"},
+				},
+			},
+			{
+				Name: "auto.sidecar-injector.istio.io",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "istio-injection", Operator: metav1.LabelSelectorOpDoesNotExist},
+						{Key: "istio.io/rev", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fixNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SidecarVersionCount is the number of Istio sidecars running a version.
+type SidecarVersionCount struct {
+	Version string
+	Count   int
+}
+
+// NamespaceSidecarStats tallies the Istio sidecars of a namespace.
+type NamespaceSidecarStats struct {
+	// Sidecars is the number of pods running an Istio sidecar.
+	Sidecars int
+	// Versions counts the sidecars per proxy version, without the distroless suffix, sorted by version.
+	Versions []SidecarVersionCount
+}
+
+func (c *DefaultIstioPerformer) SidecarInventory(kubeConfig string, logger *zap.SugaredLogger) (map[string]NamespaceSidecarStats, error) {
+	logger = operationLogger(logger, "SidecarInventory", "", kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	pods, err := kubeClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list pods")
+	}
+
+	inventory := sidecarInventoryFrom(*pods)
+	logger.Infof("Found Istio sidecars in %d namespaces", len(inventory))
+
+	return inventory, nil
+}
+
+// sidecarInventoryFrom tallies the pods running an Istio proxy per namespace and proxy version.
+func sidecarInventoryFrom(pods v1.PodList) map[string]NamespaceSidecarStats {
+	counts := make(map[string]map[string]int)
+	for _, pod := range pods.Items {
+		proxyVersion, ok := proxyVersionOf(pod)
+		if !ok {
+			continue
+		}
+		if counts[pod.Namespace] == nil {
+			counts[pod.Namespace] = make(map[string]int)
+		}
+		counts[pod.Namespace][proxyVersion]++
+	}
+
+	inventory := make(map[string]NamespaceSidecarStats, len(counts))
+	for namespace, versions := range counts {
+		stats := NamespaceSidecarStats{}
+		for version, count := range versions {
+			stats.Sidecars += count
+			stats.Versions = append(stats.Versions, SidecarVersionCount{Version: version, Count: count})
+		}
+		sort.Slice(stats.Versions, func(i, j int) bool {
+			return stats.Versions[i].Version < stats.Versions[j].Version
+		})
+		inventory[namespace] = stats
+	}
+	return inventory
+}
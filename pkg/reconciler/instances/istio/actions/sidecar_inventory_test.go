@@ -0,0 +1,90 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_sidecarInventoryFrom(t *testing.T) {
+
+	t.Run("should return an empty inventory for no pods", func(t *testing.T) {
+		// when
+		inventory := sidecarInventoryFrom(v1.PodList{})
+
+		// then
+		require.Empty(t, inventory)
+	})
+
+	t.Run("should count sidecars per namespace and version sorted by version", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPodWithProxyImage("app-1", "default", "istio/proxyv2:1.11.4-distroless", "", "", ""),
+			fixPodWithProxyImage("app-2", "default", "istio/proxyv2:1.10.2", "", "", ""),
+			fixPodWithProxyImage("app-3", "default", "istio/proxyv2:1.11.4", "", "", ""),
+			fixPodWithProxyImage("job", "kyma-system", "istio/proxyv2:1.11.4", "", "", ""),
+			fixPodWithProxyImage("no-proxy", "kyma-system", "nginx:1.21", "", "", ""),
+			fixPodWithProxyImage("no-proxy", "no-sidecars", "nginx:1.21", "", "", ""),
+		}}
+
+		// when
+		inventory := sidecarInventoryFrom(pods)
+
+		// then
+		require.Equal(t, map[string]NamespaceSidecarStats{
+			"default": {Sidecars: 3, Versions: []SidecarVersionCount{
+				{Version: "1.10.2", Count: 1},
+				{Version: "1.11.4", Count: 2},
+			}},
+			"kyma-system": {Sidecars: 1, Versions: []SidecarVersionCount{{Version: "1.11.4", Count: 1}}},
+		}, inventory)
+	})
+}
+
+func Test_DefaultIstioPerformer_SidecarInventory(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		_, err := wrapper.SidecarInventory(kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
+
+	t.Run("should count the sidecars on the cluster", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			fixRunningPodWithProxy("app-1", "default", "1.11.4-distroless", "StatefulSet", "app"),
+			fixRunningPodWithProxy("app-2", "default", "1.11.4-distroless", "StatefulSet", "app"),
+			fixRunningPodWithProxy("job-1", "kyma-system", "1.10.2", "Job", "job"),
+		)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		inventory, err := wrapper.SidecarInventory(kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, map[string]NamespaceSidecarStats{
+			"default":     {Sidecars: 2, Versions: []SidecarVersionCount{{Version: "1.11.4", Count: 2}}},
+			"kyma-system": {Sidecars: 1, Versions: []SidecarVersionCount{{Version: "1.10.2", Count: 1}}},
+		}, inventory)
+	})
+}
@@ -0,0 +1,108 @@
+package actions
+
+import (
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	distrolessSuffix = "-distroless"
+	podTemplateHash  = "pod-template-hash"
+)
+
+// StaleProxy is a pod running an Istio proxy in a version other than expected.
+type StaleProxy struct {
+	Pod          string
+	ProxyVersion string
+}
+
+// StaleWorkload groups the stale proxies of a single workload.
+// Pods without an owner are reported as separate workloads of kind Pod.
+type StaleWorkload struct {
+	Kind    string
+	Name    string
+	Proxies []StaleProxy
+}
+
+// StaleProxies maps namespaces to their workloads with stale proxies.
+type StaleProxies map[string][]StaleWorkload
+
+// Count returns the number of stale proxies in all namespaces.
+func (s StaleProxies) Count() int {
+	count := 0
+	for _, workloads := range s {
+		for _, workload := range workloads {
+			count += len(workload.Proxies)
+		}
+	}
+	return count
+}
+
+func staleProxiesFrom(pods v1.PodList, expectedVersion string) StaleProxies {
+	expectedVersion = strings.TrimSuffix(expectedVersion, distrolessSuffix)
+	return proxiesFrom(pods, func(proxyVersion string) bool {
+		return proxyVersion != expectedVersion
+	})
+}
+
+// proxiesFrom groups the pods running an Istio proxy in a version for which include returns true by namespace and workload.
+func proxiesFrom(pods v1.PodList, include func(proxyVersion string) bool) StaleProxies {
+	stale := StaleProxies{}
+	workloadIndex := make(map[workloadKey]int)
+
+	for _, pod := range pods.Items {
+		proxyVersion, ok := proxyVersionOf(pod)
+		if !ok || !include(proxyVersion) {
+			continue
+		}
+
+		kind, name := workloadOf(pod)
+		key := workloadKey{namespace: pod.Namespace, kind: kind, name: name}
+		i, exists := workloadIndex[key]
+		if !exists {
+			i = len(stale[pod.Namespace])
+			workloadIndex[key] = i
+			stale[pod.Namespace] = append(stale[pod.Namespace], StaleWorkload{Kind: kind, Name: name})
+		}
+		stale[pod.Namespace][i].Proxies = append(stale[pod.Namespace][i].Proxies, StaleProxy{Pod: pod.Name, ProxyVersion: proxyVersion})
+	}
+
+	for _, workloads := range stale {
+		sort.SliceStable(workloads, func(i, j int) bool {
+			if workloads[i].Kind != workloads[j].Kind {
+				return workloads[i].Kind < workloads[j].Kind
+			}
+			return workloads[i].Name < workloads[j].Name
+		})
+	}
+	return stale
+}
+
+// proxyVersionOf returns the version of the Istio proxy image without the distroless suffix, if the pod runs an Istio proxy.
+func proxyVersionOf(pod v1.Pod) (string, bool) {
+	for _, container := range pod.Spec.Containers {
+		if !strings.Contains(container.Image, istioImagePrefix) {
+			continue
+		}
+		version := ""
+		if i := strings.LastIndex(container.Image, ":"); i >= 0 {
+			version = container.Image[i+1:]
+		}
+		return strings.TrimSuffix(version, distrolessSuffix), true
+	}
+	return "", false
+}
+
+// workloadOf returns the kind and name of the workload owning the pod, resolving ReplicaSets to their Deployment.
+func workloadOf(pod v1.Pod) (kind, name string) {
+	if len(pod.OwnerReferences) == 0 {
+		return "Pod", pod.Name
+	}
+	owner := pod.OwnerReferences[0]
+	if hash, ok := pod.Labels[podTemplateHash]; ok && owner.Kind == "ReplicaSet" && strings.HasSuffix(owner.Name, "-"+hash) {
+		return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Kind, owner.Name
+}
@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_staleProxiesFrom(t *testing.T) {
+
+	t.Run("should return no stale proxies for no pods", func(t *testing.T) {
+		// when
+		stale := staleProxiesFrom(v1.PodList{}, "1.11.4")
+
+		// then
+		require.Empty(t, stale)
+		require.Zero(t, stale.Count())
+	})
+
+	t.Run("should group stale proxies by namespace and workload", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPodWithProxyImage("app-5d8f-abc", "default", "istio/proxyv2:1.10.2-distroless", "ReplicaSet", "app-5d8f", "5d8f"),
+			fixPodWithProxyImage("app-5d8f-def", "default", "istio/proxyv2:1.10.2", "ReplicaSet", "app-5d8f", "5d8f"),
+			fixPodWithProxyImage("db-0", "default", "istio/proxyv2:1.10.2", "StatefulSet", "db", ""),
+			fixPodWithProxyImage("up-to-date", "default", "istio/proxyv2:1.11.4-distroless", "ReplicaSet", "other-7c9b", "7c9b"),
+			fixPodWithProxyImage("standalone", "kyma-system", "istio/proxyv2:1.9.0", "", "", ""),
+			fixPodWithProxyImage("no-proxy", "kyma-system", "nginx:1.21", "", "", ""),
+		}}
+
+		// when
+		stale := staleProxiesFrom(pods, "1.11.4")
+
+		// then
+		require.Equal(t, StaleProxies{
+			"default": {
+				{Kind: "Deployment", Name: "app", Proxies: []StaleProxy{
+					{Pod: "app-5d8f-abc", ProxyVersion: "1.10.2"},
+					{Pod: "app-5d8f-def", ProxyVersion: "1.10.2"},
+				}},
+				{Kind: "StatefulSet", Name: "db", Proxies: []StaleProxy{{Pod: "db-0", ProxyVersion: "1.10.2"}}},
+			},
+			"kyma-system": {
+				{Kind: "Pod", Name: "standalone", Proxies: []StaleProxy{{Pod: "standalone", ProxyVersion: "1.9.0"}}},
+			},
+		}, stale)
+		require.Equal(t, 4, stale.Count())
+	})
+
+	t.Run("should accept expected version with distroless suffix", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPodWithProxyImage("app", "default", "istio/proxyv2:1.11.4-distroless", "", "", ""),
+		}}
+
+		// when
+		stale := staleProxiesFrom(pods, "1.11.4-distroless")
+
+		// then
+		require.Empty(t, stale)
+	})
+
+	t.Run("should keep ReplicaSets not matching the pod template hash", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPodWithProxyImage("app-abc", "default", "istio/proxyv2:1.10.2", "ReplicaSet", "standalone-rs", ""),
+		}}
+
+		// when
+		stale := staleProxiesFrom(pods, "1.11.4")
+
+		// then
+		require.Equal(t, "ReplicaSet", stale["default"][0].Kind)
+		require.Equal(t, "standalone-rs", stale["default"][0].Name)
+	})
+}
+
+func fixPodWithProxyImage(name, namespace, image, ownerKind, ownerName, templateHash string) v1.Pod {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1.PodSpec{Containers: []v1.Container{
+			{Name: "app", Image: "app:1.0.0"},
+			{Name: "istio-proxy", Image: image},
+		}},
+	}
+	if ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName}}
+	}
+	if templateHash != "" {
+		pod.Labels = map[string]string{"pod-template-hash": templateHash}
+	}
+	return pod
+}
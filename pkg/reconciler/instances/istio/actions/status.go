@@ -0,0 +1,29 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Equal returns true if both statuses describe the same Istio installation on the cluster.
+// ClientVersion and BinaryPath are ignored, as they reflect the local istioctl binary and not the cluster state.
+func (s IstioStatus) Equal(other IstioStatus) bool {
+	return s.Diff(other) == ""
+}
+
+// Diff returns a human-readable list of the fields which differ from the other status, e.g. for logging, or an empty string if both are Equal.
+func (s IstioStatus) Diff(other IstioStatus) string {
+	var diffs []string
+	addDiff := func(field string, value, otherValue interface{}) {
+		if value != otherValue {
+			diffs = append(diffs, fmt.Sprintf("%s: %q -> %q", field, fmt.Sprint(value), fmt.Sprint(otherValue)))
+		}
+	}
+	addDiff("TargetVersion", s.TargetVersion, other.TargetVersion)
+	addDiff("TargetVersionSource", s.TargetVersionSource, other.TargetVersionSource)
+	addDiff("TargetVersionValuePath", s.TargetVersionValuePath, other.TargetVersionValuePath)
+	addDiff("PilotVersion", s.PilotVersion, other.PilotVersion)
+	addDiff("DataPlaneVersion", s.DataPlaneVersion, other.DataPlaneVersion)
+	addDiff("DataPlanePresent", s.DataPlanePresent, other.DataPlanePresent)
+	return strings.Join(diffs, ", ")
+}
@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IstioStatus_Equal(t *testing.T) {
+
+	status := IstioStatus{
+		ClientVersion:       "1.11.1",
+		TargetVersion:       "1.11.2",
+		PilotVersion:        "1.11.1",
+		DataPlaneVersion:    "1.11.1",
+		DataPlanePresent:    true,
+		TargetVersionSource: TargetVersionSourceAppVersion,
+		BinaryPath:          "/bin/istioctl-1.11.1",
+	}
+
+	t.Run("should be equal when only the client version differs", func(t *testing.T) {
+		// given
+		other := status
+		other.ClientVersion = "1.11.2"
+		other.BinaryPath = "/bin/istioctl-1.11.2"
+
+		// when
+		equal := status.Equal(other)
+		diff := status.Diff(other)
+
+		// then
+		require.True(t, equal)
+		require.Empty(t, diff)
+	})
+
+	t.Run("should not be equal when the cluster state differs", func(t *testing.T) {
+		// given
+		other := status
+		other.PilotVersion = "1.11.2"
+		other.DataPlanePresent = false
+
+		// when
+		equal := status.Equal(other)
+		diff := status.Diff(other)
+
+		// then
+		require.False(t, equal)
+		require.Equal(t, `PilotVersion: "1.11.1" -> "1.11.2", DataPlanePresent: "true" -> "false"`, diff)
+	})
+
+	t.Run("should not report the client version in the diff", func(t *testing.T) {
+		// given
+		other := status
+		other.ClientVersion = "1.11.2"
+		other.TargetVersion = "1.11.3"
+
+		// when
+		diff := status.Diff(other)
+
+		// then
+		require.Equal(t, `TargetVersion: "1.11.2" -> "1.11.3"`, diff)
+	})
+}
@@ -0,0 +1,241 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	clientgo "k8s.io/client-go/kubernetes"
+)
+
+// ResourceState is the state of a resource expected by VerifyInstall.
+type ResourceState string
+
+const (
+	// ResourceStatePresent means that the resource exists as expected.
+	ResourceStatePresent ResourceState = "present"
+	// ResourceStateMissing means that the resource does not exist.
+	ResourceStateMissing ResourceState = "missing"
+	// ResourceStateMismatch means that the resource exists but does not match the expectation, the reason tells why.
+	ResourceStateMismatch ResourceState = "mismatch"
+)
+
+// VerifiedResource is the verification result of a single resource expected by VerifyInstall.
+type VerifiedResource struct {
+	Kind string
+	Name string
+	// Namespace is empty for cluster scoped resources.
+	Namespace string
+	State     ResourceState
+	// Reason describes why the resource is missing or mismatching.
+	Reason string
+}
+
+// InstallVerification lists the verified resources of the Istio installation.
+type InstallVerification struct {
+	Resources []VerifiedResource
+}
+
+// Installed returns true if all expected resources are present.
+func (v InstallVerification) Installed() bool {
+	return len(v.Failed()) == 0
+}
+
+// Failed returns the resources which are missing or mismatching.
+func (v InstallVerification) Failed() []VerifiedResource {
+	var failed []VerifiedResource
+	for _, resource := range v.Resources {
+		if resource.State != ResourceStatePresent {
+			failed = append(failed, resource)
+		}
+	}
+	return failed
+}
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// expectedIstioCRDs lists the CRDs verified by VerifyInstall.
+var expectedIstioCRDs = []string{
+	"authorizationpolicies.security.istio.io",
+	"destinationrules.networking.istio.io",
+	"envoyfilters.networking.istio.io",
+	"gateways.networking.istio.io",
+	"peerauthentications.security.istio.io",
+	"requestauthentications.security.istio.io",
+	"serviceentries.networking.istio.io",
+	"sidecars.networking.istio.io",
+	"virtualservices.networking.istio.io",
+}
+
+func (c *DefaultIstioPerformer) VerifyInstall(kubeConfig, version string, logger *zap.SugaredLogger) (InstallVerification, error) {
+	logger = operationLogger(logger, "VerifyInstall", version, kubeConfig)
+
+	kubeConfig, logger, err := c.resolveKubeconfig(kubeConfig, logger)
+	if err != nil {
+		return InstallVerification{}, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		return InstallVerification{}, err
+	}
+	dynamicClient, err := c.dynamicProvider.RetrieveDynamicFrom(kubeConfig, logger)
+	if err != nil {
+		return InstallVerification{}, err
+	}
+
+	ctx, cancel := c.operationContext()
+	defer cancel()
+
+	var resources []VerifiedResource
+	for _, name := range expectedIstioCRDs {
+		resource, err := verifyCRD(ctx, dynamicClient, name)
+		if err != nil {
+			return InstallVerification{}, err
+		}
+		resources = append(resources, resource)
+	}
+	for _, component := range readinessComponents {
+		resource, err := c.verifyDeployment(ctx, kubeClient, component, strings.TrimSuffix(version, distrolessSuffix))
+		if err != nil {
+			return InstallVerification{}, err
+		}
+		if resource != nil {
+			resources = append(resources, *resource)
+		}
+	}
+	resource, err := c.verifyMutatingWebhook(ctx, kubeClient)
+	if err != nil {
+		return InstallVerification{}, err
+	}
+	resources = append(resources, resource)
+	resource, err = c.verifyValidatingWebhook(ctx, kubeClient)
+	if err != nil {
+		return InstallVerification{}, err
+	}
+	resources = append(resources, resource)
+
+	verification := InstallVerification{Resources: resources}
+	logger.Debugf("Verified %d Istio resources, %d missing or mismatching", len(resources), len(verification.Failed()))
+	return verification, nil
+}
+
+// verifyCRD reports a CRD which is not established as mismatching.
+func verifyCRD(ctx context.Context, dynamicClient dynamic.Interface, name string) (VerifiedResource, error) {
+	resource := VerifiedResource{Kind: "CustomResourceDefinition", Name: name}
+	crd, err := dynamicClient.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return withState(resource, ResourceStateMissing, ""), nil
+	}
+	if err != nil {
+		return resource, errors.Wrapf(err, "Could not get CustomResourceDefinition %s", name)
+	}
+	if !isCRDEstablished(crd) {
+		return withState(resource, ResourceStateMismatch, "not established"), nil
+	}
+	return withState(resource, ResourceStatePresent, ""), nil
+}
+
+func isCRDEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]interface{})
+		if ok && fields["type"] == "Established" && fields["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyDeployment reports a deployment without available replicas or running another version than expected as mismatching.
+// Optional components which do not exist are not reported.
+func (c *DefaultIstioPerformer) verifyDeployment(ctx context.Context, kubeClient clientgo.Interface, component readinessComponent, version string) (*VerifiedResource, error) {
+	resource := VerifiedResource{Kind: "Deployment", Name: component.name, Namespace: c.namespace}
+	deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(ctx, component.name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		if component.optional {
+			return nil, nil
+		}
+		resource = withState(resource, ResourceStateMissing, "")
+		return &resource, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not get Deployment %s/%s", c.namespace, component.name)
+	}
+
+	if imageVersion := deploymentImageVersion(deployment); version != "" && imageVersion != version {
+		resource = withState(resource, ResourceStateMismatch, fmt.Sprintf("runs version %s instead of %s", imageVersion, version))
+	} else if !isDeploymentAvailable(deployment) {
+		resource = withState(resource, ResourceStateMismatch, "no available replicas")
+	} else {
+		resource = withState(resource, ResourceStatePresent, "")
+	}
+	return &resource, nil
+}
+
+// deploymentImageVersion returns the image tag of the first container of the deployment without the distroless suffix.
+func deploymentImageVersion(deployment *appsv1.Deployment) string {
+	containers := deployment.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return ""
+	}
+	image := containers[0].Image
+	i := strings.LastIndex(image, ":")
+	if i < 0 || i < strings.LastIndex(image, "/") {
+		return ""
+	}
+	return strings.TrimSuffix(image[i+1:], distrolessSuffix)
+}
+
+// verifyMutatingWebhook verifies the MutatingWebhookConfiguration selected from the webhook candidates like PatchMutatingWebhook does.
+func (c *DefaultIstioPerformer) verifyMutatingWebhook(ctx context.Context, kubeClient clientgo.Interface) (VerifiedResource, error) {
+	resource := VerifiedResource{Kind: "MutatingWebhookConfiguration", Name: strings.Join(c.webhookCandidates, ", ")}
+	for _, name := range c.webhookCandidates {
+		whConf, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return resource, errors.Wrapf(err, "Could not get MutatingWebhookConfiguration %s", name)
+		}
+		resource.Name = name
+		for _, webhook := range whConf.Webhooks {
+			if webhook.Name == webhookNameToChange {
+				return withState(resource, ResourceStatePresent, ""), nil
+			}
+		}
+		return withState(resource, ResourceStateMismatch, fmt.Sprintf("webhook %s not found", webhookNameToChange)), nil
+	}
+	return withState(resource, ResourceStateMissing, ""), nil
+}
+
+// verifyValidatingWebhook verifies the ValidatingWebhookConfiguration which istiod creates for its namespace.
+func (c *DefaultIstioPerformer) verifyValidatingWebhook(ctx context.Context, kubeClient clientgo.Interface) (VerifiedResource, error) {
+	name := "istio-validator-" + c.namespace
+	resource := VerifiedResource{Kind: "ValidatingWebhookConfiguration", Name: name}
+	whConf, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return withState(resource, ResourceStateMissing, ""), nil
+	}
+	if err != nil {
+		return resource, errors.Wrapf(err, "Could not get ValidatingWebhookConfiguration %s", name)
+	}
+	if len(whConf.Webhooks) == 0 {
+		return withState(resource, ResourceStateMismatch, "no webhooks configured"), nil
+	}
+	return withState(resource, ResourceStatePresent, ""), nil
+}
+
+func withState(resource VerifiedResource, state ResourceState, reason string) VerifiedResource {
+	resource.State = state
+	resource.Reason = reason
+	return resource
+}
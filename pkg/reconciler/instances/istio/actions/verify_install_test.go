@@ -0,0 +1,170 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_VerifyInstall(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	t.Run("should report all resources of a complete installation as present", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			fixVerifyDeployment(istiodDeploymentName, "docker.io/istio/pilot:1.17.3-distroless", true),
+			fixVerifyDeployment(ingressGatewayDeploymentName, "docker.io/istio/proxyv2:1.17.3", true),
+			fixVerifyMutatingWebhook("istio-revision-tag-default", webhookNameToChange),
+			fixVerifyValidatingWebhook("istio-validator-istio-system"),
+		)
+		wrapper := fixVerifyPerformer(kubeClient, fixVerifyDynamicClient(fixVerifyCRDs(expectedIstioCRDs...)...))
+
+		// when
+		verification, err := wrapper.VerifyInstall(kubeConfig, "1.17.3", log)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, verification.Installed())
+		require.Len(t, verification.Resources, len(expectedIstioCRDs)+4)
+		require.Contains(t, verification.Resources, VerifiedResource{Kind: "Deployment", Name: istiodDeploymentName, Namespace: "istio-system", State: ResourceStatePresent})
+		require.Contains(t, verification.Resources, VerifiedResource{Kind: "MutatingWebhookConfiguration", Name: "istio-revision-tag-default", State: ResourceStatePresent})
+	})
+
+	t.Run("should report missing and mismatching resources of a partial installation", func(t *testing.T) {
+		// given
+		crds := fixVerifyCRDs(expectedIstioCRDs[1:]...)
+		unstructured.RemoveNestedField(crds[0].(*unstructured.Unstructured).Object, "status")
+		kubeClient := fake.NewSimpleClientset(
+			fixVerifyDeployment(istiodDeploymentName, "docker.io/istio/pilot:1.16.1", true),
+			fixVerifyDeployment(ingressGatewayDeploymentName, "docker.io/istio/proxyv2:1.17.3", false),
+			fixVerifyMutatingWebhook("istio-sidecar-injector", "namespace.sidecar-injector.istio.io"),
+		)
+		wrapper := fixVerifyPerformer(kubeClient, fixVerifyDynamicClient(crds...))
+
+		// when
+		verification, err := wrapper.VerifyInstall(kubeConfig, "1.17.3", log)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, verification.Installed())
+		require.Equal(t, []VerifiedResource{
+			{Kind: "CustomResourceDefinition", Name: expectedIstioCRDs[0], State: ResourceStateMissing},
+			{Kind: "CustomResourceDefinition", Name: expectedIstioCRDs[1], State: ResourceStateMismatch, Reason: "not established"},
+			{Kind: "Deployment", Name: istiodDeploymentName, Namespace: "istio-system", State: ResourceStateMismatch, Reason: "runs version 1.16.1 instead of 1.17.3"},
+			{Kind: "Deployment", Name: ingressGatewayDeploymentName, Namespace: "istio-system", State: ResourceStateMismatch, Reason: "no available replicas"},
+			{Kind: "MutatingWebhookConfiguration", Name: "istio-sidecar-injector", State: ResourceStateMismatch, Reason: "webhook auto.sidecar-injector.istio.io not found"},
+			{Kind: "ValidatingWebhookConfiguration", Name: "istio-validator-istio-system", State: ResourceStateMissing},
+		}, verification.Failed())
+	})
+
+	t.Run("should report the resources of a cluster without Istio as missing", func(t *testing.T) {
+		// given
+		wrapper := fixVerifyPerformer(fake.NewSimpleClientset(), fixVerifyDynamicClient())
+
+		// when
+		verification, err := wrapper.VerifyInstall(kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, verification.Failed(), len(expectedIstioCRDs)+3)
+		require.Contains(t, verification.Failed(), VerifiedResource{Kind: "Deployment", Name: istiodDeploymentName, Namespace: "istio-system", State: ResourceStateMissing})
+		require.Contains(t, verification.Failed(), VerifiedResource{Kind: "MutatingWebhookConfiguration", Name: "istio-revision-tag-default, istio-sidecar-injector", State: ResourceStateMissing})
+	})
+
+	t.Run("should return an error when the kube client could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("kubeconfig error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider)
+
+		// when
+		_, err := wrapper.VerifyInstall(kubeConfig, "1.17.3", log)
+
+		// then
+		require.EqualError(t, err, "kubeconfig error")
+	})
+}
+
+func Test_deploymentImageVersion(t *testing.T) {
+	require.Equal(t, "1.17.3", deploymentImageVersion(fixVerifyDeployment("istiod", "registry.local:5000/istio/pilot:1.17.3-distroless", true)))
+	require.Equal(t, "", deploymentImageVersion(fixVerifyDeployment("istiod", "registry.local:5000/istio/pilot", true)))
+	require.Equal(t, "", deploymentImageVersion(&appsv1.Deployment{}))
+}
+
+func fixVerifyPerformer(kubeClient *fake.Clientset, dynamicClient *dynamicfake.FakeDynamicClient) *DefaultIstioPerformer {
+	provider := clientsetmocks.Provider{}
+	provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+	dynamicProvider := clientsetmocks.DynamicProvider{}
+	dynamicProvider.On("RetrieveDynamicFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(dynamicClient, nil)
+	return NewDefaultIstioPerformer(nil, nil, &provider, WithDynamicProvider(&dynamicProvider))
+}
+
+func fixVerifyDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+// fixVerifyCRDs returns established CRDs with the given names.
+func fixVerifyCRDs(names ...string) []runtime.Object {
+	var crds []runtime.Object
+	for _, name := range names {
+		crd := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+					map[string]interface{}{"type": "Established", "status": "True"},
+				},
+			},
+		}}
+		crd.SetAPIVersion("apiextensions.k8s.io/v1")
+		crd.SetKind("CustomResourceDefinition")
+		crd.SetName(name)
+		crds = append(crds, crd)
+	}
+	return crds
+}
+
+func fixVerifyDeployment(name, image string, available bool) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "discovery", Image: image}}},
+			},
+		},
+	}
+	if available {
+		deployment.Status.AvailableReplicas = 1
+	}
+	return deployment
+}
+
+func fixVerifyMutatingWebhook(name string, webhooks ...string) *v1.MutatingWebhookConfiguration {
+	whConf := &v1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, webhook := range webhooks {
+		whConf.Webhooks = append(whConf.Webhooks, v1.MutatingWebhook{Name: webhook})
+	}
+	return whConf
+}
+
+func fixVerifyValidatingWebhook(name string) *v1.ValidatingWebhookConfiguration {
+	return &v1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Webhooks:   []v1.ValidatingWebhook{{Name: "rev.validation.istio.io"}},
+	}
+}
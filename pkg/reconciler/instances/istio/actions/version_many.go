@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const defaultVersionConcurrency = 4
+
+// VersionTarget describes a single cluster queried by VersionMany, with the same parameters as Version.
+type VersionTarget struct {
+	// ClusterID identifies the cluster in the results, e.g. the runtime ID.
+	ClusterID       string
+	Workspace       chart.Factory
+	BranchVersion   string
+	IstioChart      string
+	KubeConfig      string
+	VersionOverride string
+}
+
+// IstioStatusResult is the outcome of Version for a single cluster queried by VersionMany.
+type IstioStatusResult struct {
+	ClusterID string
+	Status    IstioStatus
+	// Err is the error returned by Version for the cluster, e.g. ErrIstioNotInstalled.
+	// It wraps context.DeadlineExceeded if the cluster did not respond within the cluster timeout.
+	Err error
+	// Elapsed is the time it took to query the cluster, at most the cluster timeout.
+	Elapsed time.Duration
+}
+
+// WithVersionConcurrency sets the number of clusters queried in parallel by VersionMany. Values lower than one query the clusters one after another.
+func WithVersionConcurrency(concurrency int) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.versionConcurrency = concurrency
+	}
+}
+
+// WithVersionClusterTimeout bounds the time VersionMany waits for a single cluster and aborts its istioctl version call on timeout,
+// so unreachable clusters do not delay the results of the other clusters. If the commander cannot abort istioctl version, the cluster
+// keeps its worker until the call returned. A timeout of zero waits until Version returned.
+func WithVersionClusterTimeout(timeout time.Duration) PerformerOption {
+	return func(c *DefaultIstioPerformer) {
+		c.versionClusterTimeout = timeout
+	}
+}
+
+// VersionMany calls Version for each of the targets, querying up to the configured number of clusters in parallel.
+// The results are returned in the order of the targets. The returned error lists the clusters whose Version failed,
+// the error of each cluster is reported in its result.
+func (c *DefaultIstioPerformer) VersionMany(targets []VersionTarget, logger *zap.SugaredLogger) ([]IstioStatusResult, error) {
+	workers := c.versionConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(targets) {
+		workers = len(targets)
+	}
+
+	results := make([]IstioStatusResult, len(targets))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = c.versionOf(targets[index], logger.With("clusterID", targets[index].ClusterID))
+			}
+		}()
+	}
+	for index := range targets {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.ClusterID)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return results, errors.Errorf("Version failed for %d of %d clusters: %s", len(failed), len(targets), strings.Join(failed, ", "))
+	}
+	return results, nil
+}
+
+// versionOf calls Version for the target, waiting at most the cluster timeout. On timeout, the context of istioctl version is cancelled,
+// which aborts it if the commander supports it. The worker slot stays occupied until Version returned, so no more than the configured
+// number of clusters are queried at once.
+func (c *DefaultIstioPerformer) versionOf(target VersionTarget, logger *zap.SugaredLogger) IstioStatusResult {
+	ctx, cancel := contextWithTimeout(context.Background(), c.versionClusterTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan IstioStatusResult, 1)
+	go func() {
+		details, err := c.versionDetailed(ctx, target.Workspace, target.BranchVersion, target.IstioChart, target.KubeConfig, target.VersionOverride, logger)
+		done <- IstioStatusResult{ClusterID: target.ClusterID, Status: details.Status, Err: err}
+	}()
+
+	var result IstioStatusResult
+	select {
+	case result = <-done:
+		result.Elapsed = time.Since(start)
+	case <-ctx.Done():
+		logger.Warnf("Version of cluster %s did not complete within %s", target.ClusterID, c.versionClusterTimeout)
+		result = IstioStatusResult{
+			ClusterID: target.ClusterID,
+			Err:       errors.Wrapf(context.DeadlineExceeded, "Version of cluster %s did not complete within %s", target.ClusterID, c.versionClusterTimeout),
+			Elapsed:   time.Since(start),
+		}
+		<-done
+	}
+	return result
+}
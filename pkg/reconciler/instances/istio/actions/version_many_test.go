@@ -0,0 +1,291 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func versionTargets(count int) []VersionTarget {
+	factory := &workspacemocks.Factory{}
+	factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+	targets := make([]VersionTarget, count)
+	for i := range targets {
+		targets[i] = VersionTarget{
+			ClusterID:     fmt.Sprintf("cluster-%d", i),
+			Workspace:     factory,
+			BranchVersion: "version",
+			IstioChart:    "istio-test",
+			KubeConfig:    fmt.Sprintf("kubeconfig-%d", i),
+		}
+	}
+	return targets
+}
+
+func Test_DefaultIstioPerformer_VersionMany(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should return the status of each cluster in the order of the targets", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", "kubeconfig-0", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmder.On("Version", "kubeconfig-1", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockSimpleVersion), nil)
+		cmder.On("Version", "kubeconfig-2", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("connection refused"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		results, err := wrapper.VersionMany(versionTargets(3), log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Version failed for 2 of 3 clusters: cluster-1, cluster-2")
+		require.Len(t, results, 3)
+		require.Equal(t, "cluster-0", results[0].ClusterID)
+		require.NoError(t, results[0].Err)
+		require.Equal(t, "1.11.1", results[0].Status.PilotVersion)
+		require.Equal(t, "cluster-1", results[1].ClusterID)
+		require.ErrorIs(t, results[1].Err, ErrIstioNotInstalled)
+		require.Equal(t, "1.11.2", results[1].Status.ClientVersion)
+		require.Equal(t, "cluster-2", results[2].ClusterID)
+		require.Contains(t, results[2].Err.Error(), "connection refused")
+	})
+
+	t.Run("should return no error when the status of all clusters could be retrieved", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		results, err := wrapper.VersionMany(versionTargets(2), log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+	})
+
+	t.Run("should return no results for no targets", func(t *testing.T) {
+		// given
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+
+		// when
+		results, err := wrapper.VersionMany(nil, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+
+	t.Run("should log the cluster identifier of the target without overwriting the cluster of the kubeconfig", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.DebugLevel)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{})
+		targets := versionTargets(1)
+		targets[0].KubeConfig = testKubeconfig
+
+		// when
+		_, err := wrapper.VersionMany(targets, zap.New(core).Sugar())
+
+		// then
+		require.NoError(t, err)
+		require.NotZero(t, logs.Len())
+		for _, entry := range logs.All() {
+			keys := map[string]int{}
+			for _, field := range entry.Context {
+				keys[field.Key]++
+			}
+			require.Equal(t, 1, keys["cluster"])
+			require.Equal(t, 1, keys["clusterID"])
+			require.Equal(t, "cluster-0", entry.ContextMap()["clusterID"])
+		}
+	})
+
+	t.Run("should query the clusters concurrently but not more than the configured number at once", func(t *testing.T) {
+		// given
+		var running, maxRunning int32
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(mock.Arguments) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					observed := atomic.LoadInt32(&maxRunning)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			}).
+			Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, WithVersionConcurrency(3))
+
+		// when
+		results, err := wrapper.VersionMany(versionTargets(12), log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, results, 12)
+		for i, result := range results {
+			require.Equal(t, fmt.Sprintf("cluster-%d", i), result.ClusterID)
+			require.Equal(t, "1.11.1", result.Status.PilotVersion)
+		}
+		require.Greater(t, atomic.LoadInt32(&maxRunning), int32(1))
+		require.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+		cmder.AssertNumberOfCalls(t, "Version", 12)
+	})
+
+	t.Run("should report the result of every cluster when half of the clusters failed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		for i := 0; i < 8; i++ {
+			kubeConfig := fmt.Sprintf("kubeconfig-%d", i)
+			if i%2 == 0 {
+				cmder.On("Version", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+			} else {
+				cmder.On("Version", kubeConfig, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("connection refused"))
+			}
+		}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, WithVersionConcurrency(2))
+
+		// when
+		results, err := wrapper.VersionMany(versionTargets(8), log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Version failed for 4 of 8 clusters: cluster-1, cluster-3, cluster-5, cluster-7")
+		require.Len(t, results, 8)
+		for i, result := range results {
+			require.Equal(t, fmt.Sprintf("cluster-%d", i), result.ClusterID)
+			require.Greater(t, int64(result.Elapsed), int64(0))
+			if i%2 == 0 {
+				require.NoError(t, result.Err)
+				require.Equal(t, "1.11.1", result.Status.PilotVersion)
+			} else {
+				require.Contains(t, result.Err.Error(), "connection refused")
+			}
+		}
+		cmder.AssertNumberOfCalls(t, "Version", 8)
+	})
+
+	t.Run("should not wait longer than the cluster timeout for a cluster", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", "kubeconfig-0", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmder.On("Version", "kubeconfig-1", mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(mock.Arguments) { time.Sleep(time.Second) }).
+			Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, WithVersionClusterTimeout(50*time.Millisecond))
+
+		// when
+		results, err := wrapper.VersionMany(versionTargets(2), log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Version failed for 1 of 2 clusters: cluster-1")
+		require.NoError(t, results[0].Err)
+		require.Equal(t, "1.11.1", results[0].Status.PilotVersion)
+		require.ErrorIs(t, results[1].Err, context.DeadlineExceeded)
+		require.Contains(t, results[1].Err.Error(), "Version of cluster cluster-1 did not complete within 50ms")
+		require.Less(t, int64(results[1].Elapsed), int64(time.Second))
+	})
+
+	t.Run("should abort the istioctl version call of a cluster which did not complete within the cluster timeout", func(t *testing.T) {
+		// given
+		cmder := &blockingVersionCommander{Commander: &istioctlmocks.Commander{}, aborted: make(chan struct{})}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, WithVersionClusterTimeout(50*time.Millisecond))
+
+		// when
+		results, err := wrapper.VersionMany(versionTargets(1), log)
+
+		// then
+		require.Error(t, err)
+		require.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+		select {
+		case <-cmder.aborted:
+		default:
+			require.Fail(t, "istioctl version was not aborted")
+		}
+	})
+
+	t.Run("should not query more than the configured number of clusters at once when clusters time out", func(t *testing.T) {
+		// given
+		var running, maxRunning int32
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(mock.Arguments) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					observed := atomic.LoadInt32(&maxRunning)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+						break
+					}
+				}
+				time.Sleep(100 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			}).
+			Return([]byte(istioctlMockCompleteVersion), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{},
+			WithVersionConcurrency(1), WithVersionClusterTimeout(20*time.Millisecond))
+
+		// when
+		results, err := wrapper.VersionMany(versionTargets(3), log)
+
+		// then
+		require.Error(t, err)
+		for _, result := range results {
+			require.ErrorIs(t, result.Err, context.DeadlineExceeded)
+		}
+		require.Equal(t, int32(1), atomic.LoadInt32(&maxRunning))
+		cmder.AssertNumberOfCalls(t, "Version", 3)
+	})
+}
+
+func Benchmark_DefaultIstioPerformer_VersionMany(b *testing.B) {
+	log := logger.NewLogger(false)
+	cmder := istioctlmocks.Commander{}
+	cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+		Run(func(mock.Arguments) { time.Sleep(time.Millisecond) }).
+		Return([]byte(istioctlMockCompleteVersion), nil)
+	targets := versionTargets(16)
+
+	for _, concurrency := range []int{1, 4, 16} {
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, WithVersionConcurrency(concurrency))
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := wrapper.VersionMany(targets, log); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// blockingVersionCommander is an istioctl.Commander implementing istioctl.ContextVersioner, whose version call blocks until it is aborted.
+type blockingVersionCommander struct {
+	*istioctlmocks.Commander
+	aborted chan struct{}
+}
+
+func (c *blockingVersionCommander) VersionContext(ctx context.Context, _ string, _ *zap.SugaredLogger) ([]byte, error) {
+	<-ctx.Done()
+	close(c.aborted)
+	return nil, ctx.Err()
+}
@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// sidecarStatusAnnotation is set by the Istio sidecar injector on every pod it injected a sidecar into.
+	sidecarStatusAnnotation = "sidecar.istio.io/status"
+	injectionProbePodPrefix = "istio-injection-probe-"
+)
+
+// WaitForWebhookInjection polls until a pod created in the namespace gets an Istio sidecar injected, the timeout expired or ctx is done, whichever comes first.
+// The pods are created as dry-run, so nothing is persisted on the cluster. The namespace must have sidecar injection enabled.
+// A timeout of zero uses the readiness timeout of the performer.
+func (c *DefaultIstioPerformer) WaitForWebhookInjection(ctx context.Context, kubeClient kubernetes.Client, namespace string, timeout time.Duration, logger *zap.SugaredLogger) error {
+	logger = operationLogger(logger, "WaitForWebhookInjection", "", kubeClient.Kubeconfig())
+
+	clientSet, err := kubeClient.Clientset()
+	if err != nil {
+		return err
+	}
+
+	if timeout <= 0 {
+		timeout = c.readinessTimeout
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = wait.PollImmediateUntil(c.readinessInterval, func() (bool, error) {
+		pod, err := clientSet.CoreV1().Pods(namespace).Create(pollCtx, injectionProbePod(namespace), metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		if kerrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "Could not create injection probe pod in namespace %s", namespace)
+		}
+		if err != nil {
+			logger.Debugf("Could not create injection probe pod in namespace %s: %s", namespace, err)
+			return false, nil
+		}
+		if _, injected := pod.Annotations[sidecarStatusAnnotation]; !injected {
+			logger.Debugf("Sidecar was not yet injected into the injection probe pod in namespace %s", namespace)
+			return false, nil
+		}
+		return true, nil
+	}, pollCtx.Done())
+	if err == wait.ErrWaitTimeout && ctx.Err() != nil {
+		return errors.Wrapf(ctx.Err(), "Waiting for sidecar injection in namespace %s cancelled", namespace)
+	}
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("Sidecar injection did not take effect in namespace %s within %s", namespace, timeout)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Sidecar injection is active in namespace %s", namespace)
+	return nil
+}
+
+// injectionProbePod returns the pod created as dry-run by WaitForWebhookInjection.
+func injectionProbePod(namespace string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: injectionProbePodPrefix,
+			Namespace:    namespace,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "probe", Image: "probe"}},
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// injectingReactor returns the created pods with the sidecar status annotation once more than notInjectedCalls pods were created, counting the created pods.
+func injectingReactor(notInjectedCalls int, calls *int) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod).DeepCopy()
+		*calls++
+		if *calls > notInjectedCalls {
+			pod.Annotations = map[string]string{sidecarStatusAnnotation: `{"containers":["istio-proxy"]}`}
+		}
+		return true, pod, nil
+	}
+}
+
+func Test_DefaultIstioPerformer_WaitForWebhookInjection(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should return error when kubeclient had returned an error", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(nil, errors.New("kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+
+		// when
+		err := wrapper.WaitForWebhookInjection(context.TODO(), &kubeClient, "default", time.Second, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "kubeclient error")
+	})
+
+	t.Run("should wait until the sidecar is injected into the dry-run probe pod", func(t *testing.T) {
+		// given
+		var calls int
+		clientset := fake.NewSimpleClientset()
+		clientset.PrependReactor("create", "pods", injectingReactor(2, &calls))
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithReadinessTimeout(time.Second, time.Millisecond))
+
+		// when
+		err := wrapper.WaitForWebhookInjection(context.TODO(), &kubeClient, "default", 0, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("should keep polling when the probe pod could not be created", func(t *testing.T) {
+		// given
+		var calls int
+		failures := 1
+		clientset := fake.NewSimpleClientset()
+		clientset.PrependReactor("create", "pods", injectingReactor(0, &calls))
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if failures > 0 {
+				failures--
+				return true, nil, errors.New("failed calling webhook")
+			}
+			return false, nil, nil
+		})
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithReadinessTimeout(time.Second, time.Millisecond))
+
+		// when
+		err := wrapper.WaitForWebhookInjection(context.TODO(), &kubeClient, "default", 0, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("should return error when the sidecar is not injected within the timeout", func(t *testing.T) {
+		// given
+		var calls int
+		clientset := fake.NewSimpleClientset()
+		clientset.PrependReactor("create", "pods", injectingReactor(1000, &calls))
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithReadinessTimeout(time.Second, 5*time.Millisecond))
+
+		// when
+		err := wrapper.WaitForWebhookInjection(context.TODO(), &kubeClient, "default", 50*time.Millisecond, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Sidecar injection did not take effect in namespace default within 50ms")
+	})
+
+	t.Run("should return error when the namespace does not exist", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, kerrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "missing")
+		})
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithReadinessTimeout(time.Second, time.Millisecond))
+
+		// when
+		err := wrapper.WaitForWebhookInjection(context.TODO(), &kubeClient, "missing", 0, log)
+
+		// then
+		require.Error(t, err)
+		require.True(t, kerrors.IsNotFound(errors.Cause(err)))
+	})
+
+	t.Run("should return error when ctx is cancelled", func(t *testing.T) {
+		// given
+		var calls int
+		clientset := fake.NewSimpleClientset()
+		clientset.PrependReactor("create", "pods", injectingReactor(1000, &calls))
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, WithReadinessTimeout(time.Second, time.Millisecond))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		err := wrapper.WaitForWebhookInjection(ctx, &kubeClient, "default", 0, log)
+
+		// then
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+}
@@ -0,0 +1,24 @@
+package actions
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	webhookSelectorModified  = "modified"
+	webhookSelectorCompliant = "compliant"
+)
+
+// webhookPatchSelectors counts the webhook entries whose namespace selector was modified or already compliant by PatchMutatingWebhook.
+// It is shared by all DefaultIstioPerformer instances, as a new performer is created for every reconciliation.
+var webhookPatchSelectors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Subsystem: "reconciler",
+	Name:      "istio_webhook_patch_selectors_total",
+	Help:      "Webhook entries handled by the patch of Istio's MutatingWebhookConfiguration by result, which is either modified or compliant",
+}, []string{"result"})
+
+// WebhookPatchCollector returns the collector of the webhook entries handled by PatchMutatingWebhook, labeled by modified or compliant,
+// to be registered by the caller. A converged cluster only increases the compliant count.
+func WebhookPatchCollector() prometheus.Collector {
+	return webhookPatchSelectors
+}
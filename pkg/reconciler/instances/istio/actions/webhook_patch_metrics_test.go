@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func webhookPatchSelectorCount(result string) float64 {
+	return testutil.ToFloat64(webhookPatchSelectors.WithLabelValues(result))
+}
+
+func Test_DefaultIstioPerformer_PatchMutatingWebhook_SelectorCount(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should count the modified and the already compliant webhooks until the patch converged", func(t *testing.T) {
+		// given
+		whConf := createIstioAutoMutatingWebhookConf("istio-sidecar-injector")
+		compliant := createIstioAutoMutatingWebhookConfWithSelector("istio-sidecar-injector", webhookRequiredLabelSelector()).Webhooks[0]
+		whConf.Webhooks = append(whConf.Webhooks, compliant, v1.MutatingWebhook{Name: "namespace.sidecar-injector.istio.io", NamespaceSelector: &metav1.LabelSelector{}})
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(whConf), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+		modifiedBefore := webhookPatchSelectorCount(webhookSelectorModified)
+		compliantBefore := webhookPatchSelectorCount(webhookSelectorCompliant)
+
+		// when
+		first, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+		second, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+		require.NoError(t, err)
+
+		// then
+		require.True(t, first.Changed)
+		require.Equal(t, 1, first.ModifiedWebhooks)
+		require.Equal(t, 1, first.CompliantWebhooks)
+		require.False(t, second.Changed)
+		require.Equal(t, 0, second.ModifiedWebhooks)
+		require.Equal(t, 2, second.CompliantWebhooks)
+		require.Equal(t, float64(1), webhookPatchSelectorCount(webhookSelectorModified)-modifiedBefore)
+		require.Equal(t, float64(3), webhookPatchSelectorCount(webhookSelectorCompliant)-compliantBefore)
+	})
+
+	t.Run("should not count webhooks when the patch failed", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil)
+		modifiedBefore := webhookPatchSelectorCount(webhookSelectorModified)
+		compliantBefore := webhookPatchSelectorCount(webhookSelectorCompliant)
+
+		// when
+		_, err := wrapper.PatchMutatingWebhook(context.TODO(), &kubeClient, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, modifiedBefore, webhookPatchSelectorCount(webhookSelectorModified))
+		require.Equal(t, compliantBefore, webhookPatchSelectorCount(webhookSelectorCompliant))
+	})
+}
@@ -2,12 +2,15 @@ package istio
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -16,11 +19,28 @@ import (
 const (
 	istioctlBinaryPathEnvKey = "ISTIOCTL_PATH"
 	istioctlBinaryPathMaxLen = 12290 // 3 times 4096 (maxpath) + 2 colons (separators)
+
+	// proxyImageCheckEnvKey enables the check that the target proxy image is pullable before the proxy reset, if set to "true".
+	proxyImageCheckEnvKey = "ISTIO_PROXY_IMAGE_CHECK"
+
+	// proxyResetCheckpointEnvKey enables checkpointing of the proxy reset progress in the cluster, if set to "true".
+	// A retried proxy reset then resumes instead of restarting the already reset workloads.
+	proxyResetCheckpointEnvKey = "ISTIO_PROXY_RESET_CHECKPOINT"
+
+	// uninstallPurgeCRDsEnvKey makes the uninstallation remove the Istio CRDs left behind by istioctl, if set to "true".
+	uninstallPurgeCRDsEnvKey = "ISTIO_UNINSTALL_PURGE_CRDS"
+
+	// uninstallNamespacePropagationEnvKey sets the propagation policy the Istio namespace is deleted with on uninstallation,
+	// which is one of Foreground, Background or Orphan. Defaults to Foreground.
+	uninstallNamespacePropagationEnvKey = "ISTIO_UNINSTALL_NAMESPACE_PROPAGATION"
+
+	// istioctlExecWrapperEnvKey is the space separated command all istioctl commands are run through, e.g. "ssh bastion istioctl".
+	istioctlExecWrapperEnvKey = "ISTIOCTL_EXEC_WRAPPER"
 )
 
 // IstioPerformer instance should be created only once in the Istio Reconciler life.
 // Due to current Reconciler limitations - lack of well defined reconciler instances lifetime - we have to initialize it once per reconcile/delete action.
-func istioPerformerCreator(istioProxyReset proxy.IstioProxyReset, provider clientset.Provider, name string) bootstrapIstioPerformer {
+func istioPerformerCreator(gatherer data.Gatherer, action reset.Action, provider clientset.Provider, name string) bootstrapIstioPerformer {
 
 	res := func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
 		pathsConfig := os.Getenv(istioctlBinaryPathEnvKey)
@@ -41,7 +61,34 @@ func istioPerformerCreator(istioProxyReset proxy.IstioProxyReset, provider clien
 			return nil, err
 		}
 
-		return actions.NewDefaultIstioPerformer(resolver, istioProxyReset, provider), nil
+		if wrapper := strings.Fields(os.Getenv(istioctlExecWrapperEnvKey)); len(wrapper) > 0 {
+			logger.Debugf("Running istioctl through exec wrapper %s", wrapper[0])
+			resolver = actions.NewExecWrapperCommanderResolver(resolver, wrapper)
+		}
+
+		// the checkpoints are written by the proxy reset and removed by CleanupResetArtifacts of the performer in the same namespace
+		istioProxyReset := proxy.NewDefaultIstioProxyReset(gatherer, action)
+		if strings.EqualFold(os.Getenv(proxyResetCheckpointEnvKey), "true") {
+			istioProxyReset.WithCheckpointStore(proxy.NewConfigMapCheckpointStore(istioNamespace))
+		}
+
+		opts := []actions.PerformerOption{actions.WithNamespace(istioNamespace)}
+		if strings.EqualFold(os.Getenv(proxyImageCheckEnvKey), "true") {
+			opts = append(opts, actions.WithProxyImageCheck(actions.NewRegistryImageChecker(http.DefaultClient)))
+		}
+		if strings.EqualFold(os.Getenv(uninstallPurgeCRDsEnvKey), "true") {
+			opts = append(opts, actions.WithCRDPurge())
+		}
+		if propagationConfig := os.Getenv(uninstallNamespacePropagationEnvKey); propagationConfig != "" {
+			propagation, err := actions.ParseDeletionPropagation(propagationConfig)
+			if err != nil {
+				logger.Errorf("Could not create '%s' component reconciler: Error parsing env variable '%s': %s", name, uninstallNamespacePropagationEnvKey, err.Error())
+				return nil, err
+			}
+			opts = append(opts, actions.WithNamespaceDeletionPropagation(propagation))
+		}
+
+		return actions.NewDefaultIstioPerformer(resolver, istioProxyReset, provider, opts...), nil
 	}
 	return res
 }
@@ -66,9 +113,39 @@ func (dcr *defaultCommanderResolver) GetCommander(version istioctl.Version) (ist
 	return &res, nil
 }
 
+func (dcr *defaultCommanderResolver) IsVersionSupported(version string) bool {
+	istioVersion, err := dcr.ResolveVersion(version)
+	if err != nil {
+		return false
+	}
+
+	_, err = dcr.istioBinaryResolver.FindIstioctl(istioVersion)
+	return err == nil
+}
+
+// ResolveVersion returns exact versions as is, the binary for them is found by GetCommander.
+// Version constraints resolve to the newest available binary satisfying them.
+func (dcr *defaultCommanderResolver) ResolveVersion(constraint string) (istioctl.Version, error) {
+	if version, err := istioctl.VersionFromString(constraint); err == nil {
+		return version, nil
+	}
+
+	versionConstraint, err := istioctl.ParseVersionConstraint(constraint)
+	if err != nil {
+		return istioctl.Version{}, err
+	}
+	version, err := dcr.istioBinaryResolver.ResolveVersion(versionConstraint)
+	if err != nil {
+		return istioctl.Version{}, err
+	}
+
+	dcr.log.Debugf("Resolved istio version constraint %s to version %s", versionConstraint, version)
+	return version, nil
+}
+
 func newDefaultCommanderResolver(paths []string, log *zap.SugaredLogger) (actions.CommanderResolver, error) {
 
-	istioBinaryResolver, err := istioctl.NewDefaultIstioctlResolver(paths, istioctl.DefaultVersionChecker{})
+	istioBinaryResolver, err := istioctl.NewPlatformIstioctlResolver(paths, istioctl.DefaultVersionChecker{}, istioctl.DefaultPlatformChecker{}, istioctl.HostPlatform())
 	if err != nil {
 		return nil, err
 	}
@@ -3,10 +3,20 @@ package istio
 import (
 	"go.uber.org/zap"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 )
 
 func TestParsePaths(t *testing.T) {
@@ -181,3 +191,146 @@ func TestEnsureFileExecutable(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestDefaultCommanderResolver_IsVersionSupported(t *testing.T) {
+	vc := istioctlmocks.VersionChecker{}
+	vc.On("GetIstioVersion", "/a").Return(istioctl.VersionFromString("1.11.2"))
+	vc.On("GetIstioVersion", "/b").Return(istioctl.VersionFromString("1.12.1"))
+	istioBinaryResolver, err := istioctl.NewDefaultIstioctlResolver([]string{"/a", "/b"}, &vc)
+	require.NoError(t, err)
+	resolver := &defaultCommanderResolver{log: zap.NewNop().Sugar(), paths: []string{"/a", "/b"}, istioBinaryResolver: istioBinaryResolver}
+
+	t.Run("should support a version with a binary of the same minor version", func(t *testing.T) {
+		//when
+		supported := resolver.IsVersionSupported("1.12.4")
+		//then
+		require.True(t, supported)
+	})
+	t.Run("should not support a version without a binary of the same minor version", func(t *testing.T) {
+		//when
+		supported := resolver.IsVersionSupported("1.13.0")
+		//then
+		require.False(t, supported)
+	})
+	t.Run("should support a version constraint with a matching binary", func(t *testing.T) {
+		//when
+		supported := resolver.IsVersionSupported("1.12.x")
+		//then
+		require.True(t, supported)
+	})
+	t.Run("should not support an invalid version", func(t *testing.T) {
+		//when
+		supported := resolver.IsVersionSupported("abc")
+		//then
+		require.False(t, supported)
+	})
+}
+
+func TestDefaultCommanderResolver_ResolveVersion(t *testing.T) {
+	vc := istioctlmocks.VersionChecker{}
+	vc.On("GetIstioVersion", "/a").Return(istioctl.VersionFromString("1.17.1"))
+	vc.On("GetIstioVersion", "/b").Return(istioctl.VersionFromString("1.17.4"))
+	istioBinaryResolver, err := istioctl.NewDefaultIstioctlResolver([]string{"/a", "/b"}, &vc)
+	require.NoError(t, err)
+	resolver := &defaultCommanderResolver{log: zap.NewNop().Sugar(), paths: []string{"/a", "/b"}, istioBinaryResolver: istioBinaryResolver}
+
+	t.Run("should return an exact version as is", func(t *testing.T) {
+		//when
+		version, err := resolver.ResolveVersion("1.17.2")
+		//then
+		require.NoError(t, err)
+		require.Equal(t, "1.17.2", version.String())
+	})
+	t.Run("should resolve a constraint to the newest available patch", func(t *testing.T) {
+		//when
+		version, err := resolver.ResolveVersion("1.17.x")
+		//then
+		require.NoError(t, err)
+		require.Equal(t, "1.17.4", version.String())
+	})
+	t.Run("should fail for a constraint without a matching binary", func(t *testing.T) {
+		//when
+		_, err := resolver.ResolveVersion("1.18.x")
+		//then
+		require.Error(t, err)
+	})
+	t.Run("should fail for an invalid constraint", func(t *testing.T) {
+		//when
+		_, err := resolver.ResolveVersion("abc")
+		//then
+		require.Error(t, err)
+	})
+}
+
+func TestDefaultCommanderResolver_LatestVersion(t *testing.T) {
+	host := istioctl.Platform{OS: "linux", Arch: "amd64"}
+	vc := istioctlmocks.VersionChecker{}
+	vc.On("GetIstioVersion", "/a").Return(istioctl.VersionFromString("1.16.2"))
+	vc.On("GetIstioVersion", "/b").Return(istioctl.VersionFromString("1.17.4"))
+	pc := istioctlmocks.PlatformChecker{}
+	pc.On("GetPlatform", "/a").Return(host, nil)
+	pc.On("GetPlatform", "/b").Return(host, nil)
+	pc.On("GetPlatform", "/c").Return(istioctl.Platform{OS: "darwin", Arch: "arm64"}, nil)
+	istioBinaryResolver, err := istioctl.NewPlatformIstioctlResolver([]string{"/a", "/b", "/c"}, &vc, &pc, host)
+	require.NoError(t, err)
+	resolver := &defaultCommanderResolver{log: zap.NewNop().Sugar(), paths: []string{"/a", "/b", "/c"}, istioBinaryResolver: istioBinaryResolver}
+
+	t.Run("should resolve the latest constraint to the newest binary of the platform", func(t *testing.T) {
+		//when
+		version, err := resolver.ResolveVersion(istioctl.LatestVersionConstraint)
+		//then
+		require.NoError(t, err)
+		require.Equal(t, "1.17.4", version.String())
+	})
+	t.Run("should provide the commander of the newest binary", func(t *testing.T) {
+		//given
+		version, err := resolver.ResolveVersion(istioctl.LatestVersionConstraint)
+		require.NoError(t, err)
+		//when
+		commander, err := resolver.GetCommander(version)
+		//then
+		require.NoError(t, err)
+		require.Equal(t, "/b", commander.(*istioctl.DefaultCommander).BinaryPath())
+	})
+	t.Run("should support the latest constraint", func(t *testing.T) {
+		//when
+		supported := resolver.IsVersionSupported(istioctl.LatestVersionConstraint)
+		//then
+		require.True(t, supported)
+	})
+}
+
+func TestDefaultCommanderResolver_GetInstalledOperator(t *testing.T) {
+	t.Run("should export the IstioOperator with the newest istioctl when its tag is no version", func(t *testing.T) {
+		//given
+		istioctlPath := filepath.Join(t.TempDir(), "istioctl")
+		require.NoError(t, os.WriteFile(istioctlPath, []byte("#!/bin/sh\necho \"profile: default\"\n"), 0700))
+		host := istioctl.HostPlatform()
+		vc := istioctlmocks.VersionChecker{}
+		vc.On("GetIstioVersion", "/older").Return(istioctl.VersionFromString("1.16.2"))
+		vc.On("GetIstioVersion", istioctlPath).Return(istioctl.VersionFromString("1.17.3"))
+		pc := istioctlmocks.PlatformChecker{}
+		pc.On("GetPlatform", mock.AnythingOfType("string")).Return(host, nil)
+		istioBinaryResolver, err := istioctl.NewPlatformIstioctlResolver([]string{"/older", istioctlPath}, &vc, &pc, host)
+		require.NoError(t, err)
+		resolver := &defaultCommanderResolver{log: zap.NewNop().Sugar(), paths: []string{"/older", istioctlPath}, istioBinaryResolver: istioBinaryResolver}
+
+		installed := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"profile": "default", "tag": "latest"}}}
+		installed.SetAPIVersion("install.istio.io/v1alpha1")
+		installed.SetKind("IstioOperator")
+		installed.SetNamespace(istioNamespace)
+		installed.SetName("installed-state")
+		istioOperators := schema.GroupVersionResource{Group: "install.istio.io", Version: "v1alpha1", Resource: "istiooperators"}
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{istioOperators: "IstioOperatorList"}, installed)
+		dynamicProvider := clientsetmocks.DynamicProvider{}
+		dynamicProvider.On("RetrieveDynamicFrom", "kubeConfig", mock.AnythingOfType("*zap.SugaredLogger")).Return(dynamicClient, nil)
+		performer := actions.NewDefaultIstioPerformer(resolver, nil, &clientsetmocks.Provider{}, actions.WithNamespace(istioNamespace), actions.WithDynamicProvider(&dynamicProvider))
+
+		//when
+		got, err := performer.GetInstalledOperator("kubeConfig", zap.NewNop().Sugar())
+
+		//then
+		require.NoError(t, err)
+		require.Equal(t, "profile: default\n", got)
+	})
+}
@@ -0,0 +1,104 @@
+package clientset
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// SecretRefPrefix marks a kubeconfig reference pointing to a Kubernetes Secret in the format: secret://<namespace>/<name>[/<key>]
+	SecretRefPrefix = "secret://"
+
+	defaultSecretKubeconfigKey = "config"
+)
+
+// KubeconfigResolver resolves the kubeconfig content before it is used by Provider or istioctl.
+type KubeconfigResolver interface {
+	// Resolve returns the kubeconfig content for the given kubeconfig or kubeconfig reference.
+	Resolve(kubeConfig string, log *zap.SugaredLogger) (string, error)
+}
+
+// RawKubeconfigResolver returns the passed kubeconfig unchanged.
+type RawKubeconfigResolver struct{}
+
+func (r *RawKubeconfigResolver) Resolve(kubeConfig string, _ *zap.SugaredLogger) (string, error) {
+	return kubeConfig, nil
+}
+
+// SecretKubeconfigResolver reads the kubeconfig from a Secret if a secret reference is passed. Raw kubeconfigs are returned unchanged.
+type SecretKubeconfigResolver struct {
+	kubeClient kubernetes.Interface
+}
+
+// NewSecretKubeconfigResolver creates a new instance of SecretKubeconfigResolver reading Secrets with the given kubeClient.
+func NewSecretKubeconfigResolver(kubeClient kubernetes.Interface) *SecretKubeconfigResolver {
+	return &SecretKubeconfigResolver{kubeClient: kubeClient}
+}
+
+func (r *SecretKubeconfigResolver) Resolve(kubeConfig string, log *zap.SugaredLogger) (string, error) {
+	if !strings.HasPrefix(kubeConfig, SecretRefPrefix) {
+		return kubeConfig, nil
+	}
+
+	namespace, name, key, err := parseSecretRef(kubeConfig)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := r.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not get kubeconfig secret %s/%s", namespace, name)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok || len(value) == 0 {
+		return "", errors.Errorf("Kubeconfig secret %s/%s does not contain key '%s'", namespace, name, key)
+	}
+	log.Debugf("Resolved kubeconfig from secret %s/%s", namespace, name)
+
+	return string(value), nil
+}
+
+func parseSecretRef(ref string) (namespace, name, key string, err error) {
+	parts := strings.Split(strings.TrimPrefix(ref, SecretRefPrefix), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", errors.Errorf("Invalid kubeconfig secret reference '%s', expected format: %s<namespace>/<name>[/<key>]", ref, SecretRefPrefix)
+	}
+
+	key = defaultSecretKubeconfigKey
+	if len(parts) == 3 && parts[2] != "" {
+		key = parts[2]
+	}
+
+	return parts[0], parts[1], key, nil
+}
+
+// ReadKubeconfigFile reads the kubeconfig from the file at the given path and validates that it points to a cluster,
+// so it can be passed to the Provider or the IstioPerformer.
+func ReadKubeconfigFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", errors.Errorf("Kubeconfig file %s does not exist", path)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not read kubeconfig file %s", path)
+	}
+
+	config, err := clientcmd.Load(content)
+	if err != nil {
+		return "", errors.Wrapf(err, "Kubeconfig file %s is malformed", path)
+	}
+	if err := clientcmd.ConfirmUsable(*config, ""); err != nil {
+		return "", errors.Wrapf(err, "Kubeconfig file %s is not usable", path)
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,172 @@
+package clientset
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const rawKubeconfig = "apiVersion: v1\nkind: Config"
+
+func Test_RawKubeconfigResolver_Resolve(t *testing.T) {
+	log := logger.NewLogger(false)
+
+	t.Run("should return the raw kubeconfig unchanged", func(t *testing.T) {
+		// given
+		resolver := RawKubeconfigResolver{}
+
+		// when
+		got, err := resolver.Resolve(rawKubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, rawKubeconfig, got)
+	})
+}
+
+func Test_SecretKubeconfigResolver_Resolve(t *testing.T) {
+	log := logger.NewLogger(false)
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "kcp-system"},
+		Data: map[string][]byte{
+			"config": []byte(rawKubeconfig),
+			"custom": []byte("custom-kubeconfig"),
+		},
+	})
+	resolver := NewSecretKubeconfigResolver(kubeClient)
+
+	t.Run("should pass through a raw kubeconfig", func(t *testing.T) {
+		// when
+		got, err := resolver.Resolve(rawKubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, rawKubeconfig, got)
+	})
+
+	t.Run("should read the kubeconfig from the default secret key", func(t *testing.T) {
+		// when
+		got, err := resolver.Resolve("secret://kcp-system/kubeconfig", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, rawKubeconfig, got)
+	})
+
+	t.Run("should read the kubeconfig from a custom secret key", func(t *testing.T) {
+		// when
+		got, err := resolver.Resolve("secret://kcp-system/kubeconfig/custom", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "custom-kubeconfig", got)
+	})
+
+	t.Run("should return an error when the secret does not exist", func(t *testing.T) {
+		// when
+		_, err := resolver.Resolve("secret://kcp-system/not-existing", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not get kubeconfig secret")
+	})
+
+	t.Run("should return an error when the secret key does not exist", func(t *testing.T) {
+		// when
+		_, err := resolver.Resolve("secret://kcp-system/kubeconfig/missing", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain key 'missing'")
+	})
+
+	t.Run("should return an error for an invalid secret reference", func(t *testing.T) {
+		// when
+		_, err := resolver.Resolve("secret://kcp-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid kubeconfig secret reference")
+	})
+}
+
+const fileKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://localhost:6443
+contexts:
+- name: context
+  context:
+    cluster: cluster
+    user: user
+current-context: context
+users:
+- name: user
+  user:
+    token: token
+`
+
+func Test_ReadKubeconfigFile(t *testing.T) {
+
+	t.Run("should return the content of a valid kubeconfig file", func(t *testing.T) {
+		// given
+		path := writeKubeconfigFile(t, fileKubeconfig)
+
+		// when
+		got, err := ReadKubeconfigFile(path)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, fileKubeconfig, got)
+	})
+
+	t.Run("should return error when the kubeconfig file does not exist", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "missing")
+
+		// when
+		_, err := ReadKubeconfigFile(path)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not exist")
+	})
+
+	t.Run("should return error when the kubeconfig file is malformed", func(t *testing.T) {
+		// given
+		path := writeKubeconfigFile(t, "clusters: [")
+
+		// when
+		_, err := ReadKubeconfigFile(path)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is malformed")
+	})
+
+	t.Run("should return error when the kubeconfig file does not point to a cluster", func(t *testing.T) {
+		// given
+		path := writeKubeconfigFile(t, rawKubeconfig)
+
+		// when
+		_, err := ReadKubeconfigFile(path)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not usable")
+	})
+}
+
+func writeKubeconfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
@@ -3,7 +3,9 @@ package clientset
 import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/file"
 	"go.uber.org/zap"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -14,31 +16,51 @@ type Provider interface {
 	RetrieveFrom(kubeConfig string, log *zap.SugaredLogger) (kubernetes.Interface, error)
 }
 
-// DefaultProvider provides a default implementation of Provider.
+//go:generate mockery --name=DynamicProvider --outpkg=mock --case=underscore
+// DynamicProvider offers k8s dynamic client.
+type DynamicProvider interface {
+	// RetrieveDynamicFrom kubeconfig and return new k8s dynamic client instance.
+	RetrieveDynamicFrom(kubeConfig string, log *zap.SugaredLogger) (dynamic.Interface, error)
+}
+
+// DefaultProvider provides a default implementation of Provider and DynamicProvider.
 type DefaultProvider struct{}
 
 func (c *DefaultProvider) RetrieveFrom(kubeConfig string, log *zap.SugaredLogger) (kubernetes.Interface, error) {
-	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeConfig)
+	restConfig, err := restConfigFrom(kubeConfig, log)
 	if err != nil {
 		return nil, err
 	}
 
-	defer func() {
-		cleanupErr := kubeconfigCf()
-		if cleanupErr != nil {
-			log.Error(cleanupErr)
-		}
-	}()
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubeClient, nil
+}
 
-	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+func (c *DefaultProvider) RetrieveDynamicFrom(kubeConfig string, log *zap.SugaredLogger) (dynamic.Interface, error) {
+	restConfig, err := restConfigFrom(kubeConfig, log)
 	if err != nil {
 		return nil, err
 	}
 
-	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	return dynamic.NewForConfig(restConfig)
+}
+
+func restConfigFrom(kubeConfig string, log *zap.SugaredLogger) (*rest.Config, error) {
+	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	return kubeClient, nil
+	defer func() {
+		cleanupErr := kubeconfigCf()
+		if cleanupErr != nil {
+			log.Error(cleanupErr)
+		}
+	}()
+
+	return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 }
@@ -7,7 +7,6 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
-	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
 )
 
@@ -31,9 +30,8 @@ func init() {
 	matcher := pod.NewParentKindMatcher()
 	provider := clientset.DefaultProvider{}
 	action := reset.NewDefaultPodsResetAction(matcher)
-	istioProxyReset := proxy.NewDefaultIstioProxyReset(gatherer, action)
 
-	istioPerformerCreatorFn := istioPerformerCreator(istioProxyReset, &provider, ReconcilerNameIstio)
+	istioPerformerCreatorFn := istioPerformerCreator(gatherer, action, &provider, ReconcilerNameIstio)
 	reconcilerIstio.
 		WithPreReconcileAction(NewStatusPreAction(istioPerformerCreatorFn)).
 		WithReconcileAction(NewIstioMainReconcileAction(istioPerformerCreatorFn)).
@@ -49,7 +47,7 @@ func init() {
 		log.Fatalf("Could not create '%s' component reconciler: %s", ReconcilerNameIstioConfiguration, err)
 	}
 
-	istioConfigurationPerformerCreatorFn := istioPerformerCreator(istioProxyReset, &provider, ReconcilerNameIstioConfiguration)
+	istioConfigurationPerformerCreatorFn := istioPerformerCreator(gatherer, action, &provider, ReconcilerNameIstioConfiguration)
 	reconcilerIstioConfiguration.WithReconcileAction(NewReconcileIstioConfigurationAction(istioConfigurationPerformerCreatorFn)).
 		WithDeleteAction(NewUninstallAction(istioConfigurationPerformerCreatorFn))
 
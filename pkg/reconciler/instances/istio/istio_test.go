@@ -163,7 +163,7 @@ func Test_RunUpdateAction(t *testing.T) {
 		providerMock.On("RetrieveFrom", mock.Anything, mock.Anything).Return(fake.NewSimpleClientset(), nil)
 		commanderMock := commandermocks.Commander{}
 		commanderMock.On("Version", mock.Anything, mock.Anything).Return([]byte(istioctlMockLatestVersion), nil)
-		commanderMock.On("Upgrade", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		commanderMock.On("Upgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &commanderMock}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -178,7 +178,7 @@ func Test_RunUpdateAction(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		commanderMock.AssertCalled(t, "Version", mock.Anything, mock.Anything)
-		commanderMock.AssertCalled(t, "Upgrade", mock.Anything, mock.Anything, mock.Anything)
+		commanderMock.AssertCalled(t, "Upgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("Istio update should NOT permit more than one minor downgrade", func(t *testing.T) {
@@ -257,7 +257,7 @@ func Test_RunUninstallAction(t *testing.T) {
 		provider := clientset.DefaultProvider{}
 		commanderMock := commandermocks.Commander{}
 		commanderMock.On("Version", mock.Anything, mock.Anything).Return([]byte(istioctlMockCompleteVersion), nil)
-		commanderMock.On("Uninstall", mock.Anything, mock.Anything).Return(nil)
+		commanderMock.On("Uninstall", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &commanderMock}
 
 		performer := actions.NewDefaultIstioPerformer(cmdResolver, nil, &provider)
@@ -269,7 +269,7 @@ func Test_RunUninstallAction(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		commanderMock.AssertCalled(t, "Version", mock.Anything, mock.Anything)
-		commanderMock.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything)
+		commanderMock.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.Anything)
 
 		// istio-system namespace should be deleted
 		fakeClient, _ := actionContext.KubeClient.Clientset()
@@ -331,6 +331,14 @@ func (tcr TestCommanderResolver) GetCommander(version istioctl.Version) (istioct
 	return tcr.cmder, nil
 }
 
+func (tcr TestCommanderResolver) IsVersionSupported(version string) bool {
+	return tcr.err == nil
+}
+
+func (tcr TestCommanderResolver) ResolveVersion(constraint string) (istioctl.Version, error) {
+	return istioctl.VersionFromString(constraint)
+}
+
 func TestIstioReconciler(t *testing.T) {
 	istioReconciler, err := service.GetReconciler(istio.ReconcilerNameIstio)
 
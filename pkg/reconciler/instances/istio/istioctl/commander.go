@@ -2,10 +2,16 @@ package istioctl
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"github.com/kyma-incubator/reconciler/pkg/features"
 	"github.com/pkg/errors"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/file"
@@ -16,31 +22,127 @@ import (
 // Commander for istioctl binary.
 type Commander interface {
 
-	// Install wraps `istioctl installation` command.
-	Install(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error
+	// Install wraps `istioctl installation` command. The istioctl process is killed when the ctx is done.
+	Install(ctx context.Context, istioOperator, kubeconfig string, logger *zap.SugaredLogger) error
 
-	// Upgrade wraps `istioctl upgrade` command.
-	Upgrade(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error
+	// Upgrade wraps `istioctl upgrade` command. The istioctl process is killed when the ctx is done.
+	Upgrade(ctx context.Context, istioOperator, kubeconfig string, logger *zap.SugaredLogger) error
 
 	// Version wraps `istioctl version` command.
 	Version(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error)
 
-	// Uninstall wraps `istioctl x uninstall` command.
-	Uninstall(kubeconfig string, logger *zap.SugaredLogger) error
+	// ClientVersion wraps `istioctl version --remote=false` command and returns the istioctl version without contacting a cluster.
+	ClientVersion(logger *zap.SugaredLogger) (string, error)
+
+	// Uninstall wraps `istioctl x uninstall` command. The istioctl process is killed when the ctx is done.
+	Uninstall(ctx context.Context, kubeconfig string, logger *zap.SugaredLogger) error
+
+	// ProxyStatus wraps `istioctl proxy-status` command.
+	ProxyStatus(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error)
+
+	// ProxyConfigDump wraps `istioctl proxy-config all` command for the given pod and returns the Envoy config dump as JSON.
+	ProxyConfigDump(kubeconfig, namespace, pod string, logger *zap.SugaredLogger) ([]byte, error)
+
+	// Analyze wraps `istioctl analyze` command for the given namespace, or all namespaces if it is empty, and returns the found messages as JSON.
+	// Found issues are not reported as error.
+	Analyze(kubeconfig, namespace string, logger *zap.SugaredLogger) ([]byte, error)
+
+	// PreCheck wraps `istioctl x precheck` command and returns the found messages as JSON.
+	// Found issues are not reported as error.
+	PreCheck(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error)
+
+	// ProfileDump wraps `istioctl profile dump` command and returns the given IstioOperator merged over its profile as YAML.
+	ProfileDump(istioOperator string, logger *zap.SugaredLogger) ([]byte, error)
+
+	// BugReport wraps `istioctl bug-report` command run in the outputDir and returns the path of the generated archive. The istioctl process is killed when the ctx is done.
+	// It can not run through an exec wrapper, which would create the archive on the remote host.
+	BugReport(ctx context.Context, kubeconfig, outputDir string, logger *zap.SugaredLogger) (string, error)
 }
 
+// BinaryPathReporter is implemented by the commanders which report the path of the istioctl binary they run.
+type BinaryPathReporter interface {
+	// BinaryPath returns the path of the resolved istioctl binary.
+	BinaryPath() string
+}
+
+// ContextVersioner is implemented by the commanders which can abort `istioctl version` when a context is done.
+type ContextVersioner interface {
+	// VersionContext wraps `istioctl version` like Version. The istioctl process is killed when the ctx is done.
+	VersionContext(ctx context.Context, kubeconfig string, logger *zap.SugaredLogger) ([]byte, error)
+}
+
+// analyzerFoundIssuesExitCode is the exit code of `istioctl analyze` if it found issues above the failure threshold.
+const analyzerFoundIssuesExitCode = 79
+
+// StderrLogMarker is the value of the "source" field of the warnings logged for the lines istioctl printed on stderr.
+const StderrLogMarker = "istioctl-stderr"
+
+// BugReportArchiveName is the name of the archive `istioctl bug-report` creates in its working directory.
+const BugReportArchiveName = "bug-report.tar.gz"
+
+// ExecWrapperIstioctlPlaceholder is replaced by the path of the istioctl binary in the command set by WithExecWrapper.
+const ExecWrapperIstioctlPlaceholder = "{istioctl}"
+
 var execCommand = exec.Command
 
 // DefaultCommander provides a default implementation of Commander.
 type DefaultCommander struct {
-	istioctl Executable
+	istioctl    Executable
+	outputLimit int
+	env         map[string]string
+	wrapper     []string
 }
 
 func NewDefaultCommander(istioctl Executable) DefaultCommander {
-	return DefaultCommander{istioctl}
+	return DefaultCommander{istioctl: istioctl, outputLimit: DefaultOutputLimit}
 }
 
-func (c *DefaultCommander) Uninstall(kubeconfig string, logger *zap.SugaredLogger) error {
+// WithOutputLimit sets the maximum number of bytes captured from the output of an istioctl command.
+// Output exceeding the limit is truncated and marked with the TruncatedOutputMarker. A limit of zero uses the DefaultOutputLimit.
+func (c *DefaultCommander) WithOutputLimit(outputLimit int) *DefaultCommander {
+	c.outputLimit = outputLimit
+	return c
+}
+
+// WithEnv sets environment variables for all istioctl commands, e.g. ISTIOCTL_ENABLE_ALPHA_COMMANDS or proxy settings.
+// They are set on top of the inherited environment, except for PATH which is never overridden.
+func (c *DefaultCommander) WithEnv(env map[string]string) *DefaultCommander {
+	c.env = make(map[string]string, len(env))
+	for name, value := range env {
+		c.env[name] = value
+	}
+	return c
+}
+
+// WithExecWrapper routes all istioctl commands through the wrapper command, e.g. `ssh bastion istioctl` for clusters only reachable via a bastion host.
+// The istioctl arguments are appended to the wrapper, ExecWrapperIstioctlPlaceholder in it is replaced by the path of the istioctl binary.
+// The IstioOperator is passed on stdin, as a remote istioctl can not read local files. The kubeconfig is still passed as local file,
+// the wrapper has to make it available to a remote istioctl. An empty wrapper runs istioctl directly.
+func (c *DefaultCommander) WithExecWrapper(wrapper []string) *DefaultCommander {
+	c.wrapper = append([]string{}, wrapper...)
+	return c
+}
+
+// BinaryPath returns the path of the istioctl binary the commander runs, also if it runs through an exec wrapper.
+func (c *DefaultCommander) BinaryPath() string {
+	return c.istioctl.Path()
+}
+
+// ExecWrapper returns a copy of the wrapper command set by WithExecWrapper.
+func (c *DefaultCommander) ExecWrapper() []string {
+	return append([]string{}, c.wrapper...)
+}
+
+// Env returns a copy of the environment variables set by WithEnv.
+func (c *DefaultCommander) Env() map[string]string {
+	env := make(map[string]string, len(c.env))
+	for name, value := range c.env {
+		env[name] = value
+	}
+	return env
+}
+
+func (c *DefaultCommander) Uninstall(ctx context.Context, kubeconfig string, logger *zap.SugaredLogger) error {
 
 	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
 	if err != nil {
@@ -54,12 +156,12 @@ func (c *DefaultCommander) Uninstall(kubeconfig string, logger *zap.SugaredLogge
 		}
 	}()
 
-	cmd := execCommand(c.istioctl.path, "x", "uninstall", "--purge", "--kubeconfig", kubeconfigPath, "--skip-confirmation")
+	cmd := c.command("x", "uninstall", "--purge", "--kubeconfig", kubeconfigPath, "--skip-confirmation")
 
-	return c.execute(cmd, logger)
+	return c.execute(ctx, "x uninstall", cmd, logger)
 }
 
-func (c *DefaultCommander) Install(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error {
+func (c *DefaultCommander) Install(ctx context.Context, istioOperator, kubeconfig string, logger *zap.SugaredLogger) error {
 
 	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
 	if err != nil {
@@ -73,7 +175,7 @@ func (c *DefaultCommander) Install(istioOperator, kubeconfig string, logger *zap
 		}
 	}()
 
-	istioOperatorPath, istioOperatorCf, err := file.CreateTempFileWith(istioOperator)
+	istioOperatorPath, stdin, istioOperatorCf, err := c.istioOperatorInput(istioOperator)
 	if err != nil {
 		return err
 	}
@@ -85,20 +187,144 @@ func (c *DefaultCommander) Install(istioOperator, kubeconfig string, logger *zap
 		}
 	}()
 
-	cmd := execCommand(c.istioctl.path, "apply", "-f", istioOperatorPath, "--kubeconfig", kubeconfigPath, "--skip-confirmation")
+	cmd := c.command("apply", "-f", istioOperatorPath, "--kubeconfig", kubeconfigPath, "--skip-confirmation")
+	cmd.Stdin = stdin
 
-	err = c.execute(cmd, logger)
+	err = c.execute(ctx, "apply", cmd, logger)
 	if err != nil && features.Enabled(features.LogIstioOperator) {
 		return errors.Wrapf(err, "rendered IstioOperator yaml was: %s ", istioOperator)
 	}
 	return err
 }
 
-func (c *DefaultCommander) Upgrade(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error {
-	return c.Install(istioOperator, kubeconfig, logger)
+func (c *DefaultCommander) Upgrade(ctx context.Context, istioOperator, kubeconfig string, logger *zap.SugaredLogger) error {
+	return c.Install(ctx, istioOperator, kubeconfig, logger)
 }
 
 func (c *DefaultCommander) Version(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error) {
+	return c.VersionContext(context.Background(), kubeconfig, logger)
+}
+
+func (c *DefaultCommander) VersionContext(ctx context.Context, kubeconfig string, logger *zap.SugaredLogger) ([]byte, error) {
+
+	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	defer func() {
+		cleanupErr := kubeconfigCf()
+		if cleanupErr != nil {
+			logger.Error(cleanupErr)
+		}
+	}()
+
+	cmd := c.command("version", "--output", "json", "--kubeconfig", kubeconfigPath)
+	// stderr is kept out of the output, as warnings printed there would break parsing of the JSON
+	out, err := c.outputContext(ctx, cmd, "version", logger)
+	if err != nil && ctx.Err() != nil {
+		return []byte{}, errors.Wrap(ctx.Err(), "istioctl version was aborted")
+	}
+	if err != nil {
+		return []byte{}, newCommandError("version", err)
+	}
+
+	return out, nil
+}
+
+func (c *DefaultCommander) ClientVersion(logger *zap.SugaredLogger) (string, error) {
+	cmd := c.command("version", "--remote=false", "--short")
+	out, err := c.output(cmd, "version", logger)
+	if err != nil {
+		return "", newCommandError("version", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *DefaultCommander) ProxyStatus(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error) {
+
+	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	defer func() {
+		cleanupErr := kubeconfigCf()
+		if cleanupErr != nil {
+			logger.Error(cleanupErr)
+		}
+	}()
+
+	cmd := c.command("proxy-status", "--kubeconfig", kubeconfigPath)
+	// stderr is kept out of the output, as warnings printed there would break parsing of the status table
+	out, err := c.output(cmd, "proxy-status", logger)
+	if err != nil {
+		return []byte{}, newCommandError("proxy-status", err)
+	}
+
+	return out, nil
+}
+
+func (c *DefaultCommander) ProxyConfigDump(kubeconfig, namespace, pod string, logger *zap.SugaredLogger) ([]byte, error) {
+
+	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	defer func() {
+		cleanupErr := kubeconfigCf()
+		if cleanupErr != nil {
+			logger.Error(cleanupErr)
+		}
+	}()
+
+	cmd := c.command("proxy-config", "all", pod, "--namespace", namespace, "--output", "json", "--kubeconfig", kubeconfigPath)
+	// stderr is kept out of the output, as warnings printed there would break parsing of the JSON
+	out, err := c.output(cmd, "proxy-config", logger)
+	if err != nil {
+		return []byte{}, newCommandError("proxy-config", err)
+	}
+
+	return out, nil
+}
+
+func (c *DefaultCommander) Analyze(kubeconfig, namespace string, logger *zap.SugaredLogger) ([]byte, error) {
+
+	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	defer func() {
+		cleanupErr := kubeconfigCf()
+		if cleanupErr != nil {
+			logger.Error(cleanupErr)
+		}
+	}()
+
+	args := []string{"analyze", "--output", "json", "--kubeconfig", kubeconfigPath}
+	if namespace == "" {
+		args = append(args, "--all-namespaces")
+	} else {
+		args = append(args, "--namespace", namespace)
+	}
+	cmd := c.command(args...)
+	// stderr is kept out of the output, as warnings printed there would break parsing of the JSON
+	out, err := c.output(cmd, "analyze", logger)
+	if err != nil {
+		err = newCommandError("analyze", err)
+		if cmdErr, ok := AsCommandError(err); ok && cmdErr.ExitCode == analyzerFoundIssuesExitCode {
+			return out, nil
+		}
+		return []byte{}, err
+	}
+
+	return out, nil
+}
+
+func (c *DefaultCommander) PreCheck(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error) {
 
 	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
 	if err != nil {
@@ -112,16 +338,180 @@ func (c *DefaultCommander) Version(kubeconfig string, logger *zap.SugaredLogger)
 		}
 	}()
 
-	cmd := execCommand(c.istioctl.path, "version", "--output", "json", "--kubeconfig", kubeconfigPath)
-	out, err := cmd.CombinedOutput()
+	cmd := c.command("x", "precheck", "--output", "json", "--kubeconfig", kubeconfigPath)
+	// stderr is kept out of the output, as warnings printed there would break parsing of the JSON
+	out, err := c.output(cmd, "precheck", logger)
+	if err != nil {
+		// precheck fails with a generic exit code if it found errors, but only prints the found messages to stdout
+		if len(bytes.TrimSpace(out)) > 0 {
+			return out, nil
+		}
+		return []byte{}, newCommandError("precheck", err)
+	}
+
+	return out, nil
+}
+
+func (c *DefaultCommander) ProfileDump(istioOperator string, logger *zap.SugaredLogger) ([]byte, error) {
+
+	istioOperatorPath, stdin, istioOperatorCf, err := c.istioOperatorInput(istioOperator)
 	if err != nil {
 		return []byte{}, err
 	}
 
+	defer func() {
+		cleanupErr := istioOperatorCf()
+		if cleanupErr != nil {
+			logger.Error(cleanupErr)
+		}
+	}()
+
+	cmd := c.command("profile", "dump", "--filename", istioOperatorPath)
+	cmd.Stdin = stdin
+	// stderr is kept out of the output, as warnings printed there would break parsing of the YAML
+	out, err := c.output(cmd, "profile dump", logger)
+	if err != nil {
+		return []byte{}, newCommandError("profile dump", err)
+	}
+
 	return out, nil
 }
 
-func (c *DefaultCommander) execute(cmd *exec.Cmd, logger *zap.SugaredLogger) error {
+func (c *DefaultCommander) BugReport(ctx context.Context, kubeconfig, outputDir string, logger *zap.SugaredLogger) (string, error) {
+	if len(c.wrapper) > 0 {
+		return "", errors.Errorf("istioctl bug-report can not run through the exec wrapper %s, the archive would not be created in %s", c.wrapper[0], outputDir)
+	}
+
+	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		cleanupErr := kubeconfigCf()
+		if cleanupErr != nil {
+			logger.Error(cleanupErr)
+		}
+	}()
+
+	cmd := c.command("bug-report", "--kubeconfig", kubeconfigPath)
+	// the archive is created in the working directory of istioctl
+	cmd.Dir = outputDir
+	err = c.execute(ctx, "bug-report", cmd, logger)
+	if err != nil {
+		return "", err
+	}
+
+	archivePath := filepath.Join(outputDir, BugReportArchiveName)
+	if _, err := os.Stat(archivePath); err != nil {
+		return "", errors.Wrapf(err, "istioctl bug-report did not create %s", archivePath)
+	}
+	return archivePath, nil
+}
+
+// istioOperatorInput returns the filename argument and the stdin passing the IstioOperator to istioctl, together with the function cleaning it up.
+// It is passed as temporary file, or on stdin if the commands run through an exec wrapper.
+func (c *DefaultCommander) istioOperatorInput(istioOperator string) (string, io.Reader, func() error, error) {
+	if len(c.wrapper) > 0 {
+		return "-", strings.NewReader(istioOperator), func() error { return nil }, nil
+	}
+	istioOperatorPath, istioOperatorCf, err := file.CreateTempFileWith(istioOperator)
+	return istioOperatorPath, nil, istioOperatorCf, err
+}
+
+// command creates the istioctl command with the given args, running with the environment variables set by WithEnv
+// and through the exec wrapper set by WithExecWrapper.
+func (c *DefaultCommander) command(args ...string) *exec.Cmd {
+	name := c.istioctl.path
+	if len(c.wrapper) > 0 {
+		name = c.wrapperArg(c.wrapper[0])
+		wrapperArgs := make([]string, 0, len(c.wrapper)-1+len(args))
+		for _, arg := range c.wrapper[1:] {
+			wrapperArgs = append(wrapperArgs, c.wrapperArg(arg))
+		}
+		args = append(wrapperArgs, args...)
+	}
+	cmd := execCommand(name, args...)
+	if len(c.env) == 0 {
+		return cmd
+	}
+	environ := cmd.Env
+	if environ == nil {
+		environ = os.Environ()
+	}
+	cmd.Env = mergeEnv(environ, c.env)
+	return cmd
+}
+
+func (c *DefaultCommander) wrapperArg(arg string) string {
+	return strings.ReplaceAll(arg, ExecWrapperIstioctlPlaceholder, c.istioctl.path)
+}
+
+// mergeEnv returns the environ with the overrides set on top of it, the overrides are appended in alphabetical order.
+// PATH is not overridden, as it is required to run istioctl and its credential plugins.
+func mergeEnv(environ []string, overrides map[string]string) []string {
+	var names []string
+	for name := range overrides {
+		if name != "PATH" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	overridden := make(map[string]bool, len(names))
+	for _, name := range names {
+		overridden[name] = true
+	}
+
+	merged := make([]string, 0, len(environ)+len(names))
+	for _, variable := range environ {
+		name := strings.SplitN(variable, "=", 2)[0]
+		if !overridden[name] {
+			merged = append(merged, variable)
+		}
+	}
+	for _, name := range names {
+		merged = append(merged, name+"="+overrides[name])
+	}
+	return merged
+}
+
+// output runs cmd and returns its stdout, bounded by the output limit. stderr is logged as warnings instead of being returned.
+func (c *DefaultCommander) output(cmd *exec.Cmd, command string, logger *zap.SugaredLogger) ([]byte, error) {
+	return c.outputContext(context.Background(), cmd, command, logger)
+}
+
+// outputContext runs the command like output and kills it when the ctx is done before it finished.
+func (c *DefaultCommander) outputContext(ctx context.Context, cmd *exec.Cmd, command string, logger *zap.SugaredLogger) ([]byte, error) {
+	stdout := newBoundedBuffer(c.limit())
+	stderr := newBoundedBuffer(c.limit())
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Start()
+	if err == nil {
+		stopKill := killWhenDone(ctx, cmd, command, logger)
+		err = cmd.Wait()
+		stopKill()
+	}
+	logStderr(bytes.NewReader(stderr.Bytes()), command, logger)
+	c.warnIfTruncated(stdout, command, logger)
+	return stdout.Bytes(), err
+}
+
+func (c *DefaultCommander) limit() int {
+	if c.outputLimit <= 0 {
+		return DefaultOutputLimit
+	}
+	return c.outputLimit
+}
+
+func (c *DefaultCommander) warnIfTruncated(out *boundedBuffer, command string, logger *zap.SugaredLogger) {
+	if out.Truncated() > 0 {
+		logger.Warnf("Output of istioctl %s exceeded the limit of %d bytes, %d bytes were truncated", command, c.limit(), out.Truncated())
+	}
+}
+
+func (c *DefaultCommander) execute(ctx context.Context, command string, cmd *exec.Cmd, logger *zap.SugaredLogger) error {
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
@@ -135,6 +525,10 @@ func (c *DefaultCommander) execute(cmd *exec.Cmd, logger *zap.SugaredLogger) err
 		return err
 	}
 
+	// kill istioctl if the ctx is done before the process finished
+	stopKill := killWhenDone(ctx, cmd, command, logger)
+	defer stopKill()
+
 	// cmd.Wait() should be called only after we finish reading from stdout and stderr
 	var wg sync.WaitGroup
 	wg.Add(2)
@@ -144,20 +538,55 @@ func (c *DefaultCommander) execute(cmd *exec.Cmd, logger *zap.SugaredLogger) err
 	}()
 	go func() {
 		defer wg.Done()
-		bufferAndLog(stderr, logger)
+		logStderr(stderr, command, logger)
 	}()
 
 	wg.Wait()
 
 	if err := cmd.Wait(); err != nil {
-		return err
+		if ctx.Err() != nil {
+			return errors.Wrapf(ctx.Err(), "istioctl %s was aborted", command)
+		}
+		return newCommandError(command, err)
 	}
 	return nil
 }
 
+// killWhenDone kills the process of the started cmd when the ctx is done before the returned stop func was called.
+// stop has to be called once the process was waited for. A process which exited in the meantime is not reported as kill failure.
+func killWhenDone(ctx context.Context, cmd *exec.Cmd, command string, logger *zap.SugaredLogger) (stop func()) {
+	processDone := make(chan struct{})
+	killerDone := make(chan struct{})
+	go func() {
+		defer close(killerDone)
+		select {
+		case <-ctx.Done():
+			if killErr := cmd.Process.Kill(); killErr != nil && !errors.Is(killErr, os.ErrProcessDone) {
+				logger.Warnf("Could not kill istioctl %s process: %s", command, killErr)
+			}
+		case <-processDone:
+		}
+	}()
+	return func() {
+		close(processDone)
+		<-killerDone
+	}
+}
+
 func bufferAndLog(r io.Reader, logger *zap.SugaredLogger) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		logger.Debug(scanner.Text())
 	}
 }
+
+// logStderr logs each non-empty line istioctl printed on stderr as warning, marked with the StderrLogMarker, e.g. to surface deprecation warnings.
+func logStderr(r io.Reader, command string, logger *zap.SugaredLogger) {
+	stderrLogger := logger.With("source", StderrLogMarker, "command", command)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			stderrLogger.Warn(line)
+		}
+	}
+}
@@ -1,28 +1,95 @@
 package istioctl
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 const (
-	versionOutput = "version 1.11.1"
-	kubeconfig    = "kubeConfig"
+	versionOutput     = "version 1.11.1"
+	versionWarning    = "! the version command is deprecated"
+	clientVersion     = "1.11.1"
+	proxyStatusOutput = "NAME     CDS     LDS     EDS     RDS     ISTIOD     VERSION"
+	configDumpOutput  = `{"configs":[]}`
+	analyzeOutput     = `[{"code":"IST0102","level":"Info","message":"The namespace is not enabled for Istio injection.","origin":"Namespace default"}]`
+	precheckOutput    = `[{"code":"IST0141","level":"Error","message":"The Kubernetes Version \"1.19.0\" is lower than the minimum version: 1.22","origin":""}]`
+	profileDumpOutput = "apiVersion: install.istio.io/v1alpha1\nkind: IstioOperator\nspec:\n  profile: default\n"
+	kubeconfig        = "kubeConfig"
 )
 
 var testArgs []string
+var testExitCode string
+var testSleep string
+var testOutputSize string
+var testStdout string
+var testCommand string
+var testEchoStdin string
 
 func TestExecProcess(t *testing.T) {
 	if os.Getenv("GO_WANT_EXEC_PROCESS") != "1" {
 		return
 	}
-	if os.Getenv("COMMAND") == "version" {
+	if os.Getenv("COMMAND") == "version" && os.Args[len(os.Args)-1] == "--short" {
+		_, _ = fmt.Fprint(os.Stderr, "warning: printed on stderr")
+		_, _ = fmt.Fprintln(os.Stdout, clientVersion)
+	} else if os.Getenv("COMMAND") == "version" {
+		_, _ = fmt.Fprintln(os.Stderr, versionWarning)
 		_, _ = fmt.Fprint(os.Stdout, versionOutput)
+		if alphaCommands, ok := os.LookupEnv("ISTIOCTL_ENABLE_ALPHA_COMMANDS"); ok {
+			_, _ = fmt.Fprint(os.Stdout, " alpha commands "+alphaCommands)
+		}
+	}
+	if size, err := strconv.Atoi(os.Getenv("OUTPUT_SIZE")); err == nil {
+		_, _ = fmt.Fprint(os.Stderr, strings.Repeat("e", size))
+		_, _ = fmt.Fprint(os.Stdout, strings.Repeat("o", size))
+	}
+	if os.Getenv("COMMAND") == "proxy-status" {
+		_, _ = fmt.Fprint(os.Stderr, "warning: printed on stderr")
+		_, _ = fmt.Fprint(os.Stdout, proxyStatusOutput)
+	}
+	if os.Getenv("COMMAND") == "proxy-config" {
+		_, _ = fmt.Fprint(os.Stderr, "warning: printed on stderr")
+		_, _ = fmt.Fprint(os.Stdout, configDumpOutput)
+	}
+	if os.Getenv("COMMAND") == "analyze" {
+		_, _ = fmt.Fprint(os.Stderr, "warning: printed on stderr")
+		_, _ = fmt.Fprint(os.Stdout, analyzeOutput)
+	}
+	if os.Getenv("COMMAND") == "profile" {
+		_, _ = fmt.Fprint(os.Stderr, "warning: printed on stderr")
+		_, _ = fmt.Fprint(os.Stdout, profileDumpOutput)
+	}
+	if os.Getenv("COMMAND") == "x" {
+		_, _ = fmt.Fprint(os.Stderr, "warning: printed on stderr")
+		_, _ = fmt.Fprint(os.Stdout, os.Getenv("STDOUT"))
+	}
+	if os.Getenv("COMMAND") == "bug-report" && os.Getenv("EXIT_CODE") == "" {
+		_, _ = fmt.Fprint(os.Stderr, "Creating an archive at "+BugReportArchiveName)
+		_ = ioutil.WriteFile(BugReportArchiveName, []byte("archive"), 0600)
+	}
+	if os.Getenv("ECHO_STDIN") == "1" {
+		_, _ = io.Copy(os.Stdout, os.Stdin)
+	}
+	if sleep, err := time.ParseDuration(os.Getenv("SLEEP")); err == nil {
+		time.Sleep(sleep)
+	}
+	if exitCode, err := strconv.Atoi(os.Getenv("EXIT_CODE")); err == nil {
+		os.Exit(exitCode)
 	}
 	os.Exit(0)
 }
@@ -30,11 +97,17 @@ func TestExecProcess(t *testing.T) {
 func fakeExecCommand(command string, args ...string) *exec.Cmd {
 	cs := []string{"-test.run=TestExecProcess", "--", command}
 	cs = append(cs, args...)
+	testCommand = command
 	testArgs = args
 	/* #nosec */
 	cmd := exec.Command(os.Args[0], cs...)
 	cmd.Env = []string{"GO_WANT_EXEC_PROCESS=1"}
 	cmd.Env = append(cmd.Env, "COMMAND="+args[0])
+	cmd.Env = append(cmd.Env, "EXIT_CODE="+testExitCode)
+	cmd.Env = append(cmd.Env, "SLEEP="+testSleep)
+	cmd.Env = append(cmd.Env, "OUTPUT_SIZE="+testOutputSize)
+	cmd.Env = append(cmd.Env, "STDOUT="+testStdout)
+	cmd.Env = append(cmd.Env, "ECHO_STDIN="+testEchoStdin)
 	return cmd
 }
 
@@ -45,7 +118,7 @@ func Test_DefaultCommander_Install(t *testing.T) {
 	commander := DefaultCommander{}
 	t.Run("should run the install command", func(t *testing.T) {
 		// when
-		errors := commander.Install(istioOperator, kubeconfig, log)
+		errors := commander.Install(context.TODO(), istioOperator, kubeconfig, log)
 
 		// then
 		require.NoError(t, errors)
@@ -64,7 +137,7 @@ func Test_DefaultCommander_Uninstall(t *testing.T) {
 
 	t.Run("should run the install command", func(t *testing.T) {
 		//when
-		err := commander.Uninstall(kubeconfig, log)
+		err := commander.Uninstall(context.TODO(), kubeconfig, log)
 
 		// then
 		require.NoError(t, err)
@@ -83,7 +156,7 @@ func Test_DefaultCommander_Upgrade(t *testing.T) {
 
 	t.Run("should run the apply command", func(t *testing.T) {
 		// when
-		errors := commander.Upgrade(istioOperator, kubeconfig, log)
+		errors := commander.Upgrade(context.TODO(), istioOperator, kubeconfig, log)
 
 		// then
 		require.NoError(t, errors)
@@ -110,4 +183,629 @@ func Test_DefaultCommander_Version(t *testing.T) {
 		require.EqualValues(t, testArgs[2], "json")
 		require.EqualValues(t, testArgs[3], "--kubeconfig")
 	})
+
+	t.Run("should log stderr as warnings and return stdout only", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		// when
+		got, err := commander.Version(kubeconfig, zap.New(core).Sugar())
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, versionOutput, string(got))
+		warnings := logs.FilterMessage(versionWarning).All()
+		require.Len(t, warnings, 1)
+		require.Equal(t, zapcore.WarnLevel, warnings[0].Level)
+		require.Equal(t, StderrLogMarker, warnings[0].ContextMap()["source"])
+		require.Equal(t, "version", warnings[0].ContextMap()["command"])
+	})
+}
+
+func Test_DefaultCommander_ProxyStatus(t *testing.T) {
+	execCommand = fakeExecCommand
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should run the proxy-status command and return stdout only", func(t *testing.T) {
+		// when
+		got, errors := commander.ProxyStatus(kubeconfig, log)
+
+		// then
+		require.NoError(t, errors)
+		require.EqualValues(t, proxyStatusOutput, string(got))
+		require.EqualValues(t, testArgs[0], "proxy-status")
+		require.EqualValues(t, testArgs[1], "--kubeconfig")
+	})
+}
+
+func Test_DefaultCommander_ProxyConfigDump(t *testing.T) {
+	execCommand = fakeExecCommand
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should run the proxy-config all command for the pod and return stdout only", func(t *testing.T) {
+		// when
+		got, errors := commander.ProxyConfigDump(kubeconfig, "default", "httpbin-74fb669cc6-vlbvz", log)
+
+		// then
+		require.NoError(t, errors)
+		require.EqualValues(t, configDumpOutput, string(got))
+		require.EqualValues(t, []string{"proxy-config", "all", "httpbin-74fb669cc6-vlbvz", "--namespace", "default", "--output", "json", "--kubeconfig"}, testArgs[:8])
+	})
+}
+
+func Test_DefaultCommander_Analyze(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { testExitCode = "" }()
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should run the analyze command for all namespaces", func(t *testing.T) {
+		// when
+		got, err := commander.Analyze(kubeconfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, analyzeOutput, string(got))
+		require.EqualValues(t, []string{"analyze", "--output", "json", "--kubeconfig"}, testArgs[:4])
+		require.EqualValues(t, "--all-namespaces", testArgs[5])
+	})
+
+	t.Run("should run the analyze command for the given namespace", func(t *testing.T) {
+		// when
+		_, err := commander.Analyze(kubeconfig, "default", log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"--namespace", "default"}, testArgs[5:])
+	})
+
+	t.Run("should return the output when the analyzer found issues", func(t *testing.T) {
+		// given
+		testExitCode = "79"
+
+		// when
+		got, err := commander.Analyze(kubeconfig, "default", log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, analyzeOutput, string(got))
+	})
+}
+
+func Test_DefaultCommander_ClientVersion(t *testing.T) {
+	execCommand = fakeExecCommand
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should return the client version without a kubeconfig", func(t *testing.T) {
+		// when
+		got, err := commander.ClientVersion(log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, clientVersion, got)
+		require.EqualValues(t, []string{"version", "--remote=false", "--short"}, testArgs)
+	})
+
+	t.Run("should log stderr as warnings and return stdout only", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		// when
+		got, err := commander.ClientVersion(zap.New(core).Sugar())
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, clientVersion, got)
+		warnings := logs.FilterMessage("warning: printed on stderr").All()
+		require.Len(t, warnings, 1)
+		require.Equal(t, zapcore.WarnLevel, warnings[0].Level)
+		require.Equal(t, "version", warnings[0].ContextMap()["command"])
+	})
+}
+
+func Test_DefaultCommander_PreCheck(t *testing.T) {
+	execCommand = fakeExecCommand
+	testStdout = precheckOutput
+	defer func() { testExitCode = ""; testStdout = "" }()
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should run the precheck command", func(t *testing.T) {
+		// when
+		got, err := commander.PreCheck(kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, precheckOutput, string(got))
+		require.EqualValues(t, []string{"x", "precheck", "--output", "json", "--kubeconfig"}, testArgs[:5])
+	})
+
+	t.Run("should return the output when the precheck found issues", func(t *testing.T) {
+		// given
+		testExitCode = "1"
+
+		// when
+		got, err := commander.PreCheck(kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, precheckOutput, string(got))
+	})
+}
+
+func Test_DefaultCommander_ProfileDump(t *testing.T) {
+	execCommand = fakeExecCommand
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should run the profile dump command for the IstioOperator", func(t *testing.T) {
+		// when
+		got, err := commander.ProfileDump("istioOperator", log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, profileDumpOutput, string(got))
+		require.EqualValues(t, []string{"profile", "dump", "--filename"}, testArgs[:3])
+	})
+}
+
+func Test_DefaultCommander_BugReport(t *testing.T) {
+	execCommand = fakeExecCommand
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should run the bug-report command in the output directory and return the archive path", func(t *testing.T) {
+		// given
+		outputDir, err := ioutil.TempDir("", "bug-report")
+		require.NoError(t, err)
+		defer os.RemoveAll(outputDir)
+
+		// when
+		got, err := commander.BugReport(context.TODO(), kubeconfig, outputDir, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(outputDir, BugReportArchiveName), got)
+		require.FileExists(t, got)
+		require.EqualValues(t, []string{"bug-report", "--kubeconfig"}, testArgs[:2])
+	})
+
+	t.Run("should return CommandError when istioctl failed", func(t *testing.T) {
+		// given
+		testExitCode = "2"
+		defer func() { testExitCode = "" }()
+		outputDir, err := ioutil.TempDir("", "bug-report")
+		require.NoError(t, err)
+		defer os.RemoveAll(outputDir)
+
+		// when
+		got, err := commander.BugReport(context.TODO(), kubeconfig, outputDir, log)
+
+		// then
+		require.Empty(t, got)
+		cmdErr, ok := AsCommandError(err)
+		require.True(t, ok)
+		require.Equal(t, 2, cmdErr.ExitCode)
+		require.NoFileExists(t, filepath.Join(outputDir, BugReportArchiveName))
+	})
+
+	t.Run("should kill istioctl when the deadline is exceeded", func(t *testing.T) {
+		// given
+		testSleep = "10s"
+		defer func() { testSleep = "" }()
+		outputDir, err := ioutil.TempDir("", "bug-report")
+		require.NoError(t, err)
+		defer os.RemoveAll(outputDir)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// when
+		got, err := commander.BugReport(ctx, kubeconfig, outputDir, log)
+
+		// then
+		require.Empty(t, got)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should not run through an exec wrapper", func(t *testing.T) {
+		// given
+		testArgs = nil
+		wrapped := (&DefaultCommander{}).WithExecWrapper([]string{"ssh", "bastion", ExecWrapperIstioctlPlaceholder})
+
+		// when
+		got, err := wrapped.BugReport(context.TODO(), kubeconfig, "/tmp/reports", log)
+
+		// then
+		require.Empty(t, got)
+		require.EqualError(t, err, "istioctl bug-report can not run through the exec wrapper ssh, the archive would not be created in /tmp/reports")
+		require.Nil(t, testArgs)
+	})
+}
+
+func Test_DefaultCommander_BinaryPath(t *testing.T) {
+	t.Run("should report the path of the istioctl binary also when running through an exec wrapper", func(t *testing.T) {
+		// given
+		commander := NewDefaultCommander(Executable{path: "/usr/local/bin/istioctl-1.11.1"})
+
+		// when
+		wrapped := commander.WithExecWrapper([]string{"ssh", "bastion", ExecWrapperIstioctlPlaceholder})
+
+		// then
+		var reporter BinaryPathReporter = wrapped
+		require.Equal(t, "/usr/local/bin/istioctl-1.11.1", reporter.BinaryPath())
+	})
+}
+
+func Test_DefaultCommander_Env(t *testing.T) {
+	execCommand = fakeExecCommand
+	log := logger.NewLogger(false)
+
+	t.Run("should pass the environment variables to istioctl", func(t *testing.T) {
+		// given
+		commander := (&DefaultCommander{}).WithEnv(map[string]string{"ISTIOCTL_ENABLE_ALPHA_COMMANDS": "true"})
+
+		// when
+		got, err := commander.Version(kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, versionOutput+" alpha commands true", string(got))
+	})
+
+	t.Run("should keep the inherited environment", func(t *testing.T) {
+		// given
+		commander := (&DefaultCommander{}).WithEnv(map[string]string{"HTTPS_PROXY": "http://proxy:3128"})
+
+		// when
+		cmd := commander.command("version")
+
+		// then
+		require.Contains(t, cmd.Env, "GO_WANT_EXEC_PROCESS=1")
+		require.Contains(t, cmd.Env, "HTTPS_PROXY=http://proxy:3128")
+	})
+}
+
+func Test_DefaultCommander_ExecWrapper(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { testEchoStdin = "" }()
+	istioOperator := "kind: IstioOperator"
+	wrapper := []string{"ssh", "bastion", ExecWrapperIstioctlPlaceholder}
+
+	t.Run("should run istioctl through the wrapper", func(t *testing.T) {
+		// given
+		commander := (&DefaultCommander{istioctl: Executable{path: "/usr/local/bin/istioctl"}}).WithExecWrapper(wrapper)
+
+		// when
+		_, err := commander.Version(kubeconfig, logger.NewLogger(false))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "ssh", testCommand)
+		require.EqualValues(t, []string{"bastion", "/usr/local/bin/istioctl", "version", "--output", "json", "--kubeconfig"}, testArgs[:6])
+		require.Equal(t, wrapper, commander.ExecWrapper())
+	})
+
+	t.Run("should pass the IstioOperator on stdin to the profile dump and capture its stdout", func(t *testing.T) {
+		// given
+		testEchoStdin = "1"
+		commander := (&DefaultCommander{}).WithExecWrapper(wrapper)
+
+		// when
+		got, err := commander.ProfileDump(istioOperator, logger.NewLogger(false))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, istioOperator, string(got))
+		require.EqualValues(t, []string{"profile", "dump", "--filename", "-"}, testArgs[2:])
+	})
+
+	t.Run("should pass the IstioOperator on stdin to the install and log its output", func(t *testing.T) {
+		// given
+		testEchoStdin = "1"
+		core, logs := observer.New(zapcore.DebugLevel)
+		commander := (&DefaultCommander{}).WithExecWrapper(wrapper)
+
+		// when
+		err := commander.Install(context.TODO(), istioOperator, kubeconfig, zap.New(core).Sugar())
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"apply", "-f", "-"}, testArgs[2:5])
+		require.Equal(t, 1, logs.FilterMessage(istioOperator).Len())
+	})
+
+	t.Run("should run istioctl directly without wrapper", func(t *testing.T) {
+		// given
+		testEchoStdin = ""
+		commander := (&DefaultCommander{istioctl: Executable{path: "/usr/local/bin/istioctl"}}).WithExecWrapper(nil)
+
+		// when
+		cmd := commander.command("version")
+
+		// then
+		require.Equal(t, "/usr/local/bin/istioctl", testCommand)
+		require.Nil(t, cmd.Stdin)
+	})
+}
+
+func Test_mergeEnv(t *testing.T) {
+	environ := []string{"PATH=/usr/bin", "HOME=/root", "HTTPS_PROXY=http://old:3128"}
+
+	t.Run("should set the overrides on top of the environment", func(t *testing.T) {
+		// when
+		got := mergeEnv(environ, map[string]string{"NO_PROXY": "localhost", "HTTPS_PROXY": "http://proxy:3128"})
+
+		// then
+		require.Equal(t, []string{"PATH=/usr/bin", "HOME=/root", "HTTPS_PROXY=http://proxy:3128", "NO_PROXY=localhost"}, got)
+	})
+
+	t.Run("should not override PATH", func(t *testing.T) {
+		// when
+		got := mergeEnv(environ, map[string]string{"PATH": "/tmp"})
+
+		// then
+		require.Equal(t, environ, got)
+	})
+}
+
+func Test_DefaultCommander_OutputLimit(t *testing.T) {
+	execCommand = fakeExecCommand
+	testOutputSize = strconv.Itoa(1024 * 1024)
+	defer func() { testOutputSize = "" }()
+	log := logger.NewLogger(false)
+
+	t.Run("should truncate large output of the version command", func(t *testing.T) {
+		// given
+		commander := (&DefaultCommander{}).WithOutputLimit(4096)
+
+		// when
+		got, err := commander.Version(kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, got, 4096+len(TruncatedOutputMarker))
+		require.True(t, strings.HasPrefix(string(got), versionOutput))
+		require.True(t, strings.HasSuffix(string(got), TruncatedOutputMarker))
+	})
+
+	t.Run("should truncate large output of the proxy-status command", func(t *testing.T) {
+		// given
+		commander := (&DefaultCommander{}).WithOutputLimit(4096)
+
+		// when
+		got, err := commander.ProxyStatus(kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, proxyStatusOutput+strings.Repeat("o", 4096-len(proxyStatusOutput))+TruncatedOutputMarker, string(got))
+	})
+
+	t.Run("should not truncate output within the default limit", func(t *testing.T) {
+		// given
+		commander := DefaultCommander{}
+
+		// when
+		got, err := commander.ProxyStatus(kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, got, len(proxyStatusOutput)+1024*1024)
+		require.NotContains(t, string(got), TruncatedOutputMarker)
+	})
+}
+
+func Test_DefaultCommander_ExitCodes(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { testExitCode = "" }()
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	tests := []struct {
+		name     string
+		exitCode int
+		command  string
+		run      func() error
+	}{
+		{
+			name:     "install",
+			exitCode: 1,
+			command:  "apply",
+			run:      func() error { return commander.Install(context.TODO(), "istioOperator", kubeconfig, log) },
+		},
+		{
+			name:     "uninstall",
+			exitCode: 2,
+			command:  "x uninstall",
+			run:      func() error { return commander.Uninstall(context.TODO(), kubeconfig, log) },
+		},
+		{
+			name:     "version",
+			exitCode: 64,
+			command:  "version",
+			run: func() error {
+				_, err := commander.Version(kubeconfig, log)
+				return err
+			},
+		},
+		{
+			name:     "client version",
+			exitCode: 2,
+			command:  "version",
+			run: func() error {
+				_, err := commander.ClientVersion(log)
+				return err
+			},
+		},
+		{
+			name:     "proxy-status",
+			exitCode: 79,
+			command:  "proxy-status",
+			run: func() error {
+				_, err := commander.ProxyStatus(kubeconfig, log)
+				return err
+			},
+		},
+		{
+			name:     "analyze",
+			exitCode: 1,
+			command:  "analyze",
+			run: func() error {
+				_, err := commander.Analyze(kubeconfig, "", log)
+				return err
+			},
+		},
+		{
+			name:     "precheck",
+			exitCode: 1,
+			command:  "precheck",
+			run: func() error {
+				_, err := commander.PreCheck(kubeconfig, log)
+				return err
+			},
+		},
+		{
+			name:     "profile dump",
+			exitCode: 64,
+			command:  "profile dump",
+			run: func() error {
+				_, err := commander.ProfileDump("istioOperator", log)
+				return err
+			},
+		},
+		{
+			name:     "proxy-config",
+			exitCode: 3,
+			command:  "proxy-config",
+			run: func() error {
+				_, err := commander.ProxyConfigDump(kubeconfig, "default", "pod", log)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(fmt.Sprintf("should return CommandError with exit code %d for %s", tt.exitCode, tt.name), func(t *testing.T) {
+			// given
+			testExitCode = strconv.Itoa(tt.exitCode)
+
+			// when
+			err := tt.run()
+
+			// then
+			require.Error(t, err)
+			cmdErr, ok := AsCommandError(err)
+			require.True(t, ok)
+			require.Equal(t, tt.exitCode, cmdErr.ExitCode)
+			require.Equal(t, tt.command, cmdErr.Command)
+		})
+	}
+
+	t.Run("should not return CommandError on success", func(t *testing.T) {
+		// given
+		testExitCode = "0"
+
+		// when
+		err := commander.Uninstall(context.TODO(), kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		_, ok := AsCommandError(err)
+		require.False(t, ok)
+	})
+}
+
+func Test_DefaultCommander_Deadline(t *testing.T) {
+	execCommand = fakeExecCommand
+	var commander Commander = &DefaultCommander{}
+	log := logger.NewLogger(false)
+
+	t.Run("should kill istioctl when the deadline is exceeded", func(t *testing.T) {
+		// given
+		testSleep = "10s"
+		defer func() { testSleep = "" }()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// when
+		start := time.Now()
+		err := commander.Uninstall(ctx, kubeconfig, log)
+
+		// then
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, int64(time.Since(start)), int64(10*time.Second))
+		_, ok := AsCommandError(err)
+		require.False(t, ok)
+	})
+
+	t.Run("should not abort istioctl finishing before the deadline", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// when
+		err := commander.Uninstall(ctx, kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_DefaultCommander_VersionContext(t *testing.T) {
+	execCommand = fakeExecCommand
+	var versioner ContextVersioner = &DefaultCommander{}
+	log := logger.NewLogger(false)
+
+	t.Run("should kill istioctl version when the deadline is exceeded", func(t *testing.T) {
+		// given
+		testSleep = "10s"
+		defer func() { testSleep = "" }()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// when
+		start := time.Now()
+		_, err := versioner.VersionContext(ctx, kubeconfig, log)
+
+		// then
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, int64(time.Since(start)), int64(10*time.Second))
+	})
+
+	t.Run("should return the version finishing before the deadline", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// when
+		got, err := versioner.VersionContext(ctx, kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, versionOutput, string(got))
+	})
+}
+
+func Test_killWhenDone(t *testing.T) {
+
+	t.Run("should not warn when the process exited before it was killed", func(t *testing.T) {
+		// given
+		cmd := fakeExecCommand("istioctl", "version")
+		require.NoError(t, cmd.Run())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		// when
+		stop := killWhenDone(ctx, cmd, "version", zap.New(core).Sugar())
+		stop()
+
+		// then
+		require.Zero(t, logs.FilterLevelExact(zapcore.WarnLevel).Len())
+	})
 }
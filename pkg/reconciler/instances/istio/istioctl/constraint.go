@@ -0,0 +1,104 @@
+package istioctl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LatestVersionConstraint matches any version, so it resolves to the newest available istioctl.
+const LatestVersionConstraint = "*"
+
+// VersionConstraint selects istioctl versions of a minor release, e.g. "1.17.x" for the newest available 1.17 patch.
+type VersionConstraint struct {
+	major    int64
+	minor    int64
+	minPatch int64
+	// exact is set if the constraint is a full version, which is matched as is
+	exact bool
+	// any is set for the LatestVersionConstraint, which matches all versions
+	any bool
+}
+
+// ParseVersionConstraint parses a version constraint. A full version like "1.17.3" only matches itself,
+// "1.17" and "1.17.x" match any patch of the minor version ("X" and "*" are accepted as wildcards),
+// "~1.17.2" matches the patches of the minor version which are not smaller than the given one,
+// and the LatestVersionConstraint "*" matches any version.
+func ParseVersionConstraint(constraint string) (VersionConstraint, error) {
+	trimmed := strings.TrimSpace(constraint)
+	if trimmed == "" {
+		return VersionConstraint{}, errors.New("invalid istioctl version constraint: empty input")
+	}
+	if trimmed == LatestVersionConstraint {
+		return VersionConstraint{any: true}, nil
+	}
+
+	tilde := strings.HasPrefix(trimmed, "~")
+	parts := strings.Split(strings.TrimPrefix(trimmed, "~"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return VersionConstraint{}, errors.Errorf("Invalid istioctl version constraint '%s': expected 'major.minor.patch', 'major.minor.x' or '~major.minor.patch'", trimmed)
+	}
+
+	res := VersionConstraint{}
+	var err error
+	if res.major, err = parseVersionNumber(parts[0]); err != nil {
+		return VersionConstraint{}, errors.Wrapf(err, "Invalid istioctl version constraint '%s'", trimmed)
+	}
+	if res.minor, err = parseVersionNumber(parts[1]); err != nil {
+		return VersionConstraint{}, errors.Wrapf(err, "Invalid istioctl version constraint '%s'", trimmed)
+	}
+	if len(parts) == 2 || isWildcard(parts[2]) {
+		return res, nil
+	}
+	if res.minPatch, err = parseVersionNumber(parts[2]); err != nil {
+		return VersionConstraint{}, errors.Wrapf(err, "Invalid istioctl version constraint '%s'", trimmed)
+	}
+	res.exact = !tilde
+	return res, nil
+}
+
+func parseVersionNumber(value string) (int64, error) {
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 {
+		return 0, errors.Errorf("'%s' is not a positive integer", value)
+	}
+	return number, nil
+}
+
+func isWildcard(value string) bool {
+	return value == "x" || value == "X" || value == "*"
+}
+
+// IsExact returns true if the constraint only matches a single version.
+func (c VersionConstraint) IsExact() bool {
+	return c.exact
+}
+
+// Check returns true if the version satisfies the constraint.
+func (c VersionConstraint) Check(version Version) bool {
+	if c.any {
+		return true
+	}
+	if version.value.Major != c.major || version.value.Minor != c.minor {
+		return false
+	}
+	if c.exact {
+		return version.value.Patch == c.minPatch
+	}
+	return version.value.Patch >= c.minPatch
+}
+
+func (c VersionConstraint) String() string {
+	if c.any {
+		return LatestVersionConstraint
+	}
+	if c.exact {
+		return fmt.Sprintf("%d.%d.%d", c.major, c.minor, c.minPatch)
+	}
+	if c.minPatch > 0 {
+		return fmt.Sprintf("~%d.%d.%d", c.major, c.minor, c.minPatch)
+	}
+	return fmt.Sprintf("%d.%d.x", c.major, c.minor)
+}
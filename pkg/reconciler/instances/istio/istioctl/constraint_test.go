@@ -0,0 +1,104 @@
+package istioctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		want       string
+		exact      bool
+		matches    []string
+		mismatches []string
+		wantErr    string
+	}{
+		{
+			name:       "should match any patch of a minor version wildcard",
+			constraint: "1.17.x",
+			want:       "1.17.x",
+			matches:    []string{"1.17.0", "1.17.5"},
+			mismatches: []string{"1.16.9", "1.18.0", "2.17.0"},
+		},
+		{
+			name:       "should accept upper case and star wildcards",
+			constraint: " 1.17.* ",
+			want:       "1.17.x",
+			matches:    []string{"1.17.2"},
+		},
+		{
+			name:       "should match any patch of a minor version without patch",
+			constraint: "1.17",
+			want:       "1.17.x",
+			matches:    []string{"1.17.0", "1.17.3"},
+			mismatches: []string{"1.16.3"},
+		},
+		{
+			name:       "should match patches not smaller than the tilde patch",
+			constraint: "~1.17.2",
+			want:       "~1.17.2",
+			matches:    []string{"1.17.2", "1.17.4"},
+			mismatches: []string{"1.17.1", "1.18.2"},
+		},
+		{
+			name:       "should only match the exact version",
+			constraint: "1.17.2",
+			want:       "1.17.2",
+			exact:      true,
+			matches:    []string{"1.17.2"},
+			mismatches: []string{"1.17.3"},
+		},
+		{
+			name:       "should match any version for the latest constraint",
+			constraint: " * ",
+			want:       "*",
+			matches:    []string{"1.11.0", "1.17.3", "2.0.0"},
+		},
+		{
+			name:       "should fail for an empty constraint",
+			constraint: " ",
+			wantErr:    "invalid istioctl version constraint: empty input",
+		},
+		{
+			name:       "should fail for a major version only",
+			constraint: "1",
+			wantErr:    "Invalid istioctl version constraint '1'",
+		},
+		{
+			name:       "should fail for a wildcard minor version",
+			constraint: "1.x",
+			wantErr:    "Invalid istioctl version constraint '1.x': 'x' is not a positive integer",
+		},
+		{
+			name:       "should fail for a negative patch",
+			constraint: "~1.17.-1",
+			wantErr:    "'-1' is not a positive integer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraint, err := ParseVersionConstraint(tt.constraint)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, constraint.String())
+			require.Equal(t, tt.exact, constraint.IsExact())
+			for _, version := range tt.matches {
+				v, err := VersionFromString(version)
+				require.NoError(t, err)
+				require.True(t, constraint.Check(v), "expected %s to match %s", version, tt.constraint)
+			}
+			for _, version := range tt.mismatches {
+				v, err := VersionFromString(version)
+				require.NoError(t, err)
+				require.False(t, constraint.Check(v), "expected %s not to match %s", version, tt.constraint)
+			}
+		})
+	}
+}
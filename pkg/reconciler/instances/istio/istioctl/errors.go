@@ -0,0 +1,46 @@
+package istioctl
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// CommandError is returned when an istioctl command exits with a non-zero exit code.
+type CommandError struct {
+	// Command is the istioctl sub-command which failed, e.g. "apply".
+	Command string
+
+	// ExitCode of the istioctl process.
+	ExitCode int
+
+	err error
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("istioctl %s failed with exit code %d: %s", e.Command, e.ExitCode, e.err)
+}
+
+// Unwrap returns the underlying process error.
+func (e *CommandError) Unwrap() error {
+	return e.err
+}
+
+// AsCommandError returns the CommandError wrapped in err, if there is one.
+func AsCommandError(err error) (*CommandError, bool) {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr, true
+	}
+	return nil, false
+}
+
+// newCommandError wraps a failed process execution into a CommandError. Errors not caused by the process exit code are returned unchanged.
+func newCommandError(command string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &CommandError{Command: command, ExitCode: exitErr.ExitCode(), err: err}
+	}
+	return err
+}
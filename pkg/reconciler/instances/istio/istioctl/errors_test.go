@@ -0,0 +1,37 @@
+package istioctl
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newCommandError(t *testing.T) {
+
+	t.Run("should return non exit errors unchanged", func(t *testing.T) {
+		// given
+		err := errors.New("executable file not found")
+
+		// when
+		got := newCommandError("apply", err)
+
+		// then
+		require.Equal(t, err, got)
+		_, ok := AsCommandError(got)
+		require.False(t, ok)
+	})
+
+	t.Run("should find a CommandError wrapped in another error", func(t *testing.T) {
+		// given
+		err := errors.Wrap(&CommandError{Command: "apply", ExitCode: 3, err: errors.New("exit status 3")}, "Error occurred when calling istioctl")
+
+		// when
+		cmdErr, ok := AsCommandError(err)
+
+		// then
+		require.True(t, ok)
+		require.Equal(t, 3, cmdErr.ExitCode)
+		require.Contains(t, err.Error(), "istioctl apply failed with exit code 3")
+	})
+}
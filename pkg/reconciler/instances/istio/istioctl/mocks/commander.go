@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	zap "go.uber.org/zap"
 )
@@ -12,13 +14,78 @@ type Commander struct {
 	mock.Mock
 }
 
-// Install provides a mock function with given fields: istioOperator, kubeconfig, logger
-func (_m *Commander) Install(istioOperator string, kubeconfig string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(istioOperator, kubeconfig, logger)
+// Analyze provides a mock function with given fields: kubeconfig, namespace, logger
+func (_m *Commander) Analyze(kubeconfig string, namespace string, logger *zap.SugaredLogger) ([]byte, error) {
+	ret := _m.Called(kubeconfig, namespace, logger)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) []byte); ok {
+		r0 = rf(kubeconfig, namespace, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeconfig, namespace, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BugReport provides a mock function with given fields: ctx, kubeconfig, outputDir, logger
+func (_m *Commander) BugReport(ctx context.Context, kubeconfig string, outputDir string, logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(ctx, kubeconfig, outputDir, logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) string); ok {
+		r0 = rf(ctx, kubeconfig, outputDir, logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(ctx, kubeconfig, outputDir, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClientVersion provides a mock function with given fields: logger
+func (_m *Commander) ClientVersion(logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*zap.SugaredLogger) string); ok {
+		r0 = rf(logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*zap.SugaredLogger) error); ok {
+		r1 = rf(logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Install provides a mock function with given fields: ctx, istioOperator, kubeconfig, logger
+func (_m *Commander) Install(ctx context.Context, istioOperator string, kubeconfig string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(ctx, istioOperator, kubeconfig, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(istioOperator, kubeconfig, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(ctx, istioOperator, kubeconfig, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +93,105 @@ func (_m *Commander) Install(istioOperator string, kubeconfig string, logger *za
 	return r0
 }
 
-// Uninstall provides a mock function with given fields: kubeconfig, logger
-func (_m *Commander) Uninstall(kubeconfig string, logger *zap.SugaredLogger) error {
+// PreCheck provides a mock function with given fields: kubeconfig, logger
+func (_m *Commander) PreCheck(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error) {
 	ret := _m.Called(kubeconfig, logger)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) error); ok {
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) []byte); ok {
 		r0 = rf(kubeconfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeconfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProfileDump provides a mock function with given fields: istioOperator, logger
+func (_m *Commander) ProfileDump(istioOperator string, logger *zap.SugaredLogger) ([]byte, error) {
+	ret := _m.Called(istioOperator, logger)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) []byte); ok {
+		r0 = rf(istioOperator, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(istioOperator, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProxyConfigDump provides a mock function with given fields: kubeconfig, namespace, pod, logger
+func (_m *Commander) ProxyConfigDump(kubeconfig string, namespace string, pod string, logger *zap.SugaredLogger) ([]byte, error) {
+	ret := _m.Called(kubeconfig, namespace, pod, logger)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string, string, string, *zap.SugaredLogger) []byte); ok {
+		r0 = rf(kubeconfig, namespace, pod, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeconfig, namespace, pod, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProxyStatus provides a mock function with given fields: kubeconfig, logger
+func (_m *Commander) ProxyStatus(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error) {
+	ret := _m.Called(kubeconfig, logger)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) []byte); ok {
+		r0 = rf(kubeconfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *zap.SugaredLogger) error); ok {
+		r1 = rf(kubeconfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Uninstall provides a mock function with given fields: ctx, kubeconfig, logger
+func (_m *Commander) Uninstall(ctx context.Context, kubeconfig string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(ctx, kubeconfig, logger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(ctx, kubeconfig, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,13 +199,13 @@ func (_m *Commander) Uninstall(kubeconfig string, logger *zap.SugaredLogger) err
 	return r0
 }
 
-// Upgrade provides a mock function with given fields: istioOperator, kubeconfig, logger
-func (_m *Commander) Upgrade(istioOperator string, kubeconfig string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(istioOperator, kubeconfig, logger)
+// Upgrade provides a mock function with given fields: ctx, istioOperator, kubeconfig, logger
+func (_m *Commander) Upgrade(ctx context.Context, istioOperator string, kubeconfig string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(ctx, istioOperator, kubeconfig, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(istioOperator, kubeconfig, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(ctx, istioOperator, kubeconfig, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
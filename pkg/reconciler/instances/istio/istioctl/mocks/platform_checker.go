@@ -0,0 +1,34 @@
+// Code generated by mockery v2.9.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	istioctl "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PlatformChecker is an autogenerated mock type for the PlatformChecker type
+type PlatformChecker struct {
+	mock.Mock
+}
+
+// GetPlatform provides a mock function with given fields: pathToBinary
+func (_m *PlatformChecker) GetPlatform(pathToBinary string) (istioctl.Platform, error) {
+	ret := _m.Called(pathToBinary)
+
+	var r0 istioctl.Platform
+	if rf, ok := ret.Get(0).(func(string) istioctl.Platform); ok {
+		r0 = rf(pathToBinary)
+	} else {
+		r0 = ret.Get(0).(istioctl.Platform)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(pathToBinary)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
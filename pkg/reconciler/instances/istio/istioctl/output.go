@@ -0,0 +1,50 @@
+package istioctl
+
+import "bytes"
+
+const (
+	// DefaultOutputLimit is the maximum number of bytes captured from the output of an istioctl command, unless configured otherwise.
+	DefaultOutputLimit = 16 * 1024 * 1024
+
+	// TruncatedOutputMarker is appended to the captured output of an istioctl command which exceeded the output limit.
+	TruncatedOutputMarker = "\n[istioctl output truncated]\n"
+)
+
+// boundedBuffer captures written data up to its limit and discards the rest, so verbose istioctl output can't exhaust the memory.
+// Writes never fail, so the istioctl process is not blocked once the limit is reached.
+type boundedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated int
+}
+
+func newBoundedBuffer(limit int) *boundedBuffer {
+	return &boundedBuffer{limit: limit}
+}
+
+func (b *boundedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buf.Len()
+	if remaining < 0 {
+		remaining = 0
+	}
+	if len(p) > remaining {
+		b.truncated += len(p) - remaining
+		b.buf.Write(p[:remaining])
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// Truncated returns the number of discarded bytes.
+func (b *boundedBuffer) Truncated() int {
+	return b.truncated
+}
+
+// Bytes returns the captured data, followed by the TruncatedOutputMarker if data was discarded.
+func (b *boundedBuffer) Bytes() []byte {
+	if b.truncated == 0 {
+		return b.buf.Bytes()
+	}
+	return append(append([]byte{}, b.buf.Bytes()...), TruncatedOutputMarker...)
+}
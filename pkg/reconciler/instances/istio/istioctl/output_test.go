@@ -0,0 +1,42 @@
+package istioctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_boundedBuffer(t *testing.T) {
+
+	t.Run("should capture output within the limit", func(t *testing.T) {
+		// given
+		buffer := newBoundedBuffer(10)
+
+		// when
+		n, err := buffer.Write([]byte("0123456789"))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 10, n)
+		require.Equal(t, "0123456789", string(buffer.Bytes()))
+		require.Zero(t, buffer.Truncated())
+	})
+
+	t.Run("should truncate output exceeding the limit and accept further writes", func(t *testing.T) {
+		// given
+		buffer := newBoundedBuffer(10)
+
+		// when
+		n1, err1 := buffer.Write([]byte("012345"))
+		n2, err2 := buffer.Write([]byte("6789abcdef"))
+		n3, err3 := buffer.Write([]byte("ghij"))
+
+		// then
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.Equal(t, []int{6, 10, 4}, []int{n1, n2, n3})
+		require.Equal(t, "0123456789"+TruncatedOutputMarker, string(buffer.Bytes()))
+		require.Equal(t, 10, buffer.Truncated())
+	})
+}
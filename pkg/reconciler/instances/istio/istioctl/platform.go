@@ -0,0 +1,93 @@
+package istioctl
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// Platform represents the operating system and architecture an executable is built for, in GOOS/GOARCH notation
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// HostPlatform returns the Platform the reconciler is running on
+func HostPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+func (p Platform) String() string {
+	return fmt.Sprintf("%s/%s", p.OS, p.Arch)
+}
+
+//go:generate mockery --name=PlatformChecker --outpkg=mocks --case=underscore
+// PlatformChecker implementations are able to return the Platform an istioctl executable is built for
+type PlatformChecker interface {
+	// GetPlatform returns the Platform of the istioctl binary given it's path
+	GetPlatform(pathToBinary string) (Platform, error)
+}
+
+// DefaultPlatformChecker reads the Platform from the ELF, Mach-O or PE header of the executable
+type DefaultPlatformChecker struct {
+}
+
+var (
+	elfArchs = map[elf.Machine]string{
+		elf.EM_X86_64:  "amd64",
+		elf.EM_386:     "386",
+		elf.EM_AARCH64: "arm64",
+		elf.EM_ARM:     "arm",
+		elf.EM_PPC64:   "ppc64le",
+		elf.EM_S390:    "s390x",
+	}
+	machoArchs = map[macho.Cpu]string{
+		macho.CpuAmd64: "amd64",
+		macho.CpuArm64: "arm64",
+	}
+	peArchs = map[uint16]string{
+		pe.IMAGE_FILE_MACHINE_AMD64: "amd64",
+		pe.IMAGE_FILE_MACHINE_I386:  "386",
+		pe.IMAGE_FILE_MACHINE_ARM64: "arm64",
+	}
+)
+
+func (dpc DefaultPlatformChecker) GetPlatform(pathToBinary string) (Platform, error) {
+	if f, err := elf.Open(pathToBinary); err == nil {
+		defer f.Close()
+		return elfPlatform(f, pathToBinary)
+	}
+	if f, err := macho.Open(pathToBinary); err == nil {
+		defer f.Close()
+		return platformOf("darwin", machoArchs[f.Cpu], pathToBinary)
+	}
+	if f, err := pe.Open(pathToBinary); err == nil {
+		defer f.Close()
+		return platformOf("windows", peArchs[f.Machine], pathToBinary)
+	}
+	return Platform{}, errors.Errorf("Unsupported executable format of istioctl binary %s", pathToBinary)
+}
+
+// elfPlatform returns the platform of an ELF executable. Only Linux executables are supported, which are marked with
+// the Linux or the System V OS ABI. EM_PPC64 is used for both byte orders, so the byte order tells ppc64le and ppc64 apart.
+func elfPlatform(f *elf.File, pathToBinary string) (Platform, error) {
+	if f.OSABI != elf.ELFOSABI_LINUX && f.OSABI != elf.ELFOSABI_NONE {
+		return Platform{}, errors.Errorf("Unsupported OS ABI %s of ELF istioctl binary %s", f.OSABI, pathToBinary)
+	}
+	arch := elfArchs[f.Machine]
+	if f.Machine == elf.EM_PPC64 && f.Data == elf.ELFDATA2MSB {
+		arch = "ppc64"
+	}
+	return platformOf("linux", arch, pathToBinary)
+}
+
+func platformOf(os, arch, pathToBinary string) (Platform, error) {
+	if arch == "" {
+		return Platform{}, errors.Errorf("Unsupported architecture of %s istioctl binary %s", os, pathToBinary)
+	}
+	return Platform{OS: os, Arch: arch}, nil
+}
@@ -1,6 +1,7 @@
 package istioctl
 
 import (
+	"fmt"
 	"os/exec"
 	"sort"
 	"strings"
@@ -14,14 +15,18 @@ type Version struct {
 	value semver.Version
 }
 
-//VersionFromString returns a Version from passed semantic version in the format: "major.minor.patch", where all components must be positive integers
+// distrolessSuffix is appended to the Istio version in the tags of distroless images, e.g. 1.17.2-distroless.
+const distrolessSuffix = "-distroless"
+
+//VersionFromString returns a Version from passed semantic version in the format: "major.minor.patch", where all components must be positive integers.
+//A leading "v", build metadata and the distroless suffix are removed before parsing, e.g. "v1.17.2-distroless" is parsed as "1.17.2".
 func VersionFromString(version string) (Version, error) {
 	trimmed := strings.TrimSpace(version)
 	if trimmed == "" {
 		return Version{}, errors.New("invalid istioctl version format: empty input")
 	}
 
-	val, err := semver.NewVersion(trimmed)
+	val, err := semver.NewVersion(normalizeVersion(trimmed))
 
 	if err != nil {
 		return Version{}, errors.Errorf("Invalid istioctl version format for input '%s': %s", trimmed, err.Error())
@@ -30,6 +35,24 @@ func VersionFromString(version string) (Version, error) {
 	return Version{*val}, nil
 }
 
+//VersionFromSource returns a Version like VersionFromString, with an error naming the source the version was taken from, e.g. "current Istio version".
+func VersionFromSource(version, source string) (Version, error) {
+	parsed, err := VersionFromString(version)
+	if err != nil {
+		return Version{}, errors.Wrapf(err, "Could not parse %s", source)
+	}
+	return parsed, nil
+}
+
+// normalizeVersion removes a leading "v", the build metadata and the distroless suffix from the version.
+func normalizeVersion(version string) string {
+	normalized := strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if i := strings.Index(normalized, "+"); i >= 0 {
+		normalized = normalized[:i]
+	}
+	return strings.TrimSuffix(normalized, distrolessSuffix)
+}
+
 func (v Version) String() string {
 	return v.value.String()
 }
@@ -50,19 +73,56 @@ func (v Version) BiggerThan(other Version) bool {
 // Finds an Executable for given Version
 type ExecutableResolver interface {
 	FindIstioctl(version Version) (*Executable, error)
+	// ResolveVersion returns the newest available istioctl version satisfying the constraint.
+	ResolveVersion(constraint VersionConstraint) (Version, error)
 }
 
 type DefaultIstioctlResolver struct {
 	sortedBinaries []Executable
+	// platform the binaries were selected for, empty if the binaries were not checked
+	platform Platform
+	// otherPlatformBinaries lists the binaries skipped because they are built for another platform
+	otherPlatformBinaries []string
 }
 
 func (d *DefaultIstioctlResolver) FindIstioctl(version Version) (*Executable, error) {
 	return d.findMatchingBinary(version)
 }
 
+func (d *DefaultIstioctlResolver) ResolveVersion(constraint VersionConstraint) (Version, error) {
+	for i := len(d.sortedBinaries) - 1; i >= 0; i-- {
+		if constraint.Check(d.sortedBinaries[i].version) {
+			return d.sortedBinaries[i].version, nil
+		}
+	}
+	return Version{}, errors.Errorf("No 'istioctl' binary found for version constraint: %s. Available binaries: %s", constraint, d.availableVersions())
+}
+
 func NewDefaultIstioctlResolver(paths []string, vc VersionChecker) (*DefaultIstioctlResolver, error) {
+	return newIstioctlResolver(paths, vc, nil, Platform{})
+}
+
+// NewPlatformIstioctlResolver creates a DefaultIstioctlResolver which only selects binaries built for the given platform, usually the HostPlatform.
+// Binaries for other platforms are skipped without being executed.
+func NewPlatformIstioctlResolver(paths []string, vc VersionChecker, pc PlatformChecker, platform Platform) (*DefaultIstioctlResolver, error) {
+	return newIstioctlResolver(paths, vc, pc, platform)
+}
+
+func newIstioctlResolver(paths []string, vc VersionChecker, pc PlatformChecker, platform Platform) (*DefaultIstioctlResolver, error) {
 	binariesList := []Executable{}
+	otherPlatformBinaries := []string{}
 	for _, path := range paths {
+		if pc != nil {
+			binaryPlatform, err := pc.GetPlatform(path)
+			if err != nil {
+				return nil, err
+			}
+			if binaryPlatform != platform {
+				otherPlatformBinaries = append(otherPlatformBinaries, fmt.Sprintf("%s (%s)", path, binaryPlatform))
+				continue
+			}
+		}
+
 		version, err := vc.GetIstioVersion(path)
 		if err != nil {
 			return nil, err
@@ -73,7 +133,9 @@ func NewDefaultIstioctlResolver(paths []string, vc VersionChecker) (*DefaultIsti
 	sortBinaries(binariesList)
 
 	return &DefaultIstioctlResolver{
-		sortedBinaries: binariesList,
+		sortedBinaries:        binariesList,
+		platform:              platform,
+		otherPlatformBinaries: otherPlatformBinaries,
 	}, nil
 }
 
@@ -91,11 +153,11 @@ func (d *DefaultIstioctlResolver) findMatchingBinary(version Version) (*Executab
 	}
 
 	if len(matching) == 0 {
-		availableBinaries := []string{}
-		for _, binary := range d.sortedBinaries {
-			availableBinaries = append(availableBinaries, binary.Version().String())
+		versionList := d.availableVersions()
+		if d.platform != (Platform{}) {
+			return nil, errors.Errorf("No matching 'istioctl' binary found for version: %s on platform %s. Available binaries: %s. Binaries for other platforms: %s",
+				version.String(), d.platform, versionList, strings.Join(d.otherPlatformBinaries, ", "))
 		}
-		versionList := strings.Join(availableBinaries, ", ")
 		return nil, errors.Errorf("No matching 'istioctl' binary found for version: %s. Available binaries: %s", version.String(), versionList)
 	}
 
@@ -103,6 +165,15 @@ func (d *DefaultIstioctlResolver) findMatchingBinary(version Version) (*Executab
 	return &matching[len(matching)-1], nil
 }
 
+// availableVersions returns the comma separated versions of the available binaries.
+func (d *DefaultIstioctlResolver) availableVersions() string {
+	availableBinaries := []string{}
+	for _, binary := range d.sortedBinaries {
+		availableBinaries = append(availableBinaries, binary.Version().String())
+	}
+	return strings.Join(availableBinaries, ", ")
+}
+
 //go:generate mockery --name=VersionChecker --outpkg=istioctl --case=underscore
 // VersionChecker implementations are able to return istioctl executable version
 type VersionChecker interface {
@@ -122,7 +193,7 @@ func (dvc DefaultVersionChecker) GetIstioVersion(pathToBinary string) (Version,
 		return Version{}, err
 	}
 
-	return VersionFromString(string(out))
+	return VersionFromSource(string(out), fmt.Sprintf("version reported by istioctl binary %s", pathToBinary))
 }
 
 // Executable represents an istioctl executable in a specific version existing in a local filesystem
@@ -263,3 +263,44 @@ func TestSortBinaries(t *testing.T) {
 		require.Equal(t, "/biggest", s[3].path)
 	})
 }
+
+func Test_VersionFromString_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+		wantErr string
+	}{
+		{name: "should accept a release version", version: "1.17.2", want: "1.17.2"},
+		{name: "should accept a version with surrounding whitespace", version: " 1.17.2\n", want: "1.17.2"},
+		{name: "should strip a leading v", version: "v1.17.2", want: "1.17.2"},
+		{name: "should strip the distroless suffix", version: "1.17.2-distroless", want: "1.17.2"},
+		{name: "should strip build metadata", version: "1.17.2+build.5", want: "1.17.2"},
+		{name: "should strip all of them", version: "v1.17.2-distroless+build.5", want: "1.17.2"},
+		{name: "should keep other pre-releases", version: "1.17.2-solo-fips-distroless", want: "1.17.2-solo-fips"},
+		{name: "should reject an empty version", version: " ", wantErr: "empty input"},
+		{name: "should reject a version without patch", version: "1.17", wantErr: "Invalid istioctl version format for input '1.17'"},
+		{name: "should reject a version constraint", version: "1.17.x", wantErr: "Invalid istioctl version format for input '1.17.x'"},
+		{name: "should reject a non-numeric version", version: "latest", wantErr: "Invalid istioctl version format for input 'latest'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := VersionFromString(tt.version)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, version.String())
+		})
+	}
+}
+
+func Test_VersionFromSource(t *testing.T) {
+	t.Run("should name the source of a version which could not be parsed", func(t *testing.T) {
+		_, err := VersionFromSource("latest", "current Istio version")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not parse current Istio version: Invalid istioctl version format for input 'latest'")
+	})
+}
@@ -1,7 +1,12 @@
 package istioctl_test
 
 import (
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
@@ -80,6 +85,192 @@ func Test_DefaultIstioctlResolver(t *testing.T) {
 	})
 }
 
+func Test_DefaultIstioctlResolver_ResolveVersion(t *testing.T) {
+	vc := mocks.VersionChecker{}
+	vc.On("GetIstioVersion", "/c").Return(istioctl.VersionFromString("1.17.1"))
+	vc.On("GetIstioVersion", "/b").Return(istioctl.VersionFromString("1.17.3"))
+	vc.On("GetIstioVersion", "/a").Return(istioctl.VersionFromString("1.18.0"))
+
+	resolver, err := istioctl.NewDefaultIstioctlResolver([]string{"/a", "/b", "/c"}, &vc)
+	require.NoError(t, err)
+
+	t.Run("should resolve the newest patch matching the constraint", func(t *testing.T) {
+		constraint, err := istioctl.ParseVersionConstraint("1.17.x")
+		require.NoError(t, err)
+
+		version, err := resolver.ResolveVersion(constraint)
+		require.NoError(t, err)
+		require.Equal(t, "1.17.3", version.String())
+	})
+
+	t.Run("should return an error when no binary matches the constraint", func(t *testing.T) {
+		constraint, err := istioctl.ParseVersionConstraint("~1.17.4")
+		require.NoError(t, err)
+
+		_, err = resolver.ResolveVersion(constraint)
+		require.Error(t, err)
+		require.Equal(t, "No 'istioctl' binary found for version constraint: ~1.17.4. Available binaries: 1.17.1, 1.17.3, 1.18.0", err.Error())
+	})
+}
+
+func Test_PlatformIstioctlResolver(t *testing.T) {
+	linuxAmd64 := istioctl.Platform{OS: "linux", Arch: "amd64"}
+	linuxArm64 := istioctl.Platform{OS: "linux", Arch: "arm64"}
+	darwinArm64 := istioctl.Platform{OS: "darwin", Arch: "arm64"}
+
+	t.Run("should select the binary built for the arm64 host platform", func(t *testing.T) {
+		pc := mocks.PlatformChecker{}
+		pc.On("GetPlatform", "/amd64").Return(linuxAmd64, nil)
+		pc.On("GetPlatform", "/arm64").Return(linuxArm64, nil)
+		vc := mocks.VersionChecker{}
+		vc.On("GetIstioVersion", "/arm64").Return(istioctl.VersionFromString("1.11.2"))
+
+		resolver, err := istioctl.NewPlatformIstioctlResolver([]string{"/amd64", "/arm64"}, &vc, &pc, linuxArm64)
+		require.NoError(t, err)
+
+		actualVersion, err := istioctl.VersionFromString("1.11.2")
+		require.NoError(t, err)
+
+		binary, err := resolver.FindIstioctl(actualVersion)
+		require.NoError(t, err)
+		require.Equal(t, "/arm64", binary.Path())
+		vc.AssertNotCalled(t, "GetIstioVersion", "/amd64")
+	})
+
+	t.Run("should select the binary built for the amd64 host platform", func(t *testing.T) {
+		pc := mocks.PlatformChecker{}
+		pc.On("GetPlatform", "/amd64").Return(linuxAmd64, nil)
+		pc.On("GetPlatform", "/arm64").Return(linuxArm64, nil)
+		vc := mocks.VersionChecker{}
+		vc.On("GetIstioVersion", "/amd64").Return(istioctl.VersionFromString("1.11.2"))
+
+		resolver, err := istioctl.NewPlatformIstioctlResolver([]string{"/amd64", "/arm64"}, &vc, &pc, linuxAmd64)
+		require.NoError(t, err)
+
+		actualVersion, err := istioctl.VersionFromString("1.11.2")
+		require.NoError(t, err)
+
+		binary, err := resolver.FindIstioctl(actualVersion)
+		require.NoError(t, err)
+		require.Equal(t, "/amd64", binary.Path())
+		vc.AssertNotCalled(t, "GetIstioVersion", "/arm64")
+	})
+
+	t.Run("should return an error naming the host platform when no binary is built for it", func(t *testing.T) {
+		pc := mocks.PlatformChecker{}
+		pc.On("GetPlatform", "/amd64").Return(linuxAmd64, nil)
+		pc.On("GetPlatform", "/darwin").Return(darwinArm64, nil)
+		vc := mocks.VersionChecker{}
+
+		resolver, err := istioctl.NewPlatformIstioctlResolver([]string{"/amd64", "/darwin"}, &vc, &pc, linuxArm64)
+		require.NoError(t, err)
+
+		actualVersion, err := istioctl.VersionFromString("1.11.2")
+		require.NoError(t, err)
+
+		_, err = resolver.FindIstioctl(actualVersion)
+		require.Error(t, err)
+		require.Equal(t, "No matching 'istioctl' binary found for version: 1.11.2 on platform linux/arm64. Available binaries: . "+
+			"Binaries for other platforms: /amd64 (linux/amd64), /darwin (darwin/arm64)", err.Error())
+	})
+
+	t.Run("should return an error when the platform of a binary could not be determined", func(t *testing.T) {
+		pc := mocks.PlatformChecker{}
+		pc.On("GetPlatform", "/a").Return(istioctl.Platform{}, errors.New("Unsupported executable format"))
+		vc := mocks.VersionChecker{}
+
+		_, err := istioctl.NewPlatformIstioctlResolver([]string{"/a"}, &vc, &pc, linuxArm64)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unsupported executable format")
+	})
+}
+
+func Test_DefaultPlatformChecker(t *testing.T) {
+	t.Run("should return the host platform for the running test binary", func(t *testing.T) {
+		//given
+		pc := istioctl.DefaultPlatformChecker{}
+		path, err := os.Executable()
+		require.NoError(t, err)
+
+		//when
+		platform, err := pc.GetPlatform(path)
+
+		//then
+		require.NoError(t, err)
+		require.Equal(t, istioctl.HostPlatform(), platform)
+	})
+
+	t.Run("should return an error for a file which is not an executable", func(t *testing.T) {
+		//given
+		pc := istioctl.DefaultPlatformChecker{}
+		file, err := ioutil.TempFile("", "istioctl")
+		require.NoError(t, err)
+		defer os.Remove(file.Name())
+		_, err = file.WriteString("#!/bin/sh")
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		//when
+		_, err = pc.GetPlatform(file.Name())
+
+		//then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unsupported executable format")
+	})
+
+	t.Run("should tell the byte orders of ppc64 executables apart", func(t *testing.T) {
+		//given
+		pc := istioctl.DefaultPlatformChecker{}
+		littleEndian := writeELF(t, elf.ELFDATA2LSB, elf.ELFOSABI_NONE, elf.EM_PPC64)
+		bigEndian := writeELF(t, elf.ELFDATA2MSB, elf.ELFOSABI_LINUX, elf.EM_PPC64)
+
+		//when
+		littleEndianPlatform, littleEndianErr := pc.GetPlatform(littleEndian)
+		bigEndianPlatform, bigEndianErr := pc.GetPlatform(bigEndian)
+
+		//then
+		require.NoError(t, littleEndianErr)
+		require.Equal(t, istioctl.Platform{OS: "linux", Arch: "ppc64le"}, littleEndianPlatform)
+		require.NoError(t, bigEndianErr)
+		require.Equal(t, istioctl.Platform{OS: "linux", Arch: "ppc64"}, bigEndianPlatform)
+	})
+
+	t.Run("should return an error for an ELF executable which is not built for linux", func(t *testing.T) {
+		//given
+		pc := istioctl.DefaultPlatformChecker{}
+		path := writeELF(t, elf.ELFDATA2LSB, elf.ELFOSABI_FREEBSD, elf.EM_X86_64)
+
+		//when
+		_, err := pc.GetPlatform(path)
+
+		//then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unsupported OS ABI ELFOSABI_FREEBSD")
+	})
+}
+
+// writeELF writes the header of a 64-bit ELF executable, which is enough for the DefaultPlatformChecker.
+func writeELF(t *testing.T, data elf.Data, osABI elf.OSABI, machine elf.Machine) string {
+	var order binary.ByteOrder = binary.LittleEndian
+	if data == elf.ELFDATA2MSB {
+		order = binary.BigEndian
+	}
+	header := make([]byte, 64)
+	copy(header, elf.ELFMAG)
+	header[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header[elf.EI_DATA] = byte(data)
+	header[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	header[elf.EI_OSABI] = byte(osABI)
+	order.PutUint16(header[16:], uint16(elf.ET_EXEC))
+	order.PutUint16(header[18:], uint16(machine))
+	order.PutUint32(header[20:], uint32(elf.EV_CURRENT))
+	order.PutUint16(header[52:], uint16(len(header)))
+
+	path := filepath.Join(t.TempDir(), "istioctl")
+	require.NoError(t, ioutil.WriteFile(path, header, 0600))
+	return path
+}
+
 func Test_DefaultVersionChecker(t *testing.T) {
 	t.Run("should return istioctl version from actual invocation", func(t *testing.T) {
 		t.Skip("MANUAL TEST!")
@@ -0,0 +1,27 @@
+package istioctl
+
+import (
+	"github.com/coreos/go-semver/semver"
+	"github.com/pkg/errors"
+)
+
+// UpgradePath returns the ordered versions to upgrade through from current to target, as Istio does not support skipping minor versions.
+// Each intermediate minor version is returned with patch 0, the resolver picks the newest available istioctl patch for it. The last step is always the target.
+// Returns an empty path if current equals target and an error for downgrades or major version changes.
+func UpgradePath(current, target Version) ([]Version, error) {
+	if target.EqualTo(current) {
+		return []Version{}, nil
+	}
+	if target.SmallerThan(current) {
+		return nil, errors.Errorf("Could not compute upgrade path from %s to %s: downgrade is not supported", current, target)
+	}
+	if current.value.Major != target.value.Major {
+		return nil, errors.Errorf("Could not compute upgrade path from %s to %s: major version upgrade is not supported", current, target)
+	}
+
+	var path []Version
+	for minor := current.value.Minor + 1; minor < target.value.Minor; minor++ {
+		path = append(path, Version{semver.Version{Major: current.value.Major, Minor: minor}})
+	}
+	return append(path, target), nil
+}
@@ -0,0 +1,79 @@
+package istioctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UpgradePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "should return an empty path for equal versions",
+			current: "1.11.4",
+			target:  "1.11.4",
+			want:    []string{},
+		},
+		{
+			name:    "should upgrade directly to a patch version of the same minor",
+			current: "1.11.2",
+			target:  "1.11.4",
+			want:    []string{"1.11.4"},
+		},
+		{
+			name:    "should upgrade directly to the next minor",
+			current: "1.11.4",
+			target:  "1.12.1",
+			want:    []string{"1.12.1"},
+		},
+		{
+			name:    "should step through all intermediate minors for a multi-minor gap",
+			current: "1.9.5",
+			target:  "1.12.2",
+			want:    []string{"1.10.0", "1.11.0", "1.12.2"},
+		},
+		{
+			name:    "should not compute path for a downgrade",
+			current: "1.12.2",
+			target:  "1.11.4",
+			wantErr: "downgrade is not supported",
+		},
+		{
+			name:    "should not compute path for a major version upgrade",
+			current: "1.12.2",
+			target:  "2.0.0",
+			wantErr: "major version upgrade is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			current, err := VersionFromString(tt.current)
+			require.NoError(t, err)
+			target, err := VersionFromString(tt.target)
+			require.NoError(t, err)
+
+			// when
+			got, err := UpgradePath(current, target)
+
+			// then
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			gotVersions := []string{}
+			for _, version := range got {
+				gotVersions = append(gotVersions, version.String())
+			}
+			require.Equal(t, tt.want, gotVersions)
+		})
+	}
+}
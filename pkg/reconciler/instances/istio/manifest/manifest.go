@@ -2,13 +2,28 @@ package manifest
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const (
-	istioOperatorKind = "IstioOperator"
+	istioOperatorKind       = "IstioOperator"
+	istioOperatorAPIVersion = "install.istio.io/v1alpha1"
+)
+
+var (
+	// istioOperatorStringFields are the fields of the IstioOperator spec which must be strings, if set.
+	istioOperatorStringFields = []string{"profile", "hub", "revision", "namespace", "installPackagePath"}
+	// istioOperatorObjectFields are the fields of the IstioOperator spec which must be objects, if set.
+	istioOperatorObjectFields = []string{"meshConfig", "values", "unvalidatedValues", "components"}
+	// istioOperatorGatewayFields are the fields of the IstioOperator components which must be lists of named gateways, if set.
+	istioOperatorGatewayFields = []string{"ingressGateways", "egressGateways"}
+	// hubPattern matches a registry host, which is localhost, a domain or has a port, followed by an optional repository path.
+	hubPattern = regexp.MustCompile(`^(localhost(:[0-9]+)?|[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+(:[0-9]+)?|[a-zA-Z0-9-]+:[0-9]+)(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
 )
 
 //Returns a manifest with IstioOperator CR excluded. The given manifest must be in YAML format.
@@ -48,6 +63,10 @@ func ExtractIstioOperatorContextFrom(manifest string) (string, error) {
 			continue
 		}
 
+		if err := ValidateIstioOperator(unstruct); err != nil {
+			return "", err
+		}
+
 		unstructBytes, err := unstruct.MarshalJSON()
 		if err != nil {
 			return "", nil
@@ -58,3 +77,150 @@ func ExtractIstioOperatorContextFrom(manifest string) (string, error) {
 
 	return "", errors.New("Istio Operator definition could not be found in manifest")
 }
+
+//Validates the structure of the IstioOperator CR, so templating mistakes are reported before istioctl is called.
+//The returned error points at the first missing or invalid field.
+func ValidateIstioOperator(istioOperator *unstructured.Unstructured) error {
+	if istioOperator.GetKind() != istioOperatorKind {
+		return invalidIstioOperatorField("kind", fmt.Sprintf("must be %s but is %q", istioOperatorKind, istioOperator.GetKind()))
+	}
+	if istioOperator.GetAPIVersion() != istioOperatorAPIVersion {
+		return invalidIstioOperatorField("apiVersion", fmt.Sprintf("must be %s but is %q", istioOperatorAPIVersion, istioOperator.GetAPIVersion()))
+	}
+
+	spec, found := istioOperator.Object["spec"]
+	if !found {
+		return nil
+	}
+	specFields, ok := spec.(map[string]interface{})
+	if !ok {
+		return invalidIstioOperatorField("spec", "must be an object")
+	}
+	for _, field := range istioOperatorStringFields {
+		if value, found := specFields[field]; found {
+			if _, ok := value.(string); !ok {
+				return invalidIstioOperatorField("spec."+field, "must be a string")
+			}
+		}
+	}
+	for _, field := range istioOperatorObjectFields {
+		if value, found := specFields[field]; found {
+			if _, ok := value.(map[string]interface{}); !ok {
+				return invalidIstioOperatorField("spec."+field, "must be an object")
+			}
+		}
+	}
+
+	components, _ := specFields["components"].(map[string]interface{})
+	for _, field := range istioOperatorGatewayFields {
+		if err := validateGateways(components, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateGateways(components map[string]interface{}, field string) error {
+	value, found := components[field]
+	if !found {
+		return nil
+	}
+	path := "spec.components." + field
+	gateways, ok := value.([]interface{})
+	if !ok {
+		return invalidIstioOperatorField(path, "must be a list")
+	}
+	for i, gateway := range gateways {
+		gatewayFields, ok := gateway.(map[string]interface{})
+		if !ok {
+			return invalidIstioOperatorField(fmt.Sprintf("%s[%d]", path, i), "must be an object")
+		}
+		if name, _ := gatewayFields["name"].(string); name == "" {
+			return invalidIstioOperatorField(fmt.Sprintf("%s[%d].name", path, i), "is missing")
+		}
+	}
+	return nil
+}
+
+func invalidIstioOperatorField(path, problem string) error {
+	return fmt.Errorf("Invalid IstioOperator definition: %s %s", path, problem)
+}
+
+//Validates that the hub is a registry host with an optional repository path, e.g. "eu.gcr.io/kyma-project".
+func ValidateHub(hub string) error {
+	if !hubPattern.MatchString(hub) {
+		return fmt.Errorf("Invalid hub %q: must be a registry host with an optional repository path", hub)
+	}
+	return nil
+}
+
+//Returns the IstioOperator CR with the image hub of all Istio components set to the given hub. The given IstioOperator must be in JSON format.
+func SetIstioOperatorHub(istioOperator, hub string) (string, error) {
+	if err := ValidateHub(hub); err != nil {
+		return "", err
+	}
+
+	unstruct := &unstructured.Unstructured{}
+	if err := unstruct.UnmarshalJSON([]byte(istioOperator)); err != nil {
+		return "", err
+	}
+	if err := unstructured.SetNestedField(unstruct.Object, hub, "spec", "hub"); err != nil {
+		return "", invalidIstioOperatorField("spec", "must be an object")
+	}
+
+	unstructBytes, err := unstruct.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(unstructBytes), nil
+}
+
+//Returns the IstioOperator CR with the spec of the overlay merged over its spec. Objects are merged recursively, all other values
+//of the overlay replace the existing ones. The given IstioOperator must be in JSON format, the overlay must be an IstioOperator in YAML or JSON format.
+func MergeIstioOperatorOverlay(istioOperator, overlay string) (string, error) {
+	unstruct := &unstructured.Unstructured{}
+	if err := unstruct.UnmarshalJSON([]byte(istioOperator)); err != nil {
+		return "", err
+	}
+
+	overlayUnstructs, err := kubernetes.ToUnstructured([]byte(overlay), true)
+	if err != nil {
+		return "", err
+	}
+	if len(overlayUnstructs) != 1 {
+		return "", fmt.Errorf("IstioOperator overlay must contain exactly one resource but contains %d", len(overlayUnstructs))
+	}
+	if err := ValidateIstioOperator(overlayUnstructs[0]); err != nil {
+		return "", err
+	}
+
+	overlaySpec, found := overlayUnstructs[0].Object["spec"]
+	if !found {
+		return istioOperator, nil
+	}
+	spec, _ := unstruct.Object["spec"].(map[string]interface{})
+	unstruct.Object["spec"] = mergeValues(spec, overlaySpec.(map[string]interface{}))
+
+	unstructBytes, err := unstruct.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(unstructBytes), nil
+}
+
+func mergeValues(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		baseObject, baseIsObject := merged[key].(map[string]interface{})
+		overlayObject, overlayIsObject := value.(map[string]interface{})
+		if baseIsObject && overlayIsObject {
+			merged[key] = mergeValues(baseObject, overlayObject)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
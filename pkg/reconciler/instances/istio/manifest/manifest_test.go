@@ -3,6 +3,7 @@ package manifest
 import (
 	"testing"
 
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,4 +50,268 @@ func Test_extractIstioOperatorContextFrom(t *testing.T) {
 		require.Contains(t, result, "IstioOperator")
 	})
 
+	t.Run("should not extract an invalid istio operator", func(t *testing.T) {
+		// when
+		result, err := ExtractIstioOperatorContextFrom(`
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  values: "global: {}"
+`)
+
+		// then
+		require.Empty(t, result)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.values must be an object")
+	})
+
+}
+
+func Test_ValidateIstioOperator(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "should accept istio operator without spec",
+			yaml: `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  name: name
+`,
+		},
+		{
+			name: "should accept valid istio operator",
+			yaml: `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  profile: default
+  meshConfig:
+    enableTracing: true
+  components:
+    ingressGateways:
+    - name: istio-ingressgateway
+      enabled: true
+  values:
+    global:
+      proxy:
+        holdApplicationUntilProxyStarts: true
+`,
+		},
+		{
+			name: "should reject unexpected apiVersion",
+			yaml: `
+apiVersion: install.istio.io/v1
+kind: IstioOperator
+`,
+			wantErr: `apiVersion must be install.istio.io/v1alpha1 but is "install.istio.io/v1"`,
+		},
+		{
+			name: "should reject spec which is not an object",
+			yaml: `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec: default
+`,
+			wantErr: "spec must be an object",
+		},
+		{
+			name: "should reject profile which is not a string",
+			yaml: `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  profile:
+    name: default
+`,
+			wantErr: "spec.profile must be a string",
+		},
+		{
+			name: "should reject gateways which are not a list",
+			yaml: `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  components:
+    ingressGateways:
+      name: istio-ingressgateway
+`,
+			wantErr: "spec.components.ingressGateways must be a list",
+		},
+		{
+			name: "should reject gateway without name",
+			yaml: `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  components:
+    egressGateways:
+    - name: istio-egressgateway
+    - enabled: true
+`,
+			wantErr: "spec.components.egressGateways[1].name is missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			unstructs, err := kubernetes.ToUnstructured([]byte(tt.yaml), true)
+			require.NoError(t, err)
+			require.Len(t, unstructs, 1)
+
+			// when
+			err = ValidateIstioOperator(unstructs[0])
+
+			// then
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_ValidateHub(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		hub     string
+		wantErr bool
+	}{
+		{name: "should accept registry domain", hub: "docker.io"},
+		{name: "should accept registry domain with repository path", hub: "eu.gcr.io/kyma-project/external/istio"},
+		{name: "should accept registry with port", hub: "registry.local:5000/istio"},
+		{name: "should accept registry host with port", hub: "registry:5000/istio"},
+		{name: "should accept localhost", hub: "localhost/istio"},
+		{name: "should reject empty hub", hub: "", wantErr: true},
+		{name: "should reject repository without registry host", hub: "istio", wantErr: true},
+		{name: "should reject hub with scheme", hub: "https://registry.local/istio", wantErr: true},
+		{name: "should reject hub with image tag", hub: "registry.local/istio:1.11.4", wantErr: true},
+		{name: "should reject hub with uppercase repository path", hub: "registry.local/Istio", wantErr: true},
+		{name: "should reject hub with trailing slash", hub: "registry.local/istio/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := ValidateHub(tt.hub)
+
+			// then
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "Invalid hub")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_SetIstioOperatorHub(t *testing.T) {
+
+	t.Run("should set hub of istio operator", func(t *testing.T) {
+		// given
+		istioOperator, err := ExtractIstioOperatorContextFrom(istioManifest)
+		require.NoError(t, err)
+
+		// when
+		result, err := SetIstioOperatorHub(istioOperator, "registry.local:5000/istio")
+
+		// then
+		require.NoError(t, err)
+		unstructs, err := kubernetes.ToUnstructured([]byte(result), true)
+		require.NoError(t, err)
+		require.Len(t, unstructs, 1)
+		require.Equal(t, "registry.local:5000/istio", unstructs[0].Object["spec"].(map[string]interface{})["hub"])
+	})
+
+	t.Run("should override hub of istio operator", func(t *testing.T) {
+		// given
+		istioOperator := `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator","spec":{"hub":"docker.io/istio","profile":"default"}}`
+
+		// when
+		result, err := SetIstioOperatorHub(istioOperator, "registry.local/istio")
+
+		// then
+		require.NoError(t, err)
+		require.JSONEq(t, `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator","spec":{"hub":"registry.local/istio","profile":"default"}}`, result)
+	})
+
+	t.Run("should not set invalid hub", func(t *testing.T) {
+		// given
+		istioOperator := `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator"}`
+
+		// when
+		_, err := SetIstioOperatorHub(istioOperator, "https://registry.local")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `Invalid hub "https://registry.local"`)
+	})
+}
+
+func Test_MergeIstioOperatorOverlay(t *testing.T) {
+
+	istioOperator := `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator","spec":{"hub":"docker.io/istio","meshConfig":{"accessLogFile":"/dev/stdout","enableTracing":true},"components":{"ingressGateways":[{"name":"istio-ingressgateway","enabled":true}]}}}`
+
+	t.Run("should merge objects and replace other values of the overlay", func(t *testing.T) {
+		// given
+		overlay := `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  meshConfig:
+    enableTracing: false
+  components:
+    ingressGateways: []
+  values:
+    global:
+      meshID: mesh1
+`
+
+		// when
+		result, err := MergeIstioOperatorOverlay(istioOperator, overlay)
+
+		// then
+		require.NoError(t, err)
+		require.JSONEq(t, `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator","spec":{"hub":"docker.io/istio","meshConfig":{"accessLogFile":"/dev/stdout","enableTracing":false},"components":{"ingressGateways":[]},"values":{"global":{"meshID":"mesh1"}}}}`, result)
+	})
+
+	t.Run("should keep the IstioOperator if the overlay has no spec", func(t *testing.T) {
+		// given
+		overlay := `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator"}`
+
+		// when
+		result, err := MergeIstioOperatorOverlay(istioOperator, overlay)
+
+		// then
+		require.NoError(t, err)
+		require.JSONEq(t, istioOperator, result)
+	})
+
+	t.Run("should not merge an overlay which is not an IstioOperator", func(t *testing.T) {
+		// given
+		overlay := `{"apiVersion":"v1","kind":"ConfigMap"}`
+
+		// when
+		_, err := MergeIstioOperatorOverlay(istioOperator, overlay)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "kind must be IstioOperator")
+	})
+
+	t.Run("should not merge an overlay with multiple resources", func(t *testing.T) {
+		// when
+		_, err := MergeIstioOperatorOverlay(istioOperator, istioManifest)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must contain exactly one resource")
+	})
 }
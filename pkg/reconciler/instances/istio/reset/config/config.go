@@ -8,6 +8,30 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// ResetOrder controls the order in which the pods are passed to the proxy reset.
+type ResetOrder string
+
+const (
+	// UnspecifiedOrder keeps the order in which the pods were gathered from the cluster.
+	UnspecifiedOrder ResetOrder = ""
+	// OldestFirst resets the pods with the oldest creation timestamp first.
+	OldestFirst ResetOrder = "OldestFirst"
+	// NewestFirst resets the pods with the newest creation timestamp first.
+	NewestFirst ResetOrder = "NewestFirst"
+	// ByNamespace groups the pods by namespace, in alphabetical order.
+	ByNamespace ResetOrder = "ByNamespace"
+)
+
+// RetryBackoff controls how the delay between the retries of failed pod operations evolves.
+type RetryBackoff string
+
+const (
+	// LinearBackoff waits DelayBetweenRetries between all retries.
+	LinearBackoff RetryBackoff = ""
+	// ExponentialBackoff doubles the delay with every retry, starting at DelayBetweenRetries, up to MaxDelayBetweenRetries.
+	ExponentialBackoff RetryBackoff = "Exponential"
+)
+
 // IstioProxyConfig stores input information for IstioProxyReset.
 type IstioProxyConfig struct {
 	// Reconcile action context
@@ -25,12 +49,42 @@ type IstioProxyConfig struct {
 	// DelayBetweenRetries in seconds
 	DelayBetweenRetries time.Duration
 
+	// Backoff of the delay between retries
+	Backoff RetryBackoff
+
+	// MaxDelayBetweenRetries caps the exponential backoff. Zero means no cap.
+	MaxDelayBetweenRetries time.Duration
+
+	// Jitter is the upper bound of a random duration added to every delay between retries. Zero disables it.
+	Jitter time.Duration
+
+	// RespectRetryAfter makes the retry of a pod operation throttled by the API server wait at least
+	// for the Retry-After duration suggested by the API server.
+	RespectRetryAfter bool
+
+	// MaxRetryAfter caps the Retry-After duration respected. Zero means no cap.
+	MaxRetryAfter time.Duration
+
 	// Interval for polling ready status after Proxy Reset.
 	Interval time.Duration
 
 	// Timeout for waiting on status after reset
 	Timeout time.Duration
 
+	// Order of the pods to reset
+	Order ResetOrder
+
+	// LabelSelector restricts the reset to the pods matching it. Empty selects all pods.
+	LabelSelector string
+
+	// Deadline for the whole reset. No new pod reset is started after it is exceeded,
+	// resets in progress are finished. Zero means no deadline.
+	Deadline time.Duration
+
+	// RespectPDB makes the reset wait while a PodDisruptionBudget does not allow to restart the pods of a workload,
+	// at most for the Timeout.
+	RespectPDB bool
+
 	// Kubeclient for k8s cluster operations
 	Kubeclient kubernetes.Interface
 
@@ -16,6 +16,9 @@ type Gatherer interface {
 	// GetAllPods from the cluster and return them as a v1.PodList.
 	GetAllPods(kubeClient kubernetes.Interface, retryOpts []retry.Option) (podsList *v1.PodList, err error)
 
+	// GetPodsBySelector from the cluster matching the labelSelector and return them as a v1.PodList.
+	GetPodsBySelector(kubeClient kubernetes.Interface, labelSelector string, retryOpts []retry.Option) (podsList *v1.PodList, err error)
+
 	// GetPodsWithDifferentImage than the passed expected image to filter them out from the pods list.
 	GetPodsWithDifferentImage(inputPodsList v1.PodList, image ExpectedImage) (outputPodsList v1.PodList)
 }
@@ -35,8 +38,12 @@ func NewDefaultGatherer() *DefaultGatherer {
 }
 
 func (i *DefaultGatherer) GetAllPods(kubeClient kubernetes.Interface, retryOpts []retry.Option) (podsList *v1.PodList, err error) {
+	return i.GetPodsBySelector(kubeClient, "", retryOpts)
+}
+
+func (i *DefaultGatherer) GetPodsBySelector(kubeClient kubernetes.Interface, labelSelector string, retryOpts []retry.Option) (podsList *v1.PodList, err error) {
 	err = retry.Do(func() error {
-		podsList, err = kubeClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+		podsList, err = kubeClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
 		if err != nil {
 			return err
 		}
@@ -43,6 +43,28 @@ func Test_Gatherer_GetAllPods(t *testing.T) {
 	})
 }
 
+func Test_Gatherer_GetPodsBySelector(t *testing.T) {
+	firstPod := fixPodWith("application", "kyma", "istio/proxyv2:1.10.1", "Running")
+	firstPod.Labels = map[string]string{"app": "payment"}
+	secondPod := fixPodWith("istio", "custom", "istio/proxyv2:1.10.2", "Running")
+	secondPod.Labels = map[string]string{"app": "orders"}
+	retryOpts := getTestingRetryOptions()
+
+	t.Run("should get only the pods matching the label selector", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(firstPod, secondPod)
+		gatherer := DefaultGatherer{}
+
+		// when
+		pods, err := gatherer.GetPodsBySelector(kubeClient, "app=payment", retryOpts)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 1)
+		require.Equal(t, "application", pods.Items[0].Name)
+	})
+}
+
 func Test_Gatherer_GetPodsWithDifferentImage(t *testing.T) {
 	image := ExpectedImage{
 		Prefix:  "istio/proxyv2",
@@ -41,6 +41,29 @@ func (_m *Gatherer) GetAllPods(kubeClient kubernetes.Interface, retryOpts []retr
 	return r0, r1
 }
 
+// GetPodsBySelector provides a mock function with given fields: kubeClient, labelSelector, retryOpts
+func (_m *Gatherer) GetPodsBySelector(kubeClient kubernetes.Interface, labelSelector string, retryOpts []retry.Option) (*v1.PodList, error) {
+	ret := _m.Called(kubeClient, labelSelector, retryOpts)
+
+	var r0 *v1.PodList
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, string, []retry.Option) *v1.PodList); ok {
+		r0 = rf(kubeClient, labelSelector, retryOpts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.PodList)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(kubernetes.Interface, string, []retry.Option) error); ok {
+		r1 = rf(kubeClient, labelSelector, retryOpts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPodsWithDifferentImage provides a mock function with given fields: inputPodsList, image
 func (_m *Gatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, image data.ExpectedImage) v1.PodList {
 	ret := _m.Called(inputPodsList, image)
@@ -0,0 +1,225 @@
+package pod
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultDisruptionInterval is used to poll the PodDisruptionBudgets if no wait interval is configured.
+const defaultDisruptionInterval = 5 * time.Second
+
+// disruptionBudget is the part of a policy/v1 or policy/v1beta1 PodDisruptionBudget which decides whether a disruption is allowed.
+type disruptionBudget struct {
+	name               string
+	selector           *metav1.LabelSelector
+	disruptionsAllowed int32
+}
+
+// evictPod evicts the pod through the Eviction API, which refuses evictions violating a PodDisruptionBudget.
+// Refused evictions are retried until the wait timeout is exceeded.
+func evictPod(ctx context.Context, kubeClient kubernetes.Interface, object CustomObject, waitOpts WaitOptions, log *zap.SugaredLogger) error {
+	evict := evictFunc(ctx, kubeClient, object, log)
+	var lastErr error
+	err := pollDisruption(ctx, waitOpts, func() (bool, error) {
+		lastErr = evict()
+		if kerrors.IsTooManyRequests(lastErr) {
+			log.Debugf("Eviction of pod %s/%s blocked by a PodDisruptionBudget, waiting: %s", object.Namespace, object.Name, lastErr)
+			return false, nil
+		}
+		if kerrors.IsNotFound(lastErr) {
+			return true, nil
+		}
+		return lastErr == nil, lastErr
+	})
+	if err == wait.ErrWaitTimeout {
+		return errors.Wrapf(lastErr, "Eviction of pod %s/%s was blocked by a PodDisruptionBudget for %s", object.Namespace, object.Name, waitOpts.Timeout)
+	}
+	return err
+}
+
+// waitForDisruptionAllowed waits until all PodDisruptionBudgets selecting the pods of the workload allow a disruption, or the wait timeout is exceeded.
+func waitForDisruptionAllowed(ctx context.Context, kubeClient kubernetes.Interface, object CustomObject, waitOpts WaitOptions, log *zap.SugaredLogger) error {
+	podLabels, err := podTemplateLabels(ctx, kubeClient, object)
+	if err != nil {
+		return err
+	}
+
+	list := listDisruptionBudgetsFunc(ctx, kubeClient, object.Namespace, log)
+	var blocking string
+	err = pollDisruption(ctx, waitOpts, func() (bool, error) {
+		pdbs, err := list()
+		if err != nil {
+			return false, err
+		}
+		blocking = blockingDisruptionBudget(pdbs, podLabels)
+		if blocking != "" {
+			log.Debugf("Rollout of %s %s/%s blocked by PodDisruptionBudget %s, waiting", object.Kind, object.Namespace, object.Name, blocking)
+		}
+		return blocking == "", nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("PodDisruptionBudget %s/%s did not allow the rollout of %s %s/%s within %s", object.Namespace, blocking, object.Kind, object.Namespace, object.Name, waitOpts.Timeout)
+	}
+	return err
+}
+
+// blockingDisruptionBudget returns the name of the first PodDisruptionBudget selecting the pod labels which allows no disruption.
+func blockingDisruptionBudget(pdbs []disruptionBudget, podLabels map[string]string) string {
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.selector)
+		// an empty selector matches all pods, but PodDisruptionBudgets without selector match none
+		if err != nil || pdb.selector == nil || !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+		if pdb.disruptionsAllowed < 1 {
+			return pdb.name
+		}
+	}
+	return ""
+}
+
+// evictFunc returns the eviction of the pod in the newest policy version served by the cluster.
+// policy/v1 Evictions are served since Kubernetes 1.22, older clusters only serve policy/v1beta1.
+func evictFunc(ctx context.Context, kubeClient kubernetes.Interface, object CustomObject, log *zap.SugaredLogger) func() error {
+	meta := metav1.ObjectMeta{Name: object.Name, Namespace: object.Namespace}
+	if evictionV1Supported(kubeClient, log) {
+		return func() error {
+			return kubeClient.PolicyV1().Evictions(object.Namespace).Evict(ctx, &policyv1.Eviction{ObjectMeta: meta})
+		}
+	}
+	return func() error {
+		return kubeClient.PolicyV1beta1().Evictions(object.Namespace).Evict(ctx, &policyv1beta1.Eviction{ObjectMeta: meta})
+	}
+}
+
+// listDisruptionBudgetsFunc returns the listing of the PodDisruptionBudgets in the newest policy version served by the cluster.
+// policy/v1 PodDisruptionBudgets are served since Kubernetes 1.21, older clusters only serve policy/v1beta1.
+func listDisruptionBudgetsFunc(ctx context.Context, kubeClient kubernetes.Interface, namespace string, log *zap.SugaredLogger) func() ([]disruptionBudget, error) {
+	if disruptionBudgetV1Supported(kubeClient, log) {
+		return func() ([]disruptionBudget, error) {
+			pdbs, err := kubeClient.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			var budgets []disruptionBudget
+			for _, pdb := range pdbs.Items {
+				budgets = append(budgets, disruptionBudget{name: pdb.Name, selector: pdb.Spec.Selector, disruptionsAllowed: pdb.Status.DisruptionsAllowed})
+			}
+			return budgets, nil
+		}
+	}
+	return func() ([]disruptionBudget, error) {
+		pdbs, err := kubeClient.PolicyV1beta1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var budgets []disruptionBudget
+		for _, pdb := range pdbs.Items {
+			budgets = append(budgets, disruptionBudget{name: pdb.Name, selector: pdb.Spec.Selector, disruptionsAllowed: pdb.Status.DisruptionsAllowed})
+		}
+		return budgets, nil
+	}
+}
+
+// evictionV1Supported reports whether the eviction subresource of pods accepts policy/v1 Evictions, like `kubectl drain` detects it.
+func evictionV1Supported(kubeClient kubernetes.Interface, log *zap.SugaredLogger) bool {
+	resources, err := kubeClient.Discovery().ServerResourcesForGroupVersion("v1")
+	if err != nil {
+		log.Debugf("Could not discover the Eviction version, using %s: %s", policyv1beta1.SchemeGroupVersion, err)
+		return false
+	}
+	if resources == nil {
+		return false
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "pods/eviction" && resource.Kind == "Eviction" {
+			return resource.Group == policyv1.GroupName && resource.Version == policyv1.SchemeGroupVersion.Version
+		}
+	}
+	return false
+}
+
+// disruptionBudgetV1Supported reports whether the cluster serves policy/v1 PodDisruptionBudgets.
+func disruptionBudgetV1Supported(kubeClient kubernetes.Interface, log *zap.SugaredLogger) bool {
+	resources, err := kubeClient.Discovery().ServerResourcesForGroupVersion(policyv1.SchemeGroupVersion.String())
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			log.Debugf("Could not discover the PodDisruptionBudget version, using %s: %s", policyv1beta1.SchemeGroupVersion, err)
+		}
+		return false
+	}
+	if resources == nil {
+		return false
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "poddisruptionbudgets" {
+			return true
+		}
+	}
+	return false
+}
+
+func podTemplateLabels(ctx context.Context, kubeClient kubernetes.Interface, object CustomObject) (map[string]string, error) {
+	switch object.Kind {
+	case "DaemonSet":
+		daemonSet, err := kubeClient.AppsV1().DaemonSets(object.Namespace).Get(ctx, object.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return daemonSet.Spec.Template.Labels, nil
+	case "Deployment":
+		deployment, err := kubeClient.AppsV1().Deployments(object.Namespace).Get(ctx, object.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return deployment.Spec.Template.Labels, nil
+	case "ReplicaSet":
+		replicaSet, err := kubeClient.AppsV1().ReplicaSets(object.Namespace).Get(ctx, object.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return replicaSet.Spec.Template.Labels, nil
+	case "StatefulSet":
+		statefulSet, err := kubeClient.AppsV1().StatefulSets(object.Namespace).Get(ctx, object.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return statefulSet.Spec.Template.Labels, nil
+	default:
+		return nil, fmt.Errorf("kind %s not found", object.Kind)
+	}
+}
+
+func pollDisruption(ctx context.Context, waitOpts WaitOptions, condition wait.ConditionFunc) error {
+	interval := waitOpts.Interval
+	if interval <= 0 {
+		interval = defaultDisruptionInterval
+	}
+	return wait.PollImmediateUntil(interval, condition, timeoutChannel(ctx, waitOpts.Timeout))
+}
+
+// timeoutChannel is closed when the context is done or the timeout is exceeded.
+func timeoutChannel(ctx context.Context, timeout time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}()
+	return done
+}
@@ -0,0 +1,256 @@
+package pod
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_DeleteObjectHandler_RespectPDB(t *testing.T) {
+	waitOpts := WaitOptions{Interval: 10 * time.Millisecond, Timeout: time.Second, RespectPDB: true}
+
+	t.Run("should evict the pod once the PodDisruptionBudget allows it", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset()
+		evictions := blockEvictions(kubeClient, 3)
+		handler := DeleteObjectHandler{handlerCfg{kubeClient: kubeClient, log: log.NewLogger(true), waitOpts: waitOpts}}
+
+		// when
+		err := handler.ExecuteAndWaitFor(context.Background(), *customObject)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 4, *evictions)
+		require.Empty(t, deletedPods(kubeClient))
+	})
+
+	t.Run("should return an error when the PodDisruptionBudget blocks the eviction longer than the timeout", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset()
+		blockEvictions(kubeClient, 1000)
+		timeoutOpts := waitOpts
+		timeoutOpts.Timeout = 50 * time.Millisecond
+		handler := DeleteObjectHandler{handlerCfg{kubeClient: kubeClient, log: log.NewLogger(true), waitOpts: timeoutOpts}}
+
+		// when
+		err := handler.ExecuteAndWaitFor(context.Background(), *customObject)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Eviction of pod testNamespace/testObject was blocked by a PodDisruptionBudget")
+		require.True(t, kerrors.IsTooManyRequests(errors.Cause(err)))
+	})
+
+	t.Run("should evict the pod with a policy/v1 Eviction when the cluster serves it", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset()
+		kubeClient.Resources = []*metav1.APIResourceList{
+			{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods/eviction", Group: "policy", Version: "v1", Kind: "Eviction"}}},
+		}
+		var evicted runtime.Object
+		kubeClient.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			evicted = action.(k8stesting.CreateAction).GetObject()
+			return true, nil, nil
+		})
+		handler := DeleteObjectHandler{handlerCfg{kubeClient: kubeClient, log: log.NewLogger(true), waitOpts: waitOpts}}
+
+		// when
+		err := handler.ExecuteAndWaitFor(context.Background(), *customObject)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, &policyv1.Eviction{}, evicted)
+	})
+
+	t.Run("should evict the pod with a policy/v1beta1 Eviction when the cluster does not serve policy/v1", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset()
+		var evicted runtime.Object
+		kubeClient.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			evicted = action.(k8stesting.CreateAction).GetObject()
+			return true, nil, nil
+		})
+		handler := DeleteObjectHandler{handlerCfg{kubeClient: kubeClient, log: log.NewLogger(true), waitOpts: waitOpts}}
+
+		// when
+		err := handler.ExecuteAndWaitFor(context.Background(), *customObject)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, &policyv1beta1.Eviction{}, evicted)
+	})
+
+	t.Run("should delete the pod without eviction when RespectPDB is disabled", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: customObject.Name, Namespace: customObject.Namespace}})
+		evictions := blockEvictions(kubeClient, 1000)
+		handler := DeleteObjectHandler{handlerCfg{kubeClient: kubeClient, log: log.NewLogger(true), waitOpts: WaitOptions{}}}
+
+		// when
+		err := handler.ExecuteAndWaitFor(context.Background(), *customObject)
+
+		// then
+		require.NoError(t, err)
+		require.Zero(t, *evictions)
+		require.Equal(t, []string{customObject.Name}, deletedPods(kubeClient))
+	})
+}
+
+func Test_RolloutHandler_RespectPDB(t *testing.T) {
+	waitOpts := WaitOptions{Interval: 10 * time.Millisecond, Timeout: time.Second, RespectPDB: true}
+
+	t.Run("should wait until the PodDisruptionBudget allows a disruption", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset(fixReplicaSet(customObject), fixPodDisruptionBudget("blocking", map[string]string{"app": "test"}, 0))
+		lists := allowDisruptionsAfter(kubeClient, 3)
+
+		// when
+		err := waitForDisruptionAllowed(context.Background(), kubeClient, *customObject, waitOpts, log.NewLogger(true))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 3, *lists)
+	})
+
+	t.Run("should not roll out when the PodDisruptionBudget blocks longer than the timeout", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset(fixReplicaSet(customObject), fixPodDisruptionBudget("blocking", map[string]string{"app": "test"}, 0))
+		timeoutOpts := waitOpts
+		timeoutOpts.Timeout = 50 * time.Millisecond
+		handler := RolloutHandler{handlerCfg{kubeClient: kubeClient, log: log.NewLogger(true), waitOpts: timeoutOpts}}
+
+		// when
+		err := handler.ExecuteAndWaitFor(context.Background(), *customObject)
+
+		// then
+		require.EqualError(t, err, "PodDisruptionBudget testNamespace/blocking did not allow the rollout of ReplicaSet testNamespace/testObject within 50ms")
+		for _, action := range kubeClient.Actions() {
+			require.NotEqual(t, "patch", action.GetVerb())
+		}
+	})
+
+	t.Run("should wait for policy/v1 PodDisruptionBudgets when the cluster serves them", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset(fixReplicaSet(customObject), fixPodDisruptionBudgetV1("blocking", map[string]string{"app": "test"}, 0))
+		kubeClient.Resources = []*metav1.APIResourceList{
+			{GroupVersion: "policy/v1", APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}}},
+		}
+		timeoutOpts := waitOpts
+		timeoutOpts.Timeout = 50 * time.Millisecond
+
+		// when
+		err := waitForDisruptionAllowed(context.Background(), kubeClient, *customObject, timeoutOpts, log.NewLogger(true))
+
+		// then
+		require.EqualError(t, err, "PodDisruptionBudget testNamespace/blocking did not allow the rollout of ReplicaSet testNamespace/testObject within 50ms")
+	})
+
+	t.Run("should ignore PodDisruptionBudgets not selecting the pods of the workload", func(t *testing.T) {
+		// given
+		customObject := fixCustomObject()
+		kubeClient := fake.NewSimpleClientset(
+			fixReplicaSet(customObject),
+			fixPodDisruptionBudget("other", map[string]string{"app": "other"}, 0),
+			fixPodDisruptionBudget("allowing", map[string]string{"app": "test"}, 1),
+		)
+
+		// when
+		err := waitForDisruptionAllowed(context.Background(), kubeClient, *customObject, waitOpts, log.NewLogger(true))
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+// blockEvictions rejects the first blocked evictions like the API server does when a PodDisruptionBudget is violated and returns the number of evictions.
+func blockEvictions(kubeClient *fake.Clientset, blocked int) *int {
+	evictions := 0
+	kubeClient.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		evictions++
+		if evictions <= blocked {
+			return true, nil, kerrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		return true, nil, nil
+	})
+	return &evictions
+}
+
+// allowDisruptionsAfter lets the PodDisruptionBudgets allow a disruption from the given list call on and returns the number of list calls.
+func allowDisruptionsAfter(kubeClient *fake.Clientset, allowedFrom int) *int {
+	lists := 0
+	kubeClient.PrependReactor("list", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		if lists < allowedFrom {
+			return false, nil, nil
+		}
+		pdbs, err := kubeClient.Tracker().List(policyv1beta1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		list := pdbs.(*policyv1beta1.PodDisruptionBudgetList)
+		for i := range list.Items {
+			list.Items[i].Status.DisruptionsAllowed = 1
+		}
+		return true, list, nil
+	})
+	return &lists
+}
+
+func deletedPods(kubeClient *fake.Clientset) []string {
+	var deleted []string
+	for _, action := range kubeClient.Actions() {
+		if deleteAction, ok := action.(k8stesting.DeleteAction); ok && action.GetResource().Resource == "pods" {
+			deleted = append(deleted, deleteAction.GetName())
+		}
+	}
+	return deleted
+}
+
+func fixReplicaSet(object *CustomObject) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: object.Name, Namespace: object.Namespace},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}}},
+		},
+	}
+}
+
+func fixPodDisruptionBudget(name string, matchLabels map[string]string, disruptionsAllowed int32) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testNamespace"},
+		Spec:       policyv1beta1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: matchLabels}},
+		Status:     policyv1beta1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+func fixPodDisruptionBudgetV1(name string, matchLabels map[string]string, disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testNamespace"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: matchLabels}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
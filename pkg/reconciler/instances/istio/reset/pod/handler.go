@@ -22,9 +22,29 @@ type Handler interface {
 	ExecuteAndWaitFor(context.Context, CustomObject) error
 }
 
+// DefaultResetConcurrency is the number of objects reset in parallel if WaitOptions.Deadline is set but WaitOptions.Concurrency is not.
+const DefaultResetConcurrency = 10
+
 type WaitOptions struct {
 	Interval time.Duration
 	Timeout  time.Duration
+	// Deadline after which no new reset is started. The zero value means no deadline.
+	Deadline time.Time
+	// Concurrency is the number of objects reset in parallel, so the Deadline is checked before each further reset.
+	// Zero or less resets all objects in parallel, or uses DefaultResetConcurrency if a Deadline is set.
+	Concurrency int
+	// RespectPDB evicts pods and delays rollouts while a PodDisruptionBudget allows no disruption, at most for the Timeout.
+	RespectPDB bool
+	// Progress skips the objects reset by a previous run and records the objects reset by this one. Nil disables it.
+	Progress Progress
+}
+
+// Progress tracks the objects reset across runs, so a retried reset resumes instead of restarting all objects again.
+type Progress interface {
+	// IsDone returns true if the object was reset by a previous run.
+	IsDone(object CustomObject) bool
+	// Done records that the object was reset successfully.
+	Done(object CustomObject) error
 }
 
 type handlerCfg struct {
@@ -71,6 +91,16 @@ func (i *DeleteObjectHandler) ExecuteAndWaitFor(context context.Context, object
 		return nil
 	}
 
+	if i.waitOpts.RespectPDB {
+		err := evictPod(context, i.kubeClient, object, i.waitOpts, i.log)
+		if err != nil {
+			return err
+		}
+
+		i.log.Debugf("Evicted pod %s/%s", object.Namespace, object.Name)
+		return nil
+	}
+
 	err := retry.Do(func() error {
 		err := i.kubeClient.CoreV1().Pods(object.Namespace).Delete(context, object.Name, metav1.DeleteOptions{})
 		if err != nil {
@@ -101,6 +131,13 @@ func (i *RolloutHandler) ExecuteAndWaitFor(context context.Context, object Custo
 		return nil
 	}
 
+	if i.waitOpts.RespectPDB {
+		err := waitForDisruptionAllowed(context, i.kubeClient, object, i.waitOpts, i.log)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := retry.Do(func() error {
 		err := doRollout(context, object, i.kubeClient)
 		if err != nil {
@@ -14,8 +14,14 @@ import (
 //go:generate mockery --name=Matcher --outpkg=mocks --case=underscore
 // Matcher of Pod to the Handler.
 type Matcher interface {
-	// GetHandlersMap by given pods list.
-	GetHandlersMap(kubeClient kubernetes.Interface, retryOpts []retry.Option, podsList v1.PodList, log *zap.SugaredLogger, debug bool, waitOpts WaitOptions) map[Handler][]CustomObject
+	// GetHandlersMap returns the objects to reset for the given pods list with their Handler, in the order of the first pod of each object.
+	GetHandlersMap(kubeClient kubernetes.Interface, retryOpts []retry.Option, podsList v1.PodList, log *zap.SugaredLogger, debug bool, waitOpts WaitOptions) []HandledObject
+}
+
+// HandledObject is an object to reset together with the Handler resetting it.
+type HandledObject struct {
+	Handler Handler
+	Object  CustomObject
 }
 
 // ParentKindMatcher matches Pod to the Handler by the parent kind.
@@ -26,9 +32,17 @@ func NewParentKindMatcher() *ParentKindMatcher {
 	return &ParentKindMatcher{}
 }
 
-func (m *ParentKindMatcher) GetHandlersMap(kubeClient kubernetes.Interface, retryOpts []retry.Option, podsList v1.PodList, log *zap.SugaredLogger, debug bool, waitOpts WaitOptions) map[Handler][]CustomObject {
-	handlersMap := make(map[Handler][]CustomObject)
-	replicaSets := make(map[CustomObject][]CustomObject)
+func (m *ParentKindMatcher) GetHandlersMap(kubeClient kubernetes.Interface, retryOpts []retry.Option, podsList v1.PodList, log *zap.SugaredLogger, debug bool, waitOpts WaitOptions) []HandledObject {
+	var handledObjects []HandledObject
+	added := make(map[HandledObject]bool)
+	add := func(handler Handler, object CustomObject) {
+		handledObject := HandledObject{Handler: handler, Object: object}
+		if !added[handledObject] {
+			added[handledObject] = true
+			handledObjects = append(handledObjects, handledObject)
+		}
+	}
+	replicaSetParents := make(map[CustomObject]CustomObject)
 
 	handlerCfg := handlerCfg{
 		kubeClient: kubeClient,
@@ -48,63 +62,41 @@ func (m *ParentKindMatcher) GetHandlersMap(kubeClient kubernetes.Interface, retr
 
 		switch parentObject.Kind {
 		case "":
-			handlersMap[noActionHandler] = append(handlersMap[noActionHandler], podObject)
+			add(noActionHandler, podObject)
 		case "ReplicaSet":
-			// ReplicaSets require further processing
-			object := CustomObject{Name: parentObject.Name, Namespace: pod.Namespace, Kind: parentObject.Kind}
-			replicaSets[object] = append(replicaSets[object], podObject)
+			// pods of ReplicaSets without parent are deleted, otherwise the parent is rolled out
+			replicaSet := CustomObject{Name: parentObject.Name, Namespace: pod.Namespace, Kind: parentObject.Kind}
+			parent, found := replicaSetParents[replicaSet]
+			if !found {
+				parent = getReplicaSetParent(handlerCfg, replicaSet)
+				replicaSetParents[replicaSet] = parent
+			}
+			if parent.Name == "" {
+				add(deleteObjectHandler, podObject)
+			} else {
+				add(rolloutHandler, parent)
+			}
 		case "ReplicationController":
-			handlersMap[deleteObjectHandler] = append(handlersMap[deleteObjectHandler], podObject)
+			add(deleteObjectHandler, podObject)
 		default:
-			object := CustomObject{Name: parentObject.Name, Namespace: pod.Namespace, Kind: parentObject.Kind}
-			handlersMap[rolloutHandler] = appendUniqueObject(handlersMap[rolloutHandler], object)
+			add(rolloutHandler, CustomObject{Name: parentObject.Name, Namespace: pod.Namespace, Kind: parentObject.Kind})
 		}
 	}
 
-	podsToDelete, parentsToRollout := checkReplicaSets(handlerCfg, replicaSets)
-	for _, podToDelete := range podsToDelete {
-		handlersMap[deleteObjectHandler] = appendUniqueObject(handlersMap[deleteObjectHandler], podToDelete)
-	}
-	for _, parentToRollout := range parentsToRollout {
-		handlersMap[rolloutHandler] = appendUniqueObject(handlersMap[rolloutHandler], parentToRollout)
-	}
-
-	return handlersMap
+	return handledObjects
 }
 
-func appendUniqueObject(s []CustomObject, object CustomObject) []CustomObject {
-	if !contains(s, object) {
-		s = append(s, object)
+// getReplicaSetParent returns the parent of the ReplicaSet, which is empty if the ReplicaSet has no parent or could not be read.
+func getReplicaSetParent(handlerCfg handlerCfg, replicaSet CustomObject) CustomObject {
+	replicaSetObject, err := handlerCfg.kubeClient.AppsV1().ReplicaSets(replicaSet.Namespace).Get(context.Background(), replicaSet.Name, metav1.GetOptions{})
+	if err != nil {
+		handlerCfg.log.Error(err)
+		return CustomObject{}
 	}
 
-	return s
-}
-
-func contains(customObjects []CustomObject, object CustomObject) bool {
-	for _, customObject := range customObjects {
-		if customObject == object {
-			return true
-		}
+	replicaSetParentObject := getParentObjectFromOwnerReferences(replicaSetObject.OwnerReferences)
+	if replicaSetParentObject.Name == "" {
+		return CustomObject{}
 	}
-	return false
-}
-
-func checkReplicaSets(handlerCfg handlerCfg, replicaSets map[CustomObject][]CustomObject) (podsToDelete, parentsToRollout []CustomObject) {
-	for replicaSet, podObjects := range replicaSets {
-		replicaSet, err := handlerCfg.kubeClient.AppsV1().ReplicaSets(replicaSet.Namespace).Get(context.Background(), replicaSet.Name, metav1.GetOptions{})
-		if err != nil {
-			handlerCfg.log.Error(err)
-		}
-
-		replicaSetParentObject := getParentObjectFromOwnerReferences(replicaSet.OwnerReferences)
-		switch replicaSetParentObject.Name {
-		case "":
-			podsToDelete = append(podsToDelete, podObjects...)
-		default:
-			object := CustomObject{Name: replicaSetParentObject.Name, Namespace: replicaSet.Namespace, Kind: replicaSetParentObject.Kind}
-			parentsToRollout = append(parentsToRollout, object)
-		}
-	}
-
-	return
+	return CustomObject{Name: replicaSetParentObject.Name, Namespace: replicaSetObject.Namespace, Kind: replicaSetParentObject.Kind}
 }
@@ -36,13 +36,13 @@ func Test_ParentKindMatcher_Match(t *testing.T) {
 		matcher := ParentKindMatcher{}
 
 		// when
-		handlersMap := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
 
 		// then
-		require.NotNil(t, handlersMap)
-		require.Len(t, handlersMap, 1)
-		for k := range handlersMap {
-			require.Contains(t, reflect.TypeOf(k).String(), "NoActionHandler")
+		require.NotNil(t, handledObjects)
+		require.Len(t, handledObjects, 1)
+		for _, handledObject := range handledObjects {
+			require.Contains(t, reflect.TypeOf(handledObject.Handler).String(), "NoActionHandler")
 		}
 	})
 
@@ -53,13 +53,13 @@ func Test_ParentKindMatcher_Match(t *testing.T) {
 		matcher := ParentKindMatcher{}
 
 		// when
-		handlersMap := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
 
 		// then
-		require.NotNil(t, handlersMap)
-		require.Len(t, handlersMap, 1)
-		for k := range handlersMap {
-			require.Contains(t, reflect.TypeOf(k).String(), "RolloutHandler")
+		require.NotNil(t, handledObjects)
+		require.Len(t, handledObjects, 1)
+		for _, handledObject := range handledObjects {
+			require.Contains(t, reflect.TypeOf(handledObject.Handler).String(), "RolloutHandler")
 		}
 	})
 
@@ -78,13 +78,13 @@ func Test_ParentKindMatcher_Match(t *testing.T) {
 		matcher := ParentKindMatcher{}
 
 		// when
-		handlersMap := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
 
 		// then
-		require.NotNil(t, handlersMap)
-		require.Len(t, handlersMap, 1)
-		for k := range handlersMap {
-			require.Contains(t, reflect.TypeOf(k).String(), "RolloutHandler")
+		require.NotNil(t, handledObjects)
+		require.Len(t, handledObjects, 1)
+		for _, handledObject := range handledObjects {
+			require.Contains(t, reflect.TypeOf(handledObject.Handler).String(), "RolloutHandler")
 		}
 	})
 
@@ -101,13 +101,13 @@ func Test_ParentKindMatcher_Match(t *testing.T) {
 		matcher := ParentKindMatcher{}
 
 		// when
-		handlersMap := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
 
 		// then
-		require.NotNil(t, handlersMap)
-		require.Len(t, handlersMap, 1)
-		for k := range handlersMap {
-			require.Contains(t, reflect.TypeOf(k).String(), "DeleteObjectHandler")
+		require.NotNil(t, handledObjects)
+		require.Len(t, handledObjects, 1)
+		for _, handledObject := range handledObjects {
+			require.Contains(t, reflect.TypeOf(handledObject.Handler).String(), "DeleteObjectHandler")
 		}
 	})
 
@@ -118,13 +118,13 @@ func Test_ParentKindMatcher_Match(t *testing.T) {
 		matcher := ParentKindMatcher{}
 
 		// when
-		handlersMap := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
 
 		// then
-		require.NotNil(t, handlersMap)
-		require.Len(t, handlersMap, 1)
-		for k := range handlersMap {
-			require.Contains(t, reflect.TypeOf(k).String(), "DeleteObjectHandler")
+		require.NotNil(t, handledObjects)
+		require.Len(t, handledObjects, 1)
+		for _, handledObject := range handledObjects {
+			require.Contains(t, reflect.TypeOf(handledObject.Handler).String(), "DeleteObjectHandler")
 		}
 	})
 
@@ -144,14 +144,14 @@ func Test_ParentKindMatcher_Match(t *testing.T) {
 		matcher := ParentKindMatcher{}
 
 		// when
-		handlersMap := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
 
 		// then
 		require.Len(t, podList.Items, 2)
-		require.NotNil(t, handlersMap)
-		require.Len(t, handlersMap, 1)
-		for k := range handlersMap {
-			require.Contains(t, reflect.TypeOf(k).String(), "RolloutHandler")
+		require.NotNil(t, handledObjects)
+		require.Len(t, handledObjects, 1)
+		for _, handledObject := range handledObjects {
+			require.Contains(t, reflect.TypeOf(handledObject.Handler).String(), "RolloutHandler")
 		}
 	})
 
@@ -183,17 +183,66 @@ func Test_ParentKindMatcher_Match(t *testing.T) {
 		matcher := ParentKindMatcher{}
 
 		// when
-		handlersMap := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
 
 		// then
 		require.Len(t, podList.Items, 2)
-		require.NotNil(t, handlersMap)
-		require.Len(t, handlersMap, 1)
-		for k, v := range handlersMap {
-			require.Contains(t, reflect.TypeOf(k).String(), "RolloutHandler")
-			require.Len(t, v, 2)
+		require.NotNil(t, handledObjects)
+		require.Len(t, handledObjects, 2)
+		for _, handledObject := range handledObjects {
+			require.Contains(t, reflect.TypeOf(handledObject.Handler).String(), "RolloutHandler")
 		}
+		require.Equal(t, "name2", handledObjects[0].Object.Name)
+		require.Equal(t, "name", handledObjects[1].Object.Name)
 	})
+
+	t.Run("should return the objects in the order of their first pod", func(t *testing.T) {
+		// given
+		podList := &v1.PodList{Items: []v1.Pod{
+			fixPod("pod-1", "Deployment", "deployment-b"),
+			fixPod("pod-2", "ReplicaSet", "replicaset-a"),
+			fixPod("pod-3", "ReplicationController", "controller"),
+			fixPod("pod-4", "Deployment", "deployment-b"),
+			fixPod("pod-5", "ReplicaSet", "replicaset-a"),
+			fixPod("pod-6", "StatefulSet", "statefulset"),
+		}}
+		replicaSet := v1apps.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Name: "deployment-a", Kind: "Deployment"}},
+				Name:            "replicaset-a",
+				Namespace:       "namespace",
+			}}
+		kubeClient := fake.NewSimpleClientset(&replicaSet)
+		matcher := ParentKindMatcher{}
+
+		// when
+		handledObjects := matcher.GetHandlersMap(kubeClient, fixRetryOpts, *podList, log, debug, fixWaitOpts)
+
+		// then
+		var names []string
+		for _, handledObject := range handledObjects {
+			names = append(names, handledObject.Object.Name)
+		}
+		require.Equal(t, []string{"deployment-b", "deployment-a", "pod-3", "statefulset"}, names)
+		getReplicaSets := 0
+		for _, action := range kubeClient.Actions() {
+			if action.GetVerb() == "get" && action.GetResource().Resource == "replicasets" {
+				getReplicaSets++
+			}
+		}
+		require.Equal(t, 1, getReplicaSets)
+	})
+}
+
+func fixPod(name, ownerKind, ownerName string) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "namespace",
+			OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName}},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+	}
 }
 
 func fixPodListWithParentKind(kind string) *v1.PodList {
@@ -21,15 +21,15 @@ type Matcher struct {
 }
 
 // GetHandlersMap provides a mock function with given fields: kubeClient, retryOpts, podsList, log, debug, waitOpts
-func (_m *Matcher) GetHandlersMap(kubeClient kubernetes.Interface, retryOpts []retry.Option, podsList v1.PodList, log *zap.SugaredLogger, debug bool, waitOpts pod.WaitOptions) map[pod.Handler][]pod.CustomObject {
+func (_m *Matcher) GetHandlersMap(kubeClient kubernetes.Interface, retryOpts []retry.Option, podsList v1.PodList, log *zap.SugaredLogger, debug bool, waitOpts pod.WaitOptions) []pod.HandledObject {
 	ret := _m.Called(kubeClient, retryOpts, podsList, log, debug, waitOpts)
 
-	var r0 map[pod.Handler][]pod.CustomObject
-	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, v1.PodList, *zap.SugaredLogger, bool, pod.WaitOptions) map[pod.Handler][]pod.CustomObject); ok {
+	var r0 []pod.HandledObject
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, v1.PodList, *zap.SugaredLogger, bool, pod.WaitOptions) []pod.HandledObject); ok {
 		r0 = rf(kubeClient, retryOpts, podsList, log, debug, waitOpts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[pod.Handler][]pod.CustomObject)
+			r0 = ret.Get(0).([]pod.HandledObject)
 		}
 	}
 
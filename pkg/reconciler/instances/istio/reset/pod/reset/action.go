@@ -2,10 +2,11 @@ package reset
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/avast/retry-go"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
@@ -30,24 +31,66 @@ func NewDefaultPodsResetAction(matcher pod.Matcher) *DefaultResetAction {
 }
 
 func (i *DefaultResetAction) Reset(context context.Context, kubeClient kubernetes.Interface, retryOpts []retry.Option, podsList v1.PodList, log *zap.SugaredLogger, debug bool, waitOpts pod.WaitOptions) error {
-	handlersMap := i.matcher.GetHandlersMap(kubeClient, retryOpts, podsList, log, debug, waitOpts)
-	g, ctx := errgroup.WithContext(context)
-	for handler := range handlersMap {
-		for _, object := range handlersMap[handler] {
-			handler := handler
-			object := object
-			g.Go(func() error {
-				err := handler.ExecuteAndWaitFor(ctx, object)
-				if err != nil {
-					return err
-				}
-				return nil
-			})
+	// the objects are started in the order of the pods list, which is sorted in the configured reset order
+	handledObjects := i.matcher.GetHandlersMap(kubeClient, retryOpts, podsList, log, debug, waitOpts)
+
+	concurrency := waitOpts.Concurrency
+	if concurrency <= 0 {
+		if waitOpts.Deadline.IsZero() {
+			// without a deadline there is nothing to check between the resets, so all objects are reset at once
+			concurrency = len(handledObjects) + 1
+		} else {
+			concurrency = pod.DefaultResetConcurrency
 		}
 	}
-	if err := g.Wait(); err != nil {
-		return err
+	slots := make(chan struct{}, concurrency)
+
+	// failure of a single object must not abort the reset of the remaining ones
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	aggregatedErr := &AggregatedError{}
+	for _, handledObject := range handledObjects {
+		handler := handledObject.Handler
+		object := handledObject.Object
+		if waitOpts.Progress != nil && waitOpts.Progress.IsDone(object) {
+			log.Debugf("Skipping %s %s/%s, it was reset by a previous run", object.Kind, object.Namespace, object.Name)
+			continue
+		}
+		aggregatedErr.Total++
+		// wait for a free slot first, so the deadline also stops resets which were queued before it passed
+		slots <- struct{}{}
+		if !waitOpts.Deadline.IsZero() && time.Now().After(waitOpts.Deadline) {
+			<-slots
+			aggregatedErr.Incomplete = true
+			aggregatedErr.Remaining = append(aggregatedErr.Remaining, object)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			err := handler.ExecuteAndWaitFor(context, object)
+			if err != nil {
+				log.Warnf("Reset of %s %s/%s failed: %s", object.Kind, object.Namespace, object.Name, err)
+				mu.Lock()
+				aggregatedErr.Failed = append(aggregatedErr.Failed, ObjectError{Object: object, Err: err})
+				mu.Unlock()
+				return
+			}
+			if waitOpts.Progress != nil {
+				if err := waitOpts.Progress.Done(object); err != nil {
+					log.Warnf("Could not record the reset of %s %s/%s: %s", object.Kind, object.Namespace, object.Name, err)
+				}
+			}
+		}()
 	}
+	wg.Wait()
 
+	if aggregatedErr.Incomplete {
+		log.Warnf("Reset deadline exceeded, %d of %d objects were not reset", len(aggregatedErr.Remaining), aggregatedErr.Total)
+	}
+	if len(aggregatedErr.Failed) > 0 || aggregatedErr.Incomplete {
+		return aggregatedErr
+	}
 	return nil
 }
@@ -2,6 +2,9 @@ package reset
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -55,9 +58,9 @@ func Test_DefaultPodsResetAction_Reset(t *testing.T) {
 		matcher := mocks.Matcher{}
 		action := NewDefaultPodsResetAction(&matcher)
 		handler := mocks.Handler{}
-		handlersMap := map[pod.Handler][]pod.CustomObject{&handler: {}}
+		handledObjects := handle(&handler)
 		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
-			Return(handlersMap)
+			Return(handledObjects)
 
 		// when
 		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{}, log, debug, fixWaitOpts)
@@ -88,11 +91,11 @@ func Test_DefaultPodsResetAction_Reset(t *testing.T) {
 		matcher := mocks.Matcher{}
 		action := NewDefaultPodsResetAction(&matcher)
 		handler := mocks.Handler{}
-		handlersMap := map[pod.Handler][]pod.CustomObject{&handler: {simpleCustomObject}}
+		handledObjects := handle(&handler, simpleCustomObject)
 
 		handler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Return(nil)
 		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
-			Return(handlersMap)
+			Return(handledObjects)
 
 		// when
 		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod}}, log, debug, fixWaitOpts)
@@ -108,11 +111,11 @@ func Test_DefaultPodsResetAction_Reset(t *testing.T) {
 		matcher := mocks.Matcher{}
 		action := NewDefaultPodsResetAction(&matcher)
 		handler := mocks.Handler{}
-		handlersMap := map[pod.Handler][]pod.CustomObject{&handler: {simpleCustomObject, simpleCustomObject}}
+		handledObjects := handle(&handler, simpleCustomObject, simpleCustomObject)
 
 		handler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Return(nil)
 		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
-			Return(handlersMap)
+			Return(handledObjects)
 
 		// when
 		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod}}, log, debug, fixWaitOpts)
@@ -129,12 +132,12 @@ func Test_DefaultPodsResetAction_Reset(t *testing.T) {
 		action := NewDefaultPodsResetAction(&matcher)
 		handler1 := mocks.Handler{}
 		handler2 := mocks.Handler{}
-		handlersMap := map[pod.Handler][]pod.CustomObject{&handler1: {simpleCustomObject}, &handler2: {simpleCustomObject}}
+		handledObjects := append(handle(&handler1, simpleCustomObject), handle(&handler2, simpleCustomObject)...)
 
 		handler1.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Return(nil)
 		handler2.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Return(nil)
 		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
-			Return(handlersMap)
+			Return(handledObjects)
 
 		// when
 		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod}}, log, debug, fixWaitOpts)
@@ -145,4 +148,260 @@ func Test_DefaultPodsResetAction_Reset(t *testing.T) {
 		handler1.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 1)
 		handler2.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 1)
 	})
+
+	t.Run("should reset the objects in the order of the matched pods", func(t *testing.T) {
+		// given
+		first := pod.CustomObject{Name: "first", Namespace: "b", Kind: "Deployment"}
+		second := pod.CustomObject{Name: "second", Namespace: "a", Kind: "Pod"}
+		third := pod.CustomObject{Name: "third", Namespace: "c", Kind: "Deployment"}
+		var order []string
+		record := func(args mock.Arguments) { order = append(order, args.Get(1).(pod.CustomObject).Name) }
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		rolloutHandler := mocks.Handler{}
+		rolloutHandler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Run(record).Return(nil)
+		deleteHandler := mocks.Handler{}
+		deleteHandler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Run(record).Return(nil)
+		handledObjects := []pod.HandledObject{
+			{Handler: &rolloutHandler, Object: first},
+			{Handler: &deleteHandler, Object: second},
+			{Handler: &rolloutHandler, Object: third},
+		}
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+		waitOpts := fixWaitOpts
+		waitOpts.Concurrency = 1
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod, simplePod}}, log, debug, waitOpts)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"first", "second", "third"}, order)
+	})
+
+	t.Run("should reset all objects in parallel when neither a deadline nor a concurrency is set", func(t *testing.T) {
+		// given
+		count := pod.DefaultResetConcurrency + 1
+		objects := make([]pod.CustomObject, count)
+		pods := make([]v1.Pod, count)
+		for i := range objects {
+			objects[i] = pod.CustomObject{Name: fmt.Sprintf("name-%d", i)}
+			pods[i] = simplePod
+		}
+		var mu sync.Mutex
+		started := 0
+		allStarted := make(chan struct{})
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		handler := mocks.Handler{}
+		handledObjects := handle(&handler, objects...)
+		handler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).
+			Return(func(context.Context, pod.CustomObject) error {
+				mu.Lock()
+				started++
+				if started == count {
+					close(allStarted)
+				}
+				mu.Unlock()
+				select {
+				case <-allStarted:
+					return nil
+				case <-time.After(5 * time.Second):
+					return errors.New("not all objects were reset in parallel")
+				}
+			})
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: pods}, log, debug, fixWaitOpts)
+
+		// then
+		require.NoError(t, err)
+		handler.AssertNumberOfCalls(t, "ExecuteAndWaitFor", count)
+	})
+
+	t.Run("should reset remaining pods and aggregate the errors when some pods could not be reset", func(t *testing.T) {
+		// given
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		failingObject := pod.CustomObject{Name: "stuck", Namespace: "default", Kind: "Pod"}
+		handler := mocks.Handler{}
+		handledObjects := handle(&handler, simpleCustomObject, failingObject, simpleCustomObject)
+		handler.On("ExecuteAndWaitFor", mock.Anything, simpleCustomObject).Return(nil)
+		handler.On("ExecuteAndWaitFor", mock.Anything, failingObject).Return(errors.New("stuck terminating"))
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod, simplePod}}, log, debug, fixWaitOpts)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "reset failed for 1 of 3 objects")
+		require.Contains(t, err.Error(), "Pod default/stuck: stuck terminating")
+		aggregatedErr, ok := AsAggregatedError(err)
+		require.True(t, ok)
+		require.True(t, aggregatedErr.IsPartial())
+		require.Equal(t, 2, aggregatedErr.Succeeded())
+		require.Equal(t, failingObject, aggregatedErr.Failed[0].Object)
+		handler.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 3)
+	})
+
+	t.Run("should not report partial success when no pod could be reset", func(t *testing.T) {
+		// given
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		handler := mocks.Handler{}
+		handledObjects := handle(&handler, simpleCustomObject, simpleCustomObject)
+		handler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Return(errors.New("stuck terminating"))
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod}}, log, debug, fixWaitOpts)
+
+		// then
+		require.Error(t, err)
+		aggregatedErr, ok := AsAggregatedError(err)
+		require.True(t, ok)
+		require.False(t, aggregatedErr.IsPartial())
+		require.Equal(t, 0, aggregatedErr.Succeeded())
+		require.Len(t, aggregatedErr.Failed, 2)
+	})
+
+	t.Run("should not start any reset and report the remaining pods when the deadline is exceeded", func(t *testing.T) {
+		// given
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		handler := mocks.Handler{}
+		handledObjects := handle(&handler, simpleCustomObject, simpleCustomObject)
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+		waitOpts := fixWaitOpts
+		waitOpts.Deadline = time.Now().Add(-time.Second)
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod}}, log, debug, waitOpts)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "deadline exceeded before 2 objects were reset")
+		aggregatedErr, ok := AsAggregatedError(err)
+		require.True(t, ok)
+		require.True(t, aggregatedErr.Incomplete)
+		require.Equal(t, []pod.CustomObject{simpleCustomObject, simpleCustomObject}, aggregatedErr.Remaining)
+		require.Equal(t, 0, aggregatedErr.Succeeded())
+		require.Empty(t, aggregatedErr.Failed)
+		handler.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 0)
+	})
+
+	t.Run("should stop starting new resets when the deadline expires during the reset", func(t *testing.T) {
+		// given
+		first := pod.CustomObject{Name: "first"}
+		second := pod.CustomObject{Name: "second"}
+		third := pod.CustomObject{Name: "third"}
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		handler := mocks.Handler{}
+		handledObjects := handle(&handler, first, second, third)
+		handler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).
+			Run(func(mock.Arguments) { time.Sleep(100 * time.Millisecond) }).
+			Return(nil)
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+		waitOpts := fixWaitOpts
+		waitOpts.Concurrency = 1
+		waitOpts.Deadline = time.Now().Add(50 * time.Millisecond)
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod, simplePod}}, log, debug, waitOpts)
+
+		// then
+		require.Error(t, err)
+		aggregatedErr, ok := AsAggregatedError(err)
+		require.True(t, ok)
+		require.True(t, aggregatedErr.Incomplete)
+		require.Equal(t, 1, aggregatedErr.Succeeded())
+		require.Equal(t, []pod.CustomObject{second, third}, aggregatedErr.Remaining)
+		handler.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 1)
+		handler.AssertCalled(t, "ExecuteAndWaitFor", mock.Anything, first)
+	})
+
+	t.Run("should reset all pods when the deadline is not exceeded", func(t *testing.T) {
+		// given
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		handler := mocks.Handler{}
+		handledObjects := handle(&handler, simpleCustomObject, simpleCustomObject)
+		handler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Return(nil)
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+		waitOpts := fixWaitOpts
+		waitOpts.Deadline = time.Now().Add(time.Minute)
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod}}, log, debug, waitOpts)
+
+		// then
+		require.NoError(t, err)
+		handler.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 2)
+	})
+
+	t.Run("should skip the objects reset by a previous run and record the reset ones", func(t *testing.T) {
+		// given
+		doneObject := pod.CustomObject{Name: "done"}
+		failingObject := pod.CustomObject{Name: "failing"}
+		matcher := mocks.Matcher{}
+		action := NewDefaultPodsResetAction(&matcher)
+		handler := mocks.Handler{}
+		handledObjects := handle(&handler, doneObject, simpleCustomObject, failingObject)
+		handler.On("ExecuteAndWaitFor", mock.Anything, simpleCustomObject).Return(nil)
+		handler.On("ExecuteAndWaitFor", mock.Anything, failingObject).Return(errors.New("timeout"))
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handledObjects)
+		progress := &fakeProgress{done: map[pod.CustomObject]bool{doneObject: true}}
+		waitOpts := fixWaitOpts
+		waitOpts.Progress = progress
+
+		// when
+		err := action.Reset(ctx, kubeClient, fixRetryOpts, v1.PodList{Items: []v1.Pod{simplePod, simplePod, simplePod}}, log, debug, waitOpts)
+
+		// then
+		require.Error(t, err)
+		aggregatedErr, ok := AsAggregatedError(err)
+		require.True(t, ok)
+		require.Equal(t, 2, aggregatedErr.Total)
+		require.Equal(t, 1, aggregatedErr.Succeeded())
+		handler.AssertNotCalled(t, "ExecuteAndWaitFor", mock.Anything, doneObject)
+		require.Equal(t, []pod.CustomObject{simpleCustomObject}, progress.recorded)
+	})
+}
+
+type fakeProgress struct {
+	mu       sync.Mutex
+	done     map[pod.CustomObject]bool
+	recorded []pod.CustomObject
+}
+
+func (p *fakeProgress) IsDone(object pod.CustomObject) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done[object]
+}
+
+func (p *fakeProgress) Done(object pod.CustomObject) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recorded = append(p.recorded, object)
+	return nil
+}
+
+func handle(handler pod.Handler, objects ...pod.CustomObject) []pod.HandledObject {
+	var handledObjects []pod.HandledObject
+	for _, object := range objects {
+		handledObjects = append(handledObjects, pod.HandledObject{Handler: handler, Object: object})
+	}
+	return handledObjects
 }
@@ -0,0 +1,62 @@
+package reset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	"github.com/pkg/errors"
+)
+
+// ObjectError holds the error of a single object which could not be reset.
+type ObjectError struct {
+	Object pod.CustomObject
+	Err    error
+}
+
+// AggregatedError aggregates the errors of all objects which could not be reset.
+// Compare Failed with Total to distinguish a failed reset from a partially successful one.
+type AggregatedError struct {
+	// Total is the number of objects the reset was executed for.
+	Total int
+	// Failed holds the objects which could not be reset.
+	Failed []ObjectError
+	// Incomplete is true if the reset deadline was exceeded before all objects were reset.
+	Incomplete bool
+	// Remaining holds the objects whose reset was not started because the deadline was exceeded.
+	Remaining []pod.CustomObject
+}
+
+func (e *AggregatedError) Error() string {
+	failures := make([]string, 0, len(e.Failed))
+	for _, failed := range e.Failed {
+		failures = append(failures, fmt.Sprintf("%s %s/%s: %s", failed.Object.Kind, failed.Object.Namespace, failed.Object.Name, failed.Err))
+	}
+	msg := fmt.Sprintf("reset failed for %d of %d objects", len(e.Failed), e.Total)
+	if len(failures) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, strings.Join(failures, "; "))
+	}
+	if e.Incomplete {
+		msg = fmt.Sprintf("%s, deadline exceeded before %d objects were reset", msg, len(e.Remaining))
+	}
+	return msg
+}
+
+// Succeeded returns the number of objects which were reset successfully.
+func (e *AggregatedError) Succeeded() int {
+	return e.Total - len(e.Failed) - len(e.Remaining)
+}
+
+// IsPartial returns true if at least one object was reset successfully.
+func (e *AggregatedError) IsPartial() bool {
+	return e.Succeeded() > 0
+}
+
+// AsAggregatedError returns the AggregatedError from the err chain, if present.
+func AsAggregatedError(err error) (*AggregatedError, bool) {
+	var aggregatedErr *AggregatedError
+	if errors.As(err, &aggregatedErr) {
+		return aggregatedErr, true
+	}
+	return nil, false
+}
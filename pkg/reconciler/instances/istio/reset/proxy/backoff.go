@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// randomJitter returns a random duration in [0, max).
+var randomJitter = func(max time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// retryDelay returns the delay before the retry following the failed attempt n, counted from zero, which failed with err.
+// The exponential delay is capped at cfg.MaxDelayBetweenRetries, the jitter is added on top.
+// If the API server throttled the attempt and cfg.RespectRetryAfter is set, the delay is at least the suggested Retry-After.
+func retryDelay(cfg config.IstioProxyConfig, n uint, err error) time.Duration {
+	delay := cfg.DelayBetweenRetries
+	if cfg.Backoff == config.ExponentialBackoff {
+		for i := uint(0); i < n && delay > 0 && delay <= math.MaxInt64/2; i++ {
+			if cfg.MaxDelayBetweenRetries > 0 && delay >= cfg.MaxDelayBetweenRetries {
+				break
+			}
+			delay *= 2
+		}
+		if cfg.MaxDelayBetweenRetries > 0 && delay > cfg.MaxDelayBetweenRetries {
+			delay = cfg.MaxDelayBetweenRetries
+		}
+	}
+	if cfg.Jitter > 0 {
+		delay += randomJitter(cfg.Jitter)
+	}
+	if retryAfter, throttled := throttlingDelay(cfg, err); throttled {
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		if cfg.Log != nil {
+			cfg.Log.Warnf("API server is throttling the proxy reset, retrying in %s: %s", delay, err)
+		}
+	}
+	return delay
+}
+
+// throttlingDelay returns the Retry-After duration suggested by the API server, capped at cfg.MaxRetryAfter, if err reports throttling.
+func throttlingDelay(cfg config.IstioProxyConfig, err error) (time.Duration, bool) {
+	if !cfg.RespectRetryAfter || !kerrors.IsTooManyRequests(err) {
+		return 0, false
+	}
+	seconds, _ := kerrors.SuggestsClientDelay(err)
+	retryAfter := time.Duration(seconds) * time.Second
+	if cfg.MaxRetryAfter > 0 && retryAfter > cfg.MaxRetryAfter {
+		retryAfter = cfg.MaxRetryAfter
+	}
+	return retryAfter, true
+}
+
+func retryOptionsFrom(cfg config.IstioProxyConfig) []retry.Option {
+	return []retry.Option{
+		retry.Delay(cfg.DelayBetweenRetries),
+		retry.Attempts(uint(cfg.RetriesCount)),
+		retry.DelayType(func(n uint, err error, _ *retry.Config) time.Duration {
+			return retryDelay(cfg, n, err)
+		}),
+	}
+}
@@ -0,0 +1,184 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_retryDelay(t *testing.T) {
+
+	schedule := func(cfg config.IstioProxyConfig, retries uint) []time.Duration {
+		var delays []time.Duration
+		for n := uint(0); n < retries; n++ {
+			delays = append(delays, retryDelay(cfg, n, nil))
+		}
+		return delays
+	}
+
+	t.Run("should keep the delay constant with linear backoff", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second}
+
+		// when
+		delays := schedule(cfg, 4)
+
+		// then
+		require.Equal(t, []time.Duration{time.Second, time.Second, time.Second, time.Second}, delays)
+	})
+
+	t.Run("should double the delay with exponential backoff", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second, Backoff: config.ExponentialBackoff}
+
+		// when
+		delays := schedule(cfg, 5)
+
+		// then
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second}, delays)
+	})
+
+	t.Run("should cap the exponential backoff", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second, Backoff: config.ExponentialBackoff, MaxDelayBetweenRetries: 5 * time.Second}
+
+		// when
+		delays := schedule(cfg, 5)
+
+		// then
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+	})
+
+	t.Run("should not overflow with many retries", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second, Backoff: config.ExponentialBackoff}
+
+		// when
+		delay := retryDelay(cfg, 100, nil)
+
+		// then
+		require.Greater(t, int64(delay), int64(0))
+	})
+
+	t.Run("should add the jitter on top of the capped delay", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second, Backoff: config.ExponentialBackoff, MaxDelayBetweenRetries: 3 * time.Second, Jitter: time.Second}
+
+		// when
+		delays := schedule(cfg, 50)
+
+		// then
+		for n, delay := range delays {
+			base := 3 * time.Second
+			if n < 2 {
+				base = time.Second << n
+			}
+			require.GreaterOrEqual(t, int64(delay), int64(base))
+			require.Less(t, int64(delay), int64(base+time.Second))
+		}
+	})
+
+	t.Run("should add the jitter returned by the random source", func(t *testing.T) {
+		// given
+		original := randomJitter
+		defer func() { randomJitter = original }()
+		var maxJitter time.Duration
+		randomJitter = func(max time.Duration) time.Duration {
+			maxJitter = max
+			return 300 * time.Millisecond
+		}
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second, Jitter: time.Second}
+
+		// when
+		delay := retryDelay(cfg, 2, nil)
+
+		// then
+		require.Equal(t, 1300*time.Millisecond, delay)
+		require.Equal(t, time.Second, maxJitter)
+	})
+}
+
+func Test_retryDelay_Throttling(t *testing.T) {
+
+	throttled := kerrors.NewTooManyRequests("too many requests", 3)
+
+	t.Run("should wait for the Retry-After suggested by the API server", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.WarnLevel)
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second, RespectRetryAfter: true, Log: zap.New(core).Sugar()}
+
+		// when
+		delay := retryDelay(cfg, 0, throttled)
+
+		// then
+		require.Equal(t, 3*time.Second, delay)
+		require.Equal(t, 1, logs.FilterMessageSnippet("API server is throttling the proxy reset").Len())
+	})
+
+	t.Run("should keep a longer delay than the Retry-After", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: 5 * time.Second, RespectRetryAfter: true}
+
+		// when
+		delay := retryDelay(cfg, 0, throttled)
+
+		// then
+		require.Equal(t, 5*time.Second, delay)
+	})
+
+	t.Run("should cap the Retry-After", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second, RespectRetryAfter: true, MaxRetryAfter: 2 * time.Second}
+
+		// when
+		delay := retryDelay(cfg, 0, throttled)
+
+		// then
+		require.Equal(t, 2*time.Second, delay)
+	})
+
+	t.Run("should ignore the Retry-After if not configured", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{DelayBetweenRetries: time.Second}
+
+		// when
+		delay := retryDelay(cfg, 0, throttled)
+
+		// then
+		require.Equal(t, time.Second, delay)
+	})
+
+	t.Run("should gather pods after the API server stopped throttling", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zapcore.WarnLevel)
+		throttledCalls := 1
+		kubeClient := fake.NewSimpleClientset()
+		kubeClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if throttledCalls > 0 {
+				throttledCalls--
+				return true, nil, kerrors.NewTooManyRequests("too many requests", 1)
+			}
+			return false, nil, nil
+		})
+		cfg := config.IstioProxyConfig{RetriesCount: 2, DelayBetweenRetries: 10 * time.Millisecond, RespectRetryAfter: true, Log: zap.New(core).Sugar()}
+		start := time.Now()
+
+		// when
+		_, err := data.NewDefaultGatherer().GetAllPods(kubeClient, retryOptionsFrom(cfg))
+
+		// then
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second))
+		require.Equal(t, 1, logs.FilterMessageSnippet("API server is throttling the proxy reset").Len())
+	})
+}
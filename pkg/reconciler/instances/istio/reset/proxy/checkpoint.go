@@ -0,0 +1,198 @@
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CheckpointConfigMapName is the name of the ConfigMap the ConfigMapCheckpointStore keeps the checkpoints in.
+	CheckpointConfigMapName = "istio-proxy-reset-checkpoint"
+
+	checkpointKeyLength = 16
+)
+
+// Checkpoint holds the objects reset by a proxy reset which did not complete.
+type Checkpoint struct {
+	Image         string             `json:"image"`
+	LabelSelector string             `json:"labelSelector,omitempty"`
+	Done          []pod.CustomObject `json:"done"`
+}
+
+//go:generate mockery --name=CheckpointStore --outpkg=mocks --case=underscore
+// CheckpointStore persists the Checkpoint of a proxy reset, so a reset interrupted by an error or a reconciler restart resumes where it stopped.
+type CheckpointStore interface {
+	// Load returns the checkpoint stored under the key in the cluster, an empty Checkpoint if there is none.
+	Load(ctx context.Context, kubeClient kubernetes.Interface, key string) (Checkpoint, error)
+
+	// Save stores the checkpoint under the key in the cluster.
+	Save(ctx context.Context, kubeClient kubernetes.Interface, key string, checkpoint Checkpoint) error
+
+	// Delete removes the checkpoint stored under the key in the cluster, if any.
+	Delete(ctx context.Context, kubeClient kubernetes.Interface, key string) error
+}
+
+// ConfigMapCheckpointStore is a CheckpointStore keeping the checkpoints in a ConfigMap of the reset cluster.
+type ConfigMapCheckpointStore struct {
+	namespace string
+}
+
+// NewConfigMapCheckpointStore creates a new instance of ConfigMapCheckpointStore using the ConfigMap CheckpointConfigMapName in the namespace.
+func NewConfigMapCheckpointStore(namespace string) *ConfigMapCheckpointStore {
+	return &ConfigMapCheckpointStore{namespace: namespace}
+}
+
+func (s *ConfigMapCheckpointStore) Load(ctx context.Context, kubeClient kubernetes.Interface, key string) (Checkpoint, error) {
+	configMap, err := kubeClient.CoreV1().ConfigMaps(s.namespace).Get(ctx, CheckpointConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return Checkpoint{}, nil
+	}
+	if err != nil {
+		return Checkpoint{}, errors.Wrap(err, "Could not get the proxy reset checkpoint")
+	}
+
+	data, ok := configMap.Data[key]
+	if !ok {
+		return Checkpoint{}, nil
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal([]byte(data), &checkpoint); err != nil {
+		return Checkpoint{}, errors.Wrapf(err, "Could not parse the proxy reset checkpoint %s", key)
+	}
+	return checkpoint, nil
+}
+
+func (s *ConfigMapCheckpointStore) Save(ctx context.Context, kubeClient kubernetes.Interface, key string, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "Could not marshal the proxy reset checkpoint")
+	}
+
+	configMaps := kubeClient.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := configMaps.Get(ctx, CheckpointConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		configMap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: CheckpointConfigMapName, Namespace: s.namespace},
+			Data:       map[string]string{key: string(data)},
+		}
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+		return errors.Wrap(err, "Could not create the proxy reset checkpoint")
+	}
+	if err != nil {
+		return errors.Wrap(err, "Could not get the proxy reset checkpoint")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = string(data)
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return errors.Wrap(err, "Could not update the proxy reset checkpoint")
+}
+
+func (s *ConfigMapCheckpointStore) Delete(ctx context.Context, kubeClient kubernetes.Interface, key string) error {
+	configMaps := kubeClient.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := configMaps.Get(ctx, CheckpointConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "Could not get the proxy reset checkpoint")
+	}
+	if _, ok := configMap.Data[key]; !ok {
+		return nil
+	}
+
+	delete(configMap.Data, key)
+	if len(configMap.Data) == 0 {
+		err = configMaps.Delete(ctx, CheckpointConfigMapName, metav1.DeleteOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "Could not delete the proxy reset checkpoint")
+	}
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return errors.Wrap(err, "Could not update the proxy reset checkpoint")
+}
+
+// checkpointKey identifies the checkpoint of a reset by the target image and the label selector,
+// a reset to another image or of other pods does not resume it.
+func checkpointKey(cfg config.IstioProxyConfig) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s", cfg.ImagePrefix, cfg.ImageVersion, cfg.LabelSelector)))
+	return fmt.Sprintf("%x", sum)[:checkpointKeyLength]
+}
+
+// checkpointProgress is a pod.Progress saving the checkpoint after every object reset.
+type checkpointProgress struct {
+	ctx        context.Context
+	kubeClient kubernetes.Interface
+	store      CheckpointStore
+	key        string
+
+	mu         sync.Mutex
+	checkpoint Checkpoint
+	done       map[pod.CustomObject]bool
+}
+
+func newCheckpointProgress(cfg config.IstioProxyConfig, store CheckpointStore) (*checkpointProgress, error) {
+	key := checkpointKey(cfg)
+	checkpoint, err := store.Load(cfg.Context, cfg.Kubeclient, key)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint.Image = fmt.Sprintf("%s:%s", cfg.ImagePrefix, cfg.ImageVersion)
+	checkpoint.LabelSelector = cfg.LabelSelector
+	done := make(map[pod.CustomObject]bool, len(checkpoint.Done))
+	for _, object := range checkpoint.Done {
+		done[object] = true
+	}
+
+	return &checkpointProgress{
+		ctx:        cfg.Context,
+		kubeClient: cfg.Kubeclient,
+		store:      store,
+		key:        key,
+		checkpoint: checkpoint,
+		done:       done,
+	}, nil
+}
+
+func (p *checkpointProgress) IsDone(object pod.CustomObject) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done[object]
+}
+
+func (p *checkpointProgress) Done(object pod.CustomObject) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done[object] {
+		return nil
+	}
+	p.done[object] = true
+	p.checkpoint.Done = append(p.checkpoint.Done, object)
+	return p.store.Save(p.ctx, p.kubeClient, p.key, p.checkpoint)
+}
+
+// resumed returns the number of objects reset by previous runs.
+func (p *checkpointProgress) resumed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.checkpoint.Done)
+}
+
+func (p *checkpointProgress) clear() error {
+	return p.store.Delete(p.ctx, p.kubeClient, p.key)
+}
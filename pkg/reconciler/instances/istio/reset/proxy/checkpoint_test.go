@@ -0,0 +1,228 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	podmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
+	podresetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type recordingCheckpointStore struct {
+	err   error
+	loads int
+}
+
+func (s *recordingCheckpointStore) Load(context.Context, kubernetes.Interface, string) (Checkpoint, error) {
+	s.loads++
+	return Checkpoint{}, s.err
+}
+
+func (s *recordingCheckpointStore) Save(context.Context, kubernetes.Interface, string, Checkpoint) error {
+	return s.err
+}
+
+func (s *recordingCheckpointStore) Delete(context.Context, kubernetes.Interface, string) error {
+	return s.err
+}
+
+func Test_ConfigMapCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	namespace := "istio-system"
+	checkpoint := Checkpoint{
+		Image: "istio/proxyv2:1.10.2",
+		Done:  []pod.CustomObject{{Name: "name", Namespace: "namespace", Kind: "Deployment"}},
+	}
+
+	t.Run("should return an empty checkpoint when none is stored", func(t *testing.T) {
+		// given
+		store := NewConfigMapCheckpointStore(namespace)
+
+		// when
+		got, err := store.Load(ctx, fake.NewSimpleClientset(), "key")
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, got.Done)
+	})
+
+	t.Run("should load the saved checkpoint", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		store := NewConfigMapCheckpointStore(namespace)
+		require.NoError(t, store.Save(ctx, kubeClient, "key", Checkpoint{Image: "istio/proxyv2:1.10.1"}))
+
+		// when
+		err := store.Save(ctx, kubeClient, "key", checkpoint)
+		require.NoError(t, err)
+		got, err := store.Load(ctx, kubeClient, "key")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, checkpoint, got)
+	})
+
+	t.Run("should delete the ConfigMap with the last checkpoint", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		store := NewConfigMapCheckpointStore(namespace)
+		require.NoError(t, store.Save(ctx, kubeClient, "key", checkpoint))
+		require.NoError(t, store.Save(ctx, kubeClient, "other", checkpoint))
+
+		// when
+		err := store.Delete(ctx, kubeClient, "key")
+		require.NoError(t, err)
+		configMap, getErr := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, CheckpointConfigMapName, metav1.GetOptions{})
+		require.NoError(t, getErr)
+		require.Len(t, configMap.Data, 1)
+		err = store.Delete(ctx, kubeClient, "other")
+
+		// then
+		require.NoError(t, err)
+		_, getErr = kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, CheckpointConfigMapName, metav1.GetOptions{})
+		require.True(t, k8serrors.IsNotFound(getErr))
+	})
+
+	t.Run("should not return an error when deleting a missing checkpoint", func(t *testing.T) {
+		// given
+		store := NewConfigMapCheckpointStore(namespace)
+
+		// when
+		err := store.Delete(ctx, fake.NewSimpleClientset(), "key")
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_IstioProxyReset_Run_Checkpoint(t *testing.T) {
+	first := pod.CustomObject{Name: "first", Namespace: "namespace", Kind: "Deployment"}
+	second := pod.CustomObject{Name: "second", Namespace: "namespace", Kind: "Deployment"}
+
+	fixGatherer := func() *datamocks.Gatherer {
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}, {}}}, nil)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}, {}}})
+		return &gatherer
+	}
+	fixCfg := func() config.IstioProxyConfig {
+		return config.IstioProxyConfig{
+			Context:      context.Background(),
+			ImagePrefix:  "istio/proxyv2",
+			ImageVersion: "1.10.2",
+			RetriesCount: 5,
+			Kubeclient:   fake.NewSimpleClientset(),
+			Log:          log.NewLogger(true),
+		}
+	}
+
+	t.Run("should resume an interrupted reset without resetting the already reset objects again", func(t *testing.T) {
+		// given
+		cfg := fixCfg()
+		handler := podmocks.Handler{}
+		handler.On("ExecuteAndWaitFor", mock.Anything, first).Return(nil)
+		handler.On("ExecuteAndWaitFor", mock.Anything, second).Return(errors.New("timeout")).Once()
+		handler.On("ExecuteAndWaitFor", mock.Anything, second).Return(nil).Once()
+		matcher := podmocks.Matcher{}
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handle(&handler, first, second))
+		store := NewConfigMapCheckpointStore("istio-system")
+		istioProxyReset := NewDefaultIstioProxyReset(fixGatherer(), reset.NewDefaultPodsResetAction(&matcher)).WithCheckpointStore(store)
+
+		// when
+		interruptedErr := istioProxyReset.Run(cfg)
+		checkpoint, loadErr := store.Load(cfg.Context, cfg.Kubeclient, checkpointKey(cfg))
+		err := istioProxyReset.Run(cfg)
+
+		// then
+		require.Error(t, interruptedErr)
+		require.NoError(t, loadErr)
+		require.Equal(t, []pod.CustomObject{first}, checkpoint.Done)
+		require.Equal(t, "istio/proxyv2:1.10.2", checkpoint.Image)
+		require.NoError(t, err)
+		handler.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 3)
+		_, getErr := cfg.Kubeclient.CoreV1().ConfigMaps("istio-system").Get(cfg.Context, CheckpointConfigMapName, metav1.GetOptions{})
+		require.True(t, k8serrors.IsNotFound(getErr))
+	})
+
+	t.Run("should not resume the checkpoint of a reset to another image", func(t *testing.T) {
+		// given
+		cfg := fixCfg()
+		otherCfg := cfg
+		otherCfg.ImageVersion = "1.10.1"
+		store := NewConfigMapCheckpointStore("istio-system")
+		require.NoError(t, store.Save(cfg.Context, cfg.Kubeclient, checkpointKey(otherCfg), Checkpoint{Done: []pod.CustomObject{first}}))
+		handler := podmocks.Handler{}
+		handler.On("ExecuteAndWaitFor", mock.Anything, mock.AnythingOfType("pod.CustomObject")).Return(nil)
+		matcher := podmocks.Matcher{}
+		matcher.On("GetHandlersMap", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(handle(&handler, first, second))
+		istioProxyReset := NewDefaultIstioProxyReset(fixGatherer(), reset.NewDefaultPodsResetAction(&matcher)).WithCheckpointStore(store)
+
+		// when
+		err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		handler.AssertNumberOfCalls(t, "ExecuteAndWaitFor", 2)
+	})
+
+	t.Run("should return an error when the checkpoint can not be loaded", func(t *testing.T) {
+		// given
+		cfg := fixCfg()
+		store := recordingCheckpointStore{err: errors.New("forbidden")}
+		action := podresetmocks.Action{}
+		istioProxyReset := NewDefaultIstioProxyReset(fixGatherer(), &action).WithCheckpointStore(&store)
+
+		// when
+		err := istioProxyReset.Run(cfg)
+
+		// then
+		require.EqualError(t, err, "forbidden")
+		action.AssertNumberOfCalls(t, "Reset", 0)
+	})
+
+	t.Run("should not checkpoint a reset in debug mode", func(t *testing.T) {
+		// given
+		cfg := fixCfg()
+		cfg.Debug = true
+		store := recordingCheckpointStore{}
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"),
+			mock.MatchedBy(func(waitOpts pod.WaitOptions) bool {
+				return waitOpts.Progress == nil
+			})).
+			Return(nil)
+		istioProxyReset := NewDefaultIstioProxyReset(fixGatherer(), &action).WithCheckpointStore(&store)
+
+		// when
+		err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+		require.Zero(t, store.loads)
+	})
+}
+
+func handle(handler pod.Handler, objects ...pod.CustomObject) []pod.HandledObject {
+	var handledObjects []pod.HandledObject
+	for _, object := range objects {
+		handledObjects = append(handledObjects, pod.HandledObject{Handler: handler, Object: object})
+	}
+	return handledObjects
+}
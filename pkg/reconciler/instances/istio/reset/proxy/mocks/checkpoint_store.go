@@ -0,0 +1,67 @@
+// Code generated by mockery 2.9.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	kubernetes "k8s.io/client-go/kubernetes"
+
+	mock "github.com/stretchr/testify/mock"
+
+	proxy "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
+)
+
+// CheckpointStore is an autogenerated mock type for the CheckpointStore type
+type CheckpointStore struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, kubeClient, key
+func (_m *CheckpointStore) Delete(ctx context.Context, kubeClient kubernetes.Interface, key string) error {
+	ret := _m.Called(ctx, kubeClient, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Interface, string) error); ok {
+		r0 = rf(ctx, kubeClient, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Load provides a mock function with given fields: ctx, kubeClient, key
+func (_m *CheckpointStore) Load(ctx context.Context, kubeClient kubernetes.Interface, key string) (proxy.Checkpoint, error) {
+	ret := _m.Called(ctx, kubeClient, key)
+
+	var r0 proxy.Checkpoint
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Interface, string) proxy.Checkpoint); ok {
+		r0 = rf(ctx, kubeClient, key)
+	} else {
+		r0 = ret.Get(0).(proxy.Checkpoint)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, kubernetes.Interface, string) error); ok {
+		r1 = rf(ctx, kubeClient, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, kubeClient, key, checkpoint
+func (_m *CheckpointStore) Save(ctx context.Context, kubeClient kubernetes.Interface, key string, checkpoint proxy.Checkpoint) error {
+	ret := _m.Called(ctx, kubeClient, key, checkpoint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Interface, string, proxy.Checkpoint) error); ok {
+		r0 = rf(ctx, kubeClient, key, checkpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	config "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
 	mock "github.com/stretchr/testify/mock"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 // IstioProxyReset is an autogenerated mock type for the IstioProxyReset type
@@ -12,6 +14,27 @@ type IstioProxyReset struct {
 	mock.Mock
 }
 
+// Preview provides a mock function with given fields: cfg
+func (_m *IstioProxyReset) Preview(cfg config.IstioProxyConfig) (v1.PodList, error) {
+	ret := _m.Called(cfg)
+
+	var r0 v1.PodList
+	if rf, ok := ret.Get(0).(func(config.IstioProxyConfig) v1.PodList); ok {
+		r0 = rf(cfg)
+	} else {
+		r0 = ret.Get(0).(v1.PodList)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(config.IstioProxyConfig) error); ok {
+		r1 = rf(cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Run provides a mock function with given fields: cfg
 func (_m *IstioProxyReset) Run(cfg config.IstioProxyConfig) error {
 	ret := _m.Called(cfg)
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"sort"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	v1 "k8s.io/api/core/v1"
+)
+
+// sortPods sorts the pods in the given order. Pods equal in the given order are sorted by namespace and name, so the result is deterministic.
+func sortPods(pods v1.PodList, order config.ResetOrder) v1.PodList {
+	var less func(a, b *v1.Pod) bool
+	switch order {
+	case config.OldestFirst:
+		less = func(a, b *v1.Pod) bool {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+	case config.NewestFirst:
+		less = func(a, b *v1.Pod) bool {
+			return b.CreationTimestamp.Before(&a.CreationTimestamp)
+		}
+	case config.ByNamespace:
+		less = func(a, b *v1.Pod) bool {
+			return a.Namespace < b.Namespace
+		}
+	default:
+		return pods
+	}
+
+	sorted := append([]v1.Pod{}, pods.Items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := &sorted[i], &sorted[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	pods.Items = sorted
+	return pods
+}
@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_sortPods(t *testing.T) {
+	now := time.Now()
+	pods := v1.PodList{Items: []v1.Pod{
+		fixPodCreatedAt("b", "ns-2", now.Add(-1*time.Hour)),
+		fixPodCreatedAt("a", "ns-1", now),
+		fixPodCreatedAt("c", "ns-1", now.Add(-3*time.Hour)),
+		fixPodCreatedAt("a", "ns-2", now.Add(-2*time.Hour)),
+	}}
+
+	tests := []struct {
+		name  string
+		order config.ResetOrder
+		want  []string
+	}{
+		{
+			name:  "should keep the gathered order when order is unspecified",
+			order: config.UnspecifiedOrder,
+			want:  []string{"ns-2/b", "ns-1/a", "ns-1/c", "ns-2/a"},
+		},
+		{
+			name:  "should sort oldest pods first",
+			order: config.OldestFirst,
+			want:  []string{"ns-1/c", "ns-2/a", "ns-2/b", "ns-1/a"},
+		},
+		{
+			name:  "should sort newest pods first",
+			order: config.NewestFirst,
+			want:  []string{"ns-1/a", "ns-2/b", "ns-2/a", "ns-1/c"},
+		},
+		{
+			name:  "should group pods by namespace sorted by name",
+			order: config.ByNamespace,
+			want:  []string{"ns-1/a", "ns-1/c", "ns-2/a", "ns-2/b"},
+		},
+		{
+			name:  "should keep the gathered order when order is unknown",
+			order: config.ResetOrder("unknown"),
+			want:  []string{"ns-2/b", "ns-1/a", "ns-1/c", "ns-2/a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := sortPods(pods, tt.order)
+
+			// then
+			require.Equal(t, tt.want, podKeys(got))
+		})
+	}
+
+	t.Run("should sort pods with equal creation timestamp by namespace and name", func(t *testing.T) {
+		// given
+		samePods := v1.PodList{Items: []v1.Pod{
+			fixPodCreatedAt("b", "ns-1", now),
+			fixPodCreatedAt("a", "ns-2", now),
+			fixPodCreatedAt("a", "ns-1", now),
+		}}
+
+		// when
+		got := sortPods(samePods, config.OldestFirst)
+
+		// then
+		require.Equal(t, []string{"ns-1/a", "ns-1/b", "ns-2/a"}, podKeys(got))
+	})
+
+	t.Run("should not modify the passed pod list", func(t *testing.T) {
+		// when
+		_ = sortPods(pods, config.ByNamespace)
+
+		// then
+		require.Equal(t, []string{"ns-2/b", "ns-1/a", "ns-1/c", "ns-2/a"}, podKeys(pods))
+	})
+}
+
+func fixPodCreatedAt(name, namespace string, created time.Time) v1.Pod {
+	return v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         namespace,
+		CreationTimestamp: metav1.NewTime(created),
+	}}
+}
+
+func podKeys(pods v1.PodList) []string {
+	keys := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		keys = append(keys, pod.Namespace+"/"+pod.Name)
+	}
+	return keys
+}
@@ -1,24 +1,33 @@
 package proxy
 
 import (
-	"github.com/avast/retry-go"
+	"time"
+
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
+	v1 "k8s.io/api/core/v1"
 )
 
 //go:generate mockery --name=IstioProxyReset --outpkg=mocks --case=underscore
 // IstioProxyReset performs istio proxy containers reset on objects in the k8s cluster.
 type IstioProxyReset interface {
 	// Run istio proxy containers reset using the config.
+	// If only some objects could not be reset, the returned error is a reset.AggregatedError.
+	// If cfg.Deadline is exceeded, the returned reset.AggregatedError is marked Incomplete and holds the objects which remained.
 	Run(cfg config.IstioProxyConfig) error
+
+	// Preview returns the pods which would be reset by Run using the config, without performing any action.
+	// The pods are sorted in the order configured in cfg.Order.
+	Preview(cfg config.IstioProxyConfig) (v1.PodList, error)
 }
 
 // DefaultIstioProxyReset provides a default implementation of the IstioProxyReset.
 type DefaultIstioProxyReset struct {
-	gatherer data.Gatherer
-	action   reset.Action
+	gatherer    data.Gatherer
+	action      reset.Action
+	checkpoints CheckpointStore
 }
 
 // NewDefaultIstioProxyReset creates a new instance of IstioProxyReset.
@@ -29,36 +38,81 @@ func NewDefaultIstioProxyReset(gatherer data.Gatherer, action reset.Action) *Def
 	}
 }
 
-func (i *DefaultIstioProxyReset) Run(cfg config.IstioProxyConfig) error {
-	image := data.ExpectedImage{
-		Prefix:  cfg.ImagePrefix,
-		Version: cfg.ImageVersion,
-	}
+// WithCheckpointStore makes Run checkpoint the reset objects in the store, so a retried Run resumes
+// instead of resetting the objects reset by a failed or interrupted previous Run again.
+// The checkpoint is removed once all pods run the target image.
+func (i *DefaultIstioProxyReset) WithCheckpointStore(store CheckpointStore) *DefaultIstioProxyReset {
+	i.checkpoints = store
+	return i
+}
 
+func (i *DefaultIstioProxyReset) Run(cfg config.IstioProxyConfig) error {
 	waitOpts := pod.WaitOptions{
-		Interval: cfg.Interval,
-		Timeout:  cfg.Timeout,
+		Interval:   cfg.Interval,
+		Timeout:    cfg.Timeout,
+		RespectPDB: cfg.RespectPDB,
 	}
-
-	retryOpts := []retry.Option{
-		retry.Delay(cfg.DelayBetweenRetries),
-		retry.Attempts(uint(cfg.RetriesCount)),
-		retry.DelayType(retry.FixedDelay),
+	if cfg.Deadline > 0 {
+		waitOpts.Deadline = time.Now().Add(cfg.Deadline)
 	}
 
-	pods, err := i.gatherer.GetAllPods(cfg.Kubeclient, retryOpts)
+	podsWithDifferentImage, err := i.Preview(cfg)
 	if err != nil {
 		return err
 	}
-	cfg.Log.Debugf("Found %d pods in total", len(pods.Items))
-	podsWithDifferentImage := i.gatherer.GetPodsWithDifferentImage(*pods, image)
-	cfg.Log.Infof("Found %d pods with different istio proxy image (%s)", len(podsWithDifferentImage.Items), image)
+
+	var progress *checkpointProgress
+	if i.checkpoints != nil && !cfg.Debug {
+		progress, err = newCheckpointProgress(cfg, i.checkpoints)
+		if err != nil {
+			return err
+		}
+		if resumed := progress.resumed(); resumed > 0 {
+			cfg.Log.Infof("Resuming proxy reset, %d objects were reset by a previous run", resumed)
+		}
+		waitOpts.Progress = progress
+	}
+
 	if len(podsWithDifferentImage.Items) >= 1 {
-		err = i.action.Reset(cfg.Context, cfg.Kubeclient, retryOpts, podsWithDifferentImage, cfg.Log, cfg.Debug, waitOpts)
+		err = i.action.Reset(cfg.Context, cfg.Kubeclient, retryOptionsFrom(cfg), podsWithDifferentImage, cfg.Log, cfg.Debug, waitOpts)
+		if aggregatedErr, ok := reset.AsAggregatedError(err); ok && aggregatedErr.IsPartial() {
+			cfg.Log.Warnf("Proxy reset partially done: %d of %d objects reset, %d failed", aggregatedErr.Succeeded(), aggregatedErr.Total, len(aggregatedErr.Failed))
+		}
 		if err != nil {
 			return err
 		}
 		cfg.Log.Infof("Proxy reset for %d pods successfully done", len(podsWithDifferentImage.Items))
 	}
+
+	if progress != nil {
+		if err := progress.clear(); err != nil {
+			cfg.Log.Warnf("Could not remove the proxy reset checkpoint: %s", err)
+		}
+	}
 	return nil
 }
+
+func (i *DefaultIstioProxyReset) Preview(cfg config.IstioProxyConfig) (v1.PodList, error) {
+	image := data.ExpectedImage{
+		Prefix:  cfg.ImagePrefix,
+		Version: cfg.ImageVersion,
+	}
+
+	pods, err := i.gatherPods(cfg)
+	if err != nil {
+		return v1.PodList{}, err
+	}
+	cfg.Log.Debugf("Found %d pods in total", len(pods.Items))
+	podsWithDifferentImage := sortPods(i.gatherer.GetPodsWithDifferentImage(*pods, image), cfg.Order)
+	cfg.Log.Infof("Found %d pods with different istio proxy image (%s)", len(podsWithDifferentImage.Items), image)
+
+	return podsWithDifferentImage, nil
+}
+
+func (i *DefaultIstioProxyReset) gatherPods(cfg config.IstioProxyConfig) (*v1.PodList, error) {
+	if cfg.LabelSelector == "" {
+		return i.gatherer.GetAllPods(cfg.Kubeclient, retryOptionsFrom(cfg))
+	}
+	cfg.Log.Debugf("Gathering pods matching the label selector %s", cfg.LabelSelector)
+	return i.gatherer.GetPodsBySelector(cfg.Kubeclient, cfg.LabelSelector, retryOptionsFrom(cfg))
+}
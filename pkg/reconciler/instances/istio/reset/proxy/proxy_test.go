@@ -3,12 +3,15 @@ package proxy
 import (
 	"errors"
 	"testing"
+	"time"
 
 	log "github.com/kyma-incubator/reconciler/pkg/logger"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
 	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
 	podresetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -68,6 +71,146 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		action.AssertNumberOfCalls(t, "Reset", 1)
 	})
 
+	t.Run("should reset pods in the configured order", func(t *testing.T) {
+		// given
+		now := time.Now()
+		podsWithDifferentImage := v1.PodList{Items: []v1.Pod{
+			fixPodCreatedAt("a", "ns-2", now),
+			fixPodCreatedAt("b", "ns-1", now.Add(-1*time.Hour)),
+		}}
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&podsWithDifferentImage, nil)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(podsWithDifferentImage)
+
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(nil)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+		orderedCfg := cfg
+		orderedCfg.Order = config.ByNamespace
+
+		// when
+		err := istioProxyReset.Run(orderedCfg)
+
+		// then
+		require.NoError(t, err)
+		action.AssertCalled(t, "Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.MatchedBy(func(pods v1.PodList) bool {
+			return len(pods.Items) == 2 && pods.Items[0].Namespace == "ns-1" && pods.Items[1].Namespace == "ns-2"
+		}), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions"))
+	})
+
+	t.Run("should return the aggregated error when the reset of some pods failed", func(t *testing.T) {
+		// given
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}, {}}}, nil)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}, {}}})
+
+		aggregatedErr := &reset.AggregatedError{
+			Total:  2,
+			Failed: []reset.ObjectError{{Object: pod.CustomObject{Name: "name", Namespace: "namespace", Kind: "Pod"}, Err: errors.New("stuck terminating")}},
+		}
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(aggregatedErr)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+
+		// when
+		err := istioProxyReset.Run(cfg)
+
+		// then
+		require.Error(t, err)
+		got, ok := reset.AsAggregatedError(err)
+		require.True(t, ok)
+		require.True(t, got.IsPartial())
+		require.Equal(t, 1, got.Succeeded())
+		action.AssertNumberOfCalls(t, "Reset", 1)
+	})
+
+	t.Run("should pass the deadline counted from the start of the run to the reset", func(t *testing.T) {
+		// given
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}}}, nil).
+			After(50 * time.Millisecond)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}}})
+
+		aggregatedErr := &reset.AggregatedError{
+			Total:      1,
+			Incomplete: true,
+			Remaining:  []pod.CustomObject{{Name: "name", Namespace: "namespace", Kind: "Pod"}},
+		}
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"),
+			mock.MatchedBy(func(waitOpts pod.WaitOptions) bool {
+				return !waitOpts.Deadline.IsZero() && time.Now().After(waitOpts.Deadline)
+			})).
+			Return(aggregatedErr)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+		deadlineCfg := cfg
+		deadlineCfg.Deadline = 10 * time.Millisecond
+
+		// when
+		err := istioProxyReset.Run(deadlineCfg)
+
+		// then
+		require.Error(t, err)
+		got, ok := reset.AsAggregatedError(err)
+		require.True(t, ok)
+		require.True(t, got.Incomplete)
+		require.Len(t, got.Remaining, 1)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+	})
+
+	t.Run("should pass RespectPDB to the reset", func(t *testing.T) {
+		// given
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}}}, nil)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}}})
+
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"),
+			mock.MatchedBy(func(waitOpts pod.WaitOptions) bool {
+				return waitOpts.RespectPDB && waitOpts.Timeout == cfg.Timeout
+			})).
+			Return(nil)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+		pdbCfg := cfg
+		pdbCfg.RespectPDB = true
+
+		// when
+		err := istioProxyReset.Run(pdbCfg)
+
+		// then
+		require.NoError(t, err)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+	})
+
+	t.Run("should not pass a deadline to the reset when none is configured", func(t *testing.T) {
+		// given
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}}}, nil)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}}})
+
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"),
+			mock.MatchedBy(func(waitOpts pod.WaitOptions) bool {
+				return waitOpts.Deadline.IsZero()
+			})).
+			Return(nil)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+
+		// when
+		err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+	})
+
 	t.Run("should return an error when GetAllPods returns an error", func(t *testing.T) {
 		// given
 		expectedError := errors.New("GetAllPods error")
@@ -79,7 +222,7 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		action := podresetmocks.Action{}
 		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
 			Return(nil)
-		istioProxyReset := DefaultIstioProxyReset{&gatherer, &action}
+		istioProxyReset := DefaultIstioProxyReset{gatherer: &gatherer, action: &action}
 
 		// when
 		err := istioProxyReset.Run(cfg)
@@ -91,3 +234,70 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		action.AssertNumberOfCalls(t, "Reset", 0)
 	})
 }
+
+func Test_IstioProxyReset_Preview(t *testing.T) {
+	cfg := config.IstioProxyConfig{
+		ImagePrefix:  "istio/proxyv2",
+		ImageVersion: "1.10.2",
+		RetriesCount: 5,
+		Kubeclient:   fake.NewSimpleClientset(),
+		Log:          log.NewLogger(true),
+	}
+
+	t.Run("should return pods with different image without resetting them", func(t *testing.T) {
+		// given
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}, {}}}, nil)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}}})
+
+		action := podresetmocks.Action{}
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+
+		// when
+		pods, err := istioProxyReset.Preview(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 1)
+		action.AssertNumberOfCalls(t, "Reset", 0)
+	})
+
+	t.Run("should only gather pods matching the label selector", func(t *testing.T) {
+		// given
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetPodsBySelector", mock.Anything, "app=payment", mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}}}, nil)
+		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
+			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}}})
+
+		action := podresetmocks.Action{}
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+		selectorCfg := cfg
+		selectorCfg.LabelSelector = "app=payment"
+
+		// when
+		pods, err := istioProxyReset.Preview(selectorCfg)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 1)
+		gatherer.AssertNotCalled(t, "GetAllPods", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when GetAllPods returns an error", func(t *testing.T) {
+		// given
+		expectedError := errors.New("GetAllPods error")
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(nil, expectedError)
+
+		action := podresetmocks.Action{}
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+
+		// when
+		_, err := istioProxyReset.Preview(cfg)
+
+		// then
+		require.ErrorIs(t, err, expectedError)
+		gatherer.AssertNumberOfCalls(t, "GetPodsWithDifferentImage", 0)
+	})
+}